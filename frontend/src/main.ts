@@ -3,6 +3,7 @@ import { createPinia } from 'pinia'
 import { createI18n } from 'vue-i18n'
 import App from './App.vue'
 import router from './router'
+import { claimSessionNonce } from './session'
 import en from './locales/en.json'
 
 import './assets/styles/main.css'
@@ -22,4 +23,4 @@ app.use(createPinia())
 app.use(router)
 app.use(i18n)
 
-app.mount('#app')
+claimSessionNonce().finally(() => app.mount('#app'))
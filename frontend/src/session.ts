@@ -0,0 +1,26 @@
+import { ClaimSessionNonce } from '@wailsjs/go/app/App'
+import { EventsOn } from '@wailsjs/runtime/runtime'
+
+let nonce = ''
+
+// Claims the nonce the backend requires for sensitive methods, such as
+// Logout and ResetLauncher. The backend hands it out once per page load, so
+// it is claimed before the app mounts and kept only in this module.
+export async function claimSessionNonce(): Promise<void> {
+  try {
+    nonce = await ClaimSessionNonce()
+  } catch (error) {
+    console.error('Failed to claim session nonce:', error)
+  }
+}
+
+// The backend issues a new nonce once the page has loaded, replacing the
+// one claimed before mounting, if any.
+EventsOn('session:nonce_reissued', () => {
+  claimSessionNonce()
+})
+
+// Returns the nonce to pass to sensitive backend methods.
+export function sessionNonce(): string {
+  return nonce
+}
@@ -26,6 +26,8 @@ export interface FeedArticle {
   description: string
   image_url?: string
   dest_url?: string
+  // Sanitized HTML, with images served from the launcher's image proxy.
+  body?: string
 }
 
 export const useAppStore = defineStore('app', () => {
@@ -2,6 +2,7 @@ import { defineStore } from 'pinia'
 import { ref, computed } from 'vue'
 import * as App from '@wailsjs/go/app/App'
 import { account as accountNs } from '@wailsjs/go/models'
+import { sessionNonce } from '@/session'
 
 export interface UserProfile {
   uuid: string
@@ -74,7 +75,7 @@ export const useAuthStore = defineStore('auth', () => {
   }
 
   async function logout() {
-    await App.Logout()
+    await App.Logout(sessionNonce())
     account.value = null
     userProfiles.value = []
     currentProfileUuid.value = null
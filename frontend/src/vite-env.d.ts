@@ -15,7 +15,6 @@ interface WailsRuntime {
     WindowIsMaximised(): Promise<boolean>
     WindowIsMinimised(): Promise<boolean>
     WindowClose(): void
-    BrowserOpenURL(url: string): void
     Environment(): Promise<{
         buildType: string
         platform: string
@@ -37,7 +36,7 @@ interface WailsRuntime {
 interface WailsGoBindings {
     main: {
         App: {
-            LaunchGame(): Promise<void>
+            LaunchGame(server: string): Promise<void>
             [key: string]: (...args: any[]) => Promise<any>
         }
     }
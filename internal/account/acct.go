@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log"
 	"time"
+
+	"hytale-launcher/internal/api"
 )
 
 // keyName is the keyring key name used for encrypting account data.
@@ -15,8 +17,9 @@ const keyName = "3CA80030-8679-41AD-9E5C-09705C233580"
 type Token struct {
 	// AccessToken is the OAuth access token string.
 	AccessToken string `json:"access_token"`
-	// RefreshToken is the OAuth refresh token string.
-	RefreshToken string `json:"refresh_token"`
+	// RefreshToken is the OAuth refresh token string. It is kept in the
+	// keyring, not in the account file.
+	RefreshToken string `json:"refresh_token,omitempty"`
 	// Expiry is the expiration time of the access token.
 	Expiry time.Time `json:"expiry"`
 }
@@ -39,6 +42,19 @@ type Patchline struct {
 	Name string `json:"name"`
 	// Version is the current version of this patchline.
 	Version int `json:"version"`
+	// Preload is the patchline's next build, if it is published ahead of
+	// its release so it can be downloaded in advance.
+	Preload *Preload `json:"preload,omitempty"`
+}
+
+// Preload describes a build published ahead of its release.
+type Preload struct {
+	// Build is the build number.
+	Build int `json:"build"`
+	// Version is the build's version string.
+	Version string `json:"version"`
+	// ReleaseAt is when the build becomes playable.
+	ReleaseAt time.Time `json:"release_at"`
 }
 
 // Account represents a user's account data including profiles and settings.
@@ -52,10 +68,35 @@ type Account struct {
 	// Token holds the OAuth tokens for this account.
 	Token Token `json:"token"`
 
+	// DataValidators identify the launcher data the account was last
+	// refreshed with, so a refresh can skip unchanged data.
+	DataValidators api.Validators `json:"data_validators,omitempty"`
+
 	// SelectedProfile is the UUID of the currently selected profile.
 	SelectedProfile *string `json:"selected_profile,omitempty"`
 	// SelectedChannel is the currently selected patchline/channel name.
 	SelectedChannel *string `json:"selected_channel,omitempty"`
+	// ChannelScope is ChannelScopeProfile or ChannelScopeAccount.
+	// Empty means ChannelScopeProfile.
+	ChannelScope string `json:"channel_scope,omitempty"`
+	// PlayPolicy controls whether pending updates are applied when the
+	// user presses Play. Empty means the launcher default.
+	PlayPolicy string `json:"play_policy,omitempty"`
+	// Locale is the language tag used to format sizes and durations.
+	// Empty means the system language.
+	Locale string `json:"locale,omitempty"`
+
+	// PrereleaseConsents records the accepted consent document for each
+	// pre-release channel, keyed by channel name.
+	PrereleaseConsents map[string]ConsentRecord `json:"prerelease_consents,omitempty"`
+
+	// ExperimentalOptIns records the experimental channels the user opted
+	// into, keyed by channel name.
+	ExperimentalOptIns map[string]ExperimentalOptIn `json:"experimental_opt_ins,omitempty"`
+
+	// BreakReminders holds each profile's break reminder settings, keyed
+	// by profile UUID. Profiles without an entry have reminders off.
+	BreakReminders map[string]BreakReminder `json:"break_reminders,omitempty"`
 
 	// CurrentProfile points to the currently selected profile in the Profiles slice.
 	// This is not serialized to JSON.
@@ -0,0 +1,33 @@
+package account
+
+import "time"
+
+// ConsentRecord records that the user accepted a version of a pre-release
+// channel's consent document.
+type ConsentRecord struct {
+	// Version is the version of the document that was accepted.
+	Version int `json:"version"`
+
+	// AcceptedAt is when the document was accepted.
+	AcceptedAt time.Time `json:"accepted_at"`
+}
+
+// HasConsent returns true if the user has accepted the consent document
+// for a channel at the given version or later. A version of zero accepts
+// any recorded consent.
+func (a *Account) HasConsent(channel string, version int) bool {
+	record, ok := a.PrereleaseConsents[channel]
+	return ok && record.Version >= version
+}
+
+// RecordConsent records that the user accepted the given version of the
+// consent document for a channel.
+func (a *Account) RecordConsent(channel string, version int) {
+	if a.PrereleaseConsents == nil {
+		a.PrereleaseConsents = make(map[string]ConsentRecord)
+	}
+	a.PrereleaseConsents[channel] = ConsentRecord{
+		Version:    version,
+		AcceptedAt: time.Now(),
+	}
+}
@@ -0,0 +1,72 @@
+package account
+
+import (
+	"slices"
+	"strings"
+)
+
+// patchlinePrefix marks entitlements that grant access to a patchline.
+const patchlinePrefix = "patchline:"
+
+// Channel scopes control which profiles' entitlements decide the channels
+// offered to the user.
+const (
+	// ChannelScopeProfile offers only the selected profile's channels.
+	ChannelScopeProfile = "profile"
+
+	// ChannelScopeAccount offers the channels of every profile on the account.
+	ChannelScopeAccount = "account"
+)
+
+// Channels returns the patchlines this profile is entitled to.
+func (p *Profile) Channels() []string {
+	var channels []string
+	for _, entitlement := range p.Entitlements {
+		if channel, ok := strings.CutPrefix(entitlement, patchlinePrefix); ok {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// AllChannels returns the union of the patchlines of every profile on the
+// account, in the order they are first seen.
+func (a *Account) AllChannels() []string {
+	var channels []string
+	for i := range a.Profiles {
+		for _, channel := range a.Profiles[i].Channels() {
+			if !slices.Contains(channels, channel) {
+				channels = append(channels, channel)
+			}
+		}
+	}
+	return channels
+}
+
+// DiffChannels returns the channels in after but not before, and those in
+// before but not after.
+func DiffChannels(before, after []string) (granted, revoked []string) {
+	for _, channel := range after {
+		if !slices.Contains(before, channel) {
+			granted = append(granted, channel)
+		}
+	}
+	for _, channel := range before {
+		if !slices.Contains(after, channel) {
+			revoked = append(revoked, channel)
+		}
+	}
+	return granted, revoked
+}
+
+// ChannelOwners returns, for each patchline on the account, the UUIDs of the
+// profiles entitled to it.
+func (a *Account) ChannelOwners() map[string][]string {
+	owners := make(map[string][]string)
+	for i := range a.Profiles {
+		for _, channel := range a.Profiles[i].Channels() {
+			owners[channel] = append(owners[channel], a.Profiles[i].UUID)
+		}
+	}
+	return owners
+}
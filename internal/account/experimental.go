@@ -0,0 +1,33 @@
+package account
+
+import "time"
+
+// ExperimentalOptIn records that the user opted into an experimental
+// channel.
+type ExperimentalOptIn struct {
+	// ConsentVersion is the version of the channel's consent document
+	// accepted when opting in.
+	ConsentVersion int `json:"consent_version"`
+
+	// OptedInAt is when the user opted in.
+	OptedInAt time.Time `json:"opted_in_at"`
+}
+
+// HasExperimentalOptIn returns true if the user opted into an
+// experimental channel.
+func (a *Account) HasExperimentalOptIn(channel string) bool {
+	_, ok := a.ExperimentalOptIns[channel]
+	return ok
+}
+
+// RecordExperimentalOptIn records that the user opted into an experimental
+// channel having accepted the given version of its consent document.
+func (a *Account) RecordExperimentalOptIn(channel string, consentVersion int) {
+	if a.ExperimentalOptIns == nil {
+		a.ExperimentalOptIns = make(map[string]ExperimentalOptIn)
+	}
+	a.ExperimentalOptIns[channel] = ExperimentalOptIn{
+		ConsentVersion: consentVersion,
+		OptedInAt:      time.Now(),
+	}
+}
@@ -3,12 +3,15 @@ package account
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 
 	"hytale-launcher/internal/crypto"
 )
 
 // ReadFile reads and decrypts an account file from the given path.
 // The file is expected to be encrypted with the account encryption key.
+// Refresh tokens are loaded from the keyring; a file that still holds them
+// is rewritten with them moved to the keyring.
 // Returns the deserialized Account and any error encountered.
 func ReadFile(filePath string) (*Account, error) {
 	data, err := crypto.ReadFile(filePath, keyName)
@@ -22,13 +25,31 @@ func ReadFile(filePath string) (*Account, error) {
 		return nil, fmt.Errorf("could not unmarshal account data: %w", err)
 	}
 
+	legacy, err := acct.loadRefreshTokens()
+	if err != nil {
+		return nil, err
+	}
+	if legacy {
+		if err := acct.Write(filePath); err != nil {
+			slog.Warn("unable to move refresh tokens to keyring", "error", err)
+		} else {
+			slog.Info("moved refresh tokens from account file to keyring")
+		}
+	}
+
 	return acct, nil
 }
 
 // Write serializes and encrypts the account data to the given path.
-// The data is encrypted with the account encryption key.
+// The data is encrypted with the account encryption key. Refresh tokens are
+// stored in the keyring instead of the file.
 func (a *Account) Write(filePath string) error {
-	data, err := json.Marshal(a)
+	out, err := a.withoutRefreshTokens()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(out)
 	if err != nil {
 		return fmt.Errorf("could not marshal account data: %w", err)
 	}
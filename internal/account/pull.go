@@ -1,16 +1,16 @@
 package account
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
 
+	"hytale-launcher/internal/api"
 	"hytale-launcher/internal/build"
 	"hytale-launcher/internal/endpoints"
-	"hytale-launcher/internal/ioutil"
-	"hytale-launcher/internal/net"
 )
 
 // launcherData represents the response from the launcher data API.
@@ -28,6 +28,8 @@ type launcherData struct {
 
 // Refresh fetches the latest account data from the server.
 // It updates the account's Profiles, Patchlines, EULAAcceptedAt, and RefreshedAt fields.
+// The request is conditional on the data the account was last refreshed
+// with, so unchanged data is not sent again.
 // The client should be an authenticated HTTP client.
 // The cause parameter is used for logging purposes.
 //
@@ -35,21 +37,25 @@ type launcherData struct {
 func (a *Account) Refresh(client *http.Client, cause string) error {
 	slog.Debug("refreshing account data", "cause", cause)
 
-	// Check if we're offline
-	if err := net.OfflineError(); err != nil {
-		return err
-	}
-
 	// Build query parameters
 	params := url.Values{}
 	params.Set("os", build.OS())
 	params.Set("arch", build.Arch())
 
 	// Fetch launcher data from the API
-	data, err := ioutil.Get[launcherData](client, endpoints.LauncherData(), params)
+	validators := a.DataValidators
+	if len(a.Profiles) == 0 {
+		validators = api.Validators{}
+	}
+	data, next, modified, err := api.GetIfModified[launcherData](context.Background(), api.New(api.WithHTTPClient(client)), endpoints.LauncherData(), params, validators)
 	if err != nil {
 		return fmt.Errorf("error fetching account launcher data: %w", err)
 	}
+	if !modified {
+		slog.Debug("account launcher data unchanged")
+		a.LastRefresh = time.Now()
+		return nil
+	}
 
 	// Only update if we received profiles
 	if len(data.Profiles) == 0 {
@@ -60,6 +66,7 @@ func (a *Account) Refresh(client *http.Client, cause string) error {
 	a.Profiles = data.Profiles
 	a.Patchlines = data.Patchlines
 	a.EULAAcceptedAt = data.EULAAcceptedAt
+	a.DataValidators = next
 	a.LastRefresh = time.Now()
 
 	return nil
@@ -0,0 +1,82 @@
+package account
+
+import (
+	"fmt"
+
+	"hytale-launcher/internal/keyring"
+)
+
+// refreshTokenKey returns the keyring key a refresh token is stored under:
+// per profile, or for the account itself when uuid is empty. The keyring
+// is the Credential Manager (DPAPI) on Windows, the Keychain on macOS and
+// the Secret Service on Linux.
+func refreshTokenKey(uuid string) string {
+	if uuid == "" {
+		return "refresh-token"
+	}
+	return "refresh-token:" + uuid
+}
+
+// tokens calls fn with each token of the account and the UUID of the
+// profile it belongs to, or "" for the account's own token.
+func (a *Account) tokens(fn func(uuid string, t *Token) error) error {
+	if err := fn("", &a.Token); err != nil {
+		return err
+	}
+	for i := range a.Profiles {
+		if err := fn(a.Profiles[i].UUID, &a.Profiles[i].Token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutRefreshTokens stores the account's refresh tokens in the keyring
+// and returns a copy of the account without them, for writing to disk.
+func (a *Account) withoutRefreshTokens() (*Account, error) {
+	out := *a
+	out.Profiles = append([]Profile(nil), a.Profiles...)
+
+	err := out.tokens(func(uuid string, t *Token) error {
+		if t.RefreshToken == "" {
+			return nil
+		}
+		if err := keyring.Set(refreshTokenKey(uuid), []byte(t.RefreshToken)); err != nil {
+			return fmt.Errorf("could not store refresh token: %w", err)
+		}
+		t.RefreshToken = ""
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// loadRefreshTokens fills in the account's refresh tokens from the
+// keyring. Returns true if the account file still held a refresh token,
+// as written by older launchers, so it should be rewritten without it.
+func (a *Account) loadRefreshTokens() (bool, error) {
+	legacy := false
+	err := a.tokens(func(uuid string, t *Token) error {
+		if t.RefreshToken != "" {
+			legacy = true
+			return nil
+		}
+		value, err := keyring.Get(refreshTokenKey(uuid))
+		if err != nil {
+			return fmt.Errorf("could not load refresh token: %w", err)
+		}
+		t.RefreshToken = string(value)
+		return nil
+	})
+	return legacy, err
+}
+
+// DeleteRefreshTokens removes the account's refresh tokens from the
+// keyring, e.g. on logout.
+func (a *Account) DeleteRefreshTokens() error {
+	return a.tokens(func(uuid string, _ *Token) error {
+		return keyring.Delete(refreshTokenKey(uuid))
+	})
+}
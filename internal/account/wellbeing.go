@@ -0,0 +1,31 @@
+package account
+
+// DefaultBreakInterval is the suggested time between break reminders, in
+// minutes.
+const DefaultBreakInterval = 60
+
+// BreakReminder holds a profile's break reminder settings.
+type BreakReminder struct {
+	// Enabled turns reminders on for the profile.
+	Enabled bool `json:"enabled"`
+
+	// IntervalMinutes is how long the game runs between reminders.
+	IntervalMinutes int `json:"interval_minutes"`
+}
+
+// GetBreakReminder returns the break reminder settings for a profile.
+// Reminders are off unless the profile has turned them on.
+func (a *Account) GetBreakReminder(profileUUID string) BreakReminder {
+	if r, ok := a.BreakReminders[profileUUID]; ok {
+		return r
+	}
+	return BreakReminder{IntervalMinutes: DefaultBreakInterval}
+}
+
+// SetBreakReminder stores the break reminder settings for a profile.
+func (a *Account) SetBreakReminder(profileUUID string, r BreakReminder) {
+	if a.BreakReminders == nil {
+		a.BreakReminders = make(map[string]BreakReminder)
+	}
+	a.BreakReminders[profileUUID] = r
+}
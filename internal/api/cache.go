@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hytale-launcher/internal/ioutil"
+)
+
+// Validators identify a version of a response, so a later request can ask
+// for the resource only if it has changed.
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// validatorsOf returns the validators of a response.
+func validatorsOf(resp *http.Response) Validators {
+	return Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+}
+
+// empty reports whether there are no validators.
+func (v Validators) empty() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// apply makes a request conditional on the validators.
+func (v Validators) apply(req *http.Request) {
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+}
+
+// Cache keeps API responses on disk, so they survive restarts, can be
+// revalidated with conditional requests, and can be served when the API
+// cannot be reached.
+type Cache struct {
+	dir func() string
+}
+
+// NewCache creates a cache storing responses in the directory returned by
+// dir, which is looked up on each use.
+func NewCache(dir func() string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// cacheEntry is a cached response.
+type cacheEntry struct {
+	URL string `json:"url"`
+	Validators
+	Fetched time.Time       `json:"fetched"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// path returns the file a URL's response is cached in.
+func (c *Cache) path(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(c.dir(), hex.EncodeToString(sum[:16])+".json")
+}
+
+// load returns the cached response for a URL, or nil if there is none.
+func (c *Cache) load(rawURL string) *cacheEntry {
+	data, err := os.ReadFile(c.path(rawURL))
+	if err != nil {
+		return nil
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(data, &e); err != nil || e.URL != rawURL {
+		return nil
+	}
+	return &e
+}
+
+// store saves a response to the cache.
+func (c *Cache) store(e *cacheEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir(), 0o755); err != nil {
+		return err
+	}
+	path := c.path(e.URL)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Clear deletes every cached response and returns the bytes freed.
+func (c *Cache) Clear() (int64, error) {
+	size, _ := ioutil.DirSize(c.dir())
+	if err := os.RemoveAll(c.dir()); err != nil {
+		return 0, fmt.Errorf("unable to clear API cache: %w", err)
+	}
+	return size, nil
+}
+
+// CacheInfo describes where a response returned by GetCached came from.
+type CacheInfo struct {
+	// Fetched is when the response was last fetched or revalidated.
+	Fetched time.Time
+
+	// Stale is set if the response is served from cache because the API
+	// could not be reached.
+	Stale bool
+}
+
+// GetCached is like Get, but responses are kept in cache. A cached response
+// younger than maxAge is returned without a request; an older one is
+// revalidated with a conditional request. If the API cannot be reached, is
+// failing or is rate limiting, or the launcher is offline, the cached
+// response is returned however old it is.
+func GetCached[T any](ctx context.Context, c *Client, cache *Cache, rawURL string, params url.Values, maxAge time.Duration) (T, CacheInfo, error) {
+	var result T
+
+	rawURL = withParams(rawURL, params)
+	entry := cache.load(rawURL)
+	if entry != nil && time.Since(entry.Fetched) < maxAge {
+		return result, CacheInfo{Fetched: entry.Fetched}, decodeCached(entry, &result)
+	}
+
+	var v Validators
+	if entry != nil {
+		v = entry.Validators
+	}
+	body, next, modified, err := getRaw(ctx, c, rawURL, v)
+	if err != nil {
+		if entry != nil && servesStale(err) {
+			slog.Warn("serving cached response", "url", rawURL, "fetched", entry.Fetched, "error", err)
+			return result, CacheInfo{Fetched: entry.Fetched, Stale: true}, decodeCached(entry, &result)
+		}
+		return result, CacheInfo{}, err
+	}
+	if !modified {
+		body = entry.Body
+	}
+
+	entry = &cacheEntry{URL: rawURL, Validators: next, Fetched: time.Now(), Body: body}
+	if err := cache.store(entry); err != nil {
+		slog.Warn("unable to cache response", "url", rawURL, "error", err)
+	}
+	return result, CacheInfo{Fetched: entry.Fetched}, decodeCached(entry, &result)
+}
+
+// decodeCached decodes a cached response.
+func decodeCached(e *cacheEntry, v any) error {
+	if err := json.Unmarshal(e.Body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// servesStale reports whether a failed request should fall back to a
+// cached response: the server failed or the request did not reach it,
+// rather than the server rejecting it.
+func servesStale(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode >= 500 || se.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
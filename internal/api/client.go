@@ -0,0 +1,230 @@
+// Package api provides the HTTP client used to call the Hytale launcher
+// and account APIs. It centralizes transport configuration, authentication,
+// launcher headers, offline-mode checks and error handling, so every
+// endpoint is called the same way.
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/net"
+)
+
+// Client performs requests against the launcher APIs.
+// The zero value is not usable; create clients with New.
+type Client struct {
+	http  *http.Client
+	token string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. This is how an
+// OAuth-authenticated client or a test transport is injected.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.http = hc
+		}
+	}
+}
+
+// WithToken sets a bearer token sent with every request.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// New creates a Client. Without options it uses http.DefaultClient and
+// sends no credentials.
+func New(opts ...Option) *Client {
+	c := &Client{http: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Default is the unauthenticated client used for public endpoints.
+var Default = New()
+
+// Get performs a GET request with optional query parameters and decodes
+// the JSON response into a value of type T.
+//
+// It returns net.ErrOffline without making a request if the launcher is in
+// offline mode, and a *StatusError if the server responds with anything
+// other than 200 OK. Rate limited requests are retried after the delay the
+// server asks for.
+func Get[T any](ctx context.Context, c *Client, rawURL string, params url.Values) (T, error) {
+	var result T
+
+	body, _, _, err := getRaw(ctx, c, withParams(rawURL, params), Validators{})
+	if err != nil {
+		return result, err
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetIfModified is like Get, but the request is conditional on v, the
+// validators of a previous response. If the resource has not changed,
+// modified is false and result is the zero value. The validators of the
+// response are returned for the next request.
+func GetIfModified[T any](ctx context.Context, c *Client, rawURL string, params url.Values, v Validators) (result T, next Validators, modified bool, err error) {
+	body, next, modified, err := getRaw(ctx, c, withParams(rawURL, params), v)
+	if err != nil || !modified {
+		return result, next, modified, err
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, next, modified, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result, next, true, nil
+}
+
+// withParams appends query parameters to a URL.
+func withParams(rawURL string, params url.Values) string {
+	if len(params) > 0 {
+		rawURL = rawURL + "?" + params.Encode()
+	}
+	return rawURL
+}
+
+// getRaw performs a GET request, conditional on v if it is set, and returns
+// the body and validators of the response. For a 304 Not Modified response
+// modified is false, body is nil and v is returned as is.
+func getRaw(ctx context.Context, c *Client, rawURL string, v Validators) (body []byte, next Validators, modified bool, err error) {
+	if c == nil {
+		c = Default
+	}
+
+	if err := net.OfflineError(); err != nil {
+		return nil, v, false, err
+	}
+
+	slog.Debug("fetching URL", "url", rawURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, v, false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	hytale.SetUserAgent(req)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	v.apply(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, v, false, fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && !v.empty() {
+		return nil, v, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, v, false, &StatusError{
+			URL:        req.URL.Redacted(),
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, v, false, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, validatorsOf(resp), true, nil
+}
+
+// Post sends body as JSON in a POST request. Any 2xx response is success;
+// the response body is discarded.
+//
+// Like Get, it returns net.ErrOffline without making a request if the
+// launcher is in offline mode, and a *StatusError for other responses.
+func Post(ctx context.Context, c *Client, rawURL string, body any) error {
+	resp, err := post(ctx, c, rawURL, body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// PostJSON sends body as JSON in a POST request and decodes the JSON
+// response into a value of type T. Errors are reported as for Post.
+func PostJSON[T any](ctx context.Context, c *Client, rawURL string, body any) (T, error) {
+	var result T
+
+	resp, err := post(ctx, c, rawURL, body)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result, nil
+}
+
+// post performs a JSON POST request and returns the response if its status
+// is 2xx. The caller must close the response body.
+func post(ctx context.Context, c *Client, rawURL string, body any) (*http.Response, error) {
+	if c == nil {
+		c = Default
+	}
+
+	if err := net.OfflineError(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	slog.Debug("posting to URL", "url", rawURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	hytale.SetUserAgent(req)
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, &StatusError{
+			URL:        req.URL.Redacted(),
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors matched by a *StatusError with errors.Is.
+var (
+	// ErrUnauthorized is returned when the credentials are missing or invalid.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden is returned when the account may not access the resource,
+	// e.g. a patchline it is not entitled to.
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrNotFound is returned when the resource does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrServer is returned for 5xx responses, which are usually transient.
+	ErrServer = errors.New("server error")
+
+	// ErrRateLimited is returned when the server keeps responding with 429
+	// Too Many Requests, or asks for a longer wait than the client allows.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// StatusError is returned when an API responds with an unexpected status.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+// Error returns the error message.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// Is reports whether the status matches one of the package's error kinds.
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrServer:
+		return e.StatusCode >= 500
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
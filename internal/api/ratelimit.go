@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRateLimitRetries is how many times a rate limited request is
+	// retried before its 429 response is returned.
+	maxRateLimitRetries = 3
+
+	// maxRetryAfter is the longest a request waits for a rate limit to
+	// lift. Requests to a host limited for longer fail with ErrRateLimited.
+	maxRetryAfter = 2 * time.Minute
+
+	// defaultRetryAfter is used for a 429 response without a usable
+	// Retry-After header.
+	defaultRetryAfter = 5 * time.Second
+)
+
+// RateLimit describes a host rate limiting the launcher.
+type RateLimit struct {
+	// Host is the rate limited host.
+	Host string `json:"host"`
+
+	// Until is when requests to the host are sent again.
+	Until time.Time `json:"until"`
+
+	// RetryAfter is the number of seconds until then.
+	RetryAfter int `json:"retry_after"`
+}
+
+var (
+	// limitMu protects limits and onRateLimited.
+	limitMu sync.Mutex
+
+	// limits holds, per host, the time until which requests to it are
+	// held back.
+	limits map[string]time.Time
+
+	// onRateLimited is called when a host starts rate limiting.
+	onRateLimited func(RateLimit)
+)
+
+// OnRateLimited registers a function to be called when a host responds
+// with 429 Too Many Requests, so the user can be told why requests are
+// delayed.
+func OnRateLimited(fn func(RateLimit)) {
+	limitMu.Lock()
+	defer limitMu.Unlock()
+	onRateLimited = fn
+}
+
+// RateLimited reports whether requests to the host of rawURL are being
+// held back after a 429 response.
+func RateLimited(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return time.Now().Before(limitedUntil(u.Host))
+}
+
+// limitedUntil returns the time until which requests to host are held back.
+func limitedUntil(host string) time.Time {
+	limitMu.Lock()
+	defer limitMu.Unlock()
+	return limits[host]
+}
+
+// limit holds back requests to host for d, notifying the handler.
+func limit(host string, d time.Duration) {
+	until := time.Now().Add(d)
+
+	limitMu.Lock()
+	if limits == nil {
+		limits = make(map[string]time.Time)
+	}
+	if until.Before(limits[host]) {
+		limitMu.Unlock()
+		return
+	}
+	limits[host] = until
+	fn := onRateLimited
+	limitMu.Unlock()
+
+	slog.Warn("rate limited by server", "host", host, "retry_after", d)
+	if fn != nil {
+		fn(RateLimit{Host: host, Until: until, RetryAfter: int(d.Round(time.Second).Seconds())})
+	}
+}
+
+// waitForLimit blocks until requests to host may be sent. It fails
+// immediately if the wait is longer than maxRetryAfter.
+func waitForLimit(ctx context.Context, host string) error {
+	d := time.Until(limitedUntil(host))
+	if d <= 0 {
+		return nil
+	}
+	if d > maxRetryAfter {
+		return fmt.Errorf("%w: retry in %s", ErrRateLimited, d.Round(time.Second))
+	}
+
+	slog.Debug("waiting for rate limit", "host", host, "wait", d)
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseRetryAfter returns the delay given by a Retry-After header, either
+// in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// do sends a request, waiting out rate limits. On a 429 response requests
+// to the host are held back for the Retry-After delay and the request is
+// retried, up to maxRateLimitRetries times. The last 429 response is
+// returned if the limit does not lift in time.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	for attempt := 0; ; attempt++ {
+		if err := waitForLimit(req.Context(), host); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			wait = defaultRetryAfter
+		}
+		limit(host, wait)
+		if attempt == maxRateLimitRetries || wait > maxRetryAfter {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+
+	"hytale-launcher/internal/settings"
+)
+
+// GetAccessibility returns the user interface accessibility preferences,
+// with the UI scale filled in.
+func (a *App) GetAccessibility() settings.Accessibility {
+	acc := settings.Get().Accessibility
+	acc.UIScale = acc.Scale()
+	return acc
+}
+
+// SetAccessibility saves the user interface accessibility preferences and
+// emits them as an "accessibility:changed" event. A zero UI scale restores
+// the default.
+func (a *App) SetAccessibility(acc settings.Accessibility) error {
+	if acc.UIScale != 0 && (acc.UIScale < settings.MinUIScale || acc.UIScale > settings.MaxUIScale) {
+		return fmt.Errorf("UI scale must be between %g and %g", settings.MinUIScale, settings.MaxUIScale)
+	}
+	if acc.UIScale == settings.DefaultUIScale {
+		acc.UIScale = 0
+	}
+
+	err := settings.Update("set_accessibility", func(s *settings.Settings) {
+		s.Accessibility = acc
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("accessibility preferences changed",
+		"reduced_motion", acc.ReducedMotion,
+		"high_contrast", acc.HighContrast,
+		"ui_scale", acc.Scale(),
+	)
+	a.emitAccessibility()
+	return nil
+}
+
+// emitAccessibility sends the accessibility preferences to the frontend,
+// which applies them to the user interface.
+func (a *App) emitAccessibility() {
+	a.Emit("accessibility:changed", a.GetAccessibility())
+}
@@ -1,36 +1,49 @@
 // Package app provides the main application logic for the Hytale launcher.
 // It handles application lifecycle, user authentication, update channels,
-// and communication with the frontend via Wails.
+// and communication with the frontend through a service.Frontend, which
+// Wails or a headless command line provides.
 package app
 
 import (
 	"context"
 	"fmt"
 	"log/slog"
-	"os"
+	"runtime/debug"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/getsentry/sentry-go"
-	"github.com/wailsapp/wails/v2/pkg/runtime"
 
 	"hytale-launcher/internal/account"
+	"hytale-launcher/internal/api"
 	"hytale-launcher/internal/appstate"
 	"hytale-launcher/internal/auth"
+	"hytale-launcher/internal/discord"
+	"hytale-launcher/internal/download"
+	"hytale-launcher/internal/endpoints"
 	"hytale-launcher/internal/hytale"
 	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/lanshare"
+	"hytale-launcher/internal/launch"
 	"hytale-launcher/internal/net"
+	"hytale-launcher/internal/notifications"
+	"hytale-launcher/internal/presence"
+	"hytale-launcher/internal/service"
+	"hytale-launcher/internal/service/wailsv2"
+	"hytale-launcher/internal/telemetry"
 	"hytale-launcher/internal/throttle"
-	"hytale-launcher/internal/update"
+	"hytale-launcher/internal/tray"
 	"hytale-launcher/internal/updater"
 )
 
 // App is the main application struct that manages the launcher's state and behavior.
 // It coordinates between the authentication controller, update system, and frontend.
 type App struct {
-	// ctx is the Wails application context, used for emitting events to the frontend.
-	ctx context.Context
+	// frontend hosts the user interface, receiving events and showing
+	// dialogs. It is nil until Start.
+	frontend service.Frontend
 
 	// Auth is the authentication controller managing user sessions and OAuth tokens.
 	Auth *auth.Controller
@@ -38,14 +51,15 @@ type App struct {
 	// ready is a channel that signals when the backend initialization is complete.
 	ready chan struct{}
 
-	// listen is the update event listener that forwards events to the frontend.
-	listen *appListen
+	// channels holds the state and updater of the selected channel and of
+	// channels updating in the background.
+	channels channelManager
 
 	// Updater handles checking for and applying game updates.
 	Updater *updater.Updater
 
-	// refresher periodically refreshes application state.
-	refresher *throttle.Refresher
+	// scheduler runs the periodic jobs while a user is logged in.
+	scheduler *throttle.Scheduler
 
 	// refreshMu protects the refresh operation from concurrent access.
 	refreshMu sync.Mutex
@@ -55,12 +69,106 @@ type App struct {
 
 	// selectedChannel holds the name of the currently selected update channel.
 	selectedChannel *string
+
+	// tray is the system tray icon, or nil if no tray is available.
+	tray *tray.Tray
+
+	// background is true while the window is hidden in the tray.
+	background atomic.Bool
+
+	// runningMu protects runningGame.
+	runningMu sync.Mutex
+
+	// runningGame is the game process currently running, or nil.
+	runningGame *launch.Running
+
+	// breakTimer sends break reminders while the game runs, or is nil if
+	// reminders are off. Protected by runningMu.
+	breakTimer *launch.BreakTimer
+
+	// screenshotWatch stops watching for new screenshots while the game
+	// runs, or is nil. Protected by runningMu.
+	screenshotWatch context.CancelFunc
+
+	// presenceMu protects presence.
+	presenceMu sync.Mutex
+
+	// presence shares the user's status with their friends, or is nil if
+	// the user has not allowed it or is logged out.
+	presence *presence.Client
+
+	// lanShareMu protects lanShare, and is held while it starts or stops.
+	lanShareMu sync.Mutex
+
+	// lanShare serves the game builds to other launchers on the local
+	// network, or is nil if the user has not allowed it.
+	lanShare *lanshare.Server
+
+	// richPresence publishes the launcher's activity to Discord.
+	richPresence discord.Presence
+
+	// cloudSyncMu serializes syncs and protects cloudSyncStatus.
+	cloudSyncMu sync.Mutex
+
+	// cloudSyncStatus is the state of cloud sync reported to the frontend.
+	cloudSyncStatus CloudSyncStatus
+
+	// downloadWindowOverride is when the download window lifted by
+	// DownloadNow applies again, or nil.
+	downloadWindowOverride atomic.Pointer[time.Time]
+
+	// downloadsDeferred is set while background downloads wait for the
+	// download window to open.
+	downloadsDeferred atomic.Bool
+
+	// startupLink is a hytale:// link passed on the command line, handled
+	// once the frontend is ready.
+	startupLink string
+
+	// linkLaunch is the launch requested by a hytale:// link, waiting for
+	// the user to confirm it, or nil.
+	linkLaunch atomic.Pointer[LinkLaunch]
+
+	// lastUpdate is the outcome of the most recent ApplyUpdates call, or
+	// nil if none has run in this session.
+	lastUpdate atomic.Pointer[updateRecord]
+
+	// uncleanReported is set once the frontend has been told about an
+	// unclean exit of the previous run.
+	uncleanReported atomic.Bool
+
+	// events delivers events to the frontend at a bounded rate. It is nil
+	// until Startup.
+	events *throttle.Emitter
+
+	// quitting is set when the user quits from the tray, so closing the
+	// window exits instead of hiding it.
+	quitting atomic.Bool
+
+	// startupErr is why the backend failed to start, if it did.
+	startupErr atomic.Pointer[error]
+
+	// notifiedBuild is the channel and game version last announced with a
+	// new build notification, so each build is announced once.
+	notifiedBuild atomic.Pointer[string]
+
+	// safeMode disables optional integrations, so a misbehaving launcher
+	// can still be used to reset its data. It is set before Startup.
+	safeMode bool
+
+	// sessionNonce authorizes calls to sensitive methods from the
+	// frontend, and nonceClaimed is set once the frontend has it. Both
+	// are reset each time the frontend loads. nonceMu protects them.
+	nonceMu      sync.Mutex
+	sessionNonce string
+	nonceClaimed bool
 }
 
 // New creates a new App instance.
 func New() *App {
 	return &App{
-		ready: make(chan struct{}),
+		ready:        make(chan struct{}),
+		sessionNonce: newSessionNonce(),
 	}
 }
 
@@ -73,64 +181,157 @@ func (a *App) init() error {
 		return fmt.Errorf("unable to create storage directory: %w", err)
 	}
 
+	// Point endpoints at a non-production backend if one was selected.
+	a.applyEnvironment()
+
+	// Apply the kill switches, rollouts and endpoint overrides the
+	// maintainers publish.
+	a.applyRemoteConfig()
+
+	// Pin the account endpoints' keys before the session is restored.
+	a.applyPinning()
+
 	// Initialize the authentication controller.
 	a.Auth = new(auth.Controller)
 	if err := a.Auth.Init(); err != nil {
 		return fmt.Errorf("unable to initialize auth controller: %w", err)
 	}
 
+	// Format sizes and durations in the system language until a user
+	// with a saved locale logs in.
+	a.applyLocale()
+
 	// If user is already logged in, initialize their session.
 	if profile := a.getCurrentProfile(); profile != nil {
 		a.userInit()
 	}
 
-	// Clean up the download cache directory.
-	cacheDir := hytale.InStorageDir("cache")
-	if err := os.RemoveAll(cacheDir); err != nil {
-		slog.Warn("unable to flush download cache", "error", err)
+	// Show notifications, such as break reminders, through the system.
+	notifications.UseDesktop()
+
+	// Send any telemetry queued by a previous session, if opted in.
+	if !a.safeMode {
+		telemetry.Start(context.Background())
 	}
 
+	// Pick up a game left running by a previous launcher session.
+	a.reattachGame()
+
+	// Make hytale:// links open this launcher.
+	if !a.safeMode {
+		registerDeepLinks()
+		refreshAutoStart()
+	}
+
+	// Share the installed game builds with launchers on the network.
+	go a.startLANShare()
+
+	// Clean up downloads left by earlier runs, keeping reusable files.
+	download.CleanCache()
+
+	// Remove what the previous version left behind after a self-update.
+	go finishSelfUpdate()
+
 	slog.Info("app initialized")
 
-	// Signal that initialization is complete.
-	a.ready <- struct{}{}
+	// Signal that initialization is complete. Closing the channel releases
+	// any waiter without requiring one, so a frontend that never calls
+	// DomReady, such as service.Headless, does not block here.
 	close(a.ready)
 
 	return nil
 }
 
-// DomReady is called by Wails when the frontend DOM is ready.
-// It starts a goroutine that waits for backend initialization
-// and then notifies the frontend.
+// DomReady is called by Wails when the frontend DOM is ready, on the first
+// load and on every reload. A reloaded frontend has lost the session
+// nonce, so a new one is issued for it.
 func (a *App) DomReady(ctx context.Context) {
+	a.reissueSessionNonce()
+	a.FrontendReady()
+}
+
+// FrontendReady is called once the frontend can receive events. It pushes
+// the accessibility preferences, so the first paint honours them, and
+// starts a goroutine that waits for backend initialization and then
+// notifies the frontend.
+func (a *App) FrontendReady() {
+	a.emitAccessibility()
 	go func() {
 		slog.Debug("frontend ready, waiting for backend")
 		<-a.ready
 		slog.Debug("backend ready, notifying frontend")
 		a.ReloadLauncher("dom_ready")
+		a.refreshInBackground()
+		a.reportUncleanExit()
+		a.updateDiscord()
+		a.handleStartupLink()
 	}()
 }
 
 // Startup is called by Wails when the application starts.
-// It stores the context and initializes the application backend.
+// It starts the backend on the Wails frontend and shows the tray icon. If
+// the backend fails to start or panics, the user is told why and the
+// launcher quits.
 func (a *App) Startup(ctx context.Context) {
-	a.ctx = ctx
+	frontend := wailsv2.New(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			sentry.CurrentHub().Recover(r)
+			slog.Error("panic during startup", "panic", r, "stack", string(debug.Stack()))
+			a.startupFailed(frontend, fmt.Errorf("panic during startup: %v", r))
+		}
+	}()
+
+	if err := a.Start(frontend); err != nil {
+		a.startupFailed(frontend, err)
+		return
+	}
+	if !a.safeMode {
+		a.startTray()
+	}
+}
+
+// Start initializes the application backend, delivering events to and
+// showing dialogs through the given frontend.
+func (a *App) Start(frontend service.Frontend) error {
+	a.frontend = frontend
+	a.events = throttle.NewEmitter(frontend.Emit, throttle.EmitterOptions{
+		Coalesce:  coalesceEvent,
+		Interval:  emitInterval,
+		BatchSize: emitBatchSize,
+		QueueSize: emitQueueSize,
+	})
+	a.watchKeyring()
+	a.watchRateLimits()
+
+	// Select the user's language before anything can fail, so a startup
+	// error is reported in it.
+	a.applyLocale()
 
 	if err := a.init(); err != nil {
 		sentry.CaptureException(err)
 		slog.Error("error during app initialization", "error", err)
-		panic(err)
+		return err
 	}
+	return nil
 }
 
 // Emit sends an event to the frontend with the given name and arguments.
-// Events named "update:status" are not logged to avoid log spam.
+// Events are queued and delivered in order at a bounded rate; for progress
+// events only the latest queued one is delivered. Progress events are not
+// logged to avoid log spam.
 func (a *App) Emit(name string, args ...any) {
-	if name != "update:status" {
+	if !coalesceEvent(name) {
 		slog.Debug("emitting event", "name", name, "args", args)
 	}
 
-	runtime.EventsEmit(a.ctx, name, args...)
+	if a.events == nil {
+		if a.frontend != nil {
+			a.frontend.Emit(name, args...)
+		}
+		return
+	}
+	a.events.Emit(name, args...)
 }
 
 // ReloadLauncher emits a "reload" event to the frontend, causing it to refresh its state.
@@ -142,46 +343,27 @@ func (a *App) ReloadLauncher(cause string) {
 
 // userInit initializes user-specific state after login.
 // It selects the default profile, restores the previously selected channel if valid,
-// and starts the periodic refresh loop.
+// and starts the periodic jobs.
 func (a *App) userInit() {
 	a.selectDefaultProfile()
+	a.applyLocale()
 
 	// Check if the previously selected channel is still available.
 	acct := a.Auth.GetAccount()
 	if acct != nil && acct.SelectedChannel != nil {
-		channels := a.GetUserChannels()
+		channels := a.userChannels()
 		if slices.Contains(channels, *acct.SelectedChannel) {
 			slog.Info("restoring previously selected channel", "channel", *acct.SelectedChannel)
 			a.SetChannel(acct.SelectedChannel)
 		}
 	}
 
-	// Start the periodic refresh loop (every hour).
-	a.refresher = throttle.NewRefresher(a.refresh)
-	a.refresher.Start(time.Hour)
-}
-
-// refresh performs a soft refresh of the application state.
-// It checks for updates and refreshes the news feed.
-func (a *App) refresh() error {
-	slog.Debug("soft refreshing application state")
-
-	// Check for updates without forcing a network request.
-	count := a.CheckForUpdates(false)
-	if count > 0 {
-		a.Emit("hint:updates_available")
-	}
-
-	// Refresh the news feed.
-	if err := a.RefreshNewsFeed(); err != nil {
-		return fmt.Errorf("unable to refresh news feed: %w", err)
-	}
+	// Start the periodic jobs.
+	a.scheduler = a.newScheduler()
+	a.scheduler.Start()
 
-	if count > 0 {
-		a.Emit("hint:news_available")
-	}
-
-	return nil
+	go a.startPresence()
+	go a.syncCloud("login")
 }
 
 const refreshCooldown = 15 * time.Minute
@@ -199,20 +381,28 @@ func (a *App) refreshUser(force bool, cause string) {
 		return
 	}
 
-	// Check refresh cooldown unless forced.
+	// Check refresh cooldown unless forced. A forced refresh is dropped
+	// while the server is rate limiting, so repeated clicks do not extend
+	// the limit.
 	if !force && time.Since(acct.LastRefresh) < refreshCooldown {
 		return
 	}
+	if force && api.RateLimited(endpoints.LauncherData()) {
+		slog.Info("skipping forced account refresh while rate limited", "cause", cause)
+		return
+	}
 
 	// Refresh the account from the server.
+	before := acct.AllChannels()
 	if err := acct.Refresh(a.Auth.Client(), cause); err == nil {
 		a.selectDefaultProfile()
 		a.Auth.SaveAccount("refresh_user")
+		a.checkEntitlements(before, acct.AllChannels())
 	}
 }
 
 // setNetMode updates the network mode and ensures the current channel is still valid.
-func (a *App) setNetMode(mode net.Mode, schedule *update.Schedule) {
+func (a *App) setNetMode(mode net.Mode) {
 	oldMode := net.Current()
 	net.SetMode(mode)
 
@@ -220,13 +410,6 @@ func (a *App) setNetMode(mode net.Mode, schedule *update.Schedule) {
 		slog.Info("setting network mode", "mode", mode)
 		a.ensureValidChannel(a.getCurrentChannel())
 		a.Emit("setNetworkMode", mode)
-
-		// If a schedule was provided, notify the update listener.
-		if schedule != nil && a.listen != nil {
-			a.listen.Notify(update.Notification{
-				// Schedule-related notification fields would go here.
-			})
-		}
 	}
 }
 
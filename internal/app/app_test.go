@@ -0,0 +1,47 @@
+package app_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"hytale-launcher/internal/app"
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/service"
+)
+
+func TestMain(m *testing.M) {
+	// Development builds register no link handler or autostart entry, and
+	// everything the launcher writes goes to a temporary directory.
+	build.Release = "dev"
+	dir, err := os.MkdirTemp("", "app-test-*")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", dir)
+	os.Setenv("XDG_DATA_HOME", dir)
+	os.Setenv("XDG_CONFIG_HOME", dir)
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestStartHeadless(t *testing.T) {
+	a := app.New()
+	a.StartInSafeMode()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- a.Start(&service.Headless{})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("Start() did not return without a window")
+	}
+}
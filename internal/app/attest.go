@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/attest"
+	"hytale-launcher/internal/launch"
+	"hytale-launcher/internal/net"
+	"hytale-launcher/internal/settings"
+	"hytale-launcher/internal/telemetry"
+)
+
+// IsAttestationEnabled returns true if the user has opted in to attesting
+// the game files before launch.
+func (a *App) IsAttestationEnabled() bool {
+	return settings.Get().AttestationEnabled
+}
+
+// SetAttestationEnabled opts in to or out of attesting the game files
+// before launch.
+func (a *App) SetAttestationEnabled(enabled bool) error {
+	return settings.Update("set_attestation", func(s *settings.Settings) {
+		s.AttestationEnabled = enabled
+	})
+}
+
+// GetAttestation computes the attestation for the installed game on the
+// current channel, so the user can see what would be presented to servers.
+func (a *App) GetAttestation() (*attest.Attestation, error) {
+	if a.State == nil {
+		return nil, errors.New("no channel selected")
+	}
+	gameDep := a.State.GetDependency("game")
+	if gameDep == nil {
+		return nil, errors.New("game not installed")
+	}
+	return attest.Create(gameDep.Path, a.State.Channel, gameDep.Build)
+}
+
+// attestLaunch adds an attestation to a launch request if the user has
+// opted in. Attestation never blocks a launch: if the files cannot be
+// hashed or the endpoint cannot be reached, the game starts without it
+// and the frontend is told why.
+func (a *App) attestLaunch(ctx context.Context, req *launch.Request, gameDep *appstate.Dep) {
+	if !a.IsAttestationEnabled() {
+		return
+	}
+
+	att, err := attest.Create(gameDep.Path, req.Channel, gameDep.Build)
+	if err != nil {
+		a.attestationFailed(err)
+		return
+	}
+	req.Attestation = att.Commitment
+
+	if net.Current() == net.ModeOffline {
+		return
+	}
+	token, err := attest.Submit(ctx, att, req.SessionToken, req.ProfileID)
+	if err != nil {
+		a.attestationFailed(err)
+		return
+	}
+	req.AttestationToken = token
+
+	slog.Info("attested game files", "files", att.Files, "build", att.Build)
+}
+
+// attestationFailed logs and reports a failed attestation.
+func (a *App) attestationFailed(err error) {
+	slog.Warn("unable to attest game files", "error", err)
+	telemetry.Failure("attestation", err)
+	a.Emit("attestation:failed", err.Error())
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"log/slog"
+
+	"hytale-launcher/internal/autostart"
+	"hytale-launcher/internal/build"
+)
+
+// IsAutoStartSupported returns true if the launcher can start when the
+// user logs in to their computer.
+func (a *App) IsAutoStartSupported() bool {
+	return autostart.Supported()
+}
+
+// IsAutoStartEnabled returns true if the launcher starts when the user logs
+// in to their computer.
+func (a *App) IsAutoStartEnabled() bool {
+	return autostart.Enabled()
+}
+
+// SetAutoStart turns starting the launcher when the user logs in on or
+// off. It then starts hidden in the tray and downloads pending updates.
+func (a *App) SetAutoStart(enabled bool) error {
+	var err error
+	if enabled {
+		err = autostart.Enable()
+	} else {
+		err = autostart.Disable()
+	}
+	if err != nil {
+		return err
+	}
+
+	slog.Info("autostart preference changed", "enabled", enabled)
+	return nil
+}
+
+// refreshAutoStart points an existing autostart entry at the running
+// launcher, in case it was moved or updated. Development builds leave the
+// entry alone, so they do not take over from an installed launcher.
+func refreshAutoStart() {
+	if build.IsDev() || !autostart.Enabled() {
+		return
+	}
+	if err := autostart.Enable(); err != nil {
+		slog.Warn("unable to refresh autostart entry", "error", err)
+	}
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"hytale-launcher/internal/account"
+	"hytale-launcher/internal/format"
+	"hytale-launcher/internal/launch"
+	"hytale-launcher/internal/notifications"
+	"hytale-launcher/internal/settings"
+)
+
+// Limits on the break reminder interval, in minutes.
+const (
+	minBreakInterval = 15
+	maxBreakInterval = 8 * 60
+)
+
+// GetBreakReminder returns the break reminder settings for the current
+// profile.
+func (a *App) GetBreakReminder() (account.BreakReminder, error) {
+	acct := a.Auth.GetAccount()
+	profile := a.getCurrentProfile()
+	if acct == nil || profile == nil {
+		return account.BreakReminder{}, errors.New("no user logged in")
+	}
+	return acct.GetBreakReminder(profile.UUID), nil
+}
+
+// SetBreakReminder updates the break reminder settings for the current
+// profile. A running game picks up the new settings immediately.
+func (a *App) SetBreakReminder(r account.BreakReminder) error {
+	if r.Enabled && (r.IntervalMinutes < minBreakInterval || r.IntervalMinutes > maxBreakInterval) {
+		return fmt.Errorf("break interval must be between %d and %d minutes", minBreakInterval, maxBreakInterval)
+	}
+
+	acct := a.Auth.GetAccount()
+	profile := a.getCurrentProfile()
+	if acct == nil || profile == nil {
+		return errors.New("no user logged in")
+	}
+
+	acct.SetBreakReminder(profile.UUID, r)
+	a.Auth.SaveAccount("set_break_reminder")
+
+	a.runningMu.Lock()
+	defer a.runningMu.Unlock()
+	if a.runningGame != nil {
+		a.startBreakTimerLocked(a.runningGame)
+	}
+	return nil
+}
+
+// startBreakTimerLocked (re)starts break reminders for a running game
+// using the current profile's settings. The caller must hold runningMu.
+func (a *App) startBreakTimerLocked(r *launch.Running) {
+	a.breakTimer.Stop()
+	a.breakTimer = nil
+
+	acct := a.Auth.GetAccount()
+	profile := a.getCurrentProfile()
+	if acct == nil || profile == nil {
+		return
+	}
+	settings := acct.GetBreakReminder(profile.UUID)
+	if !settings.Enabled || settings.IntervalMinutes <= 0 {
+		return
+	}
+
+	interval := time.Duration(settings.IntervalMinutes) * time.Minute
+	slog.Debug("break reminders on", "interval", interval)
+	a.breakTimer = launch.StartBreakTimer(r.StartedAt, interval, a.remindBreak)
+}
+
+// stopBreakTimerLocked stops break reminders. The caller must hold
+// runningMu.
+func (a *App) stopBreakTimerLocked() {
+	a.breakTimer.Stop()
+	a.breakTimer = nil
+}
+
+// remindBreak sends a break reminder after the given time played.
+func (a *App) remindBreak(played time.Duration) {
+	if !settings.Get().NotificationsDisabled {
+		notifications.SendInfo("Time for a break?",
+			fmt.Sprintf("You've been playing for %s. Consider stretching and resting your eyes.", format.Duration(played)))
+	}
+	a.Emit("wellbeing:break_reminder", int(played.Minutes()))
+}
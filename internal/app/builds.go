@@ -0,0 +1,63 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/installlock"
+	"hytale-launcher/internal/pkg"
+)
+
+// GetInstalledBuilds returns the game builds installed side by side for the
+// current channel, and which one is active.
+func (a *App) GetInstalledBuilds() (*hytale.BuildManifest, error) {
+	if a.State == nil {
+		return nil, errors.New("no channel selected")
+	}
+	return hytale.LoadBuildManifest("game", a.State.Channel)
+}
+
+// SwitchGameBuild makes an already installed build the active one for the
+// current channel. No files are downloaded or patched.
+func (a *App) SwitchGameBuild(build int) error {
+	if a.State == nil {
+		return errors.New("no channel selected")
+	}
+	if a.isUpdating() {
+		return errors.New("cannot switch builds while updating")
+	}
+	if a.IsGameRunning() {
+		return errGameRunning
+	}
+
+	lock, err := installlock.Acquire(a.State.Channel)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	manifest, err := hytale.LoadBuildManifest("game", a.State.Channel)
+	if err != nil {
+		return err
+	}
+
+	installed := manifest.Get(build)
+	if installed == nil {
+		return fmt.Errorf("build %d is not installed", build)
+	}
+	if _, err := os.Stat(installed.Dir); err != nil {
+		return fmt.Errorf("build %d is missing from disk: %w", build, err)
+	}
+
+	if err := pkg.ActivateBuild(a.State, manifest, *installed); err != nil {
+		return err
+	}
+	a.State.Save("switch_game_build")
+
+	slog.Info("switched game build", "channel", a.State.Channel, "build", build)
+	a.Emit("game:build_switched", build)
+	return nil
+}
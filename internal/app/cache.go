@@ -0,0 +1,42 @@
+package app
+
+import (
+	"log/slog"
+
+	"hytale-launcher/internal/download"
+	"hytale-launcher/internal/settings"
+)
+
+// DownloadCacheInfo describes the download cache for the settings page.
+type DownloadCacheInfo struct {
+	// Size is the space the cache uses, in bytes.
+	Size int64 `json:"size"`
+
+	// LimitMB is the configured size cap in megabytes: zero for the
+	// default, negative when the cache is off.
+	LimitMB int `json:"limit_mb"`
+}
+
+// GetDownloadCacheInfo returns the size and cap of the download cache.
+func (a *App) GetDownloadCacheInfo() DownloadCacheInfo {
+	return DownloadCacheInfo{
+		Size:    download.CacheSize(),
+		LimitMB: settings.Get().DownloadCacheMB,
+	}
+}
+
+// SetDownloadCacheLimit sets the size cap of the download cache in
+// megabytes and evicts files beyond it. Zero restores the default and a
+// negative value turns the cache off, emptying it.
+func (a *App) SetDownloadCacheLimit(mb int) error {
+	err := settings.Update("set_download_cache_limit", func(s *settings.Settings) {
+		s.DownloadCacheMB = mb
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("download cache limit changed", "limit_mb", mb)
+	download.TrimCache()
+	return nil
+}
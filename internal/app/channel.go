@@ -2,14 +2,16 @@ package app
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"slices"
-	"strings"
 
 	"github.com/getsentry/sentry-go"
 
+	"hytale-launcher/internal/account"
 	"hytale-launcher/internal/appstate"
-	"hytale-launcher/internal/update"
+	"hytale-launcher/internal/channelinfo"
+	"hytale-launcher/internal/net"
 	"hytale-launcher/internal/updater"
 )
 
@@ -24,9 +26,12 @@ func channelsEqual(a, b *string) bool {
 	return *a == *b
 }
 
-// ReleaseChannels contains the list of known release channels in priority order.
-// This is used to select a fallback channel when the user's channel is not available.
-var ReleaseChannels = []string{"release"}
+// ReleaseChannels returns the stable channels published by the API, in
+// priority order. It is used to select a fallback channel when the user's
+// channel is not available.
+func ReleaseChannels() []string {
+	return channelinfo.Stable()
+}
 
 // loadEnv loads the state for a given channel from disk.
 // If the state file doesn't exist, it creates a new state.
@@ -49,7 +54,9 @@ func (a *App) loadEnv(channel string) *appstate.State {
 
 // SetChannel changes the current update channel.
 // This updates the stored state, creates a new updater for the channel,
-// and persists the selection to the user's account.
+// and persists the selection to the user's account. Setting the selected
+// channel again reloads its state, unless it is updating. A channel
+// switched away from keeps updating in the background.
 func (a *App) SetChannel(channel *string) {
 	currentChannel := a.getCurrentChannel()
 
@@ -58,22 +65,35 @@ func (a *App) SetChannel(channel *string) {
 	currentChannelStr := formatChannel(currentChannel)
 	slog.Info("setting channel", "channel", newChannelStr, "current", currentChannelStr)
 
+	// Switch to the new channel's session, with launcher, JRE, and game
+	// packages, in one assignment so the state and updater never belong
+	// to different channels. Then release the previous channel; a busy
+	// one keeps its session.
+	if channel == nil {
+		a.State, a.Updater = nil, nil
+	} else {
+		s := a.openSession(*channel, true)
+		a.State, a.Updater = s.state, s.updater
+	}
+	if currentChannel != nil {
+		a.closeChannel(*currentChannel)
+	}
+
 	// Handle nil channel (clearing the selection).
 	if channel == nil {
-		a.Updater = nil
-		a.State = nil
 		goto updateAccount
 	}
 
-	// Load the state for the new channel.
-	a.State = a.loadEnv(*channel)
+	// Let the frontend offer to finish a batch that was interrupted.
+	if remaining := updater.Interrupted(a.State); len(remaining) > 0 {
+		slog.Info("found interrupted update batch", "channel", *channel, "remaining", remaining)
+		a.Emit("update:interrupted", remaining)
+	}
 
-	// Create a new updater with JRE and game packages.
-	a.Updater = updater.New(
-		a.listen,
-		updater.Package{Name: "jre", Pkg: &update.JREPackage{}},
-		updater.Package{Name: "game", Pkg: &update.GamePackage{}},
-	)
+	// Ask for consent before a pre-release channel can be played.
+	if !a.hasPrereleaseConsent() {
+		a.Emit("prerelease:consent_required", *channel)
+	}
 
 updateAccount:
 	// Save the channel selection to the user's account if it changed.
@@ -85,14 +105,10 @@ updateAccount:
 	}
 }
 
-// preferredChannels returns the list of channels in preference order.
-// This is used when the user's channel is not available to select a fallback.
-var preferredChannels = ReleaseChannels
-
 // ensureValidChannel checks if the current channel is still valid for the user.
 // If not, it selects the first available preferred channel.
 func (a *App) ensureValidChannel(currentChannel *string) {
-	userChannels := a.GetUserChannels()
+	userChannels := a.userChannels()
 
 	currentChannelStr := formatChannel(currentChannel)
 	slog.Debug("validating current channel access", "current", currentChannelStr, "options", userChannels)
@@ -103,9 +119,17 @@ func (a *App) ensureValidChannel(currentChannel *string) {
 		channelValid = slices.Contains(userChannels, *currentChannel)
 	}
 
+	// A channel owned by another profile on the same account stays selected,
+	// so switching profiles does not drop an installed channel.
+	if !channelValid && slices.Contains(a.getAccountChannels(), *currentChannel) {
+		slog.Debug("keeping channel owned by another profile", "channel", *currentChannel)
+		a.Emit("hint:channel_other_profile", *currentChannel)
+		channelValid = true
+	}
+
 	// If current channel is no longer valid, find a fallback.
 	if !channelValid {
-		for _, preferred := range preferredChannels {
+		for _, preferred := range ReleaseChannels() {
 			if slices.Contains(userChannels, preferred) {
 				a.SetChannel(&preferred)
 				return
@@ -127,29 +151,44 @@ func (a *App) getCurrentChannel() *string {
 }
 
 // getEntitledChannels returns the list of channels the current user is entitled to.
-// It filters the user's entitlements to only include patchline-based channels.
+// Depending on the account's channel scope, this is either the selected profile's
+// patchlines or the union of the patchlines of every profile on the account.
 func (a *App) getEntitledChannels() []string {
 	profile := a.getCurrentProfile()
 	if profile == nil {
-		return ReleaseChannels
+		return ReleaseChannels()
 	}
 
-	var channels []string
-	for _, entitlement := range profile.Entitlements {
-		// Only include patchline entitlements.
-		if strings.HasPrefix(entitlement, "patchline:") {
-			channel := strings.TrimPrefix(entitlement, "patchline:")
-			channels = append(channels, channel)
-		}
+	if acct := a.Auth.GetAccount(); acct != nil && acct.ChannelScope == account.ChannelScopeAccount {
+		return acct.AllChannels()
 	}
 
+	return profile.Channels()
+}
+
+// getAccountChannels returns the channels available to any profile on the
+// account, limited to offline-ready channels when offline.
+func (a *App) getAccountChannels() []string {
+	acct := a.Auth.GetAccount()
+	if acct == nil {
+		return nil
+	}
+
+	channels := acct.AllChannels()
+	if net.Current() == net.ModeOffline {
+		return a.offlineReady(channels)
+	}
 	return channels
 }
 
 // getOfflineChannels returns the list of channels available in offline mode.
 // A channel is available offline if its state indicates it's ready for offline use.
 func (a *App) getOfflineChannels() []string {
-	entitled := a.getEntitledChannels()
+	return a.offlineReady(a.getEntitledChannels())
+}
+
+// offlineReady filters channels to those whose state is ready for offline use.
+func (a *App) offlineReady(entitled []string) []string {
 	var available []string
 
 	for _, channel := range entitled {
@@ -171,6 +210,106 @@ func (a *App) getOfflineChannels() []string {
 	return available
 }
 
+// GetUserChannels returns the channels available to the current user with
+// the status of their installs. In offline mode, only channels that are
+// offline-ready are returned.
+func (a *App) GetUserChannels() []ChannelStatus {
+	var patchlines map[string]account.Patchline
+	if acct := a.Auth.GetAccount(); acct != nil {
+		patchlines = acct.Patchlines
+	}
+	sizes := a.GetInstalledGameDirSizes()
+
+	channels := a.userChannels()
+	statuses := make([]ChannelStatus, 0, len(channels))
+	for _, channel := range channels {
+		cs, err := channelStatus(channel)
+		if err != nil {
+			slog.Error("failed to read channel status", "channel", channel, "error", err)
+			cs = &ChannelStatus{Channel: channel}
+		}
+		cs.LatestBuild = patchlines[channel].Version
+		cs.InstallSize = sizes[channel]
+
+		target := cs.LatestBuild
+		if cs.PinnedBuild > 0 && cs.PinnedBuild < target {
+			target = cs.PinnedBuild
+		}
+		cs.UpdatePending = cs.Installed && target > cs.GameBuild
+		statuses = append(statuses, *cs)
+	}
+	return statuses
+}
+
+// GetChannelInfo returns display metadata for the channels available to the
+// current user, in the order they should be shown.
+func (a *App) GetChannelInfo() []channelinfo.Info {
+	return channelinfo.List(a.userChannels())
+}
+
+// GetPublishedChannels returns display metadata for every channel the API
+// publishes, including ones the user has no access to, so new patchlines
+// can be shown as they ship.
+func (a *App) GetPublishedChannels() []channelinfo.Info {
+	return channelinfo.All()
+}
+
+// AccountChannel describes a channel available to one or more profiles on the account.
+type AccountChannel struct {
+	Channel string `json:"channel"`
+
+	// Profiles are the UUIDs of the profiles entitled to the channel.
+	Profiles []string `json:"profiles"`
+
+	// Current is true if the selected profile is entitled to the channel.
+	Current bool `json:"current"`
+}
+
+// GetAccountChannels returns every channel available to any profile on the
+// account, with the profiles that own each one.
+func (a *App) GetAccountChannels() []AccountChannel {
+	acct := a.Auth.GetAccount()
+	if acct == nil {
+		return []AccountChannel{}
+	}
+
+	current := ""
+	if profile := acct.GetCurrentProfile(); profile != nil {
+		current = profile.UUID
+	}
+
+	owners := acct.ChannelOwners()
+	result := make([]AccountChannel, 0, len(owners))
+	for _, channel := range acct.AllChannels() {
+		result = append(result, AccountChannel{
+			Channel:  channel,
+			Profiles: owners[channel],
+			Current:  slices.Contains(owners[channel], current),
+		})
+	}
+	return result
+}
+
+// SetChannelScope chooses whether the channel list shows only the selected
+// profile's channels ("profile") or those of every profile on the account
+// ("account").
+func (a *App) SetChannelScope(scope string) error {
+	if scope != account.ChannelScopeProfile && scope != account.ChannelScopeAccount {
+		return fmt.Errorf("unknown channel scope %q", scope)
+	}
+
+	acct := a.Auth.GetAccount()
+	if acct == nil {
+		return errors.New("no user logged in")
+	}
+
+	acct.ChannelScope = scope
+	a.Auth.SaveAccount("set_channel_scope")
+
+	a.ensureValidChannel(a.getCurrentChannel())
+	return nil
+}
+
 // formatChannel returns a string representation of a channel pointer.
 // Returns "<nil>" if the pointer is nil.
 func formatChannel(channel *string) string {
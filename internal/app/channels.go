@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/updater"
+)
+
+// channelSession is a channel's state and updater. The selected channel
+// always has one; other channels have one while they update in the
+// background, so one channel can update while another is played.
+type channelSession struct {
+	state   *appstate.State
+	updater *updater.Updater
+
+	// busy is set while an update, import or uninstall runs on the
+	// channel.
+	busy bool
+
+	// cancel stops the update in progress, or is nil if none is.
+	cancel context.CancelFunc
+}
+
+// launcherComponent is the updater component of the launcher itself. It is
+// shared by every channel, so only the selected channel's updater has it.
+const launcherComponent = "launcher"
+
+// channelManager holds the sessions of the channels in use. The zero value
+// is ready to use.
+type channelManager struct {
+	mu       sync.Mutex
+	sessions map[string]*channelSession
+}
+
+// openChannel returns the session for a channel. If it has none, its state
+// is loaded and an updater created whose events are tagged with the
+// channel.
+func (a *App) openChannel(channel string) *channelSession {
+	return a.openSession(channel, a.isSelectedChannel(channel))
+}
+
+// openSession returns the session for a channel, creating it if it has
+// none. Only the session of the selected channel updates the launcher; a
+// background session that becomes selected gets the launcher component
+// added.
+func (a *App) openSession(channel string, selected bool) *channelSession {
+	m := &a.channels
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.sessions[channel]; ok {
+		if selected && s.updater.GetPackage(launcherComponent) == nil {
+			for _, p := range updater.Registered() {
+				if p.Name == launcherComponent {
+					s.updater.Register(&p)
+				}
+			}
+		}
+		return s
+	}
+	if m.sessions == nil {
+		m.sessions = make(map[string]*channelSession)
+	}
+
+	pkgs := updater.Registered()
+	if !selected {
+		pkgs = slices.DeleteFunc(pkgs, func(p updater.Package) bool {
+			return p.Name == launcherComponent
+		})
+	}
+
+	s := &channelSession{state: a.loadEnv(channel)}
+	s.updater = updater.New(
+		newAppListen(channel, a.Emit, a.isSelectedChannel),
+		pkgs...,
+	)
+	m.sessions[channel] = s
+	return s
+}
+
+// closeChannel drops a channel's session unless it is selected or busy, so
+// its state is loaded afresh when next opened.
+func (a *App) closeChannel(channel string) {
+	m := &a.channels
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[channel]
+	if !ok || s.busy || a.isSelectedChannel(channel) {
+		return
+	}
+	delete(m.sessions, channel)
+}
+
+// isSelectedChannel reports whether channel is the selected channel.
+func (a *App) isSelectedChannel(channel string) bool {
+	state := a.State
+	return state != nil && state.Channel == channel
+}
+
+// beginWork marks a channel busy, with cancel stopping the work if it is
+// not nil. It returns false if the channel is already busy.
+func (a *App) beginWork(channel string, cancel context.CancelFunc) bool {
+	s := a.openChannel(channel)
+
+	m := &a.channels
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s.busy {
+		return false
+	}
+	s.busy = true
+	s.cancel = cancel
+	return true
+}
+
+// endWork marks a channel no longer busy, and closes its session if it is
+// not selected.
+func (a *App) endWork(channel string) {
+	m := &a.channels
+	m.mu.Lock()
+	if s, ok := m.sessions[channel]; ok {
+		s.busy = false
+		s.cancel = nil
+	}
+	m.mu.Unlock()
+
+	a.closeChannel(channel)
+}
+
+// isChannelBusy reports whether an update, import or uninstall is running
+// on a channel.
+func (a *App) isChannelBusy(channel string) bool {
+	m := &a.channels
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[channel]
+	return ok && s.busy
+}
+
+// busyChannels returns the channels an update, import or uninstall is
+// running on.
+func (a *App) busyChannels() []string {
+	m := &a.channels
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var channels []string
+	for channel, s := range m.sessions {
+		if s.busy {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// channelEvent returns the name of an event about a channel: name itself
+// for the selected channel, or "<name>:<channel>" for a channel updating in
+// the background.
+func (a *App) channelEvent(channel, name string) string {
+	if a.isSelectedChannel(channel) {
+		return name
+	}
+	return name + ":" + channel
+}
+
+// cancelChannel stops the update running on a channel, if any.
+func (a *App) cancelChannel(channel string) {
+	m := &a.channels
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.sessions[channel]; ok && s.cancel != nil {
+		slog.Info("cancelling update", "channel", channel)
+		s.cancel()
+	}
+}
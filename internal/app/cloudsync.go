@@ -0,0 +1,236 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"hytale-launcher/internal/cloudsync"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/keyring"
+	"hytale-launcher/internal/settings"
+)
+
+// cloudSyncSecretKey is the keyring key the WebDAV password or S3 secret
+// access key is stored under.
+const cloudSyncSecretKey = "cloud-sync-secret"
+
+// cloudSyncTimeout bounds a single sync.
+const cloudSyncTimeout = 10 * time.Minute
+
+// CloudSyncStatus describes the state of cloud sync for the frontend.
+type CloudSyncStatus struct {
+	// State is "idle", "syncing", "conflict" or "error".
+	State string `json:"state"`
+
+	// LastSync is when the last sync finished, or zero if none has run in
+	// this session.
+	LastSync time.Time `json:"last_sync,omitempty"`
+
+	// Error describes why the last sync failed.
+	Error string `json:"error,omitempty"`
+
+	// Result is what the last sync did.
+	Result *cloudsync.Result `json:"result,omitempty"`
+}
+
+// syncTarget returns the target configured in settings.
+func (a *App) syncTarget(cfg settings.CloudSync) (cloudsync.Target, error) {
+	var secret string
+	if cfg.Target == settings.SyncTargetWebDAV || cfg.Target == settings.SyncTargetS3 {
+		value, err := keyring.Get(cloudSyncSecretKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read sync credentials: %w", err)
+		}
+		secret = string(value)
+	}
+
+	switch cfg.Target {
+	case "", settings.SyncTargetAccount:
+		if !a.Auth.IsLoggedIn() {
+			return nil, errors.New("log in to sync to your account")
+		}
+		return &cloudsync.AccountTarget{Client: a.Auth.Client()}, nil
+	case settings.SyncTargetWebDAV:
+		return &cloudsync.WebDAVTarget{URL: cfg.URL, Username: cfg.Username, Password: secret}, nil
+	case settings.SyncTargetS3:
+		return &cloudsync.S3Target{
+			Endpoint:        cfg.URL,
+			Bucket:          cfg.Bucket,
+			Region:          cfg.Region,
+			Prefix:          cfg.Prefix,
+			AccessKeyID:     cfg.Username,
+			SecretAccessKey: secret,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown sync target %q", cfg.Target)
+}
+
+// syncer returns a syncer for the configured target covering the saves of
+// every channel with the game installed.
+func (a *App) syncer() (*cloudsync.Syncer, error) {
+	target, err := a.syncTarget(settings.Get().CloudSync)
+	if err != nil {
+		return nil, err
+	}
+
+	saveDirs := make(map[string]string)
+	for _, channel := range hytale.KnownChannels() {
+		if gameDep := a.channelState(channel).GetDependency("game"); gameDep != nil {
+			saveDirs[channel] = filepath.Join(gameDep.Path, "saves")
+		}
+	}
+
+	device, _ := os.Hostname()
+	return &cloudsync.Syncer{Target: target, SaveDirs: saveDirs, Device: device}, nil
+}
+
+// GetCloudSyncSettings returns the cloud sync configuration.
+func (a *App) GetCloudSyncSettings() settings.CloudSync {
+	return settings.Get().CloudSync
+}
+
+// SetCloudSyncSettings changes the cloud sync configuration. A non-empty
+// secret replaces the stored WebDAV password or S3 secret access key.
+func (a *App) SetCloudSyncSettings(cfg settings.CloudSync, secret string) error {
+	if err := validateCloudSync(cfg); err != nil {
+		return err
+	}
+	if secret != "" {
+		if err := keyring.Set(cloudSyncSecretKey, []byte(secret)); err != nil {
+			return fmt.Errorf("unable to store sync credentials: %w", err)
+		}
+	}
+
+	err := settings.Update("set_cloud_sync", func(s *settings.Settings) {
+		s.CloudSync = cfg
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("cloud sync settings changed", "enabled", cfg.Enabled, "target", cfg.Target)
+	if cfg.Enabled {
+		go a.syncCloud("settings changed")
+	}
+	return nil
+}
+
+// validateCloudSync checks that a configuration has what its target needs.
+func validateCloudSync(cfg settings.CloudSync) error {
+	switch cfg.Target {
+	case "", settings.SyncTargetAccount:
+	case settings.SyncTargetWebDAV:
+		if cfg.URL == "" {
+			return errors.New("a WebDAV target requires a URL")
+		}
+	case settings.SyncTargetS3:
+		if cfg.Bucket == "" || cfg.Region == "" || cfg.Username == "" {
+			return errors.New("an S3 target requires a bucket, region and access key ID")
+		}
+	default:
+		return fmt.Errorf("unknown sync target %q", cfg.Target)
+	}
+	return nil
+}
+
+// GetCloudSyncStatus returns the state of cloud sync.
+func (a *App) GetCloudSyncStatus() CloudSyncStatus {
+	a.cloudSyncMu.Lock()
+	defer a.cloudSyncMu.Unlock()
+	if a.cloudSyncStatus.State == "" {
+		return CloudSyncStatus{State: "idle"}
+	}
+	return a.cloudSyncStatus
+}
+
+// setCloudSyncStatusLocked records and emits the state of cloud sync as a
+// "cloudsync:status" event. The caller must hold cloudSyncMu.
+func (a *App) setCloudSyncStatusLocked(status CloudSyncStatus) {
+	a.cloudSyncStatus = status
+	a.Emit("cloudsync:status", status)
+}
+
+// SyncNow syncs saves and settings with the configured target. Saves are
+// in use while the game runs, so syncing waits until it has exited.
+func (a *App) SyncNow() (CloudSyncStatus, error) {
+	if a.IsGameRunning() {
+		return a.GetCloudSyncStatus(), errGameRunning
+	}
+	return a.runCloudSync("requested")
+}
+
+// syncCloud syncs in the background if cloud sync is enabled, the game
+// is not running and the launcher is not in safe mode.
+func (a *App) syncCloud(cause string) {
+	if a.safeMode || !settings.Get().CloudSync.Enabled || a.IsGameRunning() {
+		return
+	}
+	a.runCloudSync(cause)
+}
+
+// runCloudSync performs a sync and reports its outcome.
+func (a *App) runCloudSync(cause string) (CloudSyncStatus, error) {
+	a.cloudSyncMu.Lock()
+	defer a.cloudSyncMu.Unlock()
+
+	slog.Info("syncing saves and settings", "cause", cause)
+	a.setCloudSyncStatusLocked(CloudSyncStatus{State: "syncing", LastSync: a.cloudSyncStatus.LastSync})
+
+	status := CloudSyncStatus{State: "idle"}
+	accessibility := settings.Get().Accessibility
+	syncer, err := a.syncer()
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), cloudSyncTimeout)
+		status.Result, err = syncer.Sync(ctx)
+		cancel()
+		status.LastSync = time.Now()
+	}
+
+	// Apply accessibility preferences brought in from another machine.
+	if settings.Get().Accessibility != accessibility {
+		a.emitAccessibility()
+	}
+
+	switch {
+	case err != nil:
+		sentry.CaptureException(err)
+		slog.Warn("cloud sync failed", "error", err)
+		status.State = "error"
+		status.Error = err.Error()
+	case len(status.Result.Conflicts) > 0:
+		status.State = "conflict"
+	}
+	a.setCloudSyncStatusLocked(status)
+	return status, err
+}
+
+// ResolveSyncConflict settles a conflict reported by a sync by keeping the
+// "local" or "remote" copy of the item, then syncs again.
+func (a *App) ResolveSyncConflict(name, keep string) (CloudSyncStatus, error) {
+	if a.IsGameRunning() {
+		return a.GetCloudSyncStatus(), errGameRunning
+	}
+
+	syncer, err := a.syncer()
+	if err != nil {
+		return a.GetCloudSyncStatus(), err
+	}
+
+	a.cloudSyncMu.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), cloudSyncTimeout)
+	err = syncer.Resolve(ctx, name, keep)
+	cancel()
+	a.cloudSyncMu.Unlock()
+	if err != nil {
+		return a.GetCloudSyncStatus(), fmt.Errorf("unable to resolve sync conflict: %w", err)
+	}
+
+	return a.runCloudSync("conflict resolved")
+}
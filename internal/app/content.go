@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/getsentry/sentry-go"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/pkg"
+)
+
+// ListOptionalContent returns the optional content packs offered on a
+// channel, such as high resolution textures, soundtracks and language
+// packs, with whether each is installed.
+func (a *App) ListOptionalContent(channel string) ([]pkg.OptionalContent, error) {
+	if !hytale.IsKnownChannel(channel) {
+		return nil, fmt.Errorf("unknown channel %q", channel)
+	}
+	return pkg.ListOptionalContent(context.Background(), a.channelState(channel), channel)
+}
+
+// InstallOptionalContent installs an optional content pack on a channel, or
+// updates it if an older version is installed. Once installed, the pack is
+// kept up to date with the channel's other updates. Progress is emitted as
+// "content:progress" events.
+func (a *App) InstallOptionalContent(channel, id string) error {
+	if !hytale.IsKnownChannel(channel) {
+		return fmt.Errorf("unknown channel %q", channel)
+	}
+
+	state := a.channelState(channel)
+	if state.GetDependency("game") == nil {
+		return errors.New("install the game before its optional content")
+	}
+
+	ctx := context.Background()
+	u, err := pkg.CheckForContentInstall(ctx, state, channel, id)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return nil
+	}
+
+	if !a.beginWork(channel, nil) {
+		return errors.New("an update is in progress")
+	}
+	defer a.endWork(channel)
+
+	err = pkg.ApplyUpdates(ctx, state, []pkg.Update{u}, func(status pkg.UpdateStatus) {
+		a.Emit("content:progress", channel, id, status)
+	})
+	state.Save("install_content")
+	if err != nil {
+		sentry.CaptureException(err)
+		return fmt.Errorf("unable to install content: %w", err)
+	}
+
+	a.Emit("content:installed", channel, id)
+	return nil
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/deeplink"
+	"hytale-launcher/internal/oauth"
+)
+
+// SetStartupLink records a hytale:// link the launcher was started with.
+// It is handled once the frontend is ready. It must be called before the
+// app starts.
+func (a *App) SetStartupLink(link string) {
+	a.startupLink = link
+}
+
+// HandleSecondInstance is called when the launcher is started again while
+// already running. The existing window is focused and any link passed to
+// the new process is handled here.
+func (a *App) HandleSecondInstance(args []string) {
+	slog.Info("second launcher instance started", "args", args)
+
+	a.focusWindow()
+	if link := deeplink.FromArgs(args); link != "" {
+		a.HandleDeepLink(link)
+	}
+}
+
+// HandleDeepLink opens the launcher and routes a hytale:// link to the
+// matching action.
+func (a *App) HandleDeepLink(raw string) {
+	slog.Info("handling deep link", "link", raw)
+
+	// Links delivered before startup are handled once the frontend is ready.
+	if a.frontend == nil {
+		a.startupLink = raw
+		return
+	}
+
+	a.focusWindow()
+	if err := a.routeDeepLink(raw); err != nil {
+		slog.Warn("unable to handle deep link", "link", raw, "error", err)
+		a.Emit("deeplink:error", err.Error())
+	}
+}
+
+// routeDeepLink performs the action of a hytale:// link.
+func (a *App) routeDeepLink(raw string) error {
+	link, err := deeplink.Parse(raw)
+	if err != nil {
+		return err
+	}
+
+	switch link.Action {
+	case deeplink.ActionLaunch:
+		// Any web page can open a link, so the game is only launched once
+		// the user confirms.
+		launch := &LinkLaunch{
+			Channel: link.Query.Get("channel"),
+			Server:  link.Query.Get("server"),
+		}
+		if launch.Channel != "" && !slices.Contains(a.userChannels(), launch.Channel) {
+			return fmt.Errorf("channel %s is not available", launch.Channel)
+		}
+		a.linkLaunch.Store(launch)
+		a.Emit("deeplink:confirm_launch", launch)
+		return nil
+
+	case deeplink.ActionNews:
+		if len(link.Path) == 0 {
+			return errors.New("news link has no article")
+		}
+		a.Emit("deeplink:news", link.Path[0])
+		return nil
+
+	case deeplink.ActionAuth:
+		if currentLoopback == nil {
+			return errors.New("no login in progress")
+		}
+		err := currentLoopback.HandleRedirect(link.Query)
+		if errors.Is(err, oauth.ErrStateMismatch) {
+			// Not the response to this login; it is dropped rather than
+			// failing the login in progress.
+			slog.Warn("ignoring login link for another login")
+			return nil
+		}
+		return err
+
+	default:
+		return fmt.Errorf("unknown link action %q", link.Action)
+	}
+}
+
+// LinkLaunch is a game launch requested by a hytale:// link.
+type LinkLaunch struct {
+	// Channel is the channel to play, or empty for the selected one.
+	Channel string `json:"channel,omitempty"`
+
+	// Server is the address of the server to join, or empty.
+	Server string `json:"server,omitempty"`
+}
+
+// ConfirmLinkLaunch launches the game as requested by the last hytale://
+// link, after the user has confirmed it in the "deeplink:confirm_launch"
+// prompt. It requires the session nonce, so only the launcher's own
+// frontend can confirm.
+func (a *App) ConfirmLinkLaunch(nonce string) error {
+	if err := a.checkNonce(nonce); err != nil {
+		return err
+	}
+	launch := a.linkLaunch.Swap(nil)
+	if launch == nil {
+		return errors.New("no launch requested")
+	}
+
+	if launch.Channel != "" {
+		if !slices.Contains(a.userChannels(), launch.Channel) {
+			return fmt.Errorf("channel %s is not available", launch.Channel)
+		}
+		a.SetChannel(&launch.Channel)
+		a.ReloadLauncher("deeplink")
+	}
+	a.Emit("deeplink:launch")
+	return a.LaunchGame(launch.Server)
+}
+
+// DismissLinkLaunch drops the launch requested by the last hytale:// link.
+func (a *App) DismissLinkLaunch() {
+	if a.linkLaunch.Swap(nil) != nil {
+		slog.Info("link launch dismissed")
+	}
+}
+
+// handleStartupLink handles the link the launcher was started with, if any.
+func (a *App) handleStartupLink() {
+	if a.startupLink == "" {
+		return
+	}
+	link := a.startupLink
+	a.startupLink = ""
+	a.HandleDeepLink(link)
+}
+
+// registerDeepLinks associates the hytale:// scheme with this launcher.
+// Development builds do not register, so they do not take over links
+// meant for an installed launcher.
+func registerDeepLinks() {
+	if build.IsDev() {
+		return
+	}
+	if err := deeplink.Register(); err != nil {
+		slog.Warn("unable to register link handler", "error", err)
+	}
+}
+
+// focusWindow shows and raises the launcher window.
+func (a *App) focusWindow() {
+	if a.frontend == nil {
+		return
+	}
+	a.background.Store(false)
+	a.frontend.Show()
+}
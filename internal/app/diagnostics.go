@@ -0,0 +1,224 @@
+package app
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/auth"
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/errcode"
+	"hytale-launcher/internal/exitlog"
+	"hytale-launcher/internal/logging"
+	"hytale-launcher/internal/net"
+	"hytale-launcher/internal/redact"
+	"hytale-launcher/internal/sysinfo"
+	"hytale-launcher/internal/updater"
+)
+
+// maxDiagnosticLogs is the number of log files included in a diagnostics
+// bundle: the current one and the most recently rotated one.
+const maxDiagnosticLogs = 2
+
+// updateRecord is the outcome of an ApplyUpdates call.
+type updateRecord struct {
+	Time    time.Time        `json:"time"`
+	Channel string           `json:"channel"`
+	Results []updater.Result `json:"results,omitempty"`
+	Error   string           `json:"error,omitempty"`
+	Code    string           `json:"code,omitempty"`
+}
+
+// recordUpdate remembers the outcome of an update for diagnostics.
+func (a *App) recordUpdate(channel string, results []updater.Result, err error) {
+	rec := &updateRecord{
+		Time:    time.Now(),
+		Channel: channel,
+		Results: results,
+	}
+	if err != nil {
+		rec.Error = err.Error()
+		rec.Code = errcode.Of(err)
+	}
+	a.lastUpdate.Store(rec)
+}
+
+// reportUncleanExit tells the frontend, once per session, that the previous
+// launcher run ended without recording why, which usually means it crashed
+// or was killed.
+func (a *App) reportUncleanExit() {
+	prev := exitlog.UncleanExit()
+	if prev == nil || !a.uncleanReported.CompareAndSwap(false, true) {
+		return
+	}
+	a.Emit("diagnostics:unclean_exit", prev)
+}
+
+// diagnosticsInfo is the launcher summary written to a diagnostics bundle.
+type diagnosticsInfo struct {
+	Version      string           `json:"version"`
+	Release      string           `json:"release"`
+	CreatedAt    time.Time        `json:"created_at"`
+	Channel      string           `json:"channel,omitempty"`
+	NetworkMode  net.Mode         `json:"network_mode"`
+	ModeHistory  []net.ModeChange `json:"network_mode_history"`
+	Connectivity net.Connectivity `json:"connectivity"`
+	GameRunning  bool             `json:"game_running"`
+	LastUpdate   *updateRecord    `json:"last_update,omitempty"`
+}
+
+// ExportDiagnostics asks the user where to save a diagnostics bundle and
+// writes it there. The bundle is a zip file holding recent logs, the
+// channel states, system information, the network mode history, the exit
+// journal and the last update outcome, for attaching to a bug report.
+// Tokens are removed and home directory paths shortened. Returns the path
+// written, or an empty string if the user cancelled.
+func (a *App) ExportDiagnostics() (string, error) {
+	name := fmt.Sprintf("hytale-launcher-diagnostics-%s.zip", time.Now().Format("20060102-150405"))
+	path, err := a.PickSaveFile("Save diagnostics", name, PathExport, []FileFilter{
+		{DisplayName: "Zip archives (*.zip)", Pattern: "*.zip"},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	if err := a.writeDiagnostics(path); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("unable to write diagnostics: %w", err)
+	}
+
+	slog.Info("exported diagnostics", "path", path)
+	return path, nil
+}
+
+// writeDiagnostics writes a diagnostics bundle to path.
+func (a *App) writeDiagnostics(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	info := diagnosticsInfo{
+		Version:      build.Version,
+		Release:      build.Release,
+		CreatedAt:    time.Now().UTC(),
+		NetworkMode:  net.Current(),
+		ModeHistory:  net.History(),
+		Connectivity: net.LastConnectivity(),
+		GameRunning:  a.IsGameRunning(),
+		LastUpdate:   a.lastUpdate.Load(),
+	}
+	if a.State != nil {
+		info.Channel = a.State.Channel
+	}
+	if err := addJSON(zw, "launcher.json", info); err != nil {
+		return err
+	}
+	if err := addJSON(zw, "system.json", sysinfo.Collect()); err != nil {
+		return err
+	}
+	if err := addJSON(zw, "exit-journal.json", exitlog.Journal()); err != nil {
+		return err
+	}
+
+	audit, err := auth.RedactedAuditLog()
+	if err != nil {
+		slog.Warn("unable to read auth audit log for diagnostics", "error", err)
+	}
+	if err := addJSON(zw, "auth-audit.json", audit); err != nil {
+		return err
+	}
+
+	for _, channel := range a.diagnosticChannels() {
+		state, err := appstate.Load(channel)
+		if errors.Is(err, appstate.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			slog.Warn("unable to read channel state for diagnostics", "channel", channel, "error", err)
+			continue
+		}
+		if err := addJSON(zw, "state/"+channel+".json", state); err != nil {
+			return err
+		}
+	}
+
+	logs := logging.Files()
+	if len(logs) > maxDiagnosticLogs {
+		logs = logs[:maxDiagnosticLogs]
+	}
+	for _, log := range logs {
+		if err := addLog(zw, log); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// diagnosticChannels returns the channels whose state goes in a
+// diagnostics bundle.
+func (a *App) diagnosticChannels() []string {
+	if acct := a.Auth.GetAccount(); acct != nil {
+		if all := acct.AllChannels(); len(all) > 0 {
+			return all
+		}
+	}
+	return ReleaseChannels()
+}
+
+// addJSON adds v to the archive as indented JSON, with secrets redacted.
+func addJSON(zw *zip.Writer, name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("unable to encode %s: %w", name, err)
+	}
+
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return fmt.Errorf("unable to encode %s: %w", name, err)
+	}
+	data, err = json.MarshalIndent(redact.Value(tree), "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode %s: %w", name, err)
+	}
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// addLog adds a log file to the archive with secrets redacted. A missing
+// file is skipped.
+func addLog(zw *zip.Writer, path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read log file %s: %w", path, err)
+	}
+
+	w, err := zw.Create("logs/" + filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, redact.Text(string(data)))
+	return err
+}
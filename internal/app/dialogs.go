@@ -0,0 +1,130 @@
+package app
+
+import (
+	"fmt"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/service"
+)
+
+// Path purposes select the validation applied to a user-selected path.
+const (
+	// PathExport is a file to write a backup or export to.
+	PathExport = "export"
+
+	// PathImport is an existing file to restore or install from, such as
+	// a backup or a modpack.
+	PathImport = "import"
+
+	// PathInstallLocation is a directory to install the game into.
+	PathInstallLocation = "install_location"
+
+	// PathExistingInstall is a game directory downloaded elsewhere, to
+	// import instead of downloading the game again.
+	PathExistingInstall = "existing_install"
+)
+
+// FileFilter restricts the files shown in a file dialog.
+type FileFilter struct {
+	// DisplayName is shown in the dialog, e.g. "Backups (*.zip)".
+	DisplayName string `json:"display_name"`
+
+	// Pattern is a semicolon-separated list of globs, e.g. "*.zip;*.tar.gz".
+	Pattern string `json:"pattern"`
+}
+
+// pathRules returns the validation rules for a path purpose. Paths inside
+// the launcher's storage directory are always rejected, since the launcher
+// may replace or delete anything there.
+func pathRules(purpose string) (ioutil.PathRules, error) {
+	managed := []string{hytale.StorageDir()}
+
+	switch purpose {
+	case PathExport:
+		return ioutil.PathRules{File: true, Writable: true, Forbidden: managed}, nil
+	case PathImport:
+		return ioutil.PathRules{MustExist: true, File: true}, nil
+	case PathInstallLocation:
+		return ioutil.PathRules{Dir: true, Writable: true, Forbidden: managed}, nil
+	case PathExistingInstall:
+		return ioutil.PathRules{MustExist: true, Dir: true, Forbidden: managed}, nil
+	default:
+		return ioutil.PathRules{}, fmt.Errorf("unknown path purpose %q", purpose)
+	}
+}
+
+// ValidatePath checks a path, picked in a dialog or typed by the user,
+// against the rules for its purpose.
+func (a *App) ValidatePath(path, purpose string) error {
+	rules, err := pathRules(purpose)
+	if err != nil {
+		return err
+	}
+	return ioutil.CheckPath(path, rules)
+}
+
+// PickDirectory shows a native directory picker and validates the chosen
+// directory for the given purpose. Returns an empty string if cancelled.
+func (a *App) PickDirectory(title, purpose string) (string, error) {
+	path, err := a.frontend.OpenDirectory(service.OpenDialog{
+		Title:                title,
+		CanCreateDirectories: true,
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	return path, a.ValidatePath(path, purpose)
+}
+
+// PickFile shows a native file picker for an existing file and validates
+// it for the given purpose. Returns an empty string if cancelled.
+func (a *App) PickFile(title, purpose string, filters []FileFilter) (string, error) {
+	path, err := a.frontend.OpenFile(service.OpenDialog{
+		Title:   title,
+		Filters: dialogFilters(filters),
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	return path, a.ValidatePath(path, purpose)
+}
+
+// PickSaveFile shows a native save dialog and validates the chosen path for
+// the given purpose. Returns an empty string if cancelled.
+func (a *App) PickSaveFile(title, defaultName, purpose string, filters []FileFilter) (string, error) {
+	path, err := a.frontend.SaveFile(service.SaveDialog{
+		Title:                title,
+		DefaultFilename:      defaultName,
+		Filters:              dialogFilters(filters),
+		CanCreateDirectories: true,
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	return path, a.ValidatePath(path, purpose)
+}
+
+// dialogFilters converts file filters to frontend dialog filters.
+func dialogFilters(filters []FileFilter) []service.FileFilter {
+	result := make([]service.FileFilter, 0, len(filters))
+	for _, f := range filters {
+		result = append(result, service.FileFilter{
+			DisplayName: f.DisplayName,
+			Pattern:     f.Pattern,
+		})
+	}
+	return result
+}
+
+// CopyToClipboard places text on the system clipboard, for example a
+// diagnostics summary or an export path.
+func (a *App) CopyToClipboard(text string) error {
+	return a.frontend.SetClipboard(text)
+}
+
+// ReadClipboard returns the text on the system clipboard, for example a
+// pasted import path.
+func (a *App) ReadClipboard() (string, error) {
+	return a.frontend.Clipboard()
+}
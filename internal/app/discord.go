@@ -0,0 +1,58 @@
+package app
+
+import (
+	"log/slog"
+
+	"hytale-launcher/internal/channelinfo"
+	"hytale-launcher/internal/discord"
+	"hytale-launcher/internal/settings"
+)
+
+// discordImage is the name of the image asset shown with the activity.
+const discordImage = "hytale"
+
+// updateDiscord publishes the launcher's activity to Discord: the game and
+// its channel with the time played while it runs, otherwise that the user
+// is browsing the launcher. It is called when the game starts or stops.
+// Nothing is published in safe mode.
+func (a *App) updateDiscord() {
+	if a.safeMode || settings.Get().DiscordDisabled {
+		a.richPresence.Clear()
+		return
+	}
+
+	a.runningMu.Lock()
+	r := a.runningGame
+	a.runningMu.Unlock()
+
+	activity := &discord.Activity{
+		Details: "Browsing launcher",
+		Assets:  &discord.Assets{LargeImage: discordImage, LargeText: "Hytale"},
+	}
+	if r != nil {
+		activity.Details = "Playing Hytale"
+		activity.State = channelinfo.Get(r.Channel).DisplayName
+		activity.Timestamps = &discord.Timestamps{Start: r.StartedAt.Unix()}
+	}
+	a.richPresence.Set(activity)
+}
+
+// IsDiscordEnabled returns true if the launcher shows the user's activity
+// on their Discord profile.
+func (a *App) IsDiscordEnabled() bool {
+	return !settings.Get().DiscordDisabled
+}
+
+// SetDiscordEnabled turns showing the user's activity on Discord on or off.
+func (a *App) SetDiscordEnabled(enabled bool) error {
+	err := settings.Update("set_discord", func(s *settings.Settings) {
+		s.DiscordDisabled = !enabled
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("discord rich presence preference changed", "enabled", enabled)
+	go a.updateDiscord()
+	return nil
+}
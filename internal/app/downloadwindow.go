@@ -0,0 +1,127 @@
+package app
+
+import (
+	"log/slog"
+	"time"
+
+	"hytale-launcher/internal/settings"
+)
+
+// DownloadWindowStatus is the download window setting with whether
+// background downloads are allowed right now.
+type DownloadWindowStatus struct {
+	settings.DownloadWindow
+
+	// Open is set if background downloads are allowed now, because the
+	// window is off, open or overridden.
+	Open bool `json:"open"`
+
+	// NextOpen is when the window next opens, if it is closed.
+	NextOpen *time.Time `json:"next_open,omitempty"`
+
+	// Overridden is set while DownloadNow has lifted the window.
+	Overridden bool `json:"overridden"`
+
+	// Deferred is set if updates are waiting for the window to open.
+	Deferred bool `json:"deferred"`
+}
+
+// GetDownloadWindow returns the download window and whether background
+// downloads are allowed now.
+func (a *App) GetDownloadWindow() DownloadWindowStatus {
+	w := settings.Get().DownloadWindow
+	now := time.Now()
+
+	status := DownloadWindowStatus{
+		DownloadWindow: w,
+		Overridden:     a.isDownloadWindowOverridden(now),
+		Deferred:       a.downloadsDeferred.Load(),
+	}
+	status.Open = status.Overridden || w.Contains(now)
+	if !status.Open {
+		next := w.NextOpen(now)
+		status.NextOpen = &next
+	}
+	return status
+}
+
+// SetDownloadWindow changes the time of day updates are downloaded in the
+// background. Updates deferred by the old window are downloaded right away
+// if the new one is open.
+func (a *App) SetDownloadWindow(w settings.DownloadWindow) error {
+	if w.Enabled {
+		if err := w.Validate(); err != nil {
+			return err
+		}
+	}
+	err := settings.Update("set_download_window", func(s *settings.Settings) {
+		s.DownloadWindow = w
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("download window changed", "enabled", w.Enabled, "start", w.Start, "end", w.End)
+	go a.resumeDeferredDownloads()
+	return nil
+}
+
+// DownloadNow lifts the download window until it next opens, and downloads
+// pending updates right away.
+func (a *App) DownloadNow() error {
+	w := settings.Get().DownloadWindow
+	now := time.Now()
+	if !w.Contains(now) {
+		until := w.NextOpen(now)
+		a.downloadWindowOverride.Store(&until)
+		slog.Info("download window overridden", "until", until)
+	}
+
+	a.downloadsDeferred.Store(false)
+	go func() {
+		if a.CheckForUpdates(false) == 0 {
+			return
+		}
+		if err := a.ApplyUpdates(); err != nil {
+			slog.Warn("download failed", "error", err)
+		}
+	}()
+	return nil
+}
+
+// inDownloadWindow reports whether updates may be downloaded in the
+// background now.
+func (a *App) inDownloadWindow() bool {
+	now := time.Now()
+	return a.isDownloadWindowOverridden(now) || settings.Get().DownloadWindow.Contains(now)
+}
+
+// isDownloadWindowOverridden reports whether DownloadNow has lifted the
+// window at t.
+func (a *App) isDownloadWindowOverridden(t time.Time) bool {
+	until := a.downloadWindowOverride.Load()
+	return until != nil && t.Before(*until)
+}
+
+// deferDownloads records that background downloads are waiting for the
+// download window, and tells the frontend when it opens.
+func (a *App) deferDownloads() {
+	next := settings.Get().DownloadWindow.NextOpen(time.Now())
+	if !a.downloadsDeferred.Swap(true) {
+		slog.Info("deferring background downloads until the download window opens", "next_open", next)
+	}
+	a.Emit("updates:deferred", next)
+}
+
+// resumeDeferredDownloads downloads the updates deferred by the download
+// window once it is open.
+func (a *App) resumeDeferredDownloads() error {
+	if !a.downloadsDeferred.Load() || !a.inDownloadWindow() {
+		return nil
+	}
+	a.downloadsDeferred.Store(false)
+
+	slog.Info("download window open, downloading deferred updates")
+	a.applyUpdatesInBackground()
+	return nil
+}
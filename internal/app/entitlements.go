@@ -0,0 +1,82 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"hytale-launcher/internal/account"
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/auth"
+)
+
+// errChannelRevoked is returned by LaunchGame on a channel the account no
+// longer has access to.
+var errChannelRevoked = errors.New("your account no longer has access to this channel")
+
+// EntitlementChange describes channels granted or revoked since the
+// account was last refreshed.
+type EntitlementChange struct {
+	Granted []string `json:"granted,omitempty"`
+	Revoked []string `json:"revoked,omitempty"`
+
+	// Channels are the channels now offered to the user.
+	Channels []string `json:"channels"`
+}
+
+// checkEntitlements compares the account's channels before and after a
+// refresh. Revoked channels keep their install but become read-only, and
+// the selection moves away from them; channels granted again are restored.
+// Changes are reported with an "entitlements_changed" event and the
+// frontend is reloaded, so new channels appear without a restart.
+func (a *App) checkEntitlements(before, after []string) {
+	granted, revoked := account.DiffChannels(before, after)
+	if len(granted) == 0 && len(revoked) == 0 {
+		return
+	}
+
+	slog.Info("entitlements changed", "granted", granted, "revoked", revoked)
+	var profile string
+	if p := a.getCurrentProfile(); p != nil {
+		profile = p.UUID
+	}
+	auth.Audit(auth.AuditEntitlementChange, "account_refresh", profile,
+		fmt.Sprintf("granted: %s; revoked: %s", strings.Join(granted, ", "), strings.Join(revoked, ", ")))
+	for _, channel := range granted {
+		a.setChannelRevoked(channel, false)
+	}
+	for _, channel := range revoked {
+		a.setChannelRevoked(channel, true)
+	}
+
+	a.ensureValidChannel(a.getCurrentChannel())
+	a.Emit("entitlements_changed", EntitlementChange{
+		Granted:  granted,
+		Revoked:  revoked,
+		Channels: a.userChannels(),
+	})
+	a.ReloadLauncher("entitlements_changed")
+}
+
+// setChannelRevoked marks a channel's saved state as revoked or not. A
+// channel that was never installed has no state and is left alone.
+func (a *App) setChannelRevoked(channel string, revoked bool) {
+	state := a.State
+	if state == nil || state.Channel != channel {
+		var err error
+		state, err = appstate.Load(channel)
+		if err != nil {
+			if !errors.Is(err, appstate.ErrNotFound) {
+				slog.Warn("unable to load channel state", "channel", channel, "error", err)
+			}
+			return
+		}
+	}
+	if state.Revoked == revoked {
+		return
+	}
+
+	state.Revoked = revoked
+	state.Save("entitlements_changed")
+}
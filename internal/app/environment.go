@@ -0,0 +1,81 @@
+package app
+
+import (
+	"errors"
+	"log/slog"
+
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/settings"
+)
+
+// applyEnvironment selects the endpoint environment saved in settings. It
+// does nothing in release builds, which always use production, and uses
+// production in safe mode.
+func (a *App) applyEnvironment() {
+	if !build.IsDev() {
+		return
+	}
+	if a.safeMode {
+		if err := endpoints.SetEnvironment(endpoints.Production()); err != nil {
+			slog.Warn("unable to select endpoint environment", "error", err)
+		}
+		return
+	}
+
+	s := settings.Get()
+	env, err := endpoints.Lookup(s.Environment, s.EnvironmentDomain)
+	if err != nil {
+		slog.Warn("invalid endpoint environment, using production",
+			"environment", s.Environment,
+			"error", err,
+		)
+		return
+	}
+	if err := endpoints.SetEnvironment(env); err != nil {
+		slog.Warn("unable to select endpoint environment", "error", err)
+	}
+}
+
+// GetEnvironment returns the backend environment endpoints are served from.
+func (a *App) GetEnvironment() endpoints.Environment {
+	return endpoints.Current()
+}
+
+// GetEnvironments returns the predefined backend environments. It is empty
+// in release builds, where the environment cannot be changed.
+func (a *App) GetEnvironments() []endpoints.Environment {
+	if !build.IsDev() {
+		return nil
+	}
+	return endpoints.Environments()
+}
+
+// SetEnvironment selects the backend environment by name and saves it.
+// The domain is only used for the custom environment. Data already cached
+// from the previous environment is kept until the launcher restarts. It
+// fails outside development builds, and requires the session nonce.
+func (a *App) SetEnvironment(nonce, name, domain string) error {
+	if err := a.checkNonce(nonce); err != nil {
+		return err
+	}
+	if !build.IsDev() {
+		return errors.New("endpoint environments can only be changed in development builds")
+	}
+
+	env, err := endpoints.Lookup(name, domain)
+	if err != nil {
+		return err
+	}
+	if err := endpoints.SetEnvironment(env); err != nil {
+		return err
+	}
+
+	return settings.Update("set_environment", func(s *settings.Settings) {
+		s.Environment = env.Name
+		s.EnvironmentDomain = ""
+		if env.Name == endpoints.EnvCustom {
+			s.EnvironmentDomain = domain
+		}
+	})
+}
@@ -91,7 +91,7 @@ func (a *App) AcceptEULA() error {
 // This logs the user out.
 func (a *App) DeclineEULA() error {
 	slog.Info("EULA declined by user")
-	return a.Logout()
+	return a.logout()
 }
 
 // writeLegalFiles writes the legal files (EULA, licenses) to the storage directory.
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"strings"
+	"time"
+)
+
+// Limits for delivering events to the frontend. At these settings the
+// webview receives at most a few hundred events per second.
+const (
+	emitInterval  = 16 * time.Millisecond
+	emitBatchSize = 8
+	emitQueueSize = 512
+)
+
+// coalesceEvent reports whether only the latest event with the given name
+// needs to reach the frontend. This is the case for progress reports, where
+// an intermediate value is superseded by the next.
+func coalesceEvent(name string) bool {
+	return strings.HasPrefix(name, "update:status") || strings.HasSuffix(name, ":progress")
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/channelinfo"
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/i18n"
+)
+
+// ExperimentalChannel describes what opting into an experimental channel
+// involves, for the opt-in dialog.
+type ExperimentalChannel struct {
+	Channel string `json:"channel"`
+
+	// Consent is the document the user must accept before opting in.
+	Consent *PrereleaseConsent `json:"consent"`
+
+	// Warnings are shown with the consent document.
+	Warnings []i18n.Message `json:"warnings"`
+
+	// Dir is where the channel is installed.
+	Dir string `json:"dir"`
+
+	// OptedIn is true if the user already opted in.
+	OptedIn bool `json:"opted_in"`
+}
+
+// optInRequest is the body sent when opting into an experimental channel.
+type optInRequest struct {
+	ConsentVersion int `json:"consent_version"`
+}
+
+// GetExperimentalChannel returns the consent document and warnings for an
+// experimental channel, to show before OptIntoExperimentalChannel.
+func (a *App) GetExperimentalChannel(channel string) (*ExperimentalChannel, error) {
+	if channelinfo.Get(channel).Stability != channelinfo.StabilityExperimental {
+		return nil, fmt.Errorf("%s is not an experimental channel", channel)
+	}
+
+	c, err := channelinfo.GetConsent(channel)
+	if err != nil {
+		return nil, err
+	}
+	consent := &PrereleaseConsent{Consent: c}
+	optedIn := false
+	if acct := a.Auth.GetAccount(); acct != nil {
+		consent.Accepted = acct.HasConsent(channel, c.Version)
+		optedIn = acct.HasExperimentalOptIn(channel)
+	}
+
+	dir := experimentalChannelDir(channel)
+
+	return &ExperimentalChannel{
+		Channel: channel,
+		Consent: consent,
+		Warnings: []i18n.Message{
+			i18n.Msg("experimental.warning.unstable"),
+			i18n.Msg("experimental.warning.separate", dir),
+			i18n.Msg("experimental.warning.reset"),
+		},
+		Dir:     dir,
+		OptedIn: optedIn,
+	}, nil
+}
+
+// experimentalChannelDir returns where an experimental channel is, or will
+// be, installed.
+func experimentalChannelDir(channel string) string {
+	if hytale.IsKnownChannel(channel) {
+		return hytale.ChannelDir(channel)
+	}
+	return filepath.Join(hytale.ExperimentalDir(), channel)
+}
+
+// OptIntoExperimentalChannel opts the account into an experimental channel.
+// The channel's consent document must have been accepted with
+// AcceptPrereleaseConsent. The opt-in is sent to the API, which grants the
+// channel's patchline; the account is then refreshed so the channel
+// appears. The channel is installed in its own directory, apart from the
+// regular channels, and the opt-in is kept with the account.
+func (a *App) OptIntoExperimentalChannel(channel string) error {
+	acct := a.Auth.GetAccount()
+	if acct == nil {
+		return errors.New("no user logged in")
+	}
+	if channelinfo.Get(channel).Stability != channelinfo.StabilityExperimental {
+		return fmt.Errorf("%s is not an experimental channel", channel)
+	}
+	if !hytale.IsKnownChannel(channel) && !hytale.ValidExperimentalChannel(channel) {
+		return fmt.Errorf("invalid experimental channel name %q", channel)
+	}
+
+	consent, err := channelinfo.GetConsent(channel)
+	if err != nil {
+		return err
+	}
+	if !acct.HasConsent(channel, consent.Version) {
+		return errConsentRequired
+	}
+
+	client := api.New(api.WithHTTPClient(a.Auth.Client()))
+	req := optInRequest{ConsentVersion: consent.Version}
+	if err := api.Post(context.Background(), client, endpoints.ExperimentalOptIn(channel), req); err != nil {
+		return fmt.Errorf("unable to opt into %s: %w", channel, err)
+	}
+
+	// Regular channels keep their directory; only new channels move into
+	// the experimental directory.
+	if !hytale.IsKnownChannel(channel) {
+		if err := hytale.AddExperimentalChannel(channel); err != nil {
+			return err
+		}
+	}
+
+	slog.Info("opted into experimental channel", "channel", channel, "consent", consent.Version)
+	acct.RecordExperimentalOptIn(channel, consent.Version)
+	a.Auth.SaveAccount("experimental_opt_in")
+
+	a.refreshUser(true, "experimental_opt_in")
+	if !slices.Contains(acct.AllChannels(), channel) {
+		slog.Warn("experimental channel not granted yet", "channel", channel)
+	}
+
+	a.Emit("experimental:opted_in", channel)
+	return nil
+}
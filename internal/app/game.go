@@ -4,45 +4,37 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
-	"sync"
+	"time"
 
 	"github.com/getsentry/sentry-go"
-	"github.com/pkg/browser"
 
+	"hytale-launcher/internal/appstate"
 	"hytale-launcher/internal/build"
 	"hytale-launcher/internal/buildscan"
 	"hytale-launcher/internal/deletex"
 	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/integrity"
 	"hytale-launcher/internal/ioutil"
 	"hytale-launcher/internal/launch"
+	"hytale-launcher/internal/launchopts"
 	"hytale-launcher/internal/net"
 	"hytale-launcher/internal/pkg"
+	"hytale-launcher/internal/preflight"
 	"hytale-launcher/internal/repair"
+	"hytale-launcher/internal/serverlist"
 	"hytale-launcher/internal/session"
+	"hytale-launcher/internal/sysinfo"
+	"hytale-launcher/internal/telemetry"
+	"hytale-launcher/internal/uninstall"
 )
 
-// updatingMu protects the updating flag.
-var updatingMu sync.RWMutex
-var updating bool
-
-// isUpdating returns true if an update is currently in progress.
+// isUpdating returns true if an update, import or uninstall is running on
+// the selected channel.
 func (a *App) isUpdating() bool {
-	updatingMu.RLock()
-	defer updatingMu.RUnlock()
-	return updating
-}
-
-// markAsUpdating sets the updating flag.
-func (a *App) markAsUpdating(value bool) {
-	updatingMu.Lock()
-	defer updatingMu.Unlock()
-	updating = value
-}
-
-// cancelUpdating cancels any in-progress update.
-func (a *App) cancelUpdating() {
-	a.markAsUpdating(false)
+	state := a.State
+	return state != nil && a.isChannelBusy(state.Channel)
 }
 
 // IsGameAvailable returns true if the game is installed and ready to launch.
@@ -52,11 +44,20 @@ func (a *App) IsGameAvailable() bool {
 	}
 
 	gameDep := a.State.GetDependency("game")
-	jreDep := a.State.GetDependency("jre")
+	if a.State.JavaPath != "" {
+		return gameDep != nil
+	}
+	jreDep := a.gameJRE()
 
 	return gameDep != nil && jreDep != nil
 }
 
+// gameJRE returns the installed JRE matching the Java requirement of the
+// installed game build, or nil if none is installed.
+func (a *App) gameJRE() *appstate.Dep {
+	return a.State.FindJRE(a.State.GameJRERange())
+}
+
 // GetGameVersion returns the installed game version for the current channel.
 func (a *App) GetGameVersion() string {
 	if a.State == nil {
@@ -108,7 +109,29 @@ func (a *App) GetInstalledGameDirSizes() map[string]int64 {
 }
 
 // LaunchGame launches the game with the current configuration.
-func (a *App) LaunchGame() error {
+// It refuses to launch if the game has been flagged as crash-looping;
+// the user must launch in safe mode or repair the installation first.
+// Pending updates are handled according to the user's play policy.
+// If server is not empty, the game joins that server once started.
+func (a *App) LaunchGame(server string) error {
+	if a.State != nil && a.State.Health.IsUnhealthy() {
+		return errGameUnhealthy
+	}
+	if server != "" {
+		if err := serverlist.ValidateAddress(server); err != nil {
+			return err
+		}
+		server = serverlist.NormalizeAddress(server)
+	}
+	if err := a.prepareToPlay(a.GetPlayPolicy()); err != nil {
+		return err
+	}
+	return a.launchGame(false, server)
+}
+
+// launchGame launches the game, optionally in safe mode or joining a
+// server, and records rapid exits for crash-loop detection.
+func (a *App) launchGame(safeMode bool, server string) error {
 	if net.Current() == net.ModeOffline && !a.HasValidSession() {
 		return &launch.AuthError{Err: errors.New("offline mode requires a valid session")}
 	}
@@ -117,16 +140,23 @@ func (a *App) LaunchGame() error {
 		return errors.New("no channel selected")
 	}
 
+	if !a.hasPrereleaseConsent() {
+		return errConsentRequired
+	}
+
+	if a.State.Revoked {
+		return errChannelRevoked
+	}
+
+	if a.IsGameRunning() {
+		return errGameRunning
+	}
+
 	gameDep := a.State.GetDependency("game")
 	if gameDep == nil {
 		return errors.New("game not installed")
 	}
 
-	jreDep := a.State.GetDependency("jre")
-	if jreDep == nil {
-		return errors.New("java not installed")
-	}
-
 	// Get the game executable path
 	gamePath, err := ioutil.FindExecutable(gameDep.Path, []string{".jar", "-server.jar"})
 	if err != nil {
@@ -136,14 +166,17 @@ func (a *App) LaunchGame() error {
 		return errors.New("game executable not found")
 	}
 
+	report := a.runPreflight()
+	a.reportIntegrity(report.Files)
+	if !report.OK {
+		return &preflight.Error{Report: report}
+	}
+
 	// Get the Java executable path
-	javaPath, err := ioutil.FindExecutable(jreDep.Path, []string{"java", "java.exe"})
+	javaPath, err := a.gameJavaPath()
 	if err != nil {
 		return err
 	}
-	if javaPath == "" {
-		return errors.New("java executable not found")
-	}
 
 	// Get session data
 	gameSession := a.getGameSession()
@@ -162,16 +195,62 @@ func (a *App) LaunchGame() error {
 		SessionToken:  gameSession.SessionToken,
 		IdentityToken: gameSession.IdentityToken,
 		ProfileID:     profileID,
+		ServerAddress: server,
+		Options:       a.launchOptions(),
 	}
+	req.Options.SafeMode = safeMode
+	req.OnStart = func(pid int) {
+		a.gameStarted(&launch.Running{
+			PID:       pid,
+			Channel:   a.State.Channel,
+			JavaPath:  javaPath,
+			StartedAt: time.Now(),
+		})
+	}
+
+	go a.warnOutdatedDrivers(gameDep.Build)
+	a.attestLaunch(context.Background(), req, gameDep)
 
 	slog.Info("launching game",
 		"game_path", gamePath,
 		"java_path", javaPath,
 		"channel", a.State.Channel,
+		"safe_mode", safeMode,
+		"server", server,
 	)
 
 	ctx := context.Background()
-	return launch.Do(ctx, req)
+	started := time.Now()
+	err = launch.Do(ctx, req)
+	a.gameStopped()
+	a.recordGameExit(time.Since(started), safeMode, err)
+	if err != nil {
+		telemetry.Failure("launch", err)
+	}
+	if err == nil {
+		a.recordPrereleaseSession()
+	}
+	return err
+}
+
+// warnOutdatedDrivers emits a launch warning for each GPU whose driver is
+// below the recommended version for the given game build. It runs beside
+// the launch rather than before it, as fetching the driver table can be
+// slow.
+func (a *App) warnOutdatedDrivers(gameBuild int) {
+	if net.Current() == net.ModeOffline {
+		return
+	}
+
+	warnings, err := launch.CheckDrivers(context.Background(), gameBuild)
+	if err != nil {
+		slog.Warn("unable to check gpu drivers", "error", err)
+		return
+	}
+
+	for _, w := range warnings {
+		a.Emit("launch:warning", w)
+	}
 }
 
 // getGameSession returns the current game session or creates a new one.
@@ -203,7 +282,7 @@ func (a *App) GetLastKnownGoodVersion() string {
 // LaunchLastKnownGood launches the last known good version of the game.
 func (a *App) LaunchLastKnownGood() error {
 	slog.Info("launching last known good version")
-	return a.LaunchGame()
+	return a.LaunchCurrentVersion()
 }
 
 // launchPackage launches a specific package version.
@@ -212,11 +291,15 @@ func (a *App) launchPackage(pkgID, version string) error {
 		"package", pkgID,
 		"version", version,
 	)
-	return a.LaunchGame()
+	return a.LaunchGame("")
 }
 
 // UninstallGame uninstalls the game from the specified channel.
 func (a *App) UninstallGame(channel string) error {
+	if a.IsGameRunning() {
+		return errGameRunning
+	}
+
 	slog.Info("uninstalling game", "channel", channel)
 
 	installs := buildscan.ScanInstalledGames(false)
@@ -244,15 +327,61 @@ func (a *App) UninstallGame(channel string) error {
 	return nil
 }
 
+// UninstallChannel removes the game builds, Java runtimes and dedicated
+// server installed for a channel. With keepSaves set, the player's data is
+// first archived to the backups directory. Progress is emitted as
+// "uninstall:progress" events, and the result, including the space
+// reclaimed, as "uninstall:complete". It requires the session nonce.
+func (a *App) UninstallChannel(nonce, channel string, keepSaves bool) (*uninstall.Result, error) {
+	if err := a.checkNonce(nonce); err != nil {
+		return nil, err
+	}
+	if !hytale.IsKnownChannel(channel) {
+		return nil, fmt.Errorf("unknown channel %q", channel)
+	}
+	if a.isGameRunningOn(channel) {
+		return nil, errGameRunning
+	}
+	if isServerRunning(channel) {
+		return nil, errors.New("stop the server before uninstalling")
+	}
+	if !a.beginWork(channel, nil) {
+		return nil, errors.New("an update is in progress")
+	}
+	defer a.endWork(channel)
+
+	result, err := uninstall.Channel(a.channelState(channel), keepSaves, func(current, total int) {
+		a.Emit("uninstall:progress", map[string]interface{}{
+			"channel": channel,
+			"current": current,
+			"total":   total,
+		})
+	})
+	if err != nil {
+		sentry.CaptureException(err)
+		return nil, err
+	}
+
+	a.Emit("uninstall:complete", result)
+	return result, nil
+}
+
 // ValidateGameFiles validates the integrity of game files.
 func (a *App) ValidateGameFiles() error {
+	_, err := a.validateGameFiles()
+	return err
+}
+
+// validateGameFiles validates the game files, reporting progress and the
+// outcome to the frontend, and returns the result.
+func (a *App) validateGameFiles() (*repair.Result, error) {
 	if a.State == nil {
-		return errors.New("no channel selected")
+		return nil, errors.New("no channel selected")
 	}
 
 	gameDep := a.State.GetDependency("game")
 	if gameDep == nil {
-		return errors.New("game not installed")
+		return nil, errors.New("game not installed")
 	}
 
 	slog.Info("validating game files",
@@ -270,14 +399,19 @@ func (a *App) ValidateGameFiles() error {
 		})
 	}
 
-	// Load checksums from signature file if available
-	checksums := make(map[string]string)
-	// In a real implementation, load from gameDep.SigPath()
+	checksums, err := a.gameChecksums(a.State.Channel, gameDep)
+	if err != nil {
+		return nil, err
+	}
 
 	result, err := repair.Verify(gameDep.Path, checksums, reporter)
 	if err != nil {
 		sentry.CaptureException(err)
-		return err
+		return nil, err
+	}
+
+	if result.IsHealthy() && a.State.Health.IsUnhealthy() {
+		a.ResetGameHealth()
 	}
 
 	if !result.IsHealthy() {
@@ -289,7 +423,29 @@ func (a *App) ValidateGameFiles() error {
 		a.Emit("validate:success")
 	}
 
-	return nil
+	return result, nil
+}
+
+// gameChecksums returns the expected hashes of an installed game build's
+// files: the file listing of the channel's manifest if it has one for the
+// build, or else the integrity baseline recorded when it was installed.
+func (a *App) gameChecksums(channel string, gameDep *appstate.Dep) (map[string]string, error) {
+	checksums, err := pkg.GameFileListing(context.Background(), channel, gameDep.Build)
+	if err == nil {
+		return checksums, nil
+	}
+	if !errors.Is(err, pkg.ErrNoFileListing) {
+		slog.Warn("unable to get game file listing", "channel", channel, "error", err)
+	}
+
+	b, err := integrity.Load(channel)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil || b.Build != gameDep.Build || len(b.Files) == 0 {
+		return nil, errors.New("no file hashes available to verify this build")
+	}
+	return b.Files, nil
 }
 
 // ResetGameSettings resets game settings to defaults.
@@ -311,17 +467,21 @@ func (a *App) ResetGameSettings() error {
 func (a *App) OpenHytaleDir() error {
 	storageDir := hytale.StorageDir()
 	slog.Info("opening Hytale directory", "dir", storageDir)
-	return browser.OpenFile(storageDir)
+	return openPath(storageDir)
 }
 
 // CanDeleteUserData returns true if user data can be deleted.
 func (a *App) CanDeleteUserData() bool {
-	// Check if there are no running game processes
-	return !a.isUpdating()
+	// Check that no channel is being updated
+	return len(a.busyChannels()) == 0
 }
 
-// DeleteUserData deletes all user data from the storage directory.
-func (a *App) DeleteUserData() error {
+// DeleteUserData deletes all user data from the storage directory. It
+// requires the session nonce.
+func (a *App) DeleteUserData(nonce string) error {
+	if err := a.checkNonce(nonce); err != nil {
+		return err
+	}
 	if !a.CanDeleteUserData() {
 		return errors.New("cannot delete user data while updating")
 	}
@@ -329,7 +489,7 @@ func (a *App) DeleteUserData() error {
 	slog.Warn("deleting all user data")
 
 	// Logout first
-	if err := a.Logout(); err != nil {
+	if err := a.logout(); err != nil {
 		slog.Warn("error during logout before delete", "error", err)
 	}
 
@@ -352,6 +512,97 @@ func (a *App) DeleteUserData() error {
 	return nil
 }
 
+// IsBigPictureMode returns true if the launcher is running on a Steam Deck,
+// inside gamescope, or from Steam's Big Picture mode, and the frontend should
+// present a controller-friendly interface.
+func (a *App) IsBigPictureMode() bool {
+	return sysinfo.IsBigPicture()
+}
+
+// GetLaunchOptions returns the options the game will be launched with.
+func (a *App) GetLaunchOptions() launch.Options {
+	return a.launchOptions()
+}
+
+// launchOptions returns the default launch options for the handheld mode
+// setting with the current channel's JVM overrides applied.
+func (a *App) launchOptions() launch.Options {
+	opts := launch.OptionsFor(isHandheldMode())
+	if a.State != nil && a.State.JVM != nil {
+		opts.JVM = a.State.JVM.Merge(opts.JVM)
+	}
+	if a.State != nil && a.State.CustomArgs != nil {
+		opts.Custom = *a.State.CustomArgs
+	}
+	return opts
+}
+
+// SetJVMOptions stores JVM overrides for the current channel. Fields left
+// at zero keep using the values computed from the system's memory and CPUs.
+func (a *App) SetJVMOptions(opts launchopts.JVMOptions) error {
+	if a.State == nil {
+		return errors.New("no channel selected")
+	}
+
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	if opts == (launchopts.JVMOptions{}) {
+		a.State.JVM = nil
+	} else {
+		a.State.JVM = &opts
+	}
+	a.State.Save("jvm options changed")
+	return nil
+}
+
+// GetCustomLaunchArgs returns the current channel's custom launch
+// arguments and environment variables.
+func (a *App) GetCustomLaunchArgs() launchopts.CustomArgs {
+	if a.State == nil || a.State.CustomArgs == nil {
+		return launchopts.CustomArgs{}
+	}
+	return *a.State.CustomArgs
+}
+
+// GetLaunchTemplateVariables returns the variables custom launch arguments
+// can refer to as ${NAME}.
+func (a *App) GetLaunchTemplateVariables() []string {
+	return launchopts.TemplateVariables
+}
+
+// SetCustomLaunchArgs stores custom launch arguments and environment
+// variables for the current channel. An empty value removes them. It
+// requires the session nonce, as the variables can change what the game
+// runs.
+func (a *App) SetCustomLaunchArgs(nonce string, args launchopts.CustomArgs) error {
+	if err := a.checkNonce(nonce); err != nil {
+		return err
+	}
+	if a.State == nil {
+		return errors.New("no channel selected")
+	}
+
+	if err := args.Validate(); err != nil {
+		return err
+	}
+
+	if args.IsZero() {
+		a.State.CustomArgs = nil
+	} else {
+		a.State.CustomArgs = &args
+	}
+	a.State.Save("custom launch args changed")
+	slog.Info("custom launch arguments changed",
+		"channel", a.State.Channel,
+		"jvm_args", len(args.JVMArgs),
+		"game_args", len(args.GameArgs),
+		"env", len(args.Env),
+	)
+	return nil
+}
+
 // GetLaunchAuthMode returns the authentication mode for launching.
 func (a *App) GetLaunchAuthMode() string {
 	if net.Current() == net.ModeOffline {
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"hytale-launcher/internal/deeplink"
+	"hytale-launcher/internal/settings"
+	"hytale-launcher/internal/steam"
+	"hytale-launcher/internal/sysinfo"
+)
+
+// HandheldModeStatus describes handheld mode for the settings screen.
+type HandheldModeStatus struct {
+	// Mode is the user's choice, one of the settings.HandheldMode*
+	// constants.
+	Mode string `json:"mode"`
+
+	// SteamDeck and Gamescope report what was detected.
+	SteamDeck bool `json:"steam_deck"`
+	Gamescope bool `json:"gamescope"`
+
+	// Active is set if the game is launched in handheld mode.
+	Active bool `json:"active"`
+}
+
+// GetHandheldMode returns the handheld mode setting and whether it is in
+// effect.
+func (a *App) GetHandheldMode() HandheldModeStatus {
+	return HandheldModeStatus{
+		Mode:      settings.Get().HandheldMode,
+		SteamDeck: sysinfo.IsSteamDeck(),
+		Gamescope: sysinfo.IsGamescope(),
+		Active:    isHandheldMode(),
+	}
+}
+
+// SetHandheldMode chooses whether the game is launched fullscreen with
+// controller prompts: "on", "off", or "" to decide from the hardware and
+// session.
+func (a *App) SetHandheldMode(mode string) error {
+	switch mode {
+	case settings.HandheldModeAuto, settings.HandheldModeOn, settings.HandheldModeOff:
+	default:
+		return fmt.Errorf("unknown handheld mode %q", mode)
+	}
+
+	err := settings.Update("set_handheld_mode", func(s *settings.Settings) {
+		s.HandheldMode = mode
+	})
+	if err != nil {
+		return err
+	}
+	slog.Info("handheld mode changed", "mode", mode, "active", isHandheldMode())
+	return nil
+}
+
+// isHandheldMode reports whether the game is launched in handheld mode.
+func isHandheldMode() bool {
+	switch settings.Get().HandheldMode {
+	case settings.HandheldModeOn:
+		return true
+	case settings.HandheldModeOff:
+		return false
+	default:
+		return sysinfo.IsBigPicture()
+	}
+}
+
+// HasSteamShortcut reports whether the launcher is in a Steam library.
+func (a *App) HasSteamShortcut() bool {
+	exe, err := launcherExecutable()
+	if err != nil {
+		return false
+	}
+	return steam.HasShortcut(exe)
+}
+
+// CreateSteamShortcut adds the launcher to the library of each Steam user
+// on this machine as a non-Steam game that launches the game directly, so
+// it can be played from the Steam Deck's gaming mode. Returns the number
+// of libraries changed; Steam must be restarted to show the shortcut.
+func (a *App) CreateSteamShortcut() (int, error) {
+	exe, err := launcherExecutable()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := steam.AddShortcut(steam.Shortcut{
+		Name:          "Hytale",
+		Exe:           exe,
+		StartDir:      filepath.Dir(exe),
+		LaunchOptions: deeplink.LaunchFlag,
+	})
+	if errors.Is(err, steam.ErrNoSteam) {
+		return 0, errors.New("Steam is not installed, or no user has logged in to it")
+	}
+	return n, err
+}
+
+// launcherExecutable returns the path Steam should start the launcher
+// from. Inside an AppImage that is the image, not the extracted binary,
+// whose mount point changes on every run.
+func launcherExecutable() (string, error) {
+	if image := os.Getenv("APPIMAGE"); image != "" {
+		return image, nil
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("unable to locate launcher executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return exe, nil
+}
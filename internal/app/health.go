@@ -0,0 +1,128 @@
+package app
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/launch"
+	"hytale-launcher/internal/notifications"
+)
+
+// errGameUnhealthy is returned by LaunchGame while the game is flagged as
+// crash-looping.
+var errGameUnhealthy = errors.New("the game has crashed repeatedly on startup; launch in safe mode or repair the installation")
+
+// Recovery actions suggested to the user after a crash.
+const (
+	suggestSafeMode = "safe_mode"
+	suggestRepair   = "repair"
+)
+
+// recordGameExit updates the channel's health after the game exits. Failed
+// exits within appstate.RapidExitThreshold of launch count towards crash-loop
+// detection; a run that lasts longer or exits cleanly clears the history.
+// Crashes are reported to the frontend with a "game:crashed" event and a
+// system notification.
+func (a *App) recordGameExit(runtime time.Duration, safeMode bool, err error) {
+	if a.State == nil || launch.IsAuthError(err) {
+		return
+	}
+
+	if err == nil || runtime >= appstate.RapidExitThreshold {
+		if a.State.Health != nil && !a.State.Health.Unhealthy && len(a.State.Health.RapidExits) > 0 {
+			a.State.Health = nil
+			a.State.Save("game ran normally")
+		}
+		if err == nil {
+			return
+		}
+	}
+
+	exitCode := -1
+	var exitErr *launch.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode
+	}
+
+	rapid := runtime < appstate.RapidExitThreshold
+	becameUnhealthy := false
+
+	if rapid {
+		if a.State.Health == nil {
+			a.State.Health = &appstate.Health{}
+		}
+		becameUnhealthy = a.State.Health.RecordRapidExit(appstate.ExitRecord{
+			Time:     time.Now(),
+			Runtime:  runtime.Milliseconds(),
+			ExitCode: exitCode,
+			SafeMode: safeMode,
+		})
+		a.State.Save("game exited rapidly")
+
+		if becameUnhealthy {
+			slog.Warn("game flagged as crash-looping",
+				"channel", a.State.Channel,
+				"rapid_exits", len(a.State.Health.RapidExits),
+			)
+		}
+	}
+
+	var history []appstate.ExitRecord
+	unhealthy := a.State.Health.IsUnhealthy()
+	if a.State.Health != nil {
+		history = a.State.Health.RapidExits
+	}
+
+	suggestions := []string{}
+	if unhealthy {
+		if !safeMode {
+			suggestions = append(suggestions, suggestSafeMode)
+		}
+		suggestions = append(suggestions, suggestRepair)
+	}
+
+	a.notify(notifications.TypeError, "notify.game_crashed")
+	a.Emit("game:crashed", map[string]interface{}{
+		"exit_code":        exitCode,
+		"runtime_ms":       runtime.Milliseconds(),
+		"rapid":            rapid,
+		"safe_mode":        safeMode,
+		"unhealthy":        unhealthy,
+		"became_unhealthy": becameUnhealthy,
+		"rapid_exits":      history,
+		"suggestions":      suggestions,
+	})
+}
+
+// GetGameHealth returns the crash-loop state of the current channel.
+func (a *App) GetGameHealth() appstate.Health {
+	if a.State == nil || a.State.Health == nil {
+		return appstate.Health{}
+	}
+	return *a.State.Health
+}
+
+// ResetGameHealth clears the crash-loop flag and rapid-exit history for the
+// current channel, allowing a normal launch again.
+func (a *App) ResetGameHealth() {
+	if a.State == nil || a.State.Health == nil {
+		return
+	}
+
+	slog.Info("resetting game health", "channel", a.State.Channel)
+	a.State.Health = nil
+	a.State.Save("game health reset")
+}
+
+// LaunchGameSafeMode launches the game in safe mode. It is allowed even
+// when the game is flagged as crash-looping, and clears the flag if the
+// game then runs normally.
+func (a *App) LaunchGameSafeMode() error {
+	err := a.launchGame(true, "")
+	if err == nil {
+		a.ResetGameHealth()
+	}
+	return err
+}
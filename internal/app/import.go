@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/getsentry/sentry-go"
+
+	"hytale-launcher/internal/installlock"
+	"hytale-launcher/internal/pkg"
+)
+
+// ImportExistingInstall imports a game directory downloaded elsewhere as
+// the selected channel's installed build, after checking it against the
+// build's signature. With adopt set, the directory is used in place and
+// never deleted by the launcher; otherwise it is copied into the storage
+// directory. Progress is emitted as "import:progress" events. It requires
+// the session nonce.
+func (a *App) ImportExistingInstall(nonce, path string, adopt bool) error {
+	if err := a.checkNonce(nonce); err != nil {
+		return err
+	}
+	if a.State == nil {
+		return errors.New("no channel selected")
+	}
+	if a.isGameRunningOn(a.State.Channel) {
+		return errGameRunning
+	}
+	if err := a.ValidatePath(path, PathExistingInstall); err != nil {
+		return err
+	}
+	if !a.beginWork(a.State.Channel, nil) {
+		return errors.New("an update is in progress")
+	}
+	defer a.endWork(a.State.Channel)
+
+	build, err := pkg.ImportGame(context.Background(), a.State, path, adopt, func(status pkg.UpdateStatus) {
+		a.Emit("import:progress", status)
+	})
+	if err != nil {
+		if errors.Is(err, installlock.ErrBusy) {
+			a.Emit("install:busy")
+			return err
+		}
+		sentry.CaptureException(err)
+		slog.Error("failed to import game install", "path", path, "error", err)
+		return err
+	}
+
+	slog.Info("imported game install", "channel", a.State.Channel, "build", build.Build, "dir", build.Dir)
+	a.Emit("import:complete", build)
+	return nil
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"errors"
+	"log/slog"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/integrity"
+	"hytale-launcher/internal/settings"
+	"hytale-launcher/internal/telemetry"
+)
+
+// IsIntegrityCheckEnabled returns true if the game's critical files are
+// checked for changes made outside the launcher before each launch.
+func (a *App) IsIntegrityCheckEnabled() bool {
+	return settings.Get().IntegrityCheckEnabled
+}
+
+// SetIntegrityCheckEnabled turns the pre-launch integrity check on or off.
+// Turning it on records a baseline of the installed game right away.
+func (a *App) SetIntegrityCheckEnabled(enabled bool) error {
+	err := settings.Update("set_integrity_check", func(s *settings.Settings) {
+		s.IntegrityCheckEnabled = enabled
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("integrity check preference changed", "enabled", enabled)
+	if enabled && a.State != nil {
+		go a.recordIntegrity(a.State)
+	}
+	return nil
+}
+
+// reportIntegrity reports the pre-launch comparison of the game's critical
+// files with the baseline, if the user has turned the check on. Changed
+// files are reported with an "integrity:changed" event carrying the
+// report, from which the frontend offers RepairGameFiles. Files that were
+// not compared are reported as a failure.
+func (a *App) reportIntegrity(report *integrity.Report) {
+	if !a.IsIntegrityCheckEnabled() {
+		return
+	}
+
+	if report == nil {
+		telemetry.Failure("integrity_check", errors.New("game files were not checked"))
+		return
+	}
+	if !report.Clean() {
+		a.Emit("integrity:changed", report)
+	}
+}
+
+// recordIntegrity records the installed game's critical files as the
+// baseline for a channel, after the launcher changed them.
+func (a *App) recordIntegrity(state *appstate.State) {
+	if !a.IsIntegrityCheckEnabled() {
+		return
+	}
+	gameDep := state.GetDependency("game")
+	if gameDep == nil || gameDep.Path == "" {
+		return
+	}
+	if _, err := integrity.Record(gameDep.Path, state.Channel, gameDep.Build); err != nil {
+		slog.Warn("unable to record integrity baseline", "error", err)
+	}
+}
+
+// RepairGameFiles repairs game files reported by the integrity check by
+// validating the installation. Once it is healthy, its files are recorded
+// as the new baseline.
+func (a *App) RepairGameFiles() error {
+	if a.State == nil {
+		return errors.New("no channel selected")
+	}
+	if a.IsGameRunning() {
+		return errGameRunning
+	}
+
+	result, err := a.validateGameFiles()
+	if err != nil {
+		return err
+	}
+	if result.IsHealthy() {
+		a.recordIntegrity(a.State)
+	}
+	return nil
+}
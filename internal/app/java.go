@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/pkg"
+)
+
+// gameJavaPath returns the Java executable used to launch the game: the
+// user-specified runtime if one is set, otherwise the bundled runtime
+// matching the installed game build.
+func (a *App) gameJavaPath() (string, error) {
+	if a.State.JavaPath != "" {
+		if required := a.State.GameJRERange(); !required.IsZero() {
+			if java, err := pkg.ProbeJava(context.Background(), a.State.JavaPath); err == nil && !required.Contains(java.Major) {
+				slog.Warn("custom java runtime does not match the game's requirement",
+					"path", a.State.JavaPath,
+					"major", java.Major,
+					"min", required.Min,
+					"max", required.Max,
+				)
+			}
+		}
+		return a.State.JavaPath, nil
+	}
+
+	jreDep := a.gameJRE()
+	if jreDep == nil {
+		return "", errors.New("java not installed")
+	}
+
+	jreDir := jreDep.Path
+	if jreDir == "" {
+		jreDir = pkg.JavaDir(a.State.Channel, jreDep.Major)
+	}
+	javaPath, err := ioutil.FindExecutable(jreDir, []string{"java", "java.exe"})
+	if err != nil {
+		return "", err
+	}
+	if javaPath == "" {
+		return "", errors.New("java executable not found")
+	}
+	return javaPath, nil
+}
+
+// GetSystemJava returns the Java runtimes found on the system that can be
+// used instead of the bundled runtime.
+func (a *App) GetSystemJava() []pkg.SystemJava {
+	found := pkg.FindSystemJava(context.Background())
+	if found == nil {
+		return []pkg.SystemJava{}
+	}
+	return found
+}
+
+// GetJavaPath returns the user-specified Java executable for the current
+// channel, or an empty string if the bundled runtime is used.
+func (a *App) GetJavaPath() string {
+	if a.State == nil {
+		return ""
+	}
+	return a.State.JavaPath
+}
+
+// SetJavaPath sets a Java executable or installation directory to use
+// instead of the bundled runtime for the current channel. The runtime is
+// probed before it is saved. An empty path reverts to the bundled runtime.
+// It requires the session nonce, as the runtime is run at each launch.
+func (a *App) SetJavaPath(nonce, path string) (*pkg.SystemJava, error) {
+	if err := a.checkNonce(nonce); err != nil {
+		return nil, err
+	}
+	if a.State == nil {
+		return nil, errors.New("no channel selected")
+	}
+
+	if path == "" {
+		a.State.JavaPath = ""
+		a.State.Save("java path cleared")
+		return nil, nil
+	}
+
+	java, err := pkg.ProbeJava(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("using custom java runtime",
+		"path", java.Path,
+		"version", java.Version,
+	)
+
+	a.State.JavaPath = java.Path
+	a.State.Save("java path changed")
+	return java, nil
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"log/slog"
+
+	"hytale-launcher/internal/keyring"
+)
+
+// KeyringStatus describes the state of the system keyring for the frontend.
+type KeyringStatus struct {
+	// Available is false while the keyring is in degraded mode and
+	// credentials are only kept in memory.
+	Available bool `json:"available"`
+
+	// Backend is "system" or, while the system keyring cannot be used,
+	// "file" for the encrypted keyring file.
+	Backend string `json:"backend"`
+
+	// FallbackReason is why the system keyring is not in use, if it is
+	// not.
+	FallbackReason string `json:"fallback_reason,omitempty"`
+
+	// Code is "locked", "unavailable" or "denied" when not available.
+	Code string `json:"code,omitempty"`
+
+	// Message describes the failure.
+	Message string `json:"message,omitempty"`
+
+	// Remediation is a user-facing suggestion for fixing the failure.
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// keyringStatus converts a keyring error into a KeyringStatus.
+func keyringStatus(err *keyring.Error) KeyringStatus {
+	status := KeyringStatus{Backend: keyring.Backend()}
+	if f := keyring.Fallback(); f != nil {
+		status.FallbackReason = f.Kind.Error()
+	}
+	if err == nil {
+		status.Available = true
+		return status
+	}
+	return KeyringStatus{
+		Backend:     status.Backend,
+		Code:        err.Code(),
+		Message:     err.Kind.Error(),
+		Remediation: err.Remediation(),
+	}
+}
+
+// watchKeyring forwards keyring failures to the frontend as "keyring:error"
+// events so the user can be told how to fix them.
+func (a *App) watchKeyring() {
+	keyring.OnError(func(err *keyring.Error) {
+		a.Emit("keyring:error", keyringStatus(err))
+	})
+}
+
+// GetKeyringStatus returns whether credentials can currently be persisted.
+func (a *App) GetKeyringStatus() KeyringStatus {
+	return keyringStatus(keyring.Degraded())
+}
+
+// RetryKeyring tries the system keyring again, e.g. after the user has
+// unlocked it, moving credentials out of the keyring file if it now works,
+// and saves the in-memory session.
+func (a *App) RetryKeyring() KeyringStatus {
+	slog.Info("retrying keyring access")
+	keyring.Retry()
+
+	if a.Auth != nil {
+		a.Auth.SaveAccount("keyring_retry")
+	}
+
+	return a.GetKeyringStatus()
+}
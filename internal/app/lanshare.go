@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"hytale-launcher/internal/lanshare"
+	"hytale-launcher/internal/settings"
+)
+
+// lanPeersTimeout bounds waiting for launchers on the network to answer
+// when listing them.
+const lanPeersTimeout = 3 * time.Second
+
+// startLANShare starts sharing the installed game builds with other
+// launchers on the local network if the user has allowed it and the
+// launcher is not in safe mode. Any server already running is stopped
+// first, all under lanShareMu, so concurrent calls leave one server at
+// most, following the latest preference.
+func (a *App) startLANShare() {
+	a.lanShareMu.Lock()
+	defer a.lanShareMu.Unlock()
+
+	a.lanShare.Stop()
+	a.lanShare = nil
+
+	if a.safeMode || !settings.Get().LANShareEnabled {
+		return
+	}
+
+	s, err := lanshare.Start()
+	if err != nil {
+		slog.Warn("unable to share game builds on the local network", "error", err)
+		return
+	}
+	a.lanShare = s
+}
+
+// stopLANShare stops sharing the game builds.
+func (a *App) stopLANShare() {
+	a.lanShareMu.Lock()
+	defer a.lanShareMu.Unlock()
+
+	a.lanShare.Stop()
+	a.lanShare = nil
+}
+
+// ListLANPeers returns the launchers on the local network that share
+// their game builds, with the builds each shares.
+func (a *App) ListLANPeers() ([]lanshare.Peer, error) {
+	return lanshare.Discover(context.Background(), lanPeersTimeout)
+}
+
+// IsLANShareEnabled returns true if game builds are shared with, and
+// copied from, other launchers on the local network.
+func (a *App) IsLANShareEnabled() bool {
+	return settings.Get().LANShareEnabled
+}
+
+// SetLANShareEnabled turns sharing game builds with other launchers on
+// the local network on or off. While on, updates copy builds from those
+// launchers when they have them, instead of downloading from the CDN. It
+// requires the session nonce.
+func (a *App) SetLANShareEnabled(nonce string, enabled bool) error {
+	if err := a.checkNonce(nonce); err != nil {
+		return err
+	}
+	err := settings.Update("set_lan_share", func(s *settings.Settings) {
+		s.LANShareEnabled = enabled
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("LAN sharing preference changed", "enabled", enabled)
+	if enabled {
+		go a.startLANShare()
+	} else {
+		go a.stopLANShare()
+	}
+	return nil
+}
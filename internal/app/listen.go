@@ -4,26 +4,43 @@ import (
 	"hytale-launcher/internal/update"
 )
 
-// appListen implements the update.Listener interface.
-// It forwards update events and notifications to the frontend via the App's Emit method.
+// appListen implements the update.Listener interface for one channel.
+// It forwards update events and notifications to the frontend via the App's
+// Emit method, tagged with the channel.
 type appListen struct {
+	// channel is the channel whose updater reports to this listener.
+	channel string
+
 	// emit is a function that sends events to the frontend.
 	emit func(name string, args ...any)
+
+	// selected reports whether a channel is the selected one.
+	selected func(channel string) bool
 }
 
 // Event forwards an update event to the frontend.
 // It wraps the event in a slice and emits it with the event name.
 func (l *appListen) Event(event update.Event) {
+	event.Channel = l.channel
 	l.emit(event.Name, event)
 }
 
 // Notify forwards an update notification to the frontend.
 // Notifications are typically used for status updates during downloads/updates.
+// Progress of the selected channel is emitted as "update:status", and of
+// a channel updating in the background as "update:status:<channel>", so
+// the progress of one does not supersede the other.
 func (l *appListen) Notify(notification update.Notification) {
-	l.emit("update:status", notification)
+	notification.Channel = l.channel
+	name := "update:status"
+	if !l.selected(l.channel) {
+		name += ":" + l.channel
+	}
+	l.emit(name, notification)
 }
 
-// newAppListen creates a new appListen instance with the given emit function.
-func newAppListen(emit func(name string, args ...any)) *appListen {
-	return &appListen{emit: emit}
+// newAppListen creates a new appListen instance for a channel with the
+// given emit function.
+func newAppListen(channel string, emit func(name string, args ...any), selected func(string) bool) *appListen {
+	return &appListen{channel: channel, emit: emit, selected: selected}
 }
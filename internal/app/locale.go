@@ -0,0 +1,81 @@
+package app
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+
+	"hytale-launcher/internal/format"
+	"hytale-launcher/internal/i18n"
+	"hytale-launcher/internal/settings"
+)
+
+// applyLocale selects the locale used for formatting and backend messages
+// from the account setting, then the launcher setting, falling back to the
+// system language.
+func (a *App) applyLocale() {
+	tag := format.Detect()
+	if s := settings.Get(); s.Locale != "" {
+		tag = s.Locale
+	}
+	if a.Auth != nil {
+		if acct := a.Auth.GetAccount(); acct != nil && acct.Locale != "" {
+			tag = acct.Locale
+		}
+	}
+	slog.Debug("selected locale",
+		"locale", format.SetLocale(tag),
+		"messages", i18n.SetLocale(tag),
+	)
+}
+
+// GetLocale returns the locale used to format sizes and durations.
+func (a *App) GetLocale() format.Locale {
+	return format.Current()
+}
+
+// GetLocales returns the language tags that can be passed to SetLocale.
+func (a *App) GetLocales() []string {
+	tags := format.Supported()
+	slices.Sort(tags)
+	return tags
+}
+
+// SetLocale sets the language used for backend messages and to format
+// sizes and durations. It is saved to the account if one is logged in,
+// otherwise to the launcher settings. An empty tag follows the system
+// language. Returns the locale selected, which falls back to English for
+// unsupported languages.
+func (a *App) SetLocale(tag string) (format.Locale, error) {
+	if acct := a.Auth.GetAccount(); acct != nil {
+		acct.Locale = tag
+		a.Auth.SaveAccount("set_locale")
+	} else {
+		err := settings.Update("set_locale", func(s *settings.Settings) {
+			s.Locale = tag
+		})
+		if err != nil {
+			return format.Locale{}, err
+		}
+	}
+	a.applyLocale()
+
+	a.Emit("locale:changed", format.Current())
+	return format.Current(), nil
+}
+
+// FormatBytes formats a size in bytes for the selected locale.
+func (a *App) FormatBytes(n int64) string {
+	return format.Bytes(n)
+}
+
+// FormatDuration formats a duration in seconds for the selected locale.
+func (a *App) FormatDuration(seconds int64) string {
+	return format.Duration(time.Duration(seconds) * time.Second)
+}
+
+// FormatETA formats the time left to transfer the remaining bytes at the
+// given speed, or an empty string if the speed is not known.
+func (a *App) FormatETA(remaining, bytesPerSecond int64) string {
+	return format.ETA(remaining, bytesPerSecond)
+}
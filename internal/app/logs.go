@@ -0,0 +1,30 @@
+package app
+
+import (
+	"log/slog"
+
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/logging"
+)
+
+// maxRecentLogs caps the number of entries returned to the log viewer.
+const maxRecentLogs = 5000
+
+// GetRecentLogs returns up to n of the most recent log entries at or above
+// level ("debug", "info", "warn" or "error"), oldest first.
+func (a *App) GetRecentLogs(n int, level string) ([]logging.Entry, error) {
+	if n > maxRecentLogs {
+		n = maxRecentLogs
+	}
+	return logging.Recent(n, logging.ParseLevel(level))
+}
+
+// OpenLogsFolder opens the log directory in the file explorer.
+func (a *App) OpenLogsFolder() error {
+	dir := logging.Dir()
+	if err := ioutil.MkdirAll(dir); err != nil {
+		return err
+	}
+	slog.Info("opening logs directory", "dir", dir)
+	return openPath(dir)
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/mods"
+	"hytale-launcher/internal/settings"
+)
+
+// modIndex returns the mod index client, using the index URL from settings
+// if one is set.
+func (a *App) modIndex() *mods.Index {
+	indexURL := settings.Get().ModIndexURL
+	if indexURL == "" {
+		indexURL = endpoints.ModIndex()
+	}
+	return &mods.Index{URL: indexURL}
+}
+
+// installedGameBuild returns the directory and build of the installed game.
+func (a *App) installedGameBuild() (string, int, error) {
+	gameDep := a.State.GetDependency("game")
+	if gameDep == nil {
+		return "", 0, errors.New("game not installed")
+	}
+	return gameDep.Path, gameDep.Build, nil
+}
+
+// SearchMods returns the first page of mods in the mod index matching the
+// query that are compatible with the installed game build.
+func (a *App) SearchMods(query string) (*mods.SearchResult, error) {
+	return a.SearchModsPage(query, 1)
+}
+
+// SearchModsPage returns a page of mods matching the query, starting at 1.
+func (a *App) SearchModsPage(query string, page int) (*mods.SearchResult, error) {
+	_, build, err := a.installedGameBuild()
+	if err != nil {
+		return nil, err
+	}
+	return a.modIndex().Search(context.Background(), query, page, build)
+}
+
+// GetInstalledMods returns the mods installed from the mod index into the
+// installed game build, keyed by mod ID.
+func (a *App) GetInstalledMods() (map[string]mods.Installed, error) {
+	gameDir, _, err := a.installedGameBuild()
+	if err != nil {
+		return nil, err
+	}
+	return mods.List(gameDir)
+}
+
+// InstallMod installs a version of a mod from the mod index into the
+// installed game build, replacing any other version of it. An empty
+// version selects the newest one compatible with the build. Download
+// progress is emitted as "mods:progress" events.
+func (a *App) InstallMod(id, version string) error {
+	if a.IsGameRunning() {
+		return errGameRunning
+	}
+
+	gameDir, build, err := a.installedGameBuild()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	mod, err := a.modIndex().Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var v *mods.Version
+	if strings.TrimSpace(version) == "" {
+		v = mod.Latest(build)
+		if v == nil {
+			return fmt.Errorf("no version of %s is compatible with this game version", mod.Name)
+		}
+	} else {
+		v = mod.Find(version)
+		if v == nil {
+			return fmt.Errorf("%s has no version %s", mod.Name, version)
+		}
+		if !v.Compatible(build) {
+			return fmt.Errorf("%s %s is not compatible with this game version", mod.Name, version)
+		}
+	}
+
+	_, err = mods.Install(ctx, gameDir, mod, v, func(downloaded, speed int64) {
+		a.Emit("mods:progress", map[string]any{
+			"id":      mod.ID,
+			"version": v.Version,
+			"current": downloaded,
+			"total":   v.Size,
+			"speed":   speed,
+		})
+	})
+	return err
+}
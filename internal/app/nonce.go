@@ -0,0 +1,67 @@
+package app
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+)
+
+// errNonceClaimed is returned when the session nonce is asked for again.
+var errNonceClaimed = errors.New("the session nonce has already been claimed")
+
+// errInvalidNonce is returned by sensitive methods called without the
+// session nonce.
+var errInvalidNonce = errors.New("this action requires the launcher's session nonce")
+
+// newSessionNonce returns a random token for a launcher session.
+func newSessionNonce() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ClaimSessionNonce returns the session nonce, which the frontend passes to
+// sensitive methods such as Logout and ResetLauncher. It is only handed out
+// once per frontend load, to the launcher's own frontend as it starts, so a
+// script in remote content rendered later, such as a news article, cannot
+// call those methods.
+func (a *App) ClaimSessionNonce() (string, error) {
+	a.nonceMu.Lock()
+	defer a.nonceMu.Unlock()
+
+	if a.nonceClaimed {
+		slog.Warn("session nonce requested again")
+		return "", errNonceClaimed
+	}
+	a.nonceClaimed = true
+	return a.sessionNonce, nil
+}
+
+// reissueSessionNonce replaces the session nonce with a new one that can be
+// claimed once, for a frontend that has just loaded, and tells the
+// frontend with a "session:nonce_reissued" event. The previous nonce no
+// longer works.
+func (a *App) reissueSessionNonce() {
+	a.nonceMu.Lock()
+	a.sessionNonce = newSessionNonce()
+	a.nonceClaimed = false
+	a.nonceMu.Unlock()
+
+	slog.Debug("session nonce reissued")
+	a.Emit("session:nonce_reissued")
+}
+
+// checkNonce returns an error unless nonce is the session nonce.
+func (a *App) checkNonce(nonce string) error {
+	a.nonceMu.Lock()
+	current := a.sessionNonce
+	a.nonceMu.Unlock()
+
+	if current == "" || subtle.ConstantTimeCompare([]byte(nonce), []byte(current)) != 1 {
+		slog.Warn("sensitive method called without the session nonce")
+		return errInvalidNonce
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"log/slog"
+
+	"hytale-launcher/internal/i18n"
+	"hytale-launcher/internal/notifications"
+	"hytale-launcher/internal/settings"
+)
+
+// notify shows a system notification with the title and body messages of
+// key, unless the user has turned notifications off. Notifications are
+// shown even while the window is hidden in the tray.
+func (a *App) notify(kind notifications.NotificationType, key string, args ...any) {
+	if settings.Get().NotificationsDisabled {
+		return
+	}
+	notifications.Send(notifications.Notification{
+		Title:   i18n.T(key + ".title"),
+		Message: i18n.T(key+".body", args...),
+		Type:    kind,
+	})
+}
+
+// notifyNewBuild announces a game build found by an update check, once per
+// build.
+func (a *App) notifyNewBuild() {
+	if a.State == nil || a.Updater == nil {
+		return
+	}
+	p := a.Updater.GetPackage("game")
+	if p == nil || p.AvailableUpdate == nil {
+		return
+	}
+
+	build := a.State.Channel + "/" + p.AvailableUpdate.Version
+	if last := a.notifiedBuild.Swap(&build); last != nil && *last == build {
+		return
+	}
+	a.notify(notifications.TypeInfo, "notify.new_build", p.AvailableUpdate.Version, a.State.Channel)
+}
+
+// AreNotificationsEnabled returns true if the launcher shows system
+// notifications.
+func (a *App) AreNotificationsEnabled() bool {
+	return !settings.Get().NotificationsDisabled
+}
+
+// SetNotificationsEnabled turns system notifications on or off.
+func (a *App) SetNotificationsEnabled(enabled bool) error {
+	err := settings.Update("set_notifications", func(s *settings.Settings) {
+		s.NotificationsDisabled = !enabled
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("notification preference changed", "enabled", enabled)
+	return nil
+}
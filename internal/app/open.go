@@ -0,0 +1,130 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/browser"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/logging"
+	"hytale-launcher/internal/portal"
+	"hytale-launcher/internal/sysinfo"
+)
+
+// linkSchemes are the schemes OpenExternal opens. Anything else, such as
+// file: or a scheme registered by another application, could open local
+// content from a link in the news feed.
+var linkSchemes = []string{"https", "http", "mailto"}
+
+// OpenExternal opens a web or mail link in the user's default application.
+// Links with other schemes, or web links without a host or with
+// credentials, are refused. In a Flatpak or Snap, the link is opened
+// through the desktop portal, as the browser cannot be started from
+// inside the sandbox.
+func (a *App) OpenExternal(link string) error {
+	if err := checkLink(link); err != nil {
+		slog.Warn("refused to open link", "link", link, "error", err)
+		return err
+	}
+
+	if usePortal() {
+		err := portal.OpenURI(link)
+		if err == nil {
+			return nil
+		}
+		slog.Warn("unable to open link through the desktop portal", "error", err)
+	}
+	return browser.OpenURL(link)
+}
+
+// checkLink returns an error if a link is not one OpenExternal opens.
+func checkLink(link string) error {
+	u, err := url.Parse(link)
+	if err != nil {
+		return fmt.Errorf("invalid link: %w", err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if !slices.Contains(linkSchemes, scheme) {
+		return fmt.Errorf("links with scheme %q cannot be opened", u.Scheme)
+	}
+	if scheme != "mailto" && (u.Host == "" || u.User != nil) {
+		return errors.New("web links must have a host and no credentials")
+	}
+	return nil
+}
+
+// RevealInFileManager shows a file or directory in the file manager. Only
+// paths in the launcher's storage, its logs and the channels' game and
+// data directories can be revealed. A file is never opened; the directory
+// holding it is shown instead, so a path cannot be used to run a program.
+func (a *App) RevealInFileManager(path string) error {
+	if path == "" || !filepath.IsAbs(path) {
+		return fmt.Errorf("not an absolute path: %s", path)
+	}
+	path = filepath.Clean(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(a.revealRoots(), func(root string) bool {
+		return root != "" && ioutil.IsInside(path, root)
+	}) {
+		slog.Warn("refused to reveal path outside the launcher's directories", "path", path)
+		return fmt.Errorf("%s is not in a launcher directory", path)
+	}
+
+	if !info.IsDir() {
+		path = filepath.Dir(path)
+	}
+	slog.Info("revealing path in file manager", "path", path)
+	return openPath(path)
+}
+
+// revealRoots returns the directories RevealInFileManager may show paths
+// in.
+func (a *App) revealRoots() []string {
+	roots := []string{hytale.StorageDir(), logging.Dir()}
+	for _, channel := range hytale.KnownChannels() {
+		state := a.channelState(channel)
+		roots = append(roots, state.DataDir)
+		if dep := state.GetDependency("game"); dep != nil {
+			roots = append(roots, dep.Path)
+		}
+	}
+	return roots
+}
+
+// openPath shows a directory in the file manager, through the desktop
+// portal when sandboxed.
+func openPath(path string) error {
+	if usePortal() {
+		err := portal.OpenPath(path)
+		if err == nil {
+			return nil
+		}
+		slog.Warn("unable to open directory through the desktop portal", "path", path, "error", err)
+	}
+	return browser.OpenFile(path)
+}
+
+// usePortal reports whether links and directories are opened through the
+// desktop portal.
+func usePortal() bool {
+	if sysinfo.CurrentSandbox() == sysinfo.SandboxNone {
+		return false
+	}
+	if !portal.Available() {
+		slog.Warn("running sandboxed without a desktop portal", "sandbox", sysinfo.CurrentSandbox())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"errors"
+	"log/slog"
+
+	"hytale-launcher/internal/appstate"
+)
+
+// channelState returns the state for a channel, reusing the state of its
+// session if it is selected or updating.
+func (a *App) channelState(channel string) *appstate.State {
+	a.channels.mu.Lock()
+	s, ok := a.channels.sessions[channel]
+	a.channels.mu.Unlock()
+	if ok {
+		return s.state
+	}
+	return a.loadEnv(channel)
+}
+
+// PinGameBuild pins a channel to a game build. Update checks for the
+// channel will not offer anything newer until the pin is removed with
+// UnpinGameBuild. Pinning does not downgrade an installation that is
+// already past the pinned build.
+func (a *App) PinGameBuild(channel string, build int) error {
+	if channel == "" {
+		return errors.New("channel is required")
+	}
+	if build < 1 {
+		return errors.New("invalid build number")
+	}
+
+	state := a.channelState(channel)
+	if dep := state.GetDependency("game"); dep != nil && dep.Build > build {
+		slog.Warn("pinned build is older than the installed build",
+			"channel", channel,
+			"pinned_build", build,
+			"installed_build", dep.Build,
+		)
+	}
+
+	slog.Info("pinning game build", "channel", channel, "build", build)
+	state.PinnedBuild = build
+	state.Save("game build pinned")
+
+	a.Emit("channel:pinned", map[string]interface{}{
+		"channel": channel,
+		"build":   build,
+	})
+	return nil
+}
+
+// UnpinGameBuild removes the pinned build for a channel so that update
+// checks offer the newest build again.
+func (a *App) UnpinGameBuild(channel string) error {
+	if channel == "" {
+		return errors.New("channel is required")
+	}
+
+	state := a.channelState(channel)
+	if state.PinnedBuild == 0 {
+		return nil
+	}
+
+	slog.Info("unpinning game build", "channel", channel, "build", state.PinnedBuild)
+	state.PinnedBuild = 0
+	state.Save("game build unpinned")
+
+	a.Emit("channel:pinned", map[string]interface{}{
+		"channel": channel,
+		"build":   0,
+	})
+	return nil
+}
+
+// GetPinnedGameBuild returns the pinned build for a channel, or zero if the
+// channel is not pinned.
+func (a *App) GetPinnedGameBuild(channel string) int {
+	return a.channelState(channel).PinnedBuild
+}
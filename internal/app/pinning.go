@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"hytale-launcher/internal/pinning"
+	"hytale-launcher/internal/settings"
+)
+
+// applyPinning pins the account endpoints' TLS keys, using the last pin
+// configuration fetched if it is still valid, and fetches the current one
+// in the background. It runs before any account request is made.
+func (a *App) applyPinning() {
+	pinning.LoadConfig()
+	pinning.Install()
+	go a.refreshPins()
+}
+
+// refreshPins fetches the signed pin configuration.
+func (a *App) refreshPins() error {
+	if err := pinning.RefreshConfig(context.Background()); err != nil {
+		slog.Warn("unable to refresh pin configuration", "error", err)
+	}
+	return nil
+}
+
+// GetCertificatePinning returns the certificate pinning in effect for the
+// account endpoints.
+func (a *App) GetCertificatePinning() pinning.Status {
+	return pinning.CurrentStatus()
+}
+
+// SetCertificatePinningEnabled turns checking the account endpoints' TLS
+// keys against the pinned keys on or off. Turning it off is meant for
+// networks that intercept TLS on purpose, and requires the session nonce.
+// Open connections are closed, so the change applies to the next request.
+func (a *App) SetCertificatePinningEnabled(nonce string, enabled bool) error {
+	if err := a.checkNonce(nonce); err != nil {
+		return err
+	}
+
+	err := settings.Update("set_certificate_pinning", func(s *settings.Settings) {
+		s.CertificatePinningDisabled = !enabled
+	})
+	if err != nil {
+		return err
+	}
+
+	http.DefaultClient.CloseIdleConnections()
+	if enabled {
+		slog.Info("certificate pinning enabled")
+	} else {
+		slog.Warn("certificate pinning disabled by user")
+	}
+	return nil
+}
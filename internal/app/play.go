@@ -0,0 +1,109 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"hytale-launcher/internal/net"
+	"hytale-launcher/internal/pkg"
+)
+
+// Play policies control what happens to pending updates when the user
+// presses Play. Mandatory updates are applied under every policy.
+const (
+	// PlayAlwaysUpdate applies every pending update before launching.
+	PlayAlwaysUpdate = "always_update"
+
+	// PlayAsk stops and asks the user when a non-mandatory update is pending.
+	PlayAsk = "ask"
+
+	// PlayCurrent launches the installed version when only non-mandatory
+	// updates are pending.
+	PlayCurrent = "play_current"
+)
+
+// defaultPlayPolicy is used when the user has not chosen a policy. Play
+// launches right away, so pressing it always starts the game.
+const defaultPlayPolicy = PlayCurrent
+
+// errUpdatePending is returned by LaunchGame under PlayAsk when an optional
+// update is pending. The frontend receives a "play:update_pending" event
+// and either applies the updates or calls LaunchCurrentVersion.
+var errUpdatePending = errors.New("an update is available")
+
+// GetPlayPolicy returns the user's Play-button policy.
+func (a *App) GetPlayPolicy() string {
+	if acct := a.Auth.GetAccount(); acct != nil && acct.PlayPolicy != "" {
+		return acct.PlayPolicy
+	}
+	return defaultPlayPolicy
+}
+
+// SetPlayPolicy sets the user's Play-button policy.
+func (a *App) SetPlayPolicy(policy string) error {
+	switch policy {
+	case PlayAlwaysUpdate, PlayAsk, PlayCurrent:
+	default:
+		return fmt.Errorf("unknown play policy %q", policy)
+	}
+
+	acct := a.Auth.GetAccount()
+	if acct == nil {
+		return errors.New("no user logged in")
+	}
+
+	acct.PlayPolicy = policy
+	a.Auth.SaveAccount("set_play_policy")
+	return nil
+}
+
+// LaunchCurrentVersion launches the installed version without applying
+// optional updates. Mandatory updates are still applied first.
+func (a *App) LaunchCurrentVersion() error {
+	if a.State != nil && a.State.Health.IsUnhealthy() {
+		return errGameUnhealthy
+	}
+	if err := a.prepareToPlay(PlayCurrent); err != nil {
+		return err
+	}
+	return a.launchGame(false, "")
+}
+
+// prepareToPlay applies pending updates according to the policy before the
+// game is launched. Offline, or when the update check fails, the installed
+// version is launched as is.
+func (a *App) prepareToPlay(policy string) error {
+	if a.Updater == nil || net.Current() == net.ModeOffline {
+		return nil
+	}
+
+	if count := a.CheckForUpdates(false); count <= 0 {
+		return nil
+	}
+
+	pending := a.Updater.PendingInfo()
+	mandatory := hasMandatoryUpdate(pending)
+
+	switch {
+	case mandatory || policy == PlayAlwaysUpdate:
+		slog.Info("applying updates before play", "policy", policy, "mandatory", mandatory)
+		return a.ApplyUpdates()
+	case policy == PlayAsk:
+		a.Emit("play:update_pending", pending)
+		return errUpdatePending
+	default:
+		slog.Info("playing current version with optional updates pending", "count", len(pending))
+		return nil
+	}
+}
+
+// hasMandatoryUpdate returns true if any of the given updates is mandatory.
+func hasMandatoryUpdate(infos []pkg.UpdateInfo) bool {
+	for _, info := range infos {
+		if info.Mandatory {
+			return true
+		}
+	}
+	return false
+}
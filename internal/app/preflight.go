@@ -0,0 +1,41 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"hytale-launcher/internal/preflight"
+)
+
+// RunPreflight runs the pre-launch checks for the current channel without
+// launching, so the frontend can show what would stop the game from
+// starting and offer the fixes.
+func (a *App) RunPreflight() (*preflight.Report, error) {
+	if a.State == nil {
+		return nil, errors.New("no channel selected")
+	}
+	return a.runPreflight(), nil
+}
+
+// runPreflight runs the pre-launch checks for the current channel and
+// emits the report with a "launch:preflight" event.
+func (a *App) runPreflight() *preflight.Report {
+	in := preflight.Input{
+		CustomJava:   a.State.JavaPath != "",
+		JRERange:     a.State.GameJRERange(),
+		Channel:      a.State.Channel,
+		MaxHeapMB:    a.launchOptions().JVM.MaxHeapMB,
+		EULAAccepted: a.HasAcceptedEULA(),
+	}
+	if gameDep := a.State.GetDependency("game"); gameDep != nil {
+		in.GameDir = gameDep.Path
+		in.Build = gameDep.Build
+	}
+	if javaPath, err := a.gameJavaPath(); err == nil {
+		in.JavaPath = javaPath
+	}
+
+	report := preflight.Run(context.Background(), in)
+	a.Emit("launch:preflight", report)
+	return report
+}
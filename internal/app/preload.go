@@ -0,0 +1,44 @@
+package app
+
+import (
+	"errors"
+	"log/slog"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/pkg"
+)
+
+// activatePreloads activates the builds preloaded ahead of their release
+// once the release time has passed, so they can be played without waiting
+// for the next update check. Channels that are updating or being played
+// are left for the next run.
+func (a *App) activatePreloads() error {
+	var errs []error
+	for _, channel := range hytale.KnownChannels() {
+		manifest, err := hytale.LoadBuildManifest("game", channel)
+		if err != nil {
+			continue
+		}
+		if staged := manifest.Staged(); staged == nil || !staged.Released() {
+			continue
+		}
+		if a.isChannelBusy(channel) || a.isGameRunningOn(channel) {
+			continue
+		}
+
+		state := a.channelState(channel)
+		build, err := pkg.ActivateStagedBuild(state)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if build == 0 {
+			continue
+		}
+		state.Save("preload_activated")
+
+		slog.Info("activated preloaded build", "channel", channel, "build", build)
+		a.Emit(a.channelEvent(channel, "game:preload_activated"), build)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/channelinfo"
+)
+
+// errConsentRequired is returned by LaunchGame on a pre-release channel
+// whose consent document has not been accepted.
+var errConsentRequired = errors.New("this channel is a pre-release; accept its consent document before playing")
+
+// PrereleaseConsent is a pre-release channel's consent document and whether
+// the user has accepted its current version.
+type PrereleaseConsent struct {
+	*channelinfo.Consent
+
+	// Accepted is true if the current version has been accepted.
+	Accepted bool `json:"accepted"`
+}
+
+// GetPrereleaseConsent returns the consent document for a pre-release
+// channel. Returns nil if the channel is not a pre-release.
+func (a *App) GetPrereleaseConsent(channel string) (*PrereleaseConsent, error) {
+	if !channelinfo.Get(channel).Prerelease {
+		return nil, nil
+	}
+
+	consent, err := channelinfo.GetConsent(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := false
+	if acct := a.Auth.GetAccount(); acct != nil {
+		accepted = acct.HasConsent(channel, consent.Version)
+	}
+
+	return &PrereleaseConsent{Consent: consent, Accepted: accepted}, nil
+}
+
+// AcceptPrereleaseConsent records that the user accepted the given version
+// of a pre-release channel's consent document, and schedules the feedback
+// prompt for that channel.
+func (a *App) AcceptPrereleaseConsent(channel string, version int) error {
+	acct := a.Auth.GetAccount()
+	if acct == nil {
+		return errors.New("no user logged in")
+	}
+
+	consent, err := channelinfo.GetConsent(channel)
+	if err != nil {
+		return err
+	}
+	if version != consent.Version {
+		return fmt.Errorf("consent document for %s has changed, please review it again", channel)
+	}
+
+	slog.Info("pre-release consent accepted", "channel", channel, "version", version)
+
+	acct.RecordConsent(channel, version)
+	a.Auth.SaveAccount("prerelease_consent")
+
+	state := a.channelState(channel)
+	if state.Feedback == nil {
+		state.Feedback = &appstate.Feedback{PromptAfter: consent.FeedbackAfter}
+		state.Save("prerelease consent accepted")
+	}
+
+	a.Emit("prerelease:consent_accepted", channel)
+	return nil
+}
+
+// hasPrereleaseConsent returns true if the current channel is not a
+// pre-release, or the current version of its consent document has been
+// accepted. If the document cannot be fetched, such as offline, any
+// recorded consent is enough.
+func (a *App) hasPrereleaseConsent() bool {
+	if a.State == nil || !channelinfo.Get(a.State.Channel).Prerelease {
+		return true
+	}
+	acct := a.Auth.GetAccount()
+	if acct == nil {
+		return false
+	}
+
+	version := 0
+	if consent, err := channelinfo.GetConsent(a.State.Channel); err == nil {
+		version = consent.Version
+	} else {
+		slog.Warn("unable to check pre-release consent version", "channel", a.State.Channel, "error", err)
+	}
+	return acct.HasConsent(a.State.Channel, version)
+}
+
+// recordPrereleaseSession counts a completed game session on a pre-release
+// channel and emits "prerelease:feedback" once the feedback prompt is due.
+func (a *App) recordPrereleaseSession() {
+	if a.State == nil || a.State.Feedback == nil {
+		return
+	}
+
+	due := a.State.Feedback.RecordSession()
+	a.State.Save("prerelease session")
+
+	if due {
+		payload := map[string]interface{}{
+			"channel":  a.State.Channel,
+			"sessions": a.State.Feedback.Sessions,
+		}
+		if consent, err := channelinfo.GetConsent(a.State.Channel); err == nil {
+			payload["url"] = consent.FeedbackURL
+		}
+		a.Emit("prerelease:feedback", payload)
+	}
+}
+
+// DismissFeedbackPrompt records that the feedback prompt for the current
+// channel was shown, so it is not shown again.
+func (a *App) DismissFeedbackPrompt() {
+	if a.State == nil || a.State.Feedback == nil {
+		return
+	}
+	a.State.Feedback.Prompted = true
+	a.State.Save("feedback prompt dismissed")
+}
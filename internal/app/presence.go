@@ -0,0 +1,88 @@
+package app
+
+import (
+	"log/slog"
+
+	"hytale-launcher/internal/presence"
+	"hytale-launcher/internal/settings"
+)
+
+// startPresence starts sharing the user's status with their friends if
+// they have allowed it, are logged in and not in safe mode. Changes to
+// friends' presence are emitted as "presence:changed" events.
+func (a *App) startPresence() {
+	a.stopPresence()
+
+	if a.safeMode || !settings.Get().PresenceEnabled {
+		return
+	}
+	client := a.Auth.Client()
+	if client == nil {
+		return
+	}
+
+	p := presence.Start(client, func(changed []presence.Friend) {
+		a.Emit("presence:changed", changed)
+	})
+	if a.IsGameRunning() {
+		p.SetStatus(presence.StatusInGame)
+	}
+
+	a.presenceMu.Lock()
+	a.presence = p
+	a.presenceMu.Unlock()
+}
+
+// stopPresence reports the user as offline and stops following friends.
+func (a *App) stopPresence() {
+	a.presenceMu.Lock()
+	p := a.presence
+	a.presence = nil
+	a.presenceMu.Unlock()
+
+	p.Stop()
+}
+
+// setPresence changes the status shared with friends, if sharing is on.
+func (a *App) setPresence(status presence.Status) {
+	a.presenceMu.Lock()
+	p := a.presence
+	a.presenceMu.Unlock()
+
+	p.SetStatus(status)
+}
+
+// GetFriends returns the presence of the user's friends. It is empty
+// unless the user has turned on sharing their online status.
+func (a *App) GetFriends() []presence.Friend {
+	a.presenceMu.Lock()
+	p := a.presence
+	a.presenceMu.Unlock()
+
+	return p.Friends()
+}
+
+// IsPresenceEnabled returns true if the user shares their online status
+// with their friends.
+func (a *App) IsPresenceEnabled() bool {
+	return settings.Get().PresenceEnabled
+}
+
+// SetPresenceEnabled turns sharing the user's online status, and seeing
+// their friends', on or off.
+func (a *App) SetPresenceEnabled(enabled bool) error {
+	err := settings.Update("set_presence", func(s *settings.Settings) {
+		s.PresenceEnabled = enabled
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("presence preference changed", "enabled", enabled)
+	if enabled {
+		go a.startPresence()
+	} else {
+		go a.stopPresence()
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/getsentry/sentry-go"
@@ -67,9 +68,9 @@ func (a *App) IsUserLoggedIn() bool {
 	return a.Auth.IsLoggedIn()
 }
 
-// LogoutUser logs out the current user.
-func (a *App) LogoutUser() error {
-	return a.Logout()
+// LogoutUser logs out the current user. It requires the session nonce.
+func (a *App) LogoutUser(nonce string) error {
+	return a.Logout(nonce)
 }
 
 // FatalError handles a fatal error by logging it and emitting an event.
@@ -104,6 +105,10 @@ func (a *App) CheckForUpdates(force bool) int {
 		slog.Warn("cannot check for updates: no update environment configured")
 		return -1
 	}
+	if a.State.Revoked {
+		slog.Info("not checking for updates on revoked channel", "channel", a.State.Channel)
+		return 0
+	}
 
 	if force {
 		// Check network connectivity and potentially go online.
@@ -144,26 +149,39 @@ func (a *App) CheckForUpdates(force bool) int {
 func (a *App) CheckNetworkMode(canGoOnline bool, cause string) bool {
 	slog.Debug("checking network mode", "can_go_online", canGoOnline, "cause", cause)
 
-	// Check for connectivity.
-	connected := net.CheckConnectivity()
+	// Check for connectivity, and tell the frontend when something other
+	// than a lost connection keeps the launcher offline.
+	previous := net.LastConnectivity()
+	conn := net.Probe()
+	connected := conn.State == net.StateOnline
+	if conn.State != previous.State {
+		slog.Info("connectivity changed", "state", conn.State)
+		a.Emit("network:connectivity", conn)
+	}
 
 	currentMode := net.Current()
 
 	if connected && canGoOnline && currentMode == net.ModeOffline {
 		// We're offline but have connectivity and permission to go online.
-		a.setNetMode(net.ModeOnline, nil)
+		a.setNetMode(net.ModeOnline)
 		return false
 	}
 
 	if !connected && currentMode == net.ModeOnline {
 		// We were online but lost connectivity.
-		a.setNetMode(net.ModeOffline, nil)
+		a.setNetMode(net.ModeOffline)
 		return true
 	}
 
 	return currentMode == net.ModeOffline
 }
 
+// GetConnectivity returns the result of the last connectivity check,
+// including a message explaining what the user can do when offline.
+func (a *App) GetConnectivity() net.Connectivity {
+	return net.LastConnectivity()
+}
+
 // SetUserProfile changes the current user's active profile.
 // It validates the profile UUID and updates the account state.
 func (a *App) SetUserProfile(uuid string) error {
@@ -200,6 +218,8 @@ func (a *App) SetUserProfile(uuid string) error {
 		return err
 	}
 
+	auth.Audit(auth.AuditProfileSwitch, "set_user_profile", uuid, "")
+
 	// Ensure the current channel is still valid for this profile.
 	a.ensureValidChannel(a.getCurrentChannel())
 
@@ -239,9 +259,9 @@ func (a *App) selectDefaultProfile() {
 	}
 }
 
-// GetUserChannels returns the list of channels available to the current user.
+// userChannels returns the list of channels available to the current user.
 // In offline mode, only channels that are offline-ready are returned.
-func (a *App) GetUserChannels() []string {
+func (a *App) userChannels() []string {
 	if net.Current() == net.ModeOffline {
 		return a.getOfflineChannels()
 	}
@@ -263,6 +283,26 @@ func (a *App) RefreshNewsFeed() error {
 	return nil
 }
 
+// GetAuthAuditLog returns the local log of auth events, oldest first,
+// to help diagnose unexpected logouts.
+func (a *App) GetAuthAuditLog() ([]auth.AuditEntry, error) {
+	entries, err := auth.AuditLog()
+	if entries == nil {
+		entries = []auth.AuditEntry{}
+	}
+	return entries, err
+}
+
+// GetAuthHistory returns the local log of auth events, newest first, so
+// users can spot unexpected logins or profile switches on shared machines.
+// Entries that were edited or removed since being written are marked as
+// tampered.
+func (a *App) GetAuthHistory() ([]auth.AuditEntry, error) {
+	entries, err := a.GetAuthAuditLog()
+	slices.Reverse(entries)
+	return entries, err
+}
+
 // GetAccount returns the current user's account for frontend access.
 func (a *App) GetAccount() *account.Account {
 	return a.Auth.GetAccount()
@@ -273,17 +313,29 @@ func (a *App) IsLoggedIn() bool {
 	return a.Auth.IsLoggedIn()
 }
 
-// Logout logs out the current user and clears their session.
-func (a *App) Logout() error {
+// Logout logs out the current user and clears their session. It requires
+// the session nonce.
+func (a *App) Logout(nonce string) error {
+	if err := a.checkNonce(nonce); err != nil {
+		return err
+	}
+	return a.logout()
+}
+
+// logout logs out the current user and clears their session.
+func (a *App) logout() error {
 	// Clear the update environment.
 	a.SetChannel(nil)
 
-	// Stop the refresh loop.
-	if a.refresher != nil {
-		a.refresher.Stop()
-		a.refresher = nil
+	// Stop the periodic jobs.
+	if a.scheduler != nil {
+		a.scheduler.Stop()
+		a.scheduler = nil
 	}
 
+	// Stop sharing the user's status with their friends.
+	a.stopPresence()
+
 	// Logout from the auth controller.
 	if err := a.Auth.Logout(); err != nil {
 		return err
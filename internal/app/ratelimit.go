@@ -0,0 +1,12 @@
+package app
+
+import "hytale-launcher/internal/api"
+
+// watchRateLimits forwards rate limits imposed by the servers to the
+// frontend as "rate_limited" events, so it can tell the user why requests
+// are delayed.
+func (a *App) watchRateLimits() {
+	api.OnRateLimited(func(rl api.RateLimit) {
+		a.Emit("rate_limited", rl)
+	})
+}
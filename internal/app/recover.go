@@ -0,0 +1,52 @@
+package app
+
+import (
+	"log/slog"
+
+	"hytale-launcher/internal/i18n"
+	"hytale-launcher/internal/reset"
+	"hytale-launcher/internal/service"
+)
+
+// StartupError returns why the backend failed to start, or nil if it
+// started or has not been started yet.
+func (a *App) StartupError() error {
+	if err := a.startupErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// startupFailed is the last-chance handler for a backend that cannot
+// start. It explains the failure in a native dialog, offers to reset the
+// launcher's data, and quits.
+func (a *App) startupFailed(frontend service.Frontend, err error) {
+	a.startupErr.Store(&err)
+
+	confirmed, dialogErr := frontend.Confirm(service.MessageDialog{
+		Title:   i18n.T("dialog.title"),
+		Message: i18n.T("dialog.startup_failed", err),
+	})
+	if dialogErr != nil {
+		slog.Warn("unable to show startup error", "error", dialogErr)
+	}
+
+	if confirmed {
+		backup, err := reset.Everything()
+		if err != nil {
+			slog.Error("unable to reset launcher data", "error", err)
+			frontend.Error(service.MessageDialog{
+				Title:   i18n.T("dialog.title"),
+				Message: i18n.T("dialog.reset_failed", err),
+			})
+		} else {
+			slog.Info("launcher data reset", "backup", backup)
+			frontend.Info(service.MessageDialog{
+				Title:   i18n.T("dialog.title"),
+				Message: i18n.T("dialog.reset_done", backup),
+			})
+		}
+	}
+
+	frontend.Quit()
+}
@@ -0,0 +1,26 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	"hytale-launcher/internal/remoteconfig"
+)
+
+// applyRemoteConfig applies the last remote configuration fetched, so its
+// kill switches hold from startup and while offline, and fetches the
+// current one in the background.
+func (a *App) applyRemoteConfig() {
+	remoteconfig.Load()
+	go func() {
+		if err := remoteconfig.Refresh(context.Background()); err != nil {
+			slog.Warn("unable to refresh remote configuration", "error", err)
+		}
+	}()
+}
+
+// GetRemoteConfig returns the remote configuration in effect, for
+// diagnostics.
+func (a *App) GetRemoteConfig() remoteconfig.Status {
+	return remoteconfig.CurrentStatus()
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"errors"
+	"slices"
+
+	"hytale-launcher/internal/reset"
+)
+
+// ResetLauncher clears the given parts of the launcher's data: "appstate",
+// "settings", "cache", "account", and, only when named, "game_installs" and
+// "saves". Launcher data is moved to a backup directory rather than
+// deleted. The frontend is reloaded afterwards, with a "launcher:reset"
+// event carrying the result. It requires the session nonce.
+func (a *App) ResetLauncher(nonce string, scopes []string) (*reset.Result, error) {
+	if err := a.checkNonce(nonce); err != nil {
+		return nil, err
+	}
+	selected, err := reset.ParseScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+	if len(a.busyChannels()) > 0 {
+		return nil, errors.New("cannot reset the launcher while updating")
+	}
+	if a.IsGameRunning() {
+		return nil, errGameRunning
+	}
+
+	if slices.Contains(selected, reset.Account) && a.Auth.GetAccount() != nil {
+		if err := a.logout(); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := reset.Run(selected)
+
+	// Pick up whatever was reset, even if only part of it was.
+	a.applyEnvironment()
+	a.applyLocale()
+	a.SetChannel(a.getCurrentChannel())
+
+	a.Emit("launcher:reset", result)
+	a.ReloadLauncher("reset")
+	return result, err
+}
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"hytale-launcher/internal/launch"
+	"hytale-launcher/internal/presence"
+)
+
+// errGameRunning is returned by operations that would conflict with a
+// running game, such as updating or launching it a second time.
+var errGameRunning = errors.New("the game is running; close it and try again")
+
+// IsGameRunning returns true while a game process started by the launcher,
+// in this session or a previous one, is running.
+func (a *App) IsGameRunning() bool {
+	a.runningMu.Lock()
+	defer a.runningMu.Unlock()
+	return a.runningGame != nil
+}
+
+// isGameRunningOn returns true while a game started from channel is
+// running.
+func (a *App) isGameRunningOn(channel string) bool {
+	a.runningMu.Lock()
+	defer a.runningMu.Unlock()
+	return a.runningGame != nil && a.runningGame.Channel == channel
+}
+
+// gameStarted records a newly started game process, both in memory and on
+// disk so a restarted launcher can re-attach to it.
+func (a *App) gameStarted(r *launch.Running) {
+	a.runningMu.Lock()
+	a.runningGame = r
+	a.startBreakTimerLocked(r)
+	a.startScreenshotWatchLocked(r)
+	a.runningMu.Unlock()
+
+	if err := r.Save(); err != nil {
+		sentry.CaptureException(err)
+		slog.Warn("unable to record game process", "error", err)
+	}
+	a.setPresence(presence.StatusInGame)
+	go a.updateDiscord()
+	a.Emit("game:started", r)
+}
+
+// gameStopped clears the running game and adds its playtime to the
+// channel it was launched from.
+func (a *App) gameStopped() {
+	a.runningMu.Lock()
+	r := a.runningGame
+	a.runningGame = nil
+	a.stopBreakTimerLocked()
+	a.stopScreenshotWatchLocked()
+	a.runningMu.Unlock()
+
+	launch.ClearRunning()
+	if r == nil {
+		return
+	}
+	a.setPresence(presence.StatusInLauncher)
+	go a.updateDiscord()
+
+	state := a.channelState(r.Channel)
+	state.Playtime += int64(time.Since(r.StartedAt).Seconds())
+	state.Save("game exited")
+	go a.syncCloud("game exited")
+
+	a.Emit("game:exited", r.Channel)
+}
+
+// reattachGame checks for a game process left running by a previous
+// launcher session. If it is still the game, the launcher tracks it as if
+// it had started it; otherwise the stale record is removed.
+func (a *App) reattachGame() {
+	r, err := launch.LoadRunning()
+	if err != nil {
+		slog.Warn("unable to read game process record", "error", err)
+		launch.ClearRunning()
+		return
+	}
+	if r == nil {
+		return
+	}
+
+	if !r.Alive() {
+		slog.Debug("recorded game process is no longer running", "pid", r.PID)
+		launch.ClearRunning()
+		return
+	}
+
+	slog.Info("re-attaching to running game",
+		"pid", r.PID,
+		"channel", r.Channel,
+		"started_at", r.StartedAt,
+	)
+
+	a.runningMu.Lock()
+	a.runningGame = r
+	a.startBreakTimerLocked(r)
+	a.startScreenshotWatchLocked(r)
+	a.runningMu.Unlock()
+	a.Emit("game:attached", r)
+
+	go func() {
+		r.Wait(context.Background())
+		slog.Info("re-attached game exited", "pid", r.PID)
+		a.gameStopped()
+	}()
+}
+
+// GetPlaytime returns the total time played on the current channel, in seconds.
+func (a *App) GetPlaytime() int64 {
+	if a.State == nil {
+		return 0
+	}
+	return a.State.Playtime
+}
@@ -0,0 +1,18 @@
+package app
+
+import "log/slog"
+
+// StartInSafeMode makes the launcher start with only what is needed to
+// log in, update and play: no tray icon, Discord or friends presence,
+// cloud sync, telemetry, link registration or custom backend environment.
+// It is used to recover from a launcher that misbehaves, together with
+// ResetLauncher. It must be called before Startup.
+func (a *App) StartInSafeMode() {
+	slog.Info("starting in safe mode")
+	a.safeMode = true
+}
+
+// IsSafeMode reports whether the launcher was started in safe mode.
+func (a *App) IsSafeMode() bool {
+	return a.safeMode
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"hytale-launcher/internal/download"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/throttle"
+)
+
+// Names of the periodic jobs.
+const (
+	jobUpdates      = "updates"
+	jobNews         = "news"
+	jobEntitlements = "entitlements"
+	jobCacheGC      = "cache_gc"
+	jobPreloads     = "preloads"
+	jobDownloads    = "deferred_downloads"
+	jobPins         = "certificate_pins"
+)
+
+// newScheduler creates the scheduler for the periodic jobs run while a user
+// is logged in. Jobs that download or may reload the launcher wait while
+// the game is running.
+func (a *App) newScheduler() *throttle.Scheduler {
+	return throttle.NewScheduler(
+		throttle.SchedulerOptions{
+			StatePath: hytale.InStorageDir("schedule.json"),
+			Paused:    a.IsGameRunning,
+		},
+		throttle.Job{
+			Name:     jobUpdates,
+			Interval: time.Hour,
+			Jitter:   5 * time.Minute,
+			Pausable: true,
+			Run:      a.refreshUpdates,
+		},
+		throttle.Job{
+			Name:     jobNews,
+			Interval: 30 * time.Minute,
+			Jitter:   2 * time.Minute,
+			Run:      a.refreshNews,
+		},
+		throttle.Job{
+			Name:     jobEntitlements,
+			Interval: 6 * time.Hour,
+			Jitter:   15 * time.Minute,
+			Pausable: true,
+			Run:      a.refreshEntitlements,
+		},
+		throttle.Job{
+			Name:     jobCacheGC,
+			Interval: 24 * time.Hour,
+			Jitter:   time.Hour,
+			Pausable: true,
+			Run:      a.collectCache,
+		},
+		throttle.Job{
+			Name:     jobPreloads,
+			Interval: time.Minute,
+			Pausable: true,
+			Run:      a.activatePreloads,
+		},
+		throttle.Job{
+			Name:     jobDownloads,
+			Interval: 5 * time.Minute,
+			Pausable: true,
+			Run:      a.resumeDeferredDownloads,
+		},
+		throttle.Job{
+			Name:     jobPins,
+			Interval: 24 * time.Hour,
+			Jitter:   time.Hour,
+			Run:      a.refreshPins,
+		},
+	)
+}
+
+// GetBackgroundTasks returns the periodic jobs with when they last ran and
+// are next due, so the user can see why a check has not happened yet.
+// There are none while logged out.
+func (a *App) GetBackgroundTasks() []throttle.JobStatus {
+	if a.scheduler == nil {
+		return []throttle.JobStatus{}
+	}
+	return a.scheduler.Status()
+}
+
+// RunTaskNow runs a periodic job right away, even while the game is
+// running, and restarts its interval.
+func (a *App) RunTaskNow(name string) error {
+	if a.scheduler == nil {
+		return errors.New("background tasks only run while logged in")
+	}
+	if !a.scheduler.RunNow(name) {
+		return fmt.Errorf("unknown background task %q", name)
+	}
+	slog.Info("background task triggered", "task", name)
+	return nil
+}
+
+// refreshUpdates checks for updates without forcing a network request, and
+// downloads them if the launcher is in the tray.
+func (a *App) refreshUpdates() error {
+	if count := a.CheckForUpdates(false); count > 0 {
+		a.Emit("hint:updates_available")
+		a.notifyNewBuild()
+		a.applyUpdatesInBackground()
+	}
+	return nil
+}
+
+// refreshNews fetches the news feed.
+func (a *App) refreshNews() error {
+	if err := a.RefreshNewsFeed(); err != nil {
+		return fmt.Errorf("unable to refresh news feed: %w", err)
+	}
+	return nil
+}
+
+// refreshEntitlements picks up channels granted or revoked since the last
+// refresh.
+func (a *App) refreshEntitlements() error {
+	a.refreshUser(false, "periodic")
+	return nil
+}
+
+// collectCache trims the download cache to its size cap, unless a channel
+// is updating and may be using the cached files.
+func (a *App) collectCache() error {
+	if len(a.busyChannels()) > 0 {
+		return nil
+	}
+	download.TrimCache()
+	return nil
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/launch"
+	"hytale-launcher/internal/screenshots"
+	"hytale-launcher/internal/settings"
+)
+
+// screenshotPollInterval is how often the screenshots directory is checked
+// for new files while the game runs.
+const screenshotPollInterval = 2 * time.Second
+
+// screenshotGameDir returns the build directory of the game installed in a
+// channel.
+func (a *App) screenshotGameDir(channel string) (string, error) {
+	if channel == "" {
+		return "", errors.New("channel is required")
+	}
+	gameDep := a.channelState(channel).GetDependency("game")
+	if gameDep == nil {
+		return "", errors.New("game not installed")
+	}
+	return gameDep.Path, nil
+}
+
+// GetScreenshots returns the screenshots saved by the game in a channel,
+// newest first, with thumbnails. The files indexed are those matching the
+// screenshot glob from settings, relative to the game directory.
+func (a *App) GetScreenshots(channel string) ([]screenshots.Screenshot, error) {
+	gameDir, err := a.screenshotGameDir(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	shots, err := screenshots.Index(gameDir, settings.Get().ScreenshotGlob)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shots {
+		thumb, err := screenshots.Thumbnail(&shots[i])
+		if err != nil {
+			slog.Debug("unable to generate thumbnail", "screenshot", shots[i].Name, "error", err)
+			continue
+		}
+		shots[i].Thumbnail = thumb
+	}
+	return shots, nil
+}
+
+// DeleteScreenshot deletes a screenshot returned by GetScreenshots, by name.
+func (a *App) DeleteScreenshot(channel, name string) error {
+	gameDir, err := a.screenshotGameDir(channel)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("deleting screenshot", "channel", channel, "name", name)
+	if err := screenshots.Delete(gameDir, settings.Get().ScreenshotGlob, name); err != nil {
+		return err
+	}
+
+	a.Emit("screenshots:deleted", map[string]interface{}{
+		"channel": channel,
+		"name":    name,
+	})
+	return nil
+}
+
+// OpenScreenshotFolder opens the game's screenshots directory for a
+// channel in the file explorer.
+func (a *App) OpenScreenshotFolder(channel string) error {
+	gameDir, err := a.screenshotGameDir(channel)
+	if err != nil {
+		return err
+	}
+
+	dir := screenshots.Dir(gameDir, settings.Get().ScreenshotGlob)
+	if err := ioutil.MkdirAll(dir); err != nil {
+		return err
+	}
+	slog.Info("opening screenshots directory", "dir", dir)
+	return openPath(dir)
+}
+
+// startScreenshotWatchLocked watches the running game's directory for new
+// screenshots, emitting each as a "screenshots:added" event. The caller
+// must hold runningMu.
+func (a *App) startScreenshotWatchLocked(r *launch.Running) {
+	a.stopScreenshotWatchLocked()
+
+	gameDir, err := a.screenshotGameDir(r.Channel)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.screenshotWatch = cancel
+
+	glob := settings.Get().ScreenshotGlob
+	go screenshots.Watch(ctx, gameDir, glob, screenshotPollInterval, func(s screenshots.Screenshot) {
+		slog.Debug("new screenshot", "channel", r.Channel, "name", s.Name)
+		a.Emit("screenshots:added", map[string]interface{}{
+			"channel":    r.Channel,
+			"screenshot": s,
+		})
+	})
+}
+
+// stopScreenshotWatchLocked stops watching for new screenshots. The caller
+// must hold runningMu.
+func (a *App) stopScreenshotWatchLocked() {
+	if a.screenshotWatch != nil {
+		a.screenshotWatch()
+		a.screenshotWatch = nil
+	}
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	"hytale-launcher/internal/net"
+	"hytale-launcher/internal/serverlist"
+)
+
+// GetServers returns the servers for the server browser: the public
+// directory and the user's favorites, with the latency to each. When the
+// directory cannot be reached, only the favorites are returned.
+func (a *App) GetServers() []serverlist.Server {
+	if net.Current() == net.ModeOffline {
+		var servers []serverlist.Server
+		for _, f := range serverlist.Favorites() {
+			servers = append(servers, serverlist.Server{
+				Address:  f.Address,
+				Name:     f.Name,
+				Favorite: true,
+				Latency:  -1,
+			})
+		}
+		return servers
+	}
+
+	servers, err := serverlist.List(context.Background())
+	if err != nil {
+		slog.Warn("unable to fetch server directory", "error", err)
+	}
+	return servers
+}
+
+// AddFavoriteServer adds a server address, such as "play.example.com" or
+// "203.0.113.5:5520", to the user's favorites.
+func (a *App) AddFavoriteServer(addr string) error {
+	return serverlist.AddFavorite(addr, "")
+}
+
+// RemoveFavoriteServer removes a server from the user's favorites.
+func (a *App) RemoveFavoriteServer(addr string) error {
+	return serverlist.RemoveFavorite(addr)
+}
@@ -0,0 +1,250 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/launch"
+	"hytale-launcher/internal/launchopts"
+	"hytale-launcher/internal/pkg"
+)
+
+// serverStopTimeout is how long a server is given to shut down after the
+// stop command before it is killed.
+const serverStopTimeout = 30 * time.Second
+
+var (
+	// serversMu protects servers.
+	serversMu sync.Mutex
+
+	// servers holds the running dedicated servers by ID.
+	servers = make(map[string]*launch.Server)
+)
+
+// ServerInfo describes an installed dedicated server. Servers are
+// identified by their channel, as one server is installed per channel.
+type ServerInfo struct {
+	ID      string `json:"id"`
+	Channel string `json:"channel"`
+	Version string `json:"version"`
+	Build   int    `json:"build"`
+	Path    string `json:"path"`
+	Running bool   `json:"running"`
+}
+
+// InstallServer installs or updates the dedicated server for a channel,
+// together with the Java runtime it needs. Progress is emitted as
+// "server:progress" events.
+func (a *App) InstallServer(channel string) error {
+	if !hytale.IsKnownChannel(channel) {
+		return fmt.Errorf("unknown channel %q", channel)
+	}
+	if isServerRunning(channel) {
+		return errors.New("stop the server before updating it")
+	}
+
+	state := a.channelState(channel)
+	ctx := context.Background()
+
+	var updates []pkg.Update
+	javaUpdate, err := pkg.CheckForJavaUpdate(ctx, state, channel, state.GameJRERange())
+	if err != nil {
+		return err
+	}
+	if javaUpdate != nil {
+		updates = append(updates, javaUpdate)
+	}
+	serverUpdate, err := pkg.CheckForServerUpdate(ctx, state, channel, true)
+	if err != nil {
+		return err
+	}
+	if serverUpdate != nil {
+		updates = append(updates, serverUpdate)
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	err = pkg.ApplyUpdates(ctx, state, updates, func(status pkg.UpdateStatus) {
+		a.Emit("server:progress", channel, status)
+	})
+	state.Save("install_server")
+	if err != nil {
+		sentry.CaptureException(err)
+		return fmt.Errorf("unable to install server: %w", err)
+	}
+
+	a.Emit("server:installed", channel)
+	return nil
+}
+
+// ListServers returns the dedicated servers installed for each channel.
+func (a *App) ListServers() []ServerInfo {
+	var list []ServerInfo
+	for _, channel := range hytale.KnownChannels() {
+		dep := a.channelState(channel).GetDependency("server")
+		if dep == nil {
+			continue
+		}
+		list = append(list, ServerInfo{
+			ID:      channel,
+			Channel: channel,
+			Version: dep.Version,
+			Build:   dep.Build,
+			Path:    dep.Path,
+			Running: isServerRunning(channel),
+		})
+	}
+	return list
+}
+
+// StartServer starts the dedicated server with the given ID under the
+// managed Java runtime. Its console output is emitted as "server:log"
+// events, and "server:stopped" is emitted when it exits.
+func (a *App) StartServer(id string) error {
+	if isServerRunning(id) {
+		return errors.New("server is already running")
+	}
+
+	state := a.channelState(id)
+	dep := state.GetDependency("server")
+	if dep == nil {
+		return errors.New("server not installed")
+	}
+
+	jarPath, err := ioutil.FindExecutable(dep.Path, []string{"server.jar", "Server.jar"})
+	if err != nil {
+		return err
+	}
+	if jarPath == "" {
+		return errors.New("server jar not found")
+	}
+
+	javaPath, err := serverJavaPath(state)
+	if err != nil {
+		return err
+	}
+
+	server, err := launch.StartServer(&launch.ServerRequest{
+		JarPath:    jarPath,
+		JavaPath:   javaPath,
+		WorkingDir: dep.Path,
+		JVM:        launchopts.DefaultJVMOptions(),
+		OnOutput: func(stream, line string) {
+			a.Emit("server:log", id, stream, line)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	serversMu.Lock()
+	servers[id] = server
+	serversMu.Unlock()
+
+	a.Emit("server:started", id)
+
+	go func() {
+		err := server.Wait()
+
+		serversMu.Lock()
+		delete(servers, id)
+		serversMu.Unlock()
+
+		var msg string
+		if err != nil {
+			msg = err.Error()
+		}
+		a.Emit("server:stopped", id, msg)
+	}()
+
+	return nil
+}
+
+// SendServerCommand writes a console command to a running server.
+func (a *App) SendServerCommand(id, command string) error {
+	server := runningServer(id)
+	if server == nil {
+		return errors.New("server is not running")
+	}
+	return server.Send(command)
+}
+
+// StopServer shuts down a running server, killing it if it does not stop
+// in time.
+func (a *App) StopServer(id string) error {
+	server := runningServer(id)
+	if server == nil {
+		return nil
+	}
+	return server.Stop(serverStopTimeout)
+}
+
+// stopServers shuts down all running servers, for when the launcher quits.
+func (a *App) stopServers() {
+	serversMu.Lock()
+	running := make([]*launch.Server, 0, len(servers))
+	for _, s := range servers {
+		running = append(running, s)
+	}
+	serversMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, s := range running {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Stop(serverStopTimeout); err != nil {
+				slog.Warn("unable to stop server", "pid", s.PID(), "error", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// runningServer returns the running server with the given ID, or nil.
+func runningServer(id string) *launch.Server {
+	serversMu.Lock()
+	defer serversMu.Unlock()
+	return servers[id]
+}
+
+// isServerRunning returns true if the server with the given ID is running.
+func isServerRunning(id string) bool {
+	return runningServer(id) != nil
+}
+
+// serverJavaPath returns the Java executable for a channel's server: the
+// user's custom runtime if set, otherwise the managed one.
+func serverJavaPath(state *appstate.State) (string, error) {
+	if state.JavaPath != "" {
+		return state.JavaPath, nil
+	}
+
+	jreDep := state.FindJRE(state.GameJRERange())
+	if jreDep == nil {
+		return "", errors.New("java not installed")
+	}
+
+	jreDir := jreDep.Path
+	if jreDir == "" {
+		jreDir = pkg.JavaDir(state.Channel, jreDep.Major)
+	}
+	javaPath, err := ioutil.FindExecutable(jreDir, []string{"java", "java.exe"})
+	if err != nil {
+		return "", err
+	}
+	if javaPath == "" {
+		return "", errors.New("java executable not found")
+	}
+	return javaPath, nil
+}
@@ -0,0 +1,200 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/auth"
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/format"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/net"
+)
+
+// Status is a snapshot of the launcher state, reported by the --status
+// command line mode for scripts and external dashboards.
+type Status struct {
+	Version  string `json:"version"`
+	Release  string `json:"release"`
+	Platform string `json:"platform"`
+	Arch     string `json:"arch"`
+	Offline  bool   `json:"offline"`
+
+	// LoggedIn is true if a saved session was restored.
+	LoggedIn bool `json:"logged_in"`
+
+	// Profile is the name of the selected profile, if logged in.
+	Profile string `json:"profile,omitempty"`
+
+	// SelectedChannel is the channel last selected in the launcher.
+	SelectedChannel string `json:"selected_channel,omitempty"`
+
+	// Channels holds the install state of each channel available to the user.
+	Channels []ChannelStatus `json:"channels"`
+}
+
+// ChannelStatus is the install state of a single channel.
+type ChannelStatus struct {
+	Channel      string `json:"channel"`
+	Installed    bool   `json:"installed"`
+	GameVersion  string `json:"game_version,omitempty"`
+	GameBuild    int    `json:"game_build,omitempty"`
+	JavaVersion  string `json:"java_version,omitempty"`
+	Builds       []int  `json:"builds,omitempty"`
+	PinnedBuild  int    `json:"pinned_build,omitempty"`
+	OfflineReady bool   `json:"offline_ready"`
+	Unhealthy    bool   `json:"unhealthy,omitempty"`
+
+	// Playtime is the total time played on the channel, in seconds.
+	Playtime int64 `json:"playtime,omitempty"`
+
+	// PendingUpdates are the packages of an interrupted update batch that
+	// have not been applied yet.
+	PendingUpdates []string `json:"pending_updates,omitempty"`
+
+	// LatestBuild is the newest build published on the channel, from the
+	// account data. It is not reported by --status.
+	LatestBuild int `json:"latest_build,omitempty"`
+
+	// InstallSize is the size of the installed game in bytes. It is not
+	// reported by --status.
+	InstallSize int64 `json:"install_size,omitempty"`
+
+	// UpdatePending is true if a build newer than the installed one can be
+	// installed, taking a pinned build into account.
+	UpdatePending bool `json:"update_pending,omitempty"`
+}
+
+// CollectStatus reads the launcher state from disk without starting the UI
+// or contacting any server.
+func CollectStatus() (*Status, error) {
+	net.SetMode(net.ModeOffline)
+
+	status := &Status{
+		Version:  build.Version,
+		Release:  build.Release,
+		Platform: build.OS(),
+		Arch:     build.Arch(),
+		Offline:  true,
+		Channels: []ChannelStatus{},
+	}
+
+	ctrl := new(auth.Controller)
+	if err := ctrl.Init(); err != nil {
+		return nil, fmt.Errorf("unable to read account: %w", err)
+	}
+
+	channels := ReleaseChannels()
+	format.SetLocale(format.Detect())
+	if acct := ctrl.GetAccount(); acct != nil {
+		if acct.Locale != "" {
+			format.SetLocale(acct.Locale)
+		}
+		status.LoggedIn = ctrl.IsLoggedIn()
+		if profile := acct.GetCurrentProfile(); profile != nil {
+			status.Profile = profile.Name
+		}
+		if acct.SelectedChannel != nil {
+			status.SelectedChannel = *acct.SelectedChannel
+		}
+		if all := acct.AllChannels(); len(all) > 0 {
+			channels = all
+		}
+	}
+	if status.SelectedChannel != "" && !slices.Contains(channels, status.SelectedChannel) {
+		channels = append(channels, status.SelectedChannel)
+	}
+
+	for _, channel := range channels {
+		cs, err := channelStatus(channel)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read state for channel %s: %w", channel, err)
+		}
+		status.Channels = append(status.Channels, *cs)
+	}
+
+	return status, nil
+}
+
+// channelStatus reads the install state of a channel.
+func channelStatus(channel string) (*ChannelStatus, error) {
+	cs := &ChannelStatus{Channel: channel}
+
+	state, err := appstate.Load(channel)
+	if errors.Is(err, appstate.ErrNotFound) {
+		return cs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cs.OfflineReady = state.OfflineReady
+	cs.PinnedBuild = state.PinnedBuild
+	cs.Unhealthy = state.Health.IsUnhealthy()
+	cs.PendingUpdates = state.UpdateQueue.Remaining()
+	cs.Playtime = state.Playtime
+
+	if game := state.GetDependency("game"); game != nil {
+		cs.Installed = true
+		cs.GameVersion = game.Version
+		cs.GameBuild = game.Build
+	}
+	if jre := state.FindJRE(state.GameJRERange()); jre != nil {
+		cs.JavaVersion = jre.Version
+	}
+
+	if manifest, err := hytale.LoadBuildManifest("game", channel); err == nil {
+		for _, b := range manifest.Builds {
+			cs.Builds = append(cs.Builds, b.Build)
+		}
+	}
+
+	return cs, nil
+}
+
+// WriteStatus collects the launcher status and writes it to w, either as
+// indented JSON or as a short human-readable summary.
+func WriteStatus(w io.Writer, asJSON bool) error {
+	status, err := CollectStatus()
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+
+	fmt.Fprintf(w, "Hytale Launcher %s (%s, %s/%s)\n", status.Version, status.Release, status.Platform, status.Arch)
+	if status.LoggedIn {
+		fmt.Fprintf(w, "Logged in as %s\n", status.Profile)
+	} else {
+		fmt.Fprintln(w, "Not logged in")
+	}
+	for _, cs := range status.Channels {
+		marker := " "
+		if cs.Channel == status.SelectedChannel {
+			marker = "*"
+		}
+		if !cs.Installed {
+			fmt.Fprintf(w, "%s %s: not installed\n", marker, cs.Channel)
+			continue
+		}
+		fmt.Fprintf(w, "%s %s: %s (build %d), java %s", marker, cs.Channel, cs.GameVersion, cs.GameBuild, cs.JavaVersion)
+		if cs.Playtime > 0 {
+			fmt.Fprintf(w, ", played %s", format.Duration(time.Duration(cs.Playtime)*time.Second))
+		}
+		if len(cs.PendingUpdates) > 0 {
+			fmt.Fprintf(w, ", pending: %s", strings.Join(cs.PendingUpdates, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
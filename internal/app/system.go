@@ -0,0 +1,17 @@
+package app
+
+import (
+	"log/slog"
+
+	"hytale-launcher/internal/sysinfo"
+)
+
+// GetSystemInfo returns information about the system the launcher is running on,
+// including the display server and any known-issue warnings for the frontend.
+func (a *App) GetSystemInfo() sysinfo.Info {
+	info := sysinfo.Collect()
+	for _, w := range info.Warnings {
+		slog.Warn("system configuration warning", "code", w.Code)
+	}
+	return info
+}
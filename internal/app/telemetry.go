@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	"hytale-launcher/internal/crashreport"
+	"hytale-launcher/internal/settings"
+	"hytale-launcher/internal/telemetry"
+)
+
+// IsTelemetryEnabled returns true if the user has opted in to sending
+// anonymized launcher metrics.
+func (a *App) IsTelemetryEnabled() bool {
+	return telemetry.Enabled()
+}
+
+// SetTelemetryEnabled opts in to or out of sending anonymized launcher
+// metrics. Opting out discards any metrics not yet sent.
+func (a *App) SetTelemetryEnabled(enabled bool) error {
+	err := settings.Update("set_telemetry", func(s *settings.Settings) {
+		s.TelemetryEnabled = enabled
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("telemetry preference changed", "enabled", enabled)
+	if !enabled {
+		telemetry.Discard()
+		return nil
+	}
+	go telemetry.Flush(context.Background())
+	return nil
+}
+
+// IsCrashReportingEnabled returns true unless the user has turned off
+// sending error reports.
+func (a *App) IsCrashReportingEnabled() bool {
+	return crashreport.Enabled()
+}
+
+// SetCrashReportingEnabled turns sending error reports on or off. Reports
+// are always scrubbed of tokens, user names and account identifiers.
+func (a *App) SetCrashReportingEnabled(enabled bool) error {
+	err := settings.Update("set_crash_reporting", func(s *settings.Settings) {
+		s.CrashReportsDisabled = !enabled
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("crash reporting preference changed", "enabled", enabled)
+	return nil
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"hytale-launcher/internal/notifications"
+	"hytale-launcher/internal/tray"
+)
+
+// Tray menu item IDs.
+const (
+	trayShow         = "show"
+	trayCheckUpdates = "check_updates"
+	trayLaunch       = "launch"
+	trayQuit         = "quit"
+)
+
+// trayItems is the tray menu, in display order.
+var trayItems = []tray.Item{
+	{ID: trayShow, Label: "Open Launcher"},
+	{ID: trayCheckUpdates, Label: "Check for Updates"},
+	{ID: trayLaunch, Label: "Launch Game"},
+	{ID: trayQuit, Label: "Quit"},
+}
+
+// StartInBackground makes the launcher start with its window hidden and
+// only the tray icon shown. It must be called before the app starts.
+func (a *App) StartInBackground() {
+	a.background.Store(true)
+}
+
+// startTray shows the tray icon. If no tray is available and the launcher
+// was started in the background, the window is shown instead so the user
+// is not left without any way to reach it.
+func (a *App) startTray() {
+	t, err := tray.Start("Hytale Launcher", trayItems, tray.Handler{
+		Activate: a.showWindow,
+		Select:   a.handleTrayItem,
+	})
+	if err != nil {
+		if !errors.Is(err, tray.ErrUnsupported) {
+			slog.Warn("unable to start tray icon", "error", err)
+		}
+		if a.background.Load() {
+			a.showWindow()
+		}
+		return
+	}
+	a.tray = t
+}
+
+// handleTrayItem performs the action of a tray menu item.
+func (a *App) handleTrayItem(id string) {
+	slog.Debug("tray item selected", "item", id)
+
+	switch id {
+	case trayShow:
+		a.showWindow()
+	case trayCheckUpdates:
+		if count := a.CheckForUpdates(true); count > 0 {
+			a.Emit("hint:updates_available")
+			a.notifyNewBuild()
+			a.applyUpdatesInBackground()
+		}
+	case trayLaunch:
+		if err := a.LaunchGame(""); err != nil {
+			slog.Error("failed to launch game from tray", "error", err)
+			a.showWindow()
+			a.Emit("game:launch_error", err.Error())
+		}
+	case trayQuit:
+		a.quitting.Store(true)
+		a.frontend.Quit()
+	}
+}
+
+// showWindow brings the launcher window back from the tray.
+func (a *App) showWindow() {
+	a.focusWindow()
+	a.ReloadLauncher("tray_show")
+}
+
+// applyUpdatesInBackground pre-downloads and installs pending updates while
+// the launcher is in the tray. A launcher update, which restarts the
+// launcher, and other blocking updates are left for the user to apply with
+// the window open. Outside the download window, the updates are deferred
+// until it opens.
+func (a *App) applyUpdatesInBackground() {
+	if !a.background.Load() || a.Updater == nil || a.Updater.HasBlockingUpdates() || a.launcherUpdatePending() {
+		return
+	}
+	if !a.inDownloadWindow() {
+		a.deferDownloads()
+		return
+	}
+
+	slog.Info("applying updates in the background")
+	if err := a.ApplyUpdates(); err != nil {
+		slog.Warn("background update failed", "error", err)
+		return
+	}
+	a.notify(notifications.TypeSuccess, "notify.update_ready")
+}
+
+// launcherUpdatePending returns true if an update of the launcher itself
+// is pending on the selected channel.
+func (a *App) launcherUpdatePending() bool {
+	p := a.Updater.GetPackage(launcherComponent)
+	return p != nil && p.AvailableUpdate != nil
+}
+
+// refreshInBackground checks for updates right away when the launcher was
+// started in the tray, such as on login, so they are downloaded before the
+// user opens it rather than at the next scheduled check.
+func (a *App) refreshInBackground() {
+	if !a.background.Load() || a.scheduler == nil {
+		return
+	}
+	a.scheduler.RunNow(jobUpdates)
+}
+
+// IsTrayAvailable returns true if the tray icon is shown, so the launcher
+// can be minimized to the tray.
+func (a *App) IsTrayAvailable() bool {
+	return a.tray != nil
+}
+
+// MinimizeToTray hides the launcher window, leaving only the tray icon.
+// Updates found while hidden are downloaded in the background.
+func (a *App) MinimizeToTray() error {
+	if a.tray == nil {
+		return tray.ErrUnsupported
+	}
+
+	slog.Info("minimizing to tray")
+	a.background.Store(true)
+	a.frontend.Hide()
+	return nil
+}
+
+// BeforeClose is called by Wails when the window is closed. With a tray
+// icon the launcher keeps running in the background instead of exiting.
+func (a *App) BeforeClose(ctx context.Context) bool {
+	if a.tray == nil || a.quitting.Load() {
+		a.tray.Close()
+		a.stopServers()
+		a.stopPresence()
+		a.stopLANShare()
+		a.richPresence.Close()
+		return false
+	}
+
+	a.MinimizeToTray()
+	return true
+}
@@ -3,18 +3,26 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
-	"sync"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 
+	"hytale-launcher/internal/errcode"
+	"hytale-launcher/internal/format"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/installlock"
 	"hytale-launcher/internal/pkg"
+	"hytale-launcher/internal/selfupdate"
+	"hytale-launcher/internal/telemetry"
 	"hytale-launcher/internal/update"
 )
 
-// cancelContext holds the current update cancellation context.
-var cancelContext context.CancelFunc
-var cancelMu sync.Mutex
+// changelogTimeout bounds fetching release notes, so the pending updates
+// are shown without them rather than late when the service is slow.
+const changelogTimeout = 5 * time.Second
 
 // PendingUpdates returns information about pending updates.
 func (a *App) PendingUpdates() []update.Item {
@@ -34,71 +42,178 @@ func (a *App) PendingUpdates() []update.Item {
 	return pending
 }
 
-// ApplyUpdates applies all pending updates.
+// GetPendingUpdates returns details of the updates found by the last
+// CheckForUpdates call, in the order they will be applied, so the user
+// can see what will be installed before confirming.
+func (a *App) GetPendingUpdates() []pkg.UpdateInfo {
+	if a.Updater == nil {
+		return []pkg.UpdateInfo{}
+	}
+
+	infos := a.Updater.PendingInfo()
+	if infos == nil {
+		return []pkg.UpdateInfo{}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), changelogTimeout)
+	defer cancel()
+
+	for i := range infos {
+		if infos[i].Size > 0 {
+			infos[i].SizeText = format.Bytes(infos[i].Size)
+		}
+		if infos[i].Type != pkg.UpdateTypeGame || a.State == nil {
+			continue
+		}
+		changelog, err := pkg.GetChangelog(ctx, a.State.Channel, infos[i].TargetBuild)
+		if err != nil {
+			slog.Warn("unable to fetch changelog", "build", infos[i].TargetBuild, "error", err)
+			continue
+		}
+		infos[i].Changelog = changelog
+	}
+
+	return infos
+}
+
+// GetChangelog returns the release notes for a game build on the current channel.
+func (a *App) GetChangelog(build int) (*pkg.Changelog, error) {
+	if a.State == nil {
+		return nil, errors.New("no channel selected")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), changelogTimeout)
+	defer cancel()
+	return pkg.GetChangelog(ctx, a.State.Channel, build)
+}
+
+// ApplyUpdates applies all pending updates to the selected channel.
 func (a *App) ApplyUpdates() error {
 	if a.Updater == nil || a.State == nil {
 		return nil
 	}
+	return a.applyChannelUpdates(a.openChannel(a.State.Channel))
+}
 
-	if a.isUpdating() {
-		slog.Warn("update already in progress")
+// UpdateChannel checks for and applies updates to a channel other than the
+// selected one, in the background, so it can update while the selected
+// channel is played. Its progress is emitted as "update:status:<channel>"
+// and its outcome as "update:complete:<channel>", "update:error:<channel>"
+// or "update:cancelled:<channel>". The selected channel is updated as by
+// ApplyUpdates.
+func (a *App) UpdateChannel(channel string) error {
+	if !hytale.IsKnownChannel(channel) {
+		return fmt.Errorf("unknown channel %q", channel)
+	}
+	if a.isSelectedChannel(channel) {
+		return a.ApplyUpdates()
+	}
+
+	s := a.openChannel(channel)
+	defer a.closeChannel(channel)
+
+	if s.state.Revoked {
+		return errChannelRevoked
+	}
+	count, err := s.updater.CheckForUpdates(s.state, a.Auth)
+	if err != nil {
+		sentry.CaptureException(err)
+		slog.Error("error checking for updates", "channel", channel, "error", err)
+		return err
+	}
+	if count == 0 {
+		slog.Info("channel is up to date", "channel", channel)
+		a.Emit(a.channelEvent(channel, "update:complete"))
 		return nil
 	}
+	return a.applyChannelUpdates(s)
+}
 
-	a.markAsUpdating(true)
-	defer a.markAsUpdating(false)
+// applyChannelUpdates applies the pending updates of a channel's session.
+// Only a game running from the same channel blocks the update.
+func (a *App) applyChannelUpdates(s *channelSession) error {
+	channel := s.state.Channel
+	if a.isGameRunningOn(channel) {
+		return errGameRunning
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	cancelMu.Lock()
-	cancelContext = cancel
-	cancelMu.Unlock()
-
-	defer func() {
-		cancelMu.Lock()
-		cancelContext = nil
-		cancelMu.Unlock()
-	}()
+	if !a.beginWork(channel, cancel) {
+		slog.Warn("update already in progress", "channel", channel)
+		return nil
+	}
+	defer a.endWork(channel)
 
-	slog.Info("applying updates")
+	slog.Info("applying updates", "channel", channel)
 
 	// Apply updates through the updater
-	if err := a.Updater.ApplyUpdates(a.State); err != nil {
+	results, err := s.updater.ApplyUpdates(s.state)
+	a.recordUpdate(channel, results, err)
+	if len(results) > 0 {
+		a.Emit(a.channelEvent(channel, "update:results"), results)
+	}
+	if err != nil {
+		if errors.Is(err, installlock.ErrBusy) {
+			slog.Warn("installation busy, updates not applied", "channel", channel)
+			a.Emit(a.channelEvent(channel, "install:busy"))
+			return err
+		}
 		sentry.CaptureException(err)
-		slog.Error("failed to apply updates", "error", err)
-		a.Emit("update:error", err.Error())
+		slog.Error("failed to apply updates", "channel", channel, "error", err)
+		a.Emit(a.channelEvent(channel, "update:error"), err.Error(), errcode.Describe(err))
 		return err
 	}
 
 	// Check if context was cancelled
 	select {
 	case <-ctx.Done():
-		slog.Info("update cancelled")
-		a.Emit("update:cancelled")
+		slog.Info("update cancelled", "channel", channel)
+		a.Emit(a.channelEvent(channel, "update:cancelled"))
 		return ctx.Err()
 	default:
 	}
 
-	slog.Info("updates applied successfully")
-	a.Emit("update:complete")
+	slog.Info("updates applied successfully", "channel", channel)
+	go a.recordIntegrity(s.state)
+	a.Emit(a.channelEvent(channel, "update:complete"))
 	return nil
 }
 
-// CancelUpdates cancels any in-progress updates.
+// CancelUpdates cancels the update in progress on the selected channel.
 func (a *App) CancelUpdates() error {
-	slog.Info("cancelling updates")
-
-	cancelMu.Lock()
-	if cancelContext != nil {
-		cancelContext()
+	if a.State == nil {
+		return nil
 	}
-	cancelMu.Unlock()
+	return a.CancelChannelUpdates(a.State.Channel)
+}
 
-	a.cancelUpdating()
-	a.Emit("update:cancelled")
+// CancelChannelUpdates cancels the update in progress on a channel.
+func (a *App) CancelChannelUpdates(channel string) error {
+	a.cancelChannel(channel)
+	a.Emit(a.channelEvent(channel, "update:cancelled"))
 	return nil
 }
 
+// finishSelfUpdate removes the files the previous launcher version left
+// after a self-update, and records the upgrade.
+func finishSelfUpdate() {
+	upgrade, err := selfupdate.CleanupOldLauncher()
+	if err != nil {
+		slog.Warn("unable to clean up after launcher update", "error", err)
+	}
+	if upgrade == nil {
+		return
+	}
+
+	slog.Info("launcher updated",
+		"channel", upgrade.Channel,
+		"from", upgrade.FromVersion,
+		"to", upgrade.ToVersion,
+	)
+	telemetry.LauncherUpdated(upgrade.FromVersion, upgrade.ToVersion)
+}
+
 // CheckForFreestandingLauncherUpdate checks for launcher updates outside of the normal flow.
 func (a *App) CheckForFreestandingLauncherUpdate() (bool, error) {
 	slog.Debug("checking for freestanding launcher update")
@@ -0,0 +1,25 @@
+package appstate
+
+import "strings"
+
+// contentPrefix prefixes the dependency identifiers of optional content
+// packs, so each pack's version is tracked on its own.
+const contentPrefix = "content:"
+
+// ContentIdentifier returns the dependency identifier of an optional
+// content pack.
+func ContentIdentifier(id string) string {
+	return contentPrefix + id
+}
+
+// InstalledContent returns the installed optional content packs, keyed by
+// pack ID.
+func (s *State) InstalledContent() map[string]*Dep {
+	content := make(map[string]*Dep)
+	for identifier := range s.Dependencies {
+		if id, ok := strings.CutPrefix(identifier, contentPrefix); ok {
+			content[id] = s.GetDependency(identifier)
+		}
+	}
+	return content
+}
@@ -0,0 +1,24 @@
+package appstate
+
+// Feedback tracks game sessions on a pre-release channel so the user can be
+// asked for feedback once they have played it for a while.
+type Feedback struct {
+	// Sessions is the number of game sessions played on the channel.
+	Sessions int `json:"sessions"`
+
+	// PromptAfter is the number of sessions after which to ask for feedback.
+	PromptAfter int `json:"prompt_after"`
+
+	// Prompted is set once the feedback prompt has been shown.
+	Prompted bool `json:"prompted,omitempty"`
+}
+
+// RecordSession counts a game session and returns true if the feedback
+// prompt is now due.
+func (f *Feedback) RecordSession() bool {
+	if f == nil {
+		return false
+	}
+	f.Sessions++
+	return !f.Prompted && f.PromptAfter > 0 && f.Sessions >= f.PromptAfter
+}
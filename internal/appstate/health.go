@@ -0,0 +1,75 @@
+package appstate
+
+import "time"
+
+// Crash-loop detection thresholds.
+const (
+	// RapidExitThreshold is how soon after launch a failed exit counts as
+	// a rapid exit rather than an ordinary crash.
+	RapidExitThreshold = 10 * time.Second
+
+	// rapidExitWindow is the period in which rapid exits are counted.
+	rapidExitWindow = 10 * time.Minute
+
+	// rapidExitLimit is the number of rapid exits within the window after
+	// which the game is flagged as unhealthy.
+	rapidExitLimit = 3
+)
+
+// ExitRecord describes a game process that exited shortly after launch.
+type ExitRecord struct {
+	// Time is when the process exited.
+	Time time.Time `json:"time"`
+
+	// Runtime is how long the process ran, in milliseconds.
+	Runtime int64 `json:"runtime_ms"`
+
+	// ExitCode is the process exit code, or -1 if it could not be started.
+	ExitCode int `json:"exit_code"`
+
+	// SafeMode is true if the game was launched in safe mode.
+	SafeMode bool `json:"safe_mode,omitempty"`
+}
+
+// Health tracks rapid game exits for a channel so the launcher can stop the
+// user from repeatedly launching a game that crashes on startup.
+type Health struct {
+	// RapidExits are the recent rapid exits, oldest first.
+	RapidExits []ExitRecord `json:"rapid_exits,omitempty"`
+
+	// Unhealthy is set once the game has exited rapidly too many times.
+	// It is cleared by a successful run, a repair, or an explicit reset.
+	Unhealthy bool `json:"unhealthy,omitempty"`
+}
+
+// IsUnhealthy returns true if the game has been flagged as crash-looping.
+func (h *Health) IsUnhealthy() bool {
+	return h != nil && h.Unhealthy
+}
+
+// RecordRapidExit adds a rapid exit, drops records outside the detection
+// window, and flags the game as unhealthy if the limit has been reached.
+// It returns true if this exit caused the game to become unhealthy.
+func (h *Health) RecordRapidExit(rec ExitRecord) bool {
+	cutoff := rec.Time.Add(-rapidExitWindow)
+
+	recent := h.RapidExits[:0]
+	for _, r := range h.RapidExits {
+		if r.Time.After(cutoff) {
+			recent = append(recent, r)
+		}
+	}
+	h.RapidExits = append(recent, rec)
+
+	if h.Unhealthy || len(h.RapidExits) < rapidExitLimit {
+		return false
+	}
+	h.Unhealthy = true
+	return true
+}
+
+// Reset clears the rapid-exit history and the unhealthy flag.
+func (h *Health) Reset() {
+	h.RapidExits = nil
+	h.Unhealthy = false
+}
@@ -0,0 +1,62 @@
+package appstate
+
+// JRERange is an inclusive range of Java major versions. A zero bound is open.
+type JRERange struct {
+	Min int `json:"min,omitempty"`
+	Max int `json:"max,omitempty"`
+}
+
+// IsZero returns true if the range places no requirement on the Java major.
+func (r JRERange) IsZero() bool {
+	return r.Min == 0 && r.Max == 0
+}
+
+// Contains returns true if the given Java major satisfies the range.
+func (r JRERange) Contains(major int) bool {
+	if r.Min > 0 && major < r.Min {
+		return false
+	}
+	if r.Max > 0 && major > r.Max {
+		return false
+	}
+	return true
+}
+
+// Preferred returns the Java major to install for the range: the highest
+// allowed major, or zero if the range is open-ended above and no minimum is set.
+func (r JRERange) Preferred() int {
+	if r.Max > 0 {
+		return r.Max
+	}
+	return r.Min
+}
+
+// FindJRE returns the installed JRE with the highest major that satisfies
+// the range. JREs installed before majors were tracked have a Major of zero
+// and only satisfy an empty range. Returns nil if none match.
+func (s *State) FindJRE(r JRERange) *Dep {
+	var best *Dep
+	for _, dep := range s.getDeps("jre") {
+		if dep.Major == 0 && !r.IsZero() {
+			continue
+		}
+		if !r.Contains(dep.Major) {
+			continue
+		}
+		if best == nil || dep.Major > best.Major {
+			d := dep
+			best = &d
+		}
+	}
+	return best
+}
+
+// GameJRERange returns the Java requirement of the installed game, or an
+// empty range if the game is not installed or has no requirement.
+func (s *State) GameJRERange() JRERange {
+	dep := s.GetDependency("game")
+	if dep == nil || dep.JRE == nil {
+		return JRERange{}
+	}
+	return *dep.JRE
+}
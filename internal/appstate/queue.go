@@ -0,0 +1,61 @@
+package appstate
+
+// UpdateQueue records the progress of a batch of updates, so that a batch
+// interrupted by a crash or a launcher restart can resume at the step that
+// did not complete.
+type UpdateQueue struct {
+	// Steps are the queued updates in the order they are applied.
+	Steps []QueueStep `json:"steps"`
+}
+
+// QueueStep is a single package update within an UpdateQueue.
+type QueueStep struct {
+	// Package is the name of the package being updated.
+	Package string `json:"package"`
+
+	// Version is the version the package is being updated to.
+	Version string `json:"version"`
+
+	// Done is true once the update has been applied successfully.
+	Done bool `json:"done,omitempty"`
+}
+
+// IsDone returns true if the queue contains a completed step for the given
+// package and version.
+func (q *UpdateQueue) IsDone(pkg, version string) bool {
+	if q == nil {
+		return false
+	}
+	for _, step := range q.Steps {
+		if step.Package == pkg && step.Version == version {
+			return step.Done
+		}
+	}
+	return false
+}
+
+// MarkDone marks the step for the given package as completed.
+func (q *UpdateQueue) MarkDone(pkg string) {
+	if q == nil {
+		return
+	}
+	for i := range q.Steps {
+		if q.Steps[i].Package == pkg {
+			q.Steps[i].Done = true
+		}
+	}
+}
+
+// Remaining returns the names of packages whose steps have not completed.
+func (q *UpdateQueue) Remaining() []string {
+	if q == nil {
+		return nil
+	}
+	var remaining []string
+	for _, step := range q.Steps {
+		if !step.Done {
+			remaining = append(remaining, step.Package)
+		}
+	}
+	return remaining
+}
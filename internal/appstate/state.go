@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/launchopts"
 	"hytale-launcher/internal/logging"
 )
 
@@ -18,6 +19,25 @@ type State struct {
 	Dependencies map[string]map[string]Dep `json:"dependencies,omitempty"`
 	OfflineReady bool                      `json:"offline_ready,omitempty"`
 	DataDir      string                    `json:"data_dir,omitempty"`
+	UpdateQueue  *UpdateQueue              `json:"update_queue,omitempty"`
+	JVM          *launchopts.JVMOptions    `json:"jvm,omitempty"`
+	CustomArgs   *launchopts.CustomArgs    `json:"custom_args,omitempty"`
+	Health       *Health                   `json:"health,omitempty"`
+	PinnedBuild  int                       `json:"pinned_build,omitempty"`
+
+	// JavaPath is a user-specified Java executable used instead of the
+	// bundled runtime. When set, the bundled runtime is not downloaded.
+	JavaPath string `json:"java_path,omitempty"`
+
+	// Playtime is the total time the game has run on this channel, in seconds.
+	Playtime int64 `json:"playtime,omitempty"`
+
+	// Feedback tracks sessions for the pre-release feedback prompt.
+	Feedback *Feedback `json:"feedback,omitempty"`
+
+	// Revoked is set when the account lost access to the channel. Its
+	// install is kept, but is not updated or played until access returns.
+	Revoked bool `json:"revoked,omitempty"`
 }
 
 // Dep represents a dependency with version, path, and signature information.
@@ -29,6 +49,16 @@ type Dep struct {
 	Path    string `json:"path,omitempty"`
 	SigDir  string `json:"sig_dir,omitempty"`
 	SigFile string `json:"sig_file,omitempty"`
+
+	// Major is the Java major version of a JRE dependency.
+	Major int `json:"major,omitempty"`
+
+	// JRE is the range of Java majors a game dependency requires.
+	JRE *JRERange `json:"jre,omitempty"`
+
+	// Adopted is set for a game imported in place from a directory the
+	// user already had. The launcher never deletes an adopted directory.
+	Adopted bool `json:"adopted,omitempty"`
 }
 
 // Auth represents authentication state for API requests.
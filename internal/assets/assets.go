@@ -0,0 +1,247 @@
+// Package assets serves frontend assets supplied at runtime, such as plugin
+// or remote branding content, to the webview. Only files listed in the
+// source's manifest are served, with the content type the manifest declares,
+// and only after their contents match the manifest's hash.
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Prefix is the URL path under which extension assets are served.
+// A file is requested as /ext/<source>/<path>.
+const Prefix = "/ext/"
+
+// maxAssetSize is the largest asset that will be served.
+const maxAssetSize = 16 << 20
+
+// allowedTypes are the content types extension assets may declare, mapped
+// to the file extensions allowed for each. Script and HTML content is never
+// served.
+var allowedTypes = map[string][]string{
+	"image/png":        {".png"},
+	"image/jpeg":       {".jpg", ".jpeg"},
+	"image/webp":       {".webp"},
+	"image/gif":        {".gif"},
+	"image/svg+xml":    {".svg"},
+	"font/woff2":       {".woff2"},
+	"text/css":         {".css"},
+	"application/json": {".json"},
+}
+
+// contentSecurityPolicy is sent with every asset so that even an SVG or CSS
+// file cannot run script or pull in further content.
+const contentSecurityPolicy = "default-src 'none'; style-src 'unsafe-inline'; img-src 'self' data:; font-src 'self'; sandbox"
+
+// File describes an asset listed in a manifest.
+type File struct {
+	// SHA256 is the hex-encoded SHA-256 of the file contents.
+	SHA256 string `json:"sha256"`
+
+	// Size is the file size in bytes.
+	Size int64 `json:"size"`
+
+	// Type is the content type the file is served with.
+	Type string `json:"type"`
+}
+
+// Manifest lists the assets a source may serve, keyed by slash-separated
+// path relative to the source directory.
+type Manifest struct {
+	Files map[string]File `json:"files"`
+}
+
+// LoadManifest reads a manifest from a JSON file.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid asset manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// source is a directory of assets and the manifest that describes it.
+type source struct {
+	dir      string
+	manifest *Manifest
+}
+
+var (
+	// mu protects sources.
+	mu sync.RWMutex
+
+	// sources are the registered asset sources, keyed by name.
+	sources = make(map[string]*source)
+)
+
+// Register makes the assets in dir available under /ext/<name>/. Every file
+// in the manifest must declare an allowed content type matching its
+// extension; otherwise the source is rejected.
+func Register(name, dir string, manifest *Manifest) error {
+	if name == "" || strings.ContainsAny(name, `/\.`) {
+		return fmt.Errorf("invalid asset source name %q", name)
+	}
+	if manifest == nil {
+		return errors.New("asset manifest is required")
+	}
+
+	for p, f := range manifest.Files {
+		if _, err := cleanPath(p); err != nil {
+			return fmt.Errorf("asset source %s: %w", name, err)
+		}
+		if err := checkType(p, f.Type); err != nil {
+			return fmt.Errorf("asset source %s: %w", name, err)
+		}
+	}
+
+	mu.Lock()
+	sources[name] = &source{dir: dir, manifest: manifest}
+	mu.Unlock()
+
+	slog.Info("registered asset source", "name", name, "files", len(manifest.Files))
+	return nil
+}
+
+// Unregister removes an asset source.
+func Unregister(name string) {
+	mu.Lock()
+	delete(sources, name)
+	mu.Unlock()
+}
+
+// cleanPath validates a manifest or request path and returns it cleaned.
+// Absolute paths, parent references, and hidden files are rejected.
+func cleanPath(p string) (string, error) {
+	if p == "" || strings.Contains(p, `\`) || strings.HasPrefix(p, "/") {
+		return "", fmt.Errorf("invalid asset path %q", p)
+	}
+	clean := path.Clean(p)
+	for _, part := range strings.Split(clean, "/") {
+		if part == ".." || strings.HasPrefix(part, ".") {
+			return "", fmt.Errorf("invalid asset path %q", p)
+		}
+	}
+	return clean, nil
+}
+
+// checkType returns an error if the content type is not allowed or does not
+// match the file's extension.
+func checkType(p, contentType string) error {
+	exts, ok := allowedTypes[contentType]
+	if !ok {
+		return fmt.Errorf("content type %q is not allowed for %s", contentType, p)
+	}
+	ext := strings.ToLower(path.Ext(p))
+	for _, allowed := range exts {
+		if ext == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("extension of %s does not match content type %s", p, contentType)
+}
+
+// Handler returns the HTTP handler for extension assets, for use as the
+// Wails asset server's fallback handler.
+func Handler() http.Handler {
+	return http.HandlerFunc(serve)
+}
+
+// serve serves a single verified asset.
+func serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rest, ok := strings.CutPrefix(r.URL.Path, Prefix)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	name, rel, ok := strings.Cut(rest, "/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	mu.RLock()
+	src := sources[name]
+	mu.RUnlock()
+	if src == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	rel, err := cleanPath(rel)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	file, ok := src.manifest.Files[rel]
+	if !ok {
+		slog.Warn("blocked unlisted asset", "source", name, "path", rel)
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := readVerified(filepath.Join(src.dir, filepath.FromSlash(rel)), file)
+	if err != nil {
+		slog.Warn("blocked unverified asset", "source", name, "path", rel, "error", err)
+		http.Error(w, "asset failed verification", http.StatusForbidden)
+		return
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", file.Type)
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Content-Security-Policy", contentSecurityPolicy)
+	h.Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(data)
+	}
+}
+
+// readVerified reads an asset and checks its size and hash against the
+// manifest. The file is read once, so the bytes served are the bytes checked.
+func readVerified(p string, file File) ([]byte, error) {
+	info, err := os.Lstat(p)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, errors.New("not a regular file")
+	}
+	if info.Size() != file.Size || info.Size() > maxAssetSize {
+		return nil, fmt.Errorf("size %d does not match manifest size %d", info.Size(), file.Size)
+	}
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), file.SHA256) {
+		return nil, errors.New("hash does not match manifest")
+	}
+	return data, nil
+}
@@ -0,0 +1,129 @@
+// Package attest computes an integrity commitment over the game's code
+// files so community servers can check that a client patched through the
+// launcher is unmodified. Attestation is opt-in: nothing is computed or
+// sent unless settings.AttestationEnabled is on.
+//
+// The commitment is a SHA256 hash over the sorted list of critical files
+// and their hashes. It reveals nothing about the files beyond whether they
+// match a known build, so the server needs the official manifest for the
+// build to check it.
+package attest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/repair"
+)
+
+// Version identifies the commitment format, so servers can tell how it
+// was computed.
+const Version = 1
+
+// submitTimeout bounds the request to the attestation endpoint, so a slow
+// server never holds up a launch for long.
+const submitTimeout = 10 * time.Second
+
+// criticalExts are the extensions of files that contain code. Assets and
+// user data are left out: they change legitimately and are large to hash.
+var criticalExts = []string{".jar", ".dll", ".so", ".dylib", ".exe"}
+
+// userDirs are top-level directories in the game directory that hold user
+// content rather than files installed by the launcher.
+var userDirs = []string{"mods", "userdata", "logs", "screenshots", "saves"}
+
+// Attestation is a commitment over a game installation's critical files.
+type Attestation struct {
+	Version int    `json:"version"`
+	Channel string `json:"channel"`
+	Build   int    `json:"build"`
+
+	// Files is the number of files covered.
+	Files int `json:"files"`
+
+	// Commitment is the hex-encoded SHA256 over the file list.
+	Commitment string `json:"commitment"`
+}
+
+// submission is the request body sent to the attestation endpoint.
+type submission struct {
+	Attestation
+	ProfileID string `json:"profile_id,omitempty"`
+}
+
+// response is the attestation endpoint's reply.
+type response struct {
+	// Token is a signed statement of the attestation that the game
+	// presents to servers along with its session token.
+	Token string `json:"token"`
+}
+
+// Create computes the attestation for the game installed in gameDir.
+func Create(gameDir, channel string, build int) (*Attestation, error) {
+	hashes, err := repair.HashFiles(gameDir, IsCritical, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to hash game files: %w", err)
+	}
+	if len(hashes) == 0 {
+		return nil, fmt.Errorf("no game files found in %s", gameDir)
+	}
+
+	return &Attestation{
+		Version:    Version,
+		Channel:    channel,
+		Build:      build,
+		Files:      len(hashes),
+		Commitment: Commit(hashes),
+	}, nil
+}
+
+// Commit computes the commitment over a map of relative paths to hashes.
+// Each file contributes a "path\x00hash\n" line, in path order.
+func Commit(hashes map[string]string) string {
+	paths := make([]string, 0, len(hashes))
+	for p := range hashes {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	h := sha256.New()
+	for _, p := range paths {
+		fmt.Fprintf(h, "%s\x00%s\n", p, hashes[p])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Submit presents an attestation to the attestation endpoint and returns
+// the signed token it issues. The session token authenticates the request.
+func Submit(ctx context.Context, a *Attestation, sessionToken, profileID string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, submitTimeout)
+	defer cancel()
+
+	client := api.New(api.WithToken(sessionToken))
+	resp, err := api.PostJSON[response](ctx, client, endpoints.Attestation(), submission{
+		Attestation: *a,
+		ProfileID:   profileID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to submit attestation: %w", err)
+	}
+	return resp.Token, nil
+}
+
+// IsCritical reports whether a file, given by its slash-separated path
+// relative to the game directory, is covered by the attestation.
+func IsCritical(rel string) bool {
+	top, _, _ := strings.Cut(rel, "/")
+	if slices.Contains(userDirs, strings.ToLower(top)) {
+		return false
+	}
+	return slices.Contains(criticalExts, strings.ToLower(path.Ext(rel)))
+}
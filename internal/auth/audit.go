@@ -0,0 +1,285 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Audit log files in the storage directory. The log is append-only: each
+// entry is a JSON line, and when the file grows past maxAuditBytes it is
+// moved to rotatedAuditFileName and a new one started.
+const (
+	auditFileName        = "auth-audit.log"
+	rotatedAuditFileName = "auth-audit.log.1"
+	maxAuditBytes        = 256 << 10
+
+	// legacyAuditFileName is the capped JSON array written by older
+	// launchers. Its entries are moved into the log when it is first used.
+	legacyAuditFileName = "auth-audit.json"
+)
+
+// Audit event types.
+const (
+	AuditLogin             = "login"
+	AuditRestore           = "restore"
+	AuditRestoreFailed     = "restore_failed"
+	AuditRefresh           = "refresh"
+	AuditRefreshFailed     = "refresh_failed"
+	AuditTokenRevoked      = "token_revoked"
+	AuditLogout            = "logout"
+	AuditProfileSwitch     = "profile_switch"
+	AuditPersistFailed     = "persist_failed"
+	AuditEntitlementChange = "entitlement_change"
+)
+
+// AuditEntry is a single auth event in the local audit log.
+type AuditEntry struct {
+	// Time is when the event happened.
+	Time time.Time `json:"time"`
+
+	// Event is one of the Audit* constants.
+	Event string `json:"event"`
+
+	// Cause is what triggered the event, e.g. "token_changed" or "user".
+	Cause string `json:"cause,omitempty"`
+
+	// Profile is the UUID of the profile involved, if any.
+	Profile string `json:"profile,omitempty"`
+
+	// Detail holds an error message or other context.
+	Detail string `json:"detail,omitempty"`
+
+	// Prev is the hash of the entry written before this one, chaining the
+	// entries so edits and removals can be detected.
+	Prev string `json:"prev,omitempty"`
+
+	// Hash is the SHA-256 of the entry, including Prev.
+	Hash string `json:"hash,omitempty"`
+
+	// Tampered is set on entries read back whose hash does not match, or
+	// that do not follow the entry before them.
+	Tampered bool `json:"tampered,omitempty"`
+}
+
+var (
+	// auditMu protects the audit log files and auditLast.
+	auditMu sync.Mutex
+
+	// auditLast is the hash of the last entry written, or nil until the
+	// log is first read.
+	auditLast *string
+)
+
+// getAuditFilePath returns the path to the audit log file.
+// Returns empty string if storageDir is not set.
+func getAuditFilePath() string {
+	if storageDir == nil {
+		return ""
+	}
+	return filepath.Join(storageDir(), auditFileName)
+}
+
+// Audit appends an event to the local audit log. The log holds no secrets
+// and is stored unencrypted, so it remains readable when the keyring is not.
+// Failures are logged and otherwise ignored.
+func Audit(event, cause, profile, detail string) {
+	entry := AuditEntry{
+		Time:    sys.Clock.Now().UTC(),
+		Event:   event,
+		Cause:   cause,
+		Profile: profile,
+		Detail:  detail,
+	}
+
+	slog.Debug("auth audit", "event", event, "cause", cause)
+
+	path := getAuditFilePath()
+	if path == "" {
+		return
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	if err := openAuditLog(path); err != nil {
+		slog.Warn("unable to read auth audit log", "error", err)
+	}
+	if err := appendAudit(path, entry); err != nil {
+		slog.Warn("unable to write auth audit log", "error", err)
+	}
+}
+
+// AuditLog returns the audit log entries, oldest first. Entries that break
+// the hash chain are marked as tampered.
+func AuditLog() ([]AuditEntry, error) {
+	path := getAuditFilePath()
+	if path == "" {
+		return nil, nil
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	if err := openAuditLog(path); err != nil {
+		return nil, err
+	}
+	entries, err := readAuditLog(path)
+	if err != nil {
+		return nil, err
+	}
+	verifyAudit(entries)
+	return entries, nil
+}
+
+// openAuditLog finds the hash of the last entry written, and moves the
+// entries of a legacy audit file into the log, the first time it is called.
+// Caller must hold auditMu.
+func openAuditLog(path string) error {
+	if auditLast != nil {
+		return nil
+	}
+
+	entries, err := readAuditLog(path)
+	if err != nil {
+		return err
+	}
+	last := ""
+	if len(entries) > 0 {
+		last = entries[len(entries)-1].Hash
+	}
+	auditLast = &last
+
+	legacyPath := filepath.Join(filepath.Dir(path), legacyAuditFileName)
+	data, err := sys.FS.ReadFile(legacyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var legacy []AuditEntry
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		slog.Warn("discarding unreadable legacy auth audit log", "error", err)
+	}
+	for _, e := range legacy {
+		if err := appendAudit(path, e); err != nil {
+			return err
+		}
+	}
+	return sys.FS.Remove(legacyPath)
+}
+
+// appendAudit chains an entry to the last one written and appends it to
+// the log, rotating the log first if it is full. Caller must hold auditMu.
+func appendAudit(path string, e AuditEntry) error {
+	e.Prev, e.Tampered = *auditLast, false
+	e.Hash = auditHash(e)
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if info, err := sys.FS.Stat(path); err == nil && info.Size()+int64(len(line)) > maxAuditBytes {
+		rotated := filepath.Join(filepath.Dir(path), rotatedAuditFileName)
+		if err := sys.FS.Rename(path, rotated); err != nil {
+			return err
+		}
+	}
+
+	f, err := sys.FS.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	auditLast = &e.Hash
+	return nil
+}
+
+// auditHash returns the hash of an entry, covering every field but Hash
+// and Tampered.
+func auditHash(e AuditEntry) string {
+	e.Hash, e.Tampered = "", false
+	data, _ := json.Marshal(e)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyAudit marks the entries whose hash does not match their contents,
+// or that do not follow the entry before them. The first entry may follow
+// one that was rotated out of the log.
+func verifyAudit(entries []AuditEntry) {
+	for i := range entries {
+		e := &entries[i]
+		broken := e.Hash != auditHash(*e)
+		if i > 0 && e.Prev != entries[i-1].Hash {
+			broken = true
+		}
+		e.Tampered = broken
+	}
+}
+
+// RedactedAuditLog returns the audit log with profile identifiers shortened
+// and details removed, suitable for sharing with support.
+func RedactedAuditLog() ([]AuditEntry, error) {
+	entries, err := AuditLog()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		entries[i].Profile = redactID(entries[i].Profile)
+		if entries[i].Detail != "" {
+			entries[i].Detail = "[redacted]"
+		}
+	}
+	return entries, nil
+}
+
+// readAuditLog reads the rotated and current audit log files, oldest
+// entry first. Missing files are not an error; unreadable lines are
+// skipped, which breaks the chain at that point.
+func readAuditLog(path string) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	for _, p := range []string{filepath.Join(filepath.Dir(path), rotatedAuditFileName), path} {
+		data, err := sys.FS.ReadFile(p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for line := range bytes.Lines(data) {
+			var e AuditEntry
+			if err := json.Unmarshal(line, &e); err != nil {
+				continue
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// redactID keeps only the first 8 characters of an identifier.
+func redactID(id string) string {
+	if len(id) <= 8 {
+		return id
+	}
+	return id[:8] + "…"
+}
@@ -5,6 +5,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -17,8 +18,19 @@ import (
 
 	"hytale-launcher/internal/account"
 	"hytale-launcher/internal/crypto"
+	"hytale-launcher/internal/keyring"
+	"hytale-launcher/internal/system"
 )
 
+// ErrAuthExpired is returned by requests made with the session's client
+// once the session can no longer be refreshed, such as after the refresh
+// token was revoked. The user must log in again.
+var ErrAuthExpired = errors.New("session expired, please log in again")
+
+// sys provides the clock, file system and HTTP client used for sessions.
+// Token refreshes are sent through its HTTP client.
+var sys = system.Real()
+
 // storageDir is a function that returns the application storage directory.
 // This should be set by the application during initialization via SetStorageDir.
 var storageDir func() string
@@ -47,6 +59,12 @@ func getAccountFilePath() string {
 	return crypto.DatFile(filepath.Join(storageDir(), "account"))
 }
 
+// AccountFilePath returns the path to the account data file, or an empty
+// string if the storage directory is not set.
+func AccountFilePath() string {
+	return getAccountFilePath()
+}
+
 // Controller manages authentication state and OAuth token lifecycle.
 type Controller struct {
 	// Account holds the current user account data, including tokens and profiles.
@@ -75,16 +93,28 @@ func (c *Controller) Init() error {
 		return nil
 	}
 
+	// If the keyring is unusable the file may be fine; keep it so the
+	// session can be restored once the keyring is available again.
+	if isKeyringError(err) {
+		slog.Warn("unable to read account file, keyring unavailable",
+			"error", err,
+			"file", filePath,
+		)
+		Audit(AuditRestoreFailed, "keyring", "", err.Error())
+		return nil
+	}
+
 	if err != nil {
 		// Log and report the error, but continue with fresh state
 		sentry.CaptureException(err)
+		Audit(AuditRestoreFailed, "invalid_file", "", err.Error())
 		slog.Error("unable to read account file",
 			"error", err,
 			"file", filePath,
 		)
 
 		// Try to remove the corrupted file
-		if removeErr := os.Remove(filePath); removeErr != nil {
+		if removeErr := sys.FS.Remove(filePath); removeErr != nil {
 			sentry.CaptureException(removeErr)
 			slog.Error("failed to remove invalid account file",
 				"file", filePath,
@@ -98,6 +128,7 @@ func (c *Controller) Init() error {
 	// Account file loaded successfully - restore the OAuth session
 	if acct != nil {
 		c.restore(acct)
+		Audit(AuditRestore, "startup", profileID(acct), "")
 	}
 
 	return nil
@@ -161,6 +192,7 @@ func (c *Controller) tokenChanged(newToken *oauth2.Token) {
 		Expiry:    newToken.Expiry,
 	}
 
+	Audit(AuditRefresh, "token_changed", profileID(c.Account), "")
 	c.saveAccountLocked("token_changed")
 }
 
@@ -193,6 +225,12 @@ func (c *Controller) SaveAccount(cause string) {
 	slog.Debug("requesting account save", "cause", cause)
 
 	if err := acct.SaveFile(); err != nil {
+		// Without a keyring the session is kept in memory for this run only.
+		if isKeyringError(err) {
+			slog.Warn("keyring unavailable, session will not be persisted", "error", err)
+			Audit(AuditPersistFailed, cause, profileID(acct), err.Error())
+			return
+		}
 		sentry.CaptureException(err)
 		slog.Error("unable to save account file",
 			"error", err,
@@ -201,6 +239,14 @@ func (c *Controller) SaveAccount(cause string) {
 	}
 }
 
+// isKeyringError returns true if err was caused by the system keyring being
+// locked, unavailable or denied.
+func isKeyringError(err error) bool {
+	return errors.Is(err, keyring.ErrLocked) ||
+		errors.Is(err, keyring.ErrUnavailable) ||
+		errors.Is(err, keyring.ErrDenied)
+}
+
 // saveAccountLocked saves the account without acquiring the lock.
 // Caller must hold c.mu.
 func (c *Controller) saveAccountLocked(cause string) {
@@ -211,6 +257,11 @@ func (c *Controller) saveAccountLocked(cause string) {
 	slog.Debug("requesting account save", "cause", cause)
 
 	if err := c.Account.SaveFile(); err != nil {
+		if isKeyringError(err) {
+			slog.Warn("keyring unavailable, session will not be persisted", "error", err)
+			Audit(AuditPersistFailed, cause, profileID(c.Account), err.Error())
+			return
+		}
 		sentry.CaptureException(err)
 		slog.Error("unable to save account file",
 			"error", err,
@@ -227,12 +278,19 @@ func (c *Controller) SetAccount(acct *account.Account, client *http.Client) {
 	c.client = client
 	c.mu.Unlock()
 
+	Audit(AuditLogin, "account_set", profileID(acct), "")
 	c.SaveAccount("account_set")
 }
 
 // Logout clears the current session and removes the account file.
 func (c *Controller) Logout() error {
 	c.mu.Lock()
+	Audit(AuditLogout, "user", profileID(c.Account), "")
+	if c.Account != nil {
+		if err := c.Account.DeleteRefreshTokens(); err != nil {
+			slog.Warn("unable to remove refresh tokens from keyring", "error", err)
+		}
+	}
 	c.Account = nil
 	c.client = nil
 	c.mu.Unlock()
@@ -242,7 +300,7 @@ func (c *Controller) Logout() error {
 		return nil
 	}
 
-	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+	if err := sys.FS.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
 		return err
 	}
 
@@ -260,6 +318,8 @@ func (c *Controller) IsLoggedIn() bool {
 // newWatchClient creates an HTTP client with an OAuth token source that
 // monitors for token changes and invokes the callback when tokens are refreshed.
 func newWatchClient(ctx context.Context, token *oauth2.Token, onChange func(*oauth2.Token)) *http.Client {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, system.HTTPClient(sys.HTTP))
+
 	var tokenSource oauth2.TokenSource
 
 	// If we have an OAuth config, use it for token refresh capability
@@ -300,6 +360,19 @@ func (s *watchTokenSource) Token() (*oauth2.Token, error) {
 
 	token, err := s.src.Token()
 	if err != nil {
+		// Only record failures reported by the server; network errors
+		// would otherwise be logged for every request made while offline.
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) {
+			event := AuditRefreshFailed
+			if retrieveErr.ErrorCode == "invalid_grant" {
+				event = AuditTokenRevoked
+			}
+			Audit(event, "token_source", "", retrieveErr.ErrorCode)
+			if event == AuditTokenRevoked {
+				return nil, fmt.Errorf("%w: %w", ErrAuthExpired, err)
+			}
+		}
 		return nil, err
 	}
 
@@ -314,6 +387,14 @@ func (s *watchTokenSource) Token() (*oauth2.Token, error) {
 	return token, nil
 }
 
+// profileID returns the UUID of the account's current profile, if any.
+func profileID(acct *account.Account) string {
+	if acct == nil || acct.CurrentProfile == nil {
+		return ""
+	}
+	return acct.CurrentProfile.UUID
+}
+
 // tokenEqual checks if two tokens are equivalent.
 func tokenEqual(a, b *oauth2.Token) bool {
 	if a == nil || b == nil {
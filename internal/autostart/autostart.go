@@ -0,0 +1,26 @@
+// Package autostart starts the launcher when the user logs in to their
+// computer. It is started hidden in the tray, so pending updates are
+// downloaded before the user opens it.
+package autostart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Arg is the command line argument the launcher is started with on login.
+const Arg = "--background"
+
+// executable returns the path of the running launcher, with symbolic links
+// resolved so the entry survives a link being replaced.
+func executable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("unable to locate launcher executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return exe, nil
+}
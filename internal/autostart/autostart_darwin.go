@@ -0,0 +1,90 @@
+//go:build darwin
+
+package autostart
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+)
+
+// agentLabel identifies the launch agent.
+const agentLabel = "com.hypixel.hytale-launcher"
+
+// Supported reports whether the launcher can start on login.
+func Supported() bool {
+	return true
+}
+
+// agentPath returns the path of the launch agent property list.
+func agentPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", agentLabel+".plist"), nil
+}
+
+// Enable starts the running launcher when the user logs in, by installing
+// a launch agent that runs once at load.
+func Enable() error {
+	exe, err := executable()
+	if err != nil {
+		return err
+	}
+	path, err := agentPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+		<string>%s</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>ProcessType</key>
+	<string>Interactive</string>
+</dict>
+</plist>
+`, agentLabel, html.EscapeString(exe), Arg)
+
+	if err := os.WriteFile(path, []byte(plist), 0o644); err != nil {
+		return fmt.Errorf("unable to write launch agent: %w", err)
+	}
+	return nil
+}
+
+// Disable stops the launcher from starting when the user logs in.
+func Disable() error {
+	path, err := agentPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to remove launch agent: %w", err)
+	}
+	return nil
+}
+
+// Enabled reports whether the launcher starts when the user logs in.
+func Enabled() bool {
+	path, err := agentPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,84 @@
+//go:build linux
+
+package autostart
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// desktopFileName is the autostart entry started on login.
+const desktopFileName = "hytale-launcher.desktop"
+
+// Supported reports whether the launcher can start on login.
+func Supported() bool {
+	return true
+}
+
+// entryPath returns the path of the autostart entry, following the XDG
+// autostart specification.
+func entryPath() (string, error) {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "autostart", desktopFileName), nil
+}
+
+// Enable starts the running launcher when the user logs in, by installing
+// a desktop entry in the autostart directory.
+func Enable() error {
+	exe, err := executable()
+	if err != nil {
+		return err
+	}
+	path, err := entryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	entry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=Hytale Launcher
+Exec="%s" %s
+Terminal=false
+NoDisplay=true
+X-GNOME-Autostart-enabled=true
+`, exe, Arg)
+
+	if err := os.WriteFile(path, []byte(entry), 0o644); err != nil {
+		return fmt.Errorf("unable to write autostart entry: %w", err)
+	}
+	return nil
+}
+
+// Disable stops the launcher from starting when the user logs in.
+func Disable() error {
+	path, err := entryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to remove autostart entry: %w", err)
+	}
+	return nil
+}
+
+// Enabled reports whether the launcher starts when the user logs in.
+func Enabled() bool {
+	path, err := entryPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,29 @@
+//go:build !linux && !windows && !darwin
+
+package autostart
+
+import "errors"
+
+// ErrUnsupported is returned when the launcher cannot start on login on
+// this platform.
+var ErrUnsupported = errors.New("starting on login is not supported on this platform")
+
+// Supported reports whether the launcher can start on login.
+func Supported() bool {
+	return false
+}
+
+// Enable returns ErrUnsupported.
+func Enable() error {
+	return ErrUnsupported
+}
+
+// Disable does nothing on this platform.
+func Disable() error {
+	return nil
+}
+
+// Enabled returns false on this platform.
+func Enabled() bool {
+	return false
+}
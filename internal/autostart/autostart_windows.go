@@ -0,0 +1,67 @@
+//go:build windows
+
+package autostart
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// runKey is the registry key of programs started when the user logs in.
+const runKey = `Software\Microsoft\Windows\CurrentVersion\Run`
+
+// valueName is the launcher's value in runKey.
+const valueName = "HytaleLauncher"
+
+// Supported reports whether the launcher can start on login.
+func Supported() bool {
+	return true
+}
+
+// Enable starts the running launcher when the user logs in, by adding it
+// to the current user's Run key.
+func Enable() error {
+	exe, err := executable()
+	if err != nil {
+		return err
+	}
+
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, runKey, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("unable to open run key: %w", err)
+	}
+	defer key.Close()
+
+	return key.SetStringValue(valueName, fmt.Sprintf(`"%s" %s`, exe, Arg))
+}
+
+// Disable stops the launcher from starting when the user logs in.
+func Disable() error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKey, registry.SET_VALUE)
+	if errors.Is(err, registry.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to open run key: %w", err)
+	}
+	defer key.Close()
+
+	if err := key.DeleteValue(valueName); err != nil && !errors.Is(err, registry.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Enabled reports whether the launcher starts when the user logs in.
+func Enabled() bool {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKey, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer key.Close()
+
+	_, _, err = key.GetStringValue(valueName)
+	return err == nil
+}
@@ -30,6 +30,10 @@ type GameInstall struct {
 
 	// HasSignature indicates whether a signature file exists for this install.
 	HasSignature bool
+
+	// Adopted indicates the install was imported in place from a directory
+	// the user owns, which is never deleted.
+	Adopted bool
 }
 
 // Uninstall removes the game installation from disk and cleans up related state.
@@ -40,8 +44,11 @@ func (g *GameInstall) Uninstall(reporter deletex.ProgressReporter, state *appsta
 		"dir", g.Dir,
 	)
 
-	// Delete the installation directory with progress reporting
-	if err := deletex.Dir(g.Dir, reporter); err != nil {
+	// Delete the installation directory with progress reporting. An
+	// adopted directory is only forgotten.
+	if g.Adopted {
+		slog.Info("leaving adopted game install in place", "dir", g.Dir)
+	} else if err := deletex.Dir(g.Dir, reporter); err != nil {
 		slog.Error("failed to uninstall game install",
 			"install", g,
 			"error", err,
@@ -171,6 +178,7 @@ func ScanInstalledGames(detailed bool) []GameInstall {
 					Version:      version,
 					Dir:          installDir,
 					HasSignature: hasSignature,
+					Adopted:      dep.Adopted,
 				}
 
 				slog.Info("found game install", "install", install)
@@ -0,0 +1,254 @@
+// Package channelinfo provides display metadata for release channels, so
+// the UI does not need to hardcode channel names, icons, or badges.
+package channelinfo
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/hytale"
+)
+
+// cacheDuration is the time between metadata refreshes.
+const cacheDuration = 30 * time.Minute
+
+// fetchTimeout bounds fetching the metadata or a consent document.
+const fetchTimeout = 10 * time.Second
+
+// cacheFileName is the file in the storage directory the last fetched
+// metadata is kept in, so channels can be shown offline and before the
+// first fetch completes.
+const cacheFileName = "channels.json"
+
+// Channel stability levels.
+const (
+	StabilityStable       = "stable"
+	StabilityBeta         = "beta"
+	StabilityExperimental = "experimental"
+)
+
+// DefaultChannel is the channel offered when no metadata has been fetched.
+const DefaultChannel = "release"
+
+// Info holds the display metadata for a channel.
+type Info struct {
+	// Channel is the channel identifier, e.g. "release".
+	Channel string `json:"channel"`
+
+	// DisplayName is the human-readable channel name.
+	DisplayName string `json:"display_name"`
+
+	// Description is a short explanation of the channel.
+	Description string `json:"description,omitempty"`
+
+	// IconURL is the URL to the channel's icon.
+	IconURL string `json:"icon_url,omitempty"`
+
+	// SortOrder orders channels in the UI, lowest first.
+	SortOrder int `json:"sort_order"`
+
+	// Badge is an optional label shown next to the channel, e.g. "Experimental".
+	Badge string `json:"badge,omitempty"`
+
+	// BadgeColor is the CSS colour of the badge, e.g. "#e5a00d".
+	BadgeColor string `json:"badge_color,omitempty"`
+
+	// Stability is how stable the channel's builds are, as one of the
+	// Stability constants.
+	Stability string `json:"stability,omitempty"`
+
+	// Prerelease is true for experimental channels that require the user's
+	// consent before use.
+	Prerelease bool `json:"prerelease,omitempty"`
+}
+
+// infoResponse is the JSON structure returned by the channel metadata endpoint.
+type infoResponse struct {
+	Channels []Info `json:"channels"`
+}
+
+var (
+	// mu protects access to the cached metadata.
+	mu sync.RWMutex
+
+	// cached holds the most recently fetched metadata keyed by channel.
+	cached map[string]Info
+
+	// lastFetch is the timestamp of the last successful fetch.
+	lastFetch time.Time
+
+	// diskOnce guards loading the cache file.
+	diskOnce sync.Once
+)
+
+// loadDisk fills the cache from the cache file, if it has not been fetched
+// yet. The caller must hold mu.
+func loadDisk() {
+	if cached != nil {
+		return
+	}
+	data, err := os.ReadFile(hytale.InStorageDir(cacheFileName))
+	if err != nil {
+		return
+	}
+	var resp infoResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		slog.Warn("discarding invalid channel metadata cache", "error", err)
+		return
+	}
+	cached = index(resp.Channels)
+}
+
+// saveDisk writes fetched metadata to the cache file.
+func saveDisk(resp *infoResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	path := hytale.InStorageDir(cacheFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		slog.Warn("unable to cache channel metadata", "error", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		slog.Warn("unable to cache channel metadata", "error", err)
+	}
+}
+
+// index keys channel metadata by channel.
+func index(channels []Info) map[string]Info {
+	infos := make(map[string]Info, len(channels))
+	for _, info := range channels {
+		infos[info.Channel] = info
+	}
+	return infos
+}
+
+// refresh fetches the channel metadata if the cache has expired. On failure
+// the previously cached metadata is kept.
+func refresh() {
+	diskOnce.Do(func() {
+		mu.Lock()
+		loadDisk()
+		mu.Unlock()
+	})
+
+	mu.RLock()
+	fresh := time.Since(lastFetch) < cacheDuration
+	mu.RUnlock()
+	if fresh {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if time.Since(lastFetch) < cacheDuration {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	resp, err := api.Get[infoResponse](ctx, api.Default, endpoints.ChannelInfo(), nil)
+	if err != nil {
+		slog.Warn("failed to fetch channel metadata", "error", err)
+		return
+	}
+
+	cached = index(resp.Channels)
+	lastFetch = time.Now()
+	saveDisk(&resp)
+}
+
+// Get returns the display metadata for a channel. Channels without
+// published metadata fall back to their identifier as the display name.
+func Get(channel string) Info {
+	refresh()
+
+	mu.RLock()
+	info, ok := cached[channel]
+	mu.RUnlock()
+	if ok {
+		return info
+	}
+	return fallback(channel)
+}
+
+// List returns the display metadata for the given channels, ordered by
+// SortOrder and then by channel identifier.
+func List(channels []string) []Info {
+	infos := make([]Info, 0, len(channels))
+	for _, channel := range channels {
+		infos = append(infos, Get(channel))
+	}
+	sortInfos(infos)
+	return infos
+}
+
+// All returns the metadata of every published channel, in display order.
+// It is empty until metadata has been fetched once.
+func All() []Info {
+	refresh()
+
+	mu.RLock()
+	infos := make([]Info, 0, len(cached))
+	for _, info := range cached {
+		infos = append(infos, info)
+	}
+	mu.RUnlock()
+
+	sortInfos(infos)
+	return infos
+}
+
+// Stable returns the published channels that are not pre-releases, in
+// display order, or DefaultChannel if none are known.
+func Stable() []string {
+	var channels []string
+	for _, info := range All() {
+		if !info.Prerelease && (info.Stability == "" || info.Stability == StabilityStable) {
+			channels = append(channels, info.Channel)
+		}
+	}
+	if len(channels) == 0 {
+		return []string{DefaultChannel}
+	}
+	return channels
+}
+
+// sortInfos orders metadata by SortOrder and then by channel identifier.
+func sortInfos(infos []Info) {
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].SortOrder != infos[j].SortOrder {
+			return infos[i].SortOrder < infos[j].SortOrder
+		}
+		return infos[i].Channel < infos[j].Channel
+	})
+}
+
+// fallback returns metadata for a channel that has none published.
+// Unknown channels sort after published ones. A channel is only a
+// pre-release if its metadata says so, so none is treated as one until
+// metadata has been fetched or loaded from the cache.
+func fallback(channel string) Info {
+	name := channel
+	if name != "" {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	return Info{
+		Channel:     channel,
+		DisplayName: name,
+		SortOrder:   1000,
+		Stability:   StabilityStable,
+	}
+}
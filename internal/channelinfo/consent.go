@@ -0,0 +1,80 @@
+package channelinfo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/endpoints"
+)
+
+// defaultFeedbackAfter is the number of sessions after which the feedback
+// prompt is shown when the consent document does not specify one.
+const defaultFeedbackAfter = 5
+
+// Consent is the versioned document a user accepts before using a
+// pre-release channel. It describes the risks of the channel and how data
+// collection differs from the release channel.
+type Consent struct {
+	// Channel is the channel the document applies to.
+	Channel string `json:"channel"`
+
+	// Version is incremented whenever the document changes; users must
+	// accept the new version before continuing to use the channel.
+	Version int `json:"version"`
+
+	// Title is the heading shown above the document.
+	Title string `json:"title"`
+
+	// Body is the document text in markdown.
+	Body string `json:"body"`
+
+	// FeedbackAfter is the number of game sessions on the channel after
+	// which the user is asked for feedback.
+	FeedbackAfter int `json:"feedback_after,omitempty"`
+
+	// FeedbackURL is where the feedback prompt sends the user.
+	FeedbackURL string `json:"feedback_url,omitempty"`
+}
+
+var (
+	// consentMu protects consentCache.
+	consentMu sync.Mutex
+
+	// consentCache holds fetched consent documents keyed by channel.
+	consentCache = make(map[string]*Consent)
+)
+
+// GetConsent returns the consent document for a pre-release channel,
+// fetching it on first use and caching it for the rest of the session.
+func GetConsent(channel string) (*Consent, error) {
+	consentMu.Lock()
+	cached, ok := consentCache[channel]
+	consentMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	consent, err := api.Get[*Consent](ctx, api.Default, endpoints.PrereleaseConsent(channel), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch consent document for %s: %w", channel, err)
+	}
+	if consent == nil {
+		return nil, fmt.Errorf("empty consent document for %s", channel)
+	}
+
+	consent.Channel = channel
+	if consent.FeedbackAfter <= 0 {
+		consent.FeedbackAfter = defaultFeedbackAfter
+	}
+
+	consentMu.Lock()
+	consentCache[channel] = consent
+	consentMu.Unlock()
+
+	return consent, nil
+}
@@ -0,0 +1,416 @@
+// Package cloudsync syncs save backups and launcher settings between
+// machines through remote storage: the account's storage, or a WebDAV or
+// S3 target configured by the user.
+//
+// The target holds an index of the synced items with the hash of each,
+// next to the items themselves. Locally, the hash of each item as of its
+// last sync is recorded, so a sync can tell which side changed. An item
+// changed on both sides is reported as a conflict and left alone until the
+// user picks which copy to keep.
+//
+// Deleting a world locally removes its backup from the target. A backup
+// deleted on the target is uploaded again from a machine that still has
+// the world; saves are never deleted locally by a sync.
+package cloudsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"hytale-launcher/internal/api"
+)
+
+// indexName is the object name of the index on the target.
+const indexName = "index.json"
+
+// Copies of an item that can be kept when resolving a conflict.
+const (
+	KeepLocal  = "local"
+	KeepRemote = "remote"
+)
+
+// Entry describes the synced copy of an item in the target's index.
+type Entry struct {
+	Hash    string    `json:"hash"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+
+	// Device is the machine that uploaded the copy.
+	Device string `json:"device,omitempty"`
+}
+
+// index lists the items on a target, keyed by object name.
+type index struct {
+	Items map[string]Entry `json:"items"`
+}
+
+// Conflict is an item changed both locally and on the target since it was
+// last synced.
+type Conflict struct {
+	Name          string    `json:"name"`
+	LocalModTime  time.Time `json:"local_mod_time"`
+	RemoteModTime time.Time `json:"remote_mod_time"`
+	RemoteDevice  string    `json:"remote_device,omitempty"`
+
+	// Newer is KeepLocal or KeepRemote, whichever copy was modified
+	// last. It is the copy the user is offered to keep.
+	Newer string `json:"newer"`
+}
+
+// Result describes what a sync did.
+type Result struct {
+	Uploaded   []string   `json:"uploaded"`
+	Downloaded []string   `json:"downloaded"`
+	Deleted    []string   `json:"deleted"`
+	Conflicts  []Conflict `json:"conflicts"`
+}
+
+// Syncer syncs the local items with a target.
+type Syncer struct {
+	Target Target
+
+	// SaveDirs maps channels to their saves directories. Saves of other
+	// channels are not synced.
+	SaveDirs map[string]string
+
+	// Device names this machine in the target's index.
+	Device string
+}
+
+// items returns the items to sync, keyed by object name: the settings, the
+// local worlds, and worlds on the target for channels with a saves
+// directory.
+func (s *Syncer) items(idx *index) (map[string]item, error) {
+	items := map[string]item{settingsName: settingsItem{}}
+
+	for channel, dir := range s.SaveDirs {
+		saves, err := localSaves(channel, dir)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list saves of %s: %w", channel, err)
+		}
+		for _, save := range saves {
+			items[save.name()] = save
+		}
+	}
+
+	for name := range idx.Items {
+		if _, ok := items[name]; ok {
+			continue
+		}
+		channel, world, ok := parseSaveName(name)
+		if !ok {
+			continue
+		}
+		if dir, ok := s.SaveDirs[channel]; ok {
+			items[name] = &saveItem{channel: channel, world: world, dir: dir}
+		}
+	}
+	return items, nil
+}
+
+// Sync brings the local items and the target up to date with each other.
+// Items that fail to sync do not stop the others; their errors are
+// returned together.
+func (s *Syncer) Sync(ctx context.Context) (*Result, error) {
+	idx, err := s.fetchIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.items(idx)
+	if err != nil {
+		return nil, err
+	}
+
+	st := loadState(s.Target.ID())
+	result := &Result{}
+	indexChanged := false
+
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		it := items[name]
+		localHash, localTime, err := it.local()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		remote, hasRemote := idx.Items[name]
+		base := st.Base[name]
+
+		switch {
+		case localHash == remote.Hash:
+			st.setBase(name, localHash)
+
+		case !hasRemote:
+			err = s.upload(ctx, it, localHash, localTime, idx)
+			if err == nil {
+				result.Uploaded = append(result.Uploaded, name)
+				indexChanged = true
+				st.setBase(name, localHash)
+			}
+
+		case localHash == "" && base == remote.Hash:
+			err = s.Target.Delete(ctx, name)
+			if err == nil || errors.Is(err, api.ErrNotFound) {
+				err = nil
+				delete(idx.Items, name)
+				result.Deleted = append(result.Deleted, name)
+				indexChanged = true
+				st.setBase(name, "")
+			}
+
+		case localHash == "" || localHash == base:
+			err = s.download(ctx, it, remote)
+			if err == nil {
+				result.Downloaded = append(result.Downloaded, name)
+				st.setBase(name, remote.Hash)
+			}
+
+		case remote.Hash == base:
+			err = s.upload(ctx, it, localHash, localTime, idx)
+			if err == nil {
+				result.Uploaded = append(result.Uploaded, name)
+				indexChanged = true
+				st.setBase(name, localHash)
+			}
+
+		default:
+			c := Conflict{
+				Name:          name,
+				LocalModTime:  localTime,
+				RemoteModTime: remote.ModTime,
+				RemoteDevice:  remote.Device,
+				Newer:         KeepLocal,
+			}
+			if remote.ModTime.After(localTime) {
+				c.Newer = KeepRemote
+			}
+			result.Conflicts = append(result.Conflicts, c)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	if indexChanged {
+		if err := s.putIndex(ctx, idx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := st.save(); err != nil {
+		errs = append(errs, fmt.Errorf("unable to save sync state: %w", err))
+	}
+
+	slog.Info("cloud sync finished",
+		"uploaded", len(result.Uploaded),
+		"downloaded", len(result.Downloaded),
+		"deleted", len(result.Deleted),
+		"conflicts", len(result.Conflicts),
+		"errors", len(errs),
+	)
+	return result, errors.Join(errs...)
+}
+
+// Resolve settles a conflict by keeping the local or the remote copy of an
+// item, overwriting the other.
+func (s *Syncer) Resolve(ctx context.Context, name, keep string) error {
+	if keep != KeepLocal && keep != KeepRemote {
+		return fmt.Errorf("invalid copy to keep %q", keep)
+	}
+
+	idx, err := s.fetchIndex(ctx)
+	if err != nil {
+		return err
+	}
+	items, err := s.items(idx)
+	if err != nil {
+		return err
+	}
+	it, ok := items[name]
+	if !ok {
+		return fmt.Errorf("unknown sync item %s", name)
+	}
+
+	st := loadState(s.Target.ID())
+	slog.Info("resolving cloud sync conflict", "name", name, "keep", keep)
+
+	if keep == KeepLocal {
+		localHash, localTime, err := it.local()
+		if err != nil {
+			return err
+		}
+		if localHash == "" {
+			return fmt.Errorf("%s does not exist locally", name)
+		}
+		if err := s.upload(ctx, it, localHash, localTime, idx); err != nil {
+			return err
+		}
+		if err := s.putIndex(ctx, idx); err != nil {
+			return err
+		}
+		st.setBase(name, localHash)
+	} else {
+		remote, ok := idx.Items[name]
+		if !ok {
+			return fmt.Errorf("%s does not exist on the sync target", name)
+		}
+		if err := s.download(ctx, it, remote); err != nil {
+			return err
+		}
+		st.setBase(name, remote.Hash)
+	}
+	return st.save()
+}
+
+// setBase records the hash an item was last synced at. An empty hash means
+// the item no longer exists on either side.
+func (st *state) setBase(name, hash string) {
+	if hash == "" {
+		delete(st.Base, name)
+		return
+	}
+	st.Base[name] = hash
+}
+
+// fetchIndex downloads the target's index. A target without one is empty.
+func (s *Syncer) fetchIndex(ctx context.Context) (*index, error) {
+	idx := &index{Items: make(map[string]Entry)}
+
+	body, err := s.Target.Get(ctx, indexName)
+	if errors.Is(err, api.ErrNotFound) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch sync index: %w", err)
+	}
+	defer body.Close()
+
+	if err := json.NewDecoder(body).Decode(idx); err != nil {
+		return nil, fmt.Errorf("invalid sync index: %w", err)
+	}
+	if idx.Items == nil {
+		idx.Items = make(map[string]Entry)
+	}
+	return idx, nil
+}
+
+// putIndex uploads the target's index.
+func (s *Syncer) putIndex(ctx context.Context, idx *index) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	f, err := tempWith(data)
+	if err != nil {
+		return err
+	}
+	defer removeTemp(f)
+
+	if err := s.Target.Put(ctx, indexName, f, int64(len(data))); err != nil {
+		return fmt.Errorf("unable to upload sync index: %w", err)
+	}
+	return nil
+}
+
+// upload exports an item to a temporary file, uploads it and records it in
+// the index.
+func (s *Syncer) upload(ctx context.Context, it item, hash string, modTime time.Time, idx *index) error {
+	f, err := os.CreateTemp("", "cloudsync-*"+path.Ext(it.name()))
+	if err != nil {
+		return err
+	}
+	defer removeTemp(f)
+
+	if err := it.export(f); err != nil {
+		return fmt.Errorf("unable to export: %w", err)
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	slog.Debug("uploading sync item", "name", it.name(), "size", size)
+	if err := s.Target.Put(ctx, it.name(), f, size); err != nil {
+		return fmt.Errorf("unable to upload: %w", err)
+	}
+
+	idx.Items[it.name()] = Entry{
+		Hash:    hash,
+		Size:    size,
+		ModTime: modTime,
+		Device:  s.Device,
+	}
+	return nil
+}
+
+// download fetches an item's synced copy to a temporary file and restores
+// the local copy from it.
+func (s *Syncer) download(ctx context.Context, it item, remote Entry) error {
+	body, err := s.Target.Get(ctx, it.name())
+	if err != nil {
+		return fmt.Errorf("unable to download: %w", err)
+	}
+	defer body.Close()
+
+	f, err := os.CreateTemp("", "cloudsync-*"+path.Ext(it.name()))
+	if err != nil {
+		return err
+	}
+	defer removeTemp(f)
+
+	slog.Debug("downloading sync item", "name", it.name(), "size", remote.Size)
+	if _, err := io.Copy(f, body); err != nil {
+		return fmt.Errorf("unable to download: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := it.restore(f.Name()); err != nil {
+		return fmt.Errorf("unable to restore: %w", err)
+	}
+
+	if hash, _, err := it.local(); err == nil && hash != remote.Hash {
+		slog.Warn("restored sync item does not match its index entry", "name", it.name())
+	}
+	return nil
+}
+
+// tempWith returns a temporary file holding data, positioned at its start.
+func tempWith(data []byte) (*os.File, error) {
+	f, err := os.CreateTemp("", "cloudsync-*")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(data); err != nil {
+		removeTemp(f)
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		removeTemp(f)
+		return nil, err
+	}
+	return f, nil
+}
+
+// removeTemp closes and deletes a temporary file.
+func removeTemp(f *os.File) {
+	f.Close()
+	os.Remove(f.Name())
+}
@@ -0,0 +1,274 @@
+package cloudsync
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"hytale-launcher/internal/extract"
+	"hytale-launcher/internal/settings"
+)
+
+// settingsName is the object name of the launcher settings.
+const settingsName = "settings.json"
+
+// savesPrefix is the prefix of save backup object names, which have the
+// form "saves/<channel>/<world>.zip".
+const savesPrefix = "saves/"
+
+// item is a piece of local data that is synced.
+type item interface {
+	// name returns the object name of the item.
+	name() string
+
+	// local returns the content hash and modification time of the local
+	// copy, or an empty hash if there is none.
+	local() (string, time.Time, error)
+
+	// export writes the local copy to w.
+	export(w io.Writer) error
+
+	// restore replaces the local copy with the contents of the file at p.
+	restore(p string) error
+}
+
+// settingsItem is the launcher settings that are not specific to this
+// machine.
+type settingsItem struct{}
+
+func (settingsItem) name() string { return settingsName }
+
+func (settingsItem) local() (string, time.Time, error) {
+	data, err := json.Marshal(settings.Get().Portable())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	sum := sha256.Sum256(data)
+
+	var modTime time.Time
+	if info, err := os.Stat(settings.Path()); err == nil {
+		modTime = info.ModTime()
+	}
+	return hex.EncodeToString(sum[:]), modTime, nil
+}
+
+func (settingsItem) export(w io.Writer) error {
+	data, err := json.Marshal(settings.Get().Portable())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (settingsItem) restore(p string) error {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	var remote settings.Settings
+	if err := json.Unmarshal(data, &remote); err != nil {
+		return fmt.Errorf("invalid synced settings: %w", err)
+	}
+	return settings.Update("cloud_sync", func(s *settings.Settings) {
+		s.MergePortable(remote)
+	})
+}
+
+// saveItem is a world in a channel's saves directory, synced as a zip
+// backup of the directory.
+type saveItem struct {
+	channel string
+	world   string
+	dir     string
+}
+
+// saveName returns the object name of a world's backup.
+func saveName(channel, world string) string {
+	return savesPrefix + channel + "/" + world + ".zip"
+}
+
+// parseSaveName returns the channel and world of a save backup object
+// name.
+func parseSaveName(name string) (channel, world string, ok bool) {
+	rest, ok := strings.CutPrefix(name, savesPrefix)
+	if !ok {
+		return "", "", false
+	}
+	channel, file, ok := strings.Cut(rest, "/")
+	if !ok || channel == "" || strings.Contains(file, "/") {
+		return "", "", false
+	}
+	world, ok = strings.CutSuffix(file, ".zip")
+	if !ok || !validWorld(world) {
+		return "", "", false
+	}
+	return channel, world, true
+}
+
+// validWorld returns true if a world name is safe to use as a directory
+// name.
+func validWorld(world string) bool {
+	return world != "" && !strings.HasPrefix(world, ".") &&
+		!strings.ContainsAny(world, `/\:`) && world == filepath.Base(world)
+}
+
+func (s *saveItem) name() string { return saveName(s.channel, s.world) }
+
+func (s *saveItem) path() string { return filepath.Join(s.dir, s.world) }
+
+// files returns the world's regular files as sorted slash-separated paths.
+func (s *saveItem) files() ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(s.path(), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(s.path(), p)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// local hashes the world's file names and contents, so the hash does not
+// depend on how the backup archive was built.
+func (s *saveItem) local() (string, time.Time, error) {
+	if _, err := os.Stat(s.path()); os.IsNotExist(err) {
+		return "", time.Time{}, nil
+	}
+	files, err := s.files()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	var modTime time.Time
+	h := sha256.New()
+	for _, rel := range files {
+		p := filepath.Join(s.path(), filepath.FromSlash(rel))
+		info, err := os.Stat(p)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		fh := sha256.New()
+		_, err = io.Copy(fh, f)
+		f.Close()
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		fmt.Fprintf(h, "%s\x00%x\n", rel, fh.Sum(nil))
+	}
+	return hex.EncodeToString(h.Sum(nil)), modTime, nil
+}
+
+func (s *saveItem) export(w io.Writer) error {
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, rel := range files {
+		if err := addToZip(zw, filepath.Join(s.path(), filepath.FromSlash(rel)), rel); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// addToZip adds a file to a zip archive under the given name.
+func addToZip(zw *zip.Writer, p, name string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = path.Clean(name)
+	header.Method = zip.Deflate
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// restore extracts the backup next to the world and then swaps it in, so
+// a failed extraction leaves the existing world untouched.
+func (s *saveItem) restore(p string) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	staging := filepath.Join(s.dir, ".sync-"+s.world)
+	if err := extract.Archive(p, staging, nil, nil); err != nil {
+		os.RemoveAll(staging)
+		return fmt.Errorf("unable to extract save %s: %w", s.world, err)
+	}
+
+	old := filepath.Join(s.dir, ".old-"+s.world)
+	os.RemoveAll(old)
+	if err := os.Rename(s.path(), old); err != nil && !os.IsNotExist(err) {
+		os.RemoveAll(staging)
+		return err
+	}
+	if err := os.Rename(staging, s.path()); err != nil {
+		os.Rename(old, s.path())
+		return err
+	}
+	return os.RemoveAll(old)
+}
+
+// localSaves returns the worlds in a channel's saves directory.
+func localSaves(channel, dir string) ([]*saveItem, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*saveItem
+	for _, e := range entries {
+		if e.IsDir() && validWorld(e.Name()) {
+			result = append(result, &saveItem{channel: channel, world: e.Name(), dir: dir})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package cloudsync
+
+import (
+	"encoding/json"
+	"os"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/ioutil"
+)
+
+// stateFileName is the name of the file in the storage directory that
+// records what was last synced.
+const stateFileName = "cloud-sync-state.json"
+
+// state records the hash of each item as of its last successful sync with
+// a target. An item whose local and remote hashes both differ from it was
+// changed on both sides, which is a conflict.
+type state struct {
+	TargetID string            `json:"target_id"`
+	Base     map[string]string `json:"base"`
+}
+
+// loadState reads the sync state for a target. State recorded for another
+// target is discarded.
+func loadState(targetID string) *state {
+	s := &state{TargetID: targetID, Base: make(map[string]string)}
+
+	data, err := os.ReadFile(hytale.InStorageDir(stateFileName))
+	if err != nil {
+		return s
+	}
+	var saved state
+	if json.Unmarshal(data, &saved) != nil || saved.TargetID != targetID || saved.Base == nil {
+		return s
+	}
+	return &saved
+}
+
+// save writes the sync state, replacing the file atomically.
+func (s *state) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.MkdirAll(hytale.StorageDir()); err != nil {
+		return err
+	}
+
+	path := hytale.InStorageDir(stateFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
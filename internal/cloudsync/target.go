@@ -0,0 +1,120 @@
+package cloudsync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/net"
+)
+
+// Target is remote storage that synced data is kept in. Names are
+// slash-separated paths such as "saves/release/world.zip".
+//
+// A missing object is reported as an error matching api.ErrNotFound.
+type Target interface {
+	// ID identifies the storage location, so sync state recorded against
+	// one target is not used for another.
+	ID() string
+
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	Put(ctx context.Context, name string, body io.Reader, size int64) error
+	Delete(ctx context.Context, name string) error
+}
+
+// AccountTarget stores data in the storage that comes with the user's
+// account. The client must be authenticated.
+type AccountTarget struct {
+	Client *http.Client
+}
+
+// ID returns the account storage URL.
+func (t *AccountTarget) ID() string {
+	return endpoints.CloudStorage()
+}
+
+// Get downloads an object.
+func (t *AccountTarget) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	resp, err := do(ctx, t.Client, http.MethodGet, objectURL(endpoints.CloudStorage(), name), nil, -1, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Put uploads an object, replacing any existing one.
+func (t *AccountTarget) Put(ctx context.Context, name string, body io.Reader, size int64) error {
+	return discard(do(ctx, t.Client, http.MethodPut, objectURL(endpoints.CloudStorage(), name), body, size, nil))
+}
+
+// Delete removes an object.
+func (t *AccountTarget) Delete(ctx context.Context, name string) error {
+	return discard(do(ctx, t.Client, http.MethodDelete, objectURL(endpoints.CloudStorage(), name), nil, -1, nil))
+}
+
+// objectURL joins an object name onto a base URL, escaping each segment.
+func objectURL(base, name string) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.Join(parts, "/")
+}
+
+// do performs a request and returns the response if its status is 2xx. A
+// size of -1 means the body length is unknown. prepare, if set, is called
+// before the request is sent, e.g. to sign it. The caller must close the
+// response body.
+func do(ctx context.Context, client *http.Client, method, rawURL string, body io.Reader, size int64, prepare func(*http.Request) error) (*http.Response, error) {
+	if err := net.OfflineError(); err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if size >= 0 {
+		req.ContentLength = size
+	}
+	hytale.SetUserAgent(req)
+	if prepare != nil {
+		if err := prepare(req); err != nil {
+			return nil, err
+		}
+	}
+
+	slog.Debug("cloud sync request", "method", method, "url", req.URL.Redacted())
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, &api.StatusError{
+			URL:        req.URL.Redacted(),
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+	return resp, nil
+}
+
+// discard closes the body of a successful response.
+func discard(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,96 @@
+package cloudsync
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"hytale-launcher/internal/api"
+)
+
+// WebDAVTarget stores data in a WebDAV collection, such as a Nextcloud
+// folder. Missing collections are created on upload.
+type WebDAVTarget struct {
+	// URL is the collection data is stored in.
+	URL string
+
+	Username string
+	Password string
+}
+
+// ID returns the collection URL and user.
+func (t *WebDAVTarget) ID() string {
+	return "webdav:" + t.Username + "@" + t.URL
+}
+
+// auth adds basic authentication to a request if a user is set.
+func (t *WebDAVTarget) auth(req *http.Request) error {
+	if t.Username != "" {
+		req.SetBasicAuth(t.Username, t.Password)
+	}
+	return nil
+}
+
+// Get downloads an object.
+func (t *WebDAVTarget) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	resp, err := do(ctx, nil, http.MethodGet, objectURL(t.URL, name), nil, -1, t.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Put uploads an object. WebDAV servers answer 409 Conflict when the parent
+// collection is missing; the collections are then created and the upload
+// retried, which requires body to be seekable.
+func (t *WebDAVTarget) Put(ctx context.Context, name string, body io.Reader, size int64) error {
+	err := discard(do(ctx, nil, http.MethodPut, objectURL(t.URL, name), body, size, t.auth))
+
+	var statusErr *api.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusConflict {
+		return err
+	}
+	seeker, ok := body.(io.Seeker)
+	if !ok {
+		return err
+	}
+	if err := t.mkcolAll(ctx, path.Dir(name)); err != nil {
+		return err
+	}
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return discard(do(ctx, nil, http.MethodPut, objectURL(t.URL, name), body, size, t.auth))
+}
+
+// Delete removes an object.
+func (t *WebDAVTarget) Delete(ctx context.Context, name string) error {
+	return discard(do(ctx, nil, http.MethodDelete, objectURL(t.URL, name), nil, -1, t.auth))
+}
+
+// mkcolAll creates a collection and its parents. Collections that already
+// exist are skipped.
+func (t *WebDAVTarget) mkcolAll(ctx context.Context, dir string) error {
+	if dir == "." || dir == "" {
+		return nil
+	}
+
+	var current string
+	for _, part := range strings.Split(dir, "/") {
+		current = path.Join(current, part)
+		err := discard(do(ctx, nil, "MKCOL", objectURL(t.URL, current)+"/", nil, -1, t.auth))
+
+		var statusErr *api.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusMethodNotAllowed {
+			// The collection already exists.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Package crashreport sets up error reporting to Sentry. Every event passes
+// through a scrubber that removes tokens, user names in file paths and
+// account identifiers, and nothing is sent while the user has turned crash
+// reporting off in the launcher settings.
+package crashreport
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/redact"
+	"hytale-launcher/internal/settings"
+)
+
+// DSN is the Sentry project to report to. It is set at build time via
+// ldflags; when empty, reporting is not set up and
+// sentry.CaptureException does nothing.
+//
+//	-ldflags "-X hytale-launcher/internal/crashreport.DSN=https://..."
+var DSN string
+
+// flushTimeout bounds how long Flush waits for queued reports.
+const flushTimeout = 2 * time.Second
+
+// Init sets up the Sentry client. The setting is checked for each event,
+// so turning crash reporting off takes effect immediately.
+func Init() error {
+	if DSN == "" {
+		slog.Debug("crash reporting not configured")
+		return nil
+	}
+
+	return sentry.Init(sentry.ClientOptions{
+		Dsn:              DSN,
+		Release:          build.Version,
+		Environment:      build.Release,
+		SendDefaultPII:   false,
+		BeforeSend:       beforeSend,
+		BeforeBreadcrumb: beforeBreadcrumb,
+	})
+}
+
+// Flush waits briefly for queued reports to be sent. It should be called
+// before the launcher exits.
+func Flush() {
+	sentry.Flush(flushTimeout)
+}
+
+// Enabled returns true unless the user has turned crash reporting off.
+func Enabled() bool {
+	return !settings.Get().CrashReportsDisabled
+}
+
+// beforeSend drops events while reporting is off and scrubs the rest.
+func beforeSend(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+	if !Enabled() {
+		return nil
+	}
+	return Scrub(event)
+}
+
+// beforeBreadcrumb scrubs breadcrumbs as they are recorded.
+func beforeBreadcrumb(b *sentry.Breadcrumb, _ *sentry.BreadcrumbHint) *sentry.Breadcrumb {
+	scrubBreadcrumb(b)
+	return b
+}
+
+// Scrub removes personal details and credentials from an event. The
+// machine name, user and request are dropped entirely; text fields are
+// redacted.
+func Scrub(event *sentry.Event) *sentry.Event {
+	event.ServerName = ""
+	event.User = sentry.User{}
+	event.Request = nil
+	event.Message = clean(event.Message)
+
+	for i := range event.Exception {
+		ex := &event.Exception[i]
+		ex.Value = clean(ex.Value)
+		scrubStacktrace(ex.Stacktrace)
+	}
+	for i := range event.Threads {
+		scrubStacktrace(event.Threads[i].Stacktrace)
+	}
+	for _, b := range event.Breadcrumbs {
+		scrubBreadcrumb(b)
+	}
+
+	for key, value := range event.Tags {
+		event.Tags[key] = cleanValue(key, value).(string)
+	}
+	scrubMap(event.Extra)
+	for _, ctx := range event.Contexts {
+		scrubMap(ctx)
+	}
+	return event
+}
+
+// scrubStacktrace removes user names from the file paths in a stack trace.
+func scrubStacktrace(st *sentry.Stacktrace) {
+	if st == nil {
+		return
+	}
+	for i := range st.Frames {
+		st.Frames[i].AbsPath = clean(st.Frames[i].AbsPath)
+		st.Frames[i].Filename = clean(st.Frames[i].Filename)
+	}
+}
+
+// scrubBreadcrumb redacts a breadcrumb's message and data.
+func scrubBreadcrumb(b *sentry.Breadcrumb) {
+	if b == nil {
+		return
+	}
+	b.Message = clean(b.Message)
+	scrubMap(b.Data)
+}
+
+// scrubMap redacts the values of a map in place.
+func scrubMap(m map[string]any) {
+	for key, value := range m {
+		m[key] = cleanValue(key, value)
+	}
+}
+
+// cleanValue redacts a value stored under key. Values under secret keys
+// are replaced; strings and nested maps and slices are redacted.
+func cleanValue(key string, value any) any {
+	if redact.IsSecretKey(key) {
+		return redact.Placeholder
+	}
+
+	switch v := value.(type) {
+	case string:
+		return clean(v)
+	case map[string]any:
+		scrubMap(v)
+		return v
+	case []any:
+		for i := range v {
+			v[i] = cleanValue("", v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// clean redacts credentials, user names and identifiers in text.
+func clean(s string) string {
+	if s == "" {
+		return s
+	}
+	return redact.Identifiers(redact.Text(s))
+}
@@ -0,0 +1,40 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrBadSignature is returned when a signed payload does not verify.
+var ErrBadSignature = errors.New("bad signature")
+
+// Signed is a payload signed with Ed25519, as served for configuration
+// the launcher fetches from an unpinned endpoint and must be able to
+// trust.
+type Signed struct {
+	// Payload is the base64 encoding of the signed bytes.
+	Payload string `json:"payload"`
+
+	// Signature is the base64 Ed25519 signature of the decoded payload.
+	Signature string `json:"signature"`
+}
+
+// Open verifies the signature with publicKey, a base64 Ed25519 public
+// key, and returns the decoded payload.
+func (s Signed) Open(publicKey string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("no valid signing key")
+	}
+	payload, err := base64.StdEncoding.DecodeString(s.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(s.Signature)
+	if err != nil || !ed25519.Verify(key, payload, sig) {
+		return nil, ErrBadSignature
+	}
+	return payload, nil
+}
@@ -0,0 +1,89 @@
+// Package deeplink parses and registers hytale:// URLs, which let web pages
+// and other applications open the launcher at a specific screen or action.
+package deeplink
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Scheme is the URL scheme handled by the launcher.
+const Scheme = "hytale"
+
+// Link actions.
+const (
+	// ActionLaunch launches the game, optionally on a given channel and
+	// joining a server: hytale://launch?channel=beta&server=play.example.com
+	ActionLaunch = "launch"
+
+	// ActionNews opens a news article: hytale://news/<id>
+	ActionNews = "news"
+
+	// ActionAuth completes an OAuth login: hytale://auth/callback?code=...&state=...
+	ActionAuth = "auth"
+)
+
+// LaunchFlag is the command line flag that launches the game once the
+// launcher has started, as used by shortcuts such as Steam's. It is handled
+// as a hytale://launch link.
+const LaunchFlag = "--launch"
+
+// Link is a parsed hytale:// URL.
+type Link struct {
+	// Action is the first element of the link, e.g. "launch" or "news".
+	Action string
+
+	// Path holds the remaining path elements, e.g. the article ID.
+	Path []string
+
+	// Query holds the query parameters.
+	Query url.Values
+}
+
+// Parse parses a hytale:// URL. Both hytale://launch and hytale:launch forms
+// are accepted.
+func Parse(raw string) (*Link, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid link: %w", err)
+	}
+	if !strings.EqualFold(u.Scheme, Scheme) {
+		return nil, fmt.Errorf("unsupported link scheme %q", u.Scheme)
+	}
+
+	var parts []string
+	for _, part := range strings.Split(u.Host+"/"+u.Opaque+"/"+u.Path, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("link has no action: %s", raw)
+	}
+
+	return &Link{
+		Action: strings.ToLower(parts[0]),
+		Path:   parts[1:],
+		Query:  u.Query(),
+	}, nil
+}
+
+// FromArgs returns the first hytale:// URL in the command line arguments,
+// as passed by the operating system when a link is opened, or an empty
+// string if there is none. Without a URL, LaunchFlag returns a
+// hytale://launch link.
+func FromArgs(args []string) string {
+	prefix := Scheme + ":"
+	for _, arg := range args {
+		if len(arg) > len(prefix) && strings.EqualFold(arg[:len(prefix)], prefix) {
+			return arg
+		}
+	}
+	for _, arg := range args {
+		if arg == LaunchFlag || arg == LaunchFlag[1:] {
+			return Scheme + "://" + ActionLaunch
+		}
+	}
+	return ""
+}
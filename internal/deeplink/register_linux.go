@@ -0,0 +1,61 @@
+//go:build linux
+
+package deeplink
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// desktopFileName is the desktop entry that handles hytale:// links.
+const desktopFileName = "hytale-launcher-url.desktop"
+
+// Register associates the hytale:// scheme with the running launcher for
+// the current user, by installing a desktop entry and making it the
+// default handler with xdg-mime. Nothing is changed if the entry is
+// already installed as it would be written.
+func Register() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to locate launcher executable: %w", err)
+	}
+
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		dataDir = filepath.Join(home, ".local", "share")
+	}
+
+	appsDir := filepath.Join(dataDir, "applications")
+	if err := os.MkdirAll(appsDir, 0o755); err != nil {
+		return err
+	}
+
+	entry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=Hytale Launcher
+Exec="%s" %%u
+NoDisplay=true
+MimeType=x-scheme-handler/%s;
+`, exe, Scheme)
+
+	path := filepath.Join(appsDir, desktopFileName)
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, []byte(entry)) {
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(entry), 0o644); err != nil {
+		return fmt.Errorf("unable to write desktop entry: %w", err)
+	}
+
+	if err := exec.Command("xdg-mime", "default", desktopFileName, "x-scheme-handler/"+Scheme).Run(); err != nil {
+		return fmt.Errorf("unable to set default scheme handler: %w", err)
+	}
+	return nil
+}
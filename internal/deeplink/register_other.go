@@ -0,0 +1,10 @@
+//go:build !linux && !windows
+
+package deeplink
+
+// Register does nothing on this platform. On macOS the scheme is declared
+// in the application bundle's Info.plist and links are delivered through
+// the OnUrlOpen callback.
+func Register() error {
+	return nil
+}
@@ -0,0 +1,57 @@
+//go:build windows
+
+package deeplink
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// Register associates the hytale:// scheme with the running launcher for
+// the current user. Nothing is changed if the scheme already opens it.
+func Register() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to locate launcher executable: %w", err)
+	}
+	command := fmt.Sprintf(`"%s" "%%1"`, exe)
+
+	if current, err := registeredCommand(); err == nil && current == command {
+		return nil
+	}
+
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, `Software\Classes\`+Scheme, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("unable to create scheme key: %w", err)
+	}
+	defer key.Close()
+
+	if err := key.SetStringValue("", "URL:Hytale Protocol"); err != nil {
+		return err
+	}
+	if err := key.SetStringValue("URL Protocol", ""); err != nil {
+		return err
+	}
+
+	cmd, _, err := registry.CreateKey(key, `shell\open\command`, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("unable to create command key: %w", err)
+	}
+	defer cmd.Close()
+
+	return cmd.SetStringValue("", command)
+}
+
+// registeredCommand returns the command the scheme is opened with.
+func registeredCommand() (string, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Classes\`+Scheme+`\shell\open\command`, registry.QUERY_VALUE)
+	if err != nil {
+		return "", err
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue("")
+	return value, err
+}
@@ -0,0 +1,49 @@
+//go:build !windows
+
+package discord
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// dialTimeout bounds connecting to a Discord socket.
+const dialTimeout = time.Second
+
+// dial connects to the first Discord IPC socket that accepts. Discord
+// listens on discord-ipc-0 to discord-ipc-9 in the runtime or temporary
+// directory; Flatpak and Snap installs use a subdirectory of it.
+func dial() (io.ReadWriteCloser, error) {
+	var dirs []string
+	for _, env := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
+		if dir := os.Getenv(env); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	dirs = append(dirs, "/tmp")
+
+	var candidates []string
+	for _, dir := range dirs {
+		for _, sub := range []string{"", "app/com.discordapp.Discord", "snap.discord"} {
+			for i := 0; i < 10; i++ {
+				candidates = append(candidates, filepath.Join(dir, sub, "discord-ipc-"+strconv.Itoa(i)))
+			}
+		}
+	}
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		conn, err := net.DialTimeout("unix", path, dialTimeout)
+		if err == nil {
+			return conn, nil
+		}
+	}
+	return nil, errors.New("discord is not running")
+}
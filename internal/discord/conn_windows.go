@@ -0,0 +1,22 @@
+//go:build windows
+
+package discord
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+)
+
+// dial opens the first Discord IPC named pipe that exists. Discord listens
+// on \\.\pipe\discord-ipc-0 to discord-ipc-9.
+func dial() (io.ReadWriteCloser, error) {
+	for i := 0; i < 10; i++ {
+		pipe, err := os.OpenFile(`\\.\pipe\discord-ipc-`+strconv.Itoa(i), os.O_RDWR, 0)
+		if err == nil {
+			return pipe, nil
+		}
+	}
+	return nil, errors.New("discord is not running")
+}
@@ -0,0 +1,226 @@
+// Package discord publishes the launcher's activity to a running Discord
+// client through its local IPC socket, so it shows as Rich Presence on the
+// user's profile.
+package discord
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ClientID is the Discord application ID the activity is published under.
+// This is set at build time via ldflags:
+//
+//	-ldflags "-X hytale-launcher/internal/discord.ClientID=..."
+var ClientID string
+
+// IPC opcodes.
+const (
+	opHandshake uint32 = 0
+	opFrame     uint32 = 1
+	opClose     uint32 = 2
+)
+
+// reconnectInterval is the minimum time between attempts to connect to
+// Discord, which is often not running.
+const reconnectInterval = 30 * time.Second
+
+// maxFrameSize bounds the size of a frame read from Discord.
+const maxFrameSize = 64 * 1024
+
+// Activity is the Rich Presence shown on the user's profile.
+type Activity struct {
+	// Details is the first line, such as "Playing Hytale".
+	Details string `json:"details,omitempty"`
+
+	// State is the second line, such as the channel.
+	State string `json:"state,omitempty"`
+
+	// Timestamps shows the time elapsed since Start.
+	Timestamps *Timestamps `json:"timestamps,omitempty"`
+
+	// Assets are the images shown next to the activity.
+	Assets *Assets `json:"assets,omitempty"`
+}
+
+// Timestamps holds the start of an activity, in Unix seconds.
+type Timestamps struct {
+	Start int64 `json:"start,omitempty"`
+}
+
+// Assets names the images of an activity, as uploaded to the Discord
+// application.
+type Assets struct {
+	LargeImage string `json:"large_image,omitempty"`
+	LargeText  string `json:"large_text,omitempty"`
+}
+
+// Presence publishes activity to Discord, connecting when Discord is
+// available and reconnecting if it restarts. The zero value is ready to use.
+type Presence struct {
+	mu          sync.Mutex
+	conn        io.ReadWriteCloser
+	lastAttempt time.Time
+	nonce       int
+}
+
+// Set publishes the activity. If Discord is not running, the activity is
+// dropped; the next call tries to connect again.
+func (p *Presence) Set(activity *Activity) {
+	if ClientID == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.setLocked(activity); err != nil {
+		slog.Debug("unable to publish discord activity", "error", err)
+	}
+}
+
+// Clear removes the activity.
+func (p *Presence) Clear() {
+	p.Set(nil)
+}
+
+// Close clears the activity and disconnects from Discord.
+func (p *Presence) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		return
+	}
+	_ = writeFrame(p.conn, opClose, map[string]any{})
+	p.conn.Close()
+	p.conn = nil
+}
+
+// setLocked publishes the activity, connecting first if needed. The
+// caller must hold mu.
+func (p *Presence) setLocked(activity *Activity) error {
+	if p.conn == nil {
+		if time.Since(p.lastAttempt) < reconnectInterval {
+			return errors.New("discord not connected")
+		}
+		p.lastAttempt = time.Now()
+		if err := p.connectLocked(); err != nil {
+			return err
+		}
+	}
+
+	p.nonce++
+	err := p.request(map[string]any{
+		"cmd": "SET_ACTIVITY",
+		"args": map[string]any{
+			"pid":      os.Getpid(),
+			"activity": activity,
+		},
+		"nonce": strconv.Itoa(p.nonce),
+	})
+	if err != nil {
+		// Discord has most likely been closed; connect again next time.
+		p.conn.Close()
+		p.conn = nil
+		p.lastAttempt = time.Time{}
+	}
+	return err
+}
+
+// connectLocked connects and performs the handshake. The caller must hold
+// mu.
+func (p *Presence) connectLocked() error {
+	conn, err := dial()
+	if err != nil {
+		return err
+	}
+
+	p.conn = conn
+	if err := p.request(map[string]any{"v": 1, "client_id": ClientID}); err != nil {
+		conn.Close()
+		p.conn = nil
+		return fmt.Errorf("discord handshake failed: %w", err)
+	}
+
+	slog.Info("connected to discord")
+	return nil
+}
+
+// request sends a frame and reads the reply. The handshake is sent with
+// the handshake opcode, everything else as a regular frame.
+func (p *Presence) request(payload map[string]any) error {
+	op := opFrame
+	if _, ok := payload["client_id"]; ok {
+		op = opHandshake
+	}
+
+	if err := writeFrame(p.conn, op, payload); err != nil {
+		return err
+	}
+
+	replyOp, reply, err := readFrame(p.conn)
+	if err != nil {
+		return err
+	}
+	if replyOp == opClose {
+		return fmt.Errorf("discord closed the connection: %s", reply)
+	}
+
+	var resp struct {
+		Evt  string `json:"evt"`
+		Data struct {
+			Message string `json:"message"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(reply, &resp); err == nil && resp.Evt == "ERROR" {
+		return fmt.Errorf("discord error: %s", resp.Data.Message)
+	}
+	return nil
+}
+
+// writeFrame writes a frame: the opcode and payload length as little
+// endian uint32s, followed by the JSON payload.
+func writeFrame(w io.Writer, op uint32, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, op)
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// readFrame reads a frame written by Discord.
+func readFrame(r io.Reader) (uint32, []byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	op := binary.LittleEndian.Uint32(header[0:4])
+	size := binary.LittleEndian.Uint32(header[4:8])
+	if size > maxFrameSize {
+		return 0, nil, fmt.Errorf("discord frame too large: %d bytes", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+	return op, data, nil
+}
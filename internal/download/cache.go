@@ -0,0 +1,333 @@
+package download
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"hytale-launcher/internal/eventgroup"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/settings"
+	"hytale-launcher/internal/system"
+)
+
+// cacheDirName is the directory in the storage directory that downloads
+// are written to.
+const cacheDirName = "cache"
+
+// objectsDirName is the directory in the cache directory that holds files
+// by their SHA-256, so a file shared between channels or builds is only
+// downloaded once.
+const objectsDirName = "objects"
+
+// DefaultCacheLimit is the size the content cache is kept under when
+// settings do not set one.
+const DefaultCacheLimit int64 = 4 << 30
+
+// cacheMu serializes changes to the content cache.
+var cacheMu sync.Mutex
+
+// CacheDir returns the directory downloads are written to.
+func CacheDir() string {
+	return hytale.InStorageDir(cacheDirName)
+}
+
+// objectPath returns the path of the cached file with the given SHA-256.
+func objectPath(sum string) string {
+	return filepath.Join(CacheDir(), objectsDirName, sum[:2], sum)
+}
+
+// cacheLimit returns the size cap of the content cache from settings. Zero
+// means caching is off.
+func cacheLimit() int64 {
+	mb := settings.Get().DownloadCacheMB
+	switch {
+	case mb < 0:
+		return 0
+	case mb == 0:
+		return DefaultCacheLimit
+	}
+	return int64(mb) << 20
+}
+
+// cacheKey normalizes a SHA-256 to a cache key. It returns false if the
+// value is not a hex-encoded SHA-256.
+func cacheKey(sha256 string) (string, bool) {
+	sum := strings.ToLower(strings.TrimSpace(sha256))
+	if len(sum) != 64 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(sum); err != nil {
+		return "", false
+	}
+	return sum, true
+}
+
+// DownloadCached is like DownloadTemp, but a file with a known SHA-256 is
+// served from the content cache if present and added to it otherwise. The
+// returned file is the caller's to move or delete; the cached copy is not
+// affected.
+func DownloadCached(
+	ctx context.Context,
+	client system.HTTPDoer,
+	dir string,
+	url string,
+	sha256 string,
+	reporter ProgressReporter,
+) (string, error) {
+	return DownloadCachedFrom(ctx, client, dir, Source{URL: url}, sha256, reporter)
+}
+
+// DownloadCachedFrom is like DownloadCached, but fetches the file from src
+// with the transport it names.
+func DownloadCachedFrom(
+	ctx context.Context,
+	client system.HTTPDoer,
+	dir string,
+	src Source,
+	sha256 string,
+	reporter ProgressReporter,
+) (string, error) {
+	sum, ok := cacheKey(sha256)
+	if !ok || cacheLimit() == 0 {
+		return DownloadTempFrom(ctx, client, dir, src, sha256, reporter)
+	}
+
+	if p, size, ok := fromCache(dir, sum, base(src.URL)); ok {
+		slog.Debug("using cached download", "url", src.URL, "sha256", sum)
+		if reporter != nil {
+			reporter(size, 0)
+		}
+		return p, nil
+	}
+
+	p, err := DownloadTempFrom(ctx, client, dir, src, sum, reporter)
+	if err != nil {
+		return "", err
+	}
+	addToCache(p, sum)
+	return p, nil
+}
+
+// DownloadTempVerified downloads a file to the cache directory like
+// DownloadTempSimple, verifying it against sha256 and reusing a cached copy
+// if one exists. An empty sha256 skips both.
+func DownloadTempVerified(ctx context.Context, url, sha256 string, reporter ProgressReporter) (string, error) {
+	return DownloadCached(ctx, sys.HTTP, CacheDir(), url, sha256, reporter)
+}
+
+// fromCache places a copy of the cached file with the given SHA-256 in dir
+// and marks it as recently used. The copy is checked against the hash, so
+// a damaged cache entry is dropped rather than used.
+func fromCache(dir, sum, name string) (string, int64, bool) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	obj := objectPath(sum)
+	info, err := sys.FS.Stat(obj)
+	if err != nil {
+		return "", 0, false
+	}
+	if err := sys.FS.MkdirAll(dir, 0755); err != nil {
+		return "", 0, false
+	}
+
+	tmp, err := sys.FS.CreateTemp(dir, "dl-*-"+name)
+	if err != nil {
+		return "", 0, false
+	}
+	p := tmp.Name()
+	tmp.Close()
+
+	if err := linkOrCopy(obj, p); err != nil {
+		sys.FS.Remove(p)
+		return "", 0, false
+	}
+	if err := verifySHA256(p, sum); err != nil {
+		slog.Warn("dropping damaged cache entry", "sha256", sum, "error", err)
+		sys.FS.Remove(p)
+		sys.FS.Remove(obj)
+		return "", 0, false
+	}
+
+	now := sys.Clock.Now()
+	sys.FS.Chtimes(obj, now, now)
+	return p, info.Size(), true
+}
+
+// addToCache records a verified download in the content cache and evicts
+// the least recently used files beyond the size cap.
+func addToCache(p, sum string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	obj := objectPath(sum)
+	if _, err := sys.FS.Stat(obj); err == nil {
+		return
+	}
+	if err := ioutil.MkdirAll(filepath.Dir(obj)); err != nil {
+		slog.Warn("unable to create cache directory", "error", err)
+		return
+	}
+
+	// Write under a temporary name first, so an interrupted copy never
+	// leaves a partial file under the hash.
+	tmp := obj + ".tmp"
+	sys.FS.Remove(tmp)
+	if err := linkOrCopy(p, tmp); err != nil {
+		slog.Warn("unable to cache download", "sha256", sum, "error", err)
+		sys.FS.Remove(tmp)
+		return
+	}
+	if err := sys.FS.Rename(tmp, obj); err != nil {
+		sys.FS.Remove(tmp)
+		return
+	}
+
+	trimCacheLocked(cacheLimit())
+}
+
+// linkOrCopy hard links src to dst, copying it if the file system does not
+// support links between the two paths.
+func linkOrCopy(src, dst string) error {
+	if err := sys.FS.Link(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := sys.FS.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := sys.FS.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// evictParallelism is the number of cached files removed at once when
+// trimming the cache.
+const evictParallelism = 8
+
+// cacheEntry is a file in the content cache.
+type cacheEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// trimCacheLocked removes the least recently used files from the content
+// cache until it is no larger than limit. The caller must hold cacheMu.
+func trimCacheLocked(limit int64) {
+	var entries []cacheEntry
+	var total int64
+
+	filepath.WalkDir(filepath.Join(CacheDir(), objectsDirName), func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		entries = append(entries, cacheEntry{path: p, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if total <= limit {
+		return
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	// Pick the oldest files until the rest fit, then remove them in
+	// parallel.
+	var evict []cacheEntry
+	for excess := total - limit; excess > 0 && len(evict) < len(entries); {
+		e := entries[len(evict)]
+		evict = append(evict, e)
+		excess -= e.size
+	}
+
+	var freed atomic.Int64
+	var eg eventgroup.Group
+	eg.SetLimit(evictParallelism)
+	for _, e := range evict {
+		eg.Go(func() error {
+			if err := sys.FS.Remove(e.path); err != nil {
+				return fmt.Errorf("unable to evict %s: %w", e.path, err)
+			}
+			freed.Add(e.size)
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		slog.Warn("unable to evict some cached downloads", "error", err)
+	}
+	slog.Info("trimmed download cache", "freed", freed.Load(), "limit", limit)
+}
+
+// TrimCache evicts the least recently used files from the content cache
+// until it fits the size cap in settings.
+func TrimCache() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	trimCacheLocked(cacheLimit())
+}
+
+// CacheSize returns the total size of the files in the content cache.
+func CacheSize() int64 {
+	size, _ := ioutil.DirSize(filepath.Join(CacheDir(), objectsDirName))
+	return size
+}
+
+// ClearCache removes everything in the cache directory, including the
+// content cache, and returns the bytes freed.
+func ClearCache() (int64, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	size, _ := ioutil.DirSize(CacheDir())
+	if err := sys.FS.RemoveAll(CacheDir()); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// CleanCache removes the downloads left in the cache directory by earlier
+// runs and trims the content cache to the size cap in settings. Cached
+// files are kept for reuse by later updates.
+func CleanCache() {
+	entries, err := sys.FS.ReadDir(CacheDir())
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.Name() == objectsDirName {
+			continue
+		}
+		if err := sys.FS.RemoveAll(filepath.Join(CacheDir(), e.Name())); err != nil {
+			slog.Warn("unable to remove stale download", "name", e.Name(), "error", err)
+		}
+	}
+	TrimCache()
+}
@@ -14,8 +14,10 @@ import (
 	"strings"
 	"time"
 
+	"hytale-launcher/internal/api"
 	"hytale-launcher/internal/ioutil"
 	"hytale-launcher/internal/net"
+	"hytale-launcher/internal/system"
 )
 
 // ProgressReporter is called during downloads to report progress.
@@ -23,6 +25,18 @@ import (
 // speed is the current download speed in bytes per second.
 type ProgressReporter func(bytesDownloaded int64, speed int64)
 
+// sys provides the clock, file system and HTTP client downloads use.
+var sys = system.Real()
+
+// partSuffix marks a download still being written. The file is renamed to
+// drop it only once the download is complete and flushed to disk, so a
+// crash never leaves a file that looks finished.
+const partSuffix = ".part"
+
+// syncInterval is how many bytes are written between flushes to disk, so a
+// large download does not build up gigabytes of dirty pages.
+const syncInterval = 64 << 20
+
 // base extracts the filename from a URL, stripping any query parameters.
 func base(url string) string {
 	// Cut at the first '?' to remove query parameters
@@ -33,31 +47,49 @@ func base(url string) string {
 // DownloadTemp downloads a file from url to a temporary file in dir.
 // If sha256 is non-empty, the downloaded file's hash is verified.
 // Returns the path to the temporary file on success.
+//
+// The file is written under a ".part" name, flushed to disk and only then
+// renamed, so the returned path always holds a complete download.
 func DownloadTemp(
 	ctx context.Context,
-	client *http.Client,
+	client system.HTTPDoer,
 	dir string,
 	url string,
 	sha256 string,
 	reporter ProgressReporter,
+) (string, error) {
+	return DownloadTempFrom(ctx, client, dir, Source{URL: url}, sha256, reporter)
+}
+
+// DownloadTempFrom is like DownloadTemp, but fetches the file from src with
+// the transport it names. Whatever the transport, the whole file is
+// verified against sha256 before it is returned.
+func DownloadTempFrom(
+	ctx context.Context,
+	client system.HTTPDoer,
+	dir string,
+	src Source,
+	sha256 string,
+	reporter ProgressReporter,
 ) (string, error) {
 	var success bool
+	url := src.URL
 
 	// Ensure the directory exists
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := sys.FS.MkdirAll(dir, 0755); err != nil {
 		return "", err
 	}
 
 	// Create a temp file with a pattern based on the URL's base name
-	pattern := "dl-*-" + base(url)
-	tempFile, err := os.CreateTemp(dir, pattern)
+	pattern := "dl-*-" + base(url) + partSuffix
+	tempFile, err := sys.FS.CreateTemp(dir, pattern)
 	if err != nil {
 		return "", err
 	}
 	defer func() {
 		tempFile.Close()
 		if !success {
-			os.Remove(tempFile.Name())
+			sys.FS.Remove(tempFile.Name())
 		}
 	}()
 
@@ -68,12 +100,19 @@ func DownloadTemp(
 	)
 
 	// Download the file
-	err = downloadFile(ctx, client, url, tempFile, reporter)
+	err = src.transport().Fetch(ctx, client, src, tempFile, reporter)
 	if errors.Is(err, context.Canceled) {
 		return "", context.Canceled
 	}
 	if err != nil {
-		return "", fmt.Errorf("error downloading file from %q: %w", url, err)
+		return "", fmt.Errorf("error downloading file from %q: %w", url, ioutil.CheckDiskFull(err))
+	}
+
+	if err := tempFile.Sync(); err != nil {
+		return "", fmt.Errorf("error flushing download: %w", ioutil.CheckDiskFull(err))
+	}
+	if err := tempFile.Close(); err != nil {
+		return "", err
 	}
 
 	// Verify SHA256 if provided
@@ -83,16 +122,21 @@ func DownloadTemp(
 		}
 	}
 
+	finalPath := strings.TrimSuffix(tempFile.Name(), partSuffix)
+	if err := sys.FS.Rename(tempFile.Name(), finalPath); err != nil {
+		return "", err
+	}
+
 	success = true
-	return tempFile.Name(), nil
+	return finalPath, nil
 }
 
 // downloadFile performs the actual HTTP download to the given file.
 func downloadFile(
 	ctx context.Context,
-	client *http.Client,
+	client system.HTTPDoer,
 	url string,
-	file *os.File,
+	file system.File,
 	reporter ProgressReporter,
 ) error {
 	// Check for offline error (network connectivity)
@@ -113,14 +157,22 @@ func downloadFile(
 	}
 	defer resp.Body.Close()
 
-	// Check for 404 Not Found
-	if resp.StatusCode == http.StatusNotFound {
-		return nil
+	// Check for non-200 status. A 404 is an error too, rather than an
+	// empty file.
+	if resp.StatusCode != http.StatusOK {
+		return &api.StatusError{
+			URL:        req.URL.Redacted(),
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
 	}
 
-	// Check for non-200 status
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
+	// Reserve the space up front to limit fragmentation. Not every file
+	// system supports it, which only costs contiguity.
+	if f, ok := file.(*os.File); ok && resp.ContentLength > 0 {
+		if err := preallocate(f, resp.ContentLength); err != nil {
+			slog.Debug("unable to preallocate download", "size", resp.ContentLength, "error", err)
+		}
 	}
 
 	// Buffer for reading
@@ -134,8 +186,9 @@ func downloadFile(
 
 	var (
 		bytesDownloaded int64
+		unsynced        int64
 		speedSamples    []int64
-		lastSampleTime  = time.Now()
+		lastSampleTime  = sys.Clock.Now()
 		sampleBytes     int64
 		currentSpeed    int64
 	)
@@ -161,10 +214,18 @@ func downloadFile(
 			bytesDownloaded += int64(n)
 			sampleBytes += int64(n)
 
+			unsynced += int64(n)
+			if unsynced >= syncInterval {
+				if err := file.Sync(); err != nil {
+					return err
+				}
+				unsynced = 0
+			}
+
 			// Update speed calculation periodically
-			elapsed := time.Since(lastSampleTime)
+			elapsed := sys.Clock.Since(lastSampleTime)
 			if elapsed >= speedSamplePeriod {
-				lastSampleTime = time.Now()
+				lastSampleTime = sys.Clock.Now()
 
 				// Add sample to sliding window
 				if len(speedSamples) >= speedWindowSize {
@@ -193,6 +254,10 @@ func downloadFile(
 		// Check for EOF or error
 		if readErr != nil {
 			if errors.Is(readErr, io.EOF) {
+				if resp.ContentLength >= 0 && bytesDownloaded != resp.ContentLength {
+					return fmt.Errorf("download truncated: got %d of %d bytes", bytesDownloaded, resp.ContentLength)
+				}
+
 				// Final progress report
 				if reporter != nil {
 					reporter(bytesDownloaded, currentSpeed)
@@ -0,0 +1,286 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/eventgroup"
+	"hytale-launcher/internal/system"
+)
+
+const (
+	// chunkSize is the size of the ranges a multi-source download is
+	// split into.
+	chunkSize = 8 << 20
+
+	// connsPerSource is the number of ranges fetched from each source at
+	// once.
+	connsPerSource = 2
+
+	// reportInterval is how often a multi-source download reports
+	// progress.
+	reportInterval = 250 * time.Millisecond
+)
+
+// errNoSources is returned when every source of a multi-source download
+// has failed.
+var errNoSources = errors.New("all download sources failed")
+
+// multiSource downloads a file in ranges spread across its URL and
+// mirrors, so one congested or failing server only slows the download
+// down. A source that fails is dropped and its ranges fetched from the
+// others. It needs the file's size and servers that accept range
+// requests; without them, or if every source fails, the file is
+// downloaded from its URL in one request instead.
+type multiSource struct{}
+
+func (multiSource) Fetch(ctx context.Context, client system.HTTPDoer, src Source, file system.File, reporter ProgressReporter) error {
+	urls := src.urls()
+	w, ok := file.(io.WriterAt)
+	if !ok || len(urls) < 2 {
+		return httpTransport{}.Fetch(ctx, client, src, file, reporter)
+	}
+	if err := checkOffline(); err != nil {
+		return err
+	}
+
+	size := src.Size
+	if size <= 0 {
+		size = rangeSize(ctx, client, src.URL)
+	}
+	if size <= 0 {
+		slog.Debug("source does not support ranges, downloading in one request", "url", src.URL)
+		return httpTransport{}.Fetch(ctx, client, src, file, reporter)
+	}
+
+	err := fetchRanges(ctx, client, urls, size, w, reporter)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	slog.Warn("multi-source download failed, downloading in one request", "url", src.URL, "error", err)
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return httpTransport{}.Fetch(ctx, client, src, file, reporter)
+}
+
+// rangeSize returns the size of the file at url if its server accepts
+// range requests, or zero.
+func rangeSize(ctx context.Context, client system.HTTPDoer, url string) int64 {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return 0
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Accept-Ranges") != "bytes" {
+		return 0
+	}
+	return resp.ContentLength
+}
+
+// fetchRanges downloads size bytes in chunks from urls into w, with
+// connsPerSource workers per source taking chunks from a shared queue.
+func fetchRanges(ctx context.Context, client system.HTTPDoer, urls []string, size int64, w io.WriterAt, reporter ProgressReporter) error {
+	q := newRangeQueue(size)
+	stop := context.AfterFunc(ctx, q.cancel)
+	defer stop()
+
+	var written atomic.Int64
+	reportDone := make(chan struct{})
+	if reporter != nil {
+		go reportRanges(&written, reporter, reportDone)
+	}
+	defer close(reportDone)
+
+	var eg eventgroup.Group
+	for _, url := range urls {
+		for range connsPerSource {
+			eg.Go(func() error {
+				for {
+					off, ok := q.next()
+					if !ok {
+						return nil
+					}
+					n, err := fetchRange(ctx, client, url, off, min(off+chunkSize, size), w, &written)
+					if err != nil {
+						written.Add(-n)
+						q.retry(off)
+						if ctx.Err() == nil {
+							slog.Debug("dropping download source", "url", url, "offset", off, "error", err)
+						}
+						return nil
+					}
+					q.done()
+				}
+			})
+		}
+	}
+	eg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !q.complete() {
+		return errNoSources
+	}
+	if reporter != nil {
+		reporter(size, 0)
+	}
+	return nil
+}
+
+// fetchRange downloads the bytes [off, end) of the file at url into w,
+// adding them to written as they arrive. Returns the bytes written.
+func fetchRange(ctx context.Context, client system.HTTPDoer, url string, off, end int64, w io.WriterAt, written *atomic.Int64) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, end-1))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, &api.StatusError{
+			URL:        req.URL.Redacted(),
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	buf := make([]byte, 64*1024)
+	pos := off
+	for pos < end {
+		n, readErr := resp.Body.Read(buf[:min(int64(len(buf)), end-pos)])
+		if n > 0 {
+			if _, err := w.WriteAt(buf[:n], pos); err != nil {
+				return pos - off, err
+			}
+			pos += int64(n)
+			written.Add(int64(n))
+		}
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) && pos == end {
+				break
+			}
+			if errors.Is(readErr, io.EOF) {
+				readErr = fmt.Errorf("range truncated: got %d of %d bytes", pos-off, end-off)
+			}
+			return pos - off, readErr
+		}
+	}
+	return pos - off, nil
+}
+
+// reportRanges reports the bytes written and the speed until done is
+// closed.
+func reportRanges(written *atomic.Int64, reporter ProgressReporter, done <-chan struct{}) {
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+
+	var last int64
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			n := written.Load()
+			reporter(n, (n-last)*int64(time.Second/reportInterval))
+			last = n
+		}
+	}
+}
+
+// rangeQueue hands out the chunks of a multi-source download. A chunk that
+// failed is put back for another worker; workers wait while chunks are in
+// flight elsewhere, as one may yet fail.
+type rangeQueue struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	pending   []int64
+	remaining int
+	cancelled bool
+}
+
+// newRangeQueue returns a queue of the chunks of a file of the given size.
+func newRangeQueue(size int64) *rangeQueue {
+	q := &rangeQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	for off := int64(0); off < size; off += chunkSize {
+		q.pending = append(q.pending, off)
+	}
+	q.remaining = len(q.pending)
+	return q
+}
+
+// next returns the offset of the next chunk to fetch, or false once every
+// chunk is done or the download is cancelled.
+func (q *rangeQueue) next() (int64, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.pending) == 0 && q.remaining > 0 && !q.cancelled {
+		q.cond.Wait()
+	}
+	if q.remaining == 0 || q.cancelled {
+		return 0, false
+	}
+	off := q.pending[0]
+	q.pending = q.pending[1:]
+	return off, true
+}
+
+// done records a chunk as written.
+func (q *rangeQueue) done() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.remaining--
+	if q.remaining == 0 {
+		q.cond.Broadcast()
+	}
+}
+
+// retry puts a failed chunk back in the queue.
+func (q *rangeQueue) retry(off int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending = append(q.pending, off)
+	q.cond.Signal()
+}
+
+// cancel wakes every waiting worker to stop.
+func (q *rangeQueue) cancel() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.cancelled = true
+	q.cond.Broadcast()
+}
+
+// complete reports whether every chunk was written.
+func (q *rangeQueue) complete() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.remaining == 0
+}
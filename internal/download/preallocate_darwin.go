@@ -0,0 +1,26 @@
+//go:build darwin
+
+package download
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// preallocate reserves size bytes of disk space for f without changing its
+// length, preferring a contiguous allocation.
+func preallocate(f *os.File, size int64) error {
+	fstore := &unix.Fstore_t{
+		Flags:   unix.F_ALLOCATECONTIG | unix.F_ALLOCATEALL,
+		Posmode: unix.F_PEOFPOSMODE,
+		Length:  size,
+	}
+	if err := unix.FcntlFstore(f.Fd(), unix.F_PREALLOCATE, fstore); err == nil {
+		return nil
+	}
+
+	// No contiguous run is free; take any space.
+	fstore.Flags = unix.F_ALLOCATEALL
+	return unix.FcntlFstore(f.Fd(), unix.F_PREALLOCATE, fstore)
+}
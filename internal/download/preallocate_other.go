@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package download
+
+import (
+	"errors"
+	"os"
+)
+
+// preallocate is not supported on this platform.
+func preallocate(f *os.File, size int64) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package download
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// fileAllocationInfo is the FILE_ALLOCATION_INFO structure.
+type fileAllocationInfo struct {
+	AllocationSize int64
+}
+
+// preallocate reserves size bytes of disk space for f without changing its
+// length. SetFileValidData is not used: it needs the volume maintenance
+// privilege, and would expose stale disk contents if a download were cut
+// short.
+func preallocate(f *os.File, size int64) error {
+	info := fileAllocationInfo{AllocationSize: size}
+	return windows.SetFileInformationByHandle(
+		windows.Handle(f.Fd()),
+		windows.FileAllocationInfo,
+		(*byte)(unsafe.Pointer(&info)),
+		uint32(unsafe.Sizeof(info)),
+	)
+}
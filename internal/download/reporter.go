@@ -2,10 +2,6 @@ package download
 
 import (
 	"context"
-	"net/http"
-	"os"
-
-	"hytale-launcher/internal/hytale"
 )
 
 // ProgressReport contains information about download progress.
@@ -235,14 +231,13 @@ func NewReporterWithSize(
 // DownloadTempSimple downloads a file to a temp directory and returns the path.
 // This is a simplified version that uses default settings.
 func DownloadTempSimple(ctx context.Context, url string, reporter ProgressReporter) (string, error) {
-	client := http.DefaultClient
-	cacheDir := hytale.InStorageDir("cache")
+	cacheDir := CacheDir()
 
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+	if err := sys.FS.MkdirAll(cacheDir, 0755); err != nil {
 		return "", err
 	}
 
-	return DownloadTemp(ctx, client, cacheDir, url, "", reporter)
+	return DownloadTemp(ctx, sys.HTTP, cacheDir, url, "", reporter)
 }
 
 // ReporterWithTotal creates a ProgressReporter that knows the expected total size.
@@ -0,0 +1,86 @@
+package download
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"hytale-launcher/internal/remoteconfig"
+	"hytale-launcher/internal/system"
+)
+
+// Transport names, as given per release in version manifests.
+const (
+	// TransportHTTP downloads the file from its URL in one request. It is
+	// the default.
+	TransportHTTP = "http"
+
+	// TransportMultiSource downloads the file in ranges spread across its
+	// URL and mirrors.
+	TransportMultiSource = "multi-source"
+)
+
+// Transport fetches the contents of a download into a file. The file is
+// empty when Fetch is called; verifying the result is left to the caller.
+type Transport interface {
+	Fetch(ctx context.Context, client system.HTTPDoer, src Source, file system.File, reporter ProgressReporter) error
+}
+
+// transports are the transports a release can select.
+var transports = map[string]Transport{
+	TransportHTTP:        httpTransport{},
+	TransportMultiSource: multiSource{},
+}
+
+// Source is where a download comes from.
+type Source struct {
+	// URL is the file's primary location.
+	URL string
+
+	// Mirrors are other locations serving the same file.
+	Mirrors []string
+
+	// Transport names the transport that fetches the file. Empty means
+	// TransportHTTP.
+	Transport string
+
+	// Size is the file's size in bytes, or zero if unknown.
+	Size int64
+}
+
+// urls returns the file's locations, the primary one first.
+func (s Source) urls() []string {
+	urls := []string{s.URL}
+	for _, m := range s.Mirrors {
+		if m != "" && !slices.Contains(urls, m) {
+			urls = append(urls, m)
+		}
+	}
+	return urls
+}
+
+// transport returns the transport the source names. An unknown name, such
+// as one added in a newer launcher, falls back to TransportHTTP, as does
+// the multi-source transport while it is switched off or not rolled out
+// to this install.
+func (s Source) transport() Transport {
+	if s.Transport == "" {
+		return httpTransport{}
+	}
+	if s.Transport == TransportMultiSource && !remoteconfig.Enabled(remoteconfig.FeatureMultiSourceDownloads) {
+		return httpTransport{}
+	}
+	t, ok := transports[s.Transport]
+	if !ok {
+		slog.Warn("unknown download transport, using HTTP", "transport", s.Transport, "url", s.URL)
+		return httpTransport{}
+	}
+	return t
+}
+
+// httpTransport downloads a file from its URL in a single request.
+type httpTransport struct{}
+
+func (httpTransport) Fetch(ctx context.Context, client system.HTTPDoer, src Source, file system.File, reporter ProgressReporter) error {
+	return downloadFile(ctx, client, src.URL, file, reporter)
+}
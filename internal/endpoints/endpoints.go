@@ -7,7 +7,7 @@ import (
 	"hytale-launcher/internal/build"
 )
 
-// Domain is the base domain for all API endpoints.
+// Domain is the base domain of the production environment.
 // This is set at build time via ldflags:
 //
 //	-ldflags "-X hytale-launcher/internal/endpoints.Domain=hytale.com"
@@ -25,9 +25,9 @@ func init() {
 }
 
 // FeedBase returns the base URL for the launcher news feed.
-// The returned URL is in the format: https://launcher.{domain}/launcher-feed/{release}/
+// The returned URL is in the format: {launcher base}/launcher-feed/{release}/
 func FeedBase() string {
-	return fmt.Sprintf("https://launcher.%s/launcher-feed/%s/", Domain, build.Release)
+	return base("launcher") + fmt.Sprintf("/launcher-feed/%s/", build.Release)
 }
 
 // Feed returns the full URL for the launcher news feed JSON file.
@@ -40,7 +40,27 @@ func Feed() string {
 //   - platform: the platform identifier (e.g., "windows", "darwin", "linux")
 //   - component: the component name (e.g., "launcher", "jre")
 func LauncherVersion(platform, component string) string {
-	return fmt.Sprintf("https://launcher.%s/version/%s/%s.json", Domain, platform, component)
+	return base("launcher") + fmt.Sprintf("/version/%s/%s.json", platform, component)
+}
+
+// RemoteConfig returns the URL for fetching the signed remote
+// configuration of kill switches, rollouts and endpoint overrides.
+func RemoteConfig() string {
+	return base("launcher") + fmt.Sprintf("/config/%s.json", build.Release)
+}
+
+// CertificatePins returns the URL for fetching the signed configuration of
+// the TLS keys the account endpoints are pinned to.
+func CertificatePins() string {
+	return base("launcher") + fmt.Sprintf("/pins/%s.json", build.Release)
+}
+
+// GPUDrivers returns the URL for fetching the table of minimum recommended
+// GPU driver versions for a platform.
+// Parameters:
+//   - platform: the platform identifier (e.g., "windows", "darwin", "linux")
+func GPUDrivers(platform string) string {
+	return base("launcher") + fmt.Sprintf("/version/%s/gpu-drivers.json", platform)
 }
 
 // GamePatchSet returns the URL for fetching game patch information.
@@ -48,24 +68,100 @@ func LauncherVersion(platform, component string) string {
 //   - channel: the release channel (e.g., "release", "beta")
 //   - version: the patch version number
 func GamePatchSet(channel string, version int) string {
-	return fmt.Sprintf("https://account-data.%s/patches/%s/%s/%s/%d",
-		Domain,
+	return base("account-data") + fmt.Sprintf("/patches/%s/%s/%s/%d",
+		build.OS(),
+		build.Arch(),
+		channel,
+		version,
+	)
+}
+
+// JREPatchSet returns the URL for fetching the patches that update a Java
+// runtime of the given major from the given build.
+func JREPatchSet(channel string, major, version int) string {
+	return base("account-data") + fmt.Sprintf("/patches/%s/%s/%s/jre-%d/%d",
 		build.OS(),
 		build.Arch(),
 		channel,
+		major,
 		version,
 	)
 }
 
+// Changelog returns the URL for fetching the release notes of a game build.
+// Parameters:
+//   - channel: the release channel (e.g., "release", "beta")
+//   - build: the game build number
+func Changelog(channel string, build int) string {
+	return base("launcher") + fmt.Sprintf("/changelog/%s/%d.json", channel, build)
+}
+
+// ChannelInfo returns the URL for fetching the display metadata of the
+// release channels (display names, icons, descriptions, and badges).
+func ChannelInfo() string {
+	return base("launcher") + fmt.Sprintf("/channels/%s.json", build.Release)
+}
+
+// PrereleaseConsent returns the URL for fetching the consent document a
+// user must accept before using a pre-release channel.
+// Parameters:
+//   - channel: the pre-release channel (e.g., "beta")
+func PrereleaseConsent(channel string) string {
+	return base("launcher") + fmt.Sprintf("/consent/%s.json", channel)
+}
+
+// ExperimentalOptIn returns the URL the user opts into an experimental
+// channel at, which grants the account the channel's patchline.
+// Parameters:
+//   - channel: the experimental channel
+func ExperimentalOptIn(channel string) string {
+	return base("account-data") + fmt.Sprintf("/patchlines/%s/opt-in", channel)
+}
+
+// Telemetry returns the URL anonymized launcher metrics are sent to when
+// the user has opted in.
+func Telemetry() string {
+	return base("telemetry") + "/launcher/v1/events"
+}
+
+// Attestation returns the URL game file attestations are submitted to, in
+// exchange for a token the game presents to servers that check it.
+func Attestation() string {
+	return base("sessions") + "/attestation"
+}
+
+// ServerDirectory returns the URL for fetching the public server directory
+// shown in the server browser.
+func ServerDirectory() string {
+	return base("launcher") + "/servers/directory.json"
+}
+
+// Presence returns the base URL of the presence service, which shares a
+// user's online status with their friends.
+func Presence() string {
+	return base("presence") + "/v1"
+}
+
+// ModIndex returns the base URL of the default mod index.
+func ModIndex() string {
+	return base("mods") + "/index/v1"
+}
+
+// CloudStorage returns the base URL of the account storage that saves and
+// settings are synced to.
+func CloudStorage() string {
+	return base("account-data") + "/storage/v1/launcher"
+}
+
 // LauncherData returns the URL for fetching account launcher data.
 // This includes profile, patchline, and EULA information.
 func LauncherData() string {
-	return fmt.Sprintf("https://account-data.%s/launcher-data", Domain)
+	return base("account-data") + "/launcher-data"
 }
 
 // OAuthBase returns the base URL for the OAuth authorization server.
 func OAuthBase() string {
-	return fmt.Sprintf("https://oauth.accounts.%s", Domain)
+	return base("oauth.accounts")
 }
 
 // OAuthAuth returns the OAuth authorization endpoint URL.
@@ -0,0 +1,188 @@
+package endpoints
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+
+	"hytale-launcher/internal/build"
+)
+
+// Environment describes the backend the launcher talks to. Each service
+// (launcher, account-data, oauth.accounts, ...) is either a subdomain of
+// Domain or, for mock servers that serve everything from one host, a path
+// prefix on it.
+type Environment struct {
+	// Name identifies the environment in settings and logs.
+	Name string `json:"name"`
+
+	// Domain is the base domain, or host and port for a mock server.
+	Domain string `json:"domain"`
+
+	// Insecure uses plain HTTP instead of HTTPS.
+	Insecure bool `json:"insecure,omitempty"`
+
+	// ServicePaths serves each service under a path prefix of Domain
+	// instead of a subdomain.
+	ServicePaths bool `json:"service_paths,omitempty"`
+}
+
+// Environment names.
+const (
+	EnvProduction = "production"
+	EnvStaging    = "staging"
+	EnvLocal      = "local"
+	EnvCustom     = "custom"
+)
+
+// localMockAddr is the address of the local mock server.
+const localMockAddr = "localhost:8787"
+
+var (
+	// envMu protects env.
+	envMu sync.RWMutex
+
+	// env is the selected environment; nil means production.
+	env *Environment
+
+	// overrides maps services to the base URLs they are served from
+	// instead of their production subdomains.
+	overrides map[string]string
+)
+
+// Production returns the production environment, served from Domain.
+func Production() Environment {
+	return Environment{Name: EnvProduction, Domain: Domain}
+}
+
+// Environments returns the predefined environments. A custom environment
+// is not included as it requires a domain.
+func Environments() []Environment {
+	return []Environment{
+		Production(),
+		{Name: EnvStaging, Domain: "staging." + Domain},
+		{Name: EnvLocal, Domain: localMockAddr, Insecure: true, ServicePaths: true},
+	}
+}
+
+// Lookup returns the environment with the given name. The domain is only
+// used for a custom environment.
+func Lookup(name, domain string) (Environment, error) {
+	if name == "" {
+		return Production(), nil
+	}
+	if name == EnvCustom {
+		domain = strings.TrimSuffix(strings.TrimSpace(domain), "/")
+		if domain == "" {
+			return Environment{}, errors.New("a custom environment requires a domain")
+		}
+		e := Environment{Name: EnvCustom, Domain: domain}
+		if rest, ok := strings.CutPrefix(domain, "http://"); ok {
+			e.Domain, e.Insecure = rest, true
+		} else {
+			e.Domain = strings.TrimPrefix(domain, "https://")
+		}
+		return e, nil
+	}
+
+	for _, e := range Environments() {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	return Environment{}, fmt.Errorf("unknown environment %q", name)
+}
+
+// Current returns the selected environment.
+func Current() Environment {
+	envMu.RLock()
+	defer envMu.RUnlock()
+	if env == nil {
+		return Production()
+	}
+	return *env
+}
+
+// SetEnvironment routes all endpoints to e. Only production may be used
+// outside development builds.
+func SetEnvironment(e Environment) error {
+	if e.Name != EnvProduction && !build.IsDev() {
+		return errors.New("endpoint environments can only be changed in development builds")
+	}
+
+	envMu.Lock()
+	defer envMu.Unlock()
+
+	if e.Name == EnvProduction {
+		env = nil
+	} else {
+		env = &e
+	}
+
+	slog.Info("endpoint environment selected",
+		"name", e.Name,
+		"domain", e.Domain,
+	)
+	return nil
+}
+
+// SetOverrides serves services from other base URLs in the production
+// environment, keyed by service name, replacing any earlier overrides.
+// Each must be an HTTPS URL on the production domain or one of its
+// subdomains. If any is not, none are applied.
+func SetOverrides(o map[string]string) error {
+	clean := make(map[string]string, len(o))
+	for service, raw := range o {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("invalid override for %s: %w", service, err)
+		}
+		host := strings.ToLower(u.Hostname())
+		if u.Scheme != "https" || u.User != nil || u.RawQuery != "" || u.Fragment != "" ||
+			(host != Domain && !strings.HasSuffix(host, "."+Domain)) {
+			return fmt.Errorf("invalid override for %s: %s is not an HTTPS URL on %s", service, raw, Domain)
+		}
+		clean[service] = strings.TrimSuffix(u.String(), "/")
+	}
+
+	envMu.Lock()
+	overrides = clean
+	envMu.Unlock()
+
+	if len(clean) > 0 {
+		slog.Info("endpoint overrides applied", "overrides", clean)
+	}
+	return nil
+}
+
+// override returns the base URL a service is redirected to, if any.
+func override(service string) (string, bool) {
+	envMu.RLock()
+	defer envMu.RUnlock()
+	u, ok := overrides[service]
+	return u, ok
+}
+
+// base returns the base URL of a service in the selected environment, such
+// as "https://launcher.hytale.com". In production, an override takes
+// precedence.
+func base(service string) string {
+	e := Current()
+	if e.Name == EnvProduction {
+		if u, ok := override(service); ok {
+			return u
+		}
+	}
+
+	scheme := "https"
+	if e.Insecure {
+		scheme = "http"
+	}
+	if e.ServicePaths {
+		return fmt.Sprintf("%s://%s/%s", scheme, e.Domain, service)
+	}
+	return fmt.Sprintf("%s://%s.%s", scheme, service, e.Domain)
+}
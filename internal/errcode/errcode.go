@@ -0,0 +1,136 @@
+// Package errcode reduces errors from the updater, downloader and APIs to
+// machine-readable codes, so the frontend can offer an action that fits
+// the failure, such as freeing disk space or logging in again, instead of
+// a generic error message.
+package errcode
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/auth"
+	"hytale-launcher/internal/i18n"
+	"hytale-launcher/internal/installlock"
+	"hytale-launcher/internal/ioutil"
+	lnet "hytale-launcher/internal/net"
+	"hytale-launcher/internal/pinning"
+	"hytale-launcher/internal/pkg"
+)
+
+// Error codes.
+const (
+	Cancelled         = "cancelled"
+	Offline           = "offline"
+	Network           = "network"
+	Server            = "server_error"
+	AuthExpired       = "auth_expired"
+	Forbidden         = "forbidden"
+	NotFound          = "not_found"
+	ChecksumMismatch  = "checksum_mismatch"
+	InsufficientSpace = "insufficient_space"
+	PatchApply        = "patch_apply"
+	PatchValidation   = "patch_validation"
+	InstallBusy       = "install_busy"
+	Permission        = "permission"
+	ElevationRequired = "elevation_required"
+	RateLimited       = "rate_limited"
+	CertificatePin    = "certificate_pin"
+	Unknown           = "unknown"
+)
+
+// Error is an error as reported to the frontend.
+type Error struct {
+	// Code identifies the kind of failure.
+	Code string `json:"code"`
+
+	// Message is the error message, for display and bug reports.
+	Message string `json:"message"`
+
+	// Text describes the failure to the user in the selected locale.
+	Text string `json:"text"`
+
+	// Retryable is true if retrying the operation as is may succeed.
+	Retryable bool `json:"retryable"`
+}
+
+// Describe returns err as reported to the frontend, or nil if err is nil.
+func Describe(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	code := Of(err)
+	return &Error{
+		Code:      code,
+		Message:   err.Error(),
+		Text:      Text(code),
+		Retryable: Retryable(code),
+	}
+}
+
+// Of returns the code for err, or an empty string if err is nil. The most
+// specific cause wins: a checksum mismatch during a download is reported
+// as such, not as a network failure.
+func Of(err error) string {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return Cancelled
+	case errors.Is(err, lnet.ErrOffline):
+		return Offline
+	case errors.Is(err, ioutil.ErrInsufficientSpace):
+		return InsufficientSpace
+	case errors.Is(err, ioutil.ErrChecksumMismatch):
+		return ChecksumMismatch
+	case errors.Is(err, pkg.ErrPatchApply):
+		return PatchApply
+	case errors.Is(err, pkg.ErrPatchValidation):
+		return PatchValidation
+	case errors.Is(err, installlock.ErrBusy):
+		return InstallBusy
+	case errors.Is(err, auth.ErrAuthExpired), errors.Is(err, api.ErrUnauthorized):
+		return AuthExpired
+	case errors.Is(err, api.ErrForbidden):
+		return Forbidden
+	case errors.Is(err, api.ErrNotFound):
+		return NotFound
+	case errors.Is(err, api.ErrRateLimited):
+		return RateLimited
+	case errors.Is(err, api.ErrServer):
+		return Server
+	case errors.Is(err, pkg.ErrElevationRequired):
+		return ElevationRequired
+	case errors.Is(err, os.ErrPermission):
+		return Permission
+	case errors.Is(err, pinning.ErrPinMismatch):
+		return CertificatePin
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return Network
+	default:
+		return Unknown
+	}
+}
+
+// Text describes the failure with code to the user in the selected locale.
+func Text(code string) string {
+	if !i18n.Has("error." + code) {
+		code = Unknown
+	}
+	return i18n.T("error." + code)
+}
+
+// Retryable reports whether an operation that failed with code may succeed
+// if retried without the user doing anything else. A checksum mismatch is
+// retryable, as the damaged download is discarded.
+func Retryable(code string) bool {
+	switch code {
+	case Network, Server, RateLimited, ChecksumMismatch, PatchApply, PatchValidation, InstallBusy, Unknown:
+		return true
+	default:
+		return false
+	}
+}
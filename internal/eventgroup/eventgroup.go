@@ -4,6 +4,8 @@
 package eventgroup
 
 import (
+	"context"
+	"errors"
 	"sync"
 )
 
@@ -28,10 +30,17 @@ type Group struct {
 	tasks []Task
 
 	// For parallel execution (Go/Wait pattern)
-	wg      sync.WaitGroup
-	errOnce sync.Once
-	err     error
-	mu      sync.Mutex
+	wg   sync.WaitGroup
+	errs []error
+	mu   sync.Mutex
+
+	// sem limits the number of active goroutines, if set by SetLimit.
+	sem chan struct{}
+
+	// ctx stops functions not yet started once done, if set by
+	// WithContext.
+	ctx       context.Context
+	ctxErrSet bool
 }
 
 // New creates a new Group with the given tasks.
@@ -39,6 +48,25 @@ func New(tasks ...Task) *Group {
 	return &Group{tasks: tasks}
 }
 
+// WithContext returns a new Group whose functions passed to Go are not
+// run once ctx is done; the context's error is returned by Wait instead.
+// Functions already running are not interrupted, and an error from one
+// function does not cancel the others.
+func WithContext(ctx context.Context) *Group {
+	return &Group{ctx: ctx}
+}
+
+// SetLimit limits the number of functions passed to Go that run at once
+// to n. Go blocks until a slot is free. A negative n removes the limit.
+// SetLimit must not be called while functions are running.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
 // Add appends a task to the group for sequential execution.
 func (g *Group) Add(task Task) {
 	g.tasks = append(g.tasks, task)
@@ -50,33 +78,49 @@ func (g *Group) Len() int {
 }
 
 // Go spawns a new goroutine that executes the given function.
-// The first call to return a non-nil error will be recorded and
-// returned by Wait. Go is safe to call from multiple goroutines.
+// Every non-nil error is recorded and returned by Wait. Go is safe to
+// call from multiple goroutines.
 //
 // This is similar to golang.org/x/sync/errgroup but does not
 // cancel other goroutines when one returns an error.
 func (g *Group) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
 	g.wg.Add(1)
 	go func() {
 		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+
+		if g.ctx != nil && g.ctx.Err() != nil {
+			g.mu.Lock()
+			if !g.ctxErrSet {
+				g.ctxErrSet = true
+				g.errs = append(g.errs, g.ctx.Err())
+			}
+			g.mu.Unlock()
+			return
+		}
+
 		if err := f(); err != nil {
-			g.errOnce.Do(func() {
-				g.mu.Lock()
-				g.err = err
-				g.mu.Unlock()
-			})
+			g.mu.Lock()
+			g.errs = append(g.errs, err)
+			g.mu.Unlock()
 		}
 	}()
 }
 
 // Wait blocks until all goroutines spawned with Go have completed.
-// It returns the first non-nil error encountered by any goroutine,
-// or nil if all goroutines completed successfully.
+// It returns the errors of every goroutine that failed joined with
+// errors.Join, or nil if all goroutines completed successfully.
 func (g *Group) Wait() error {
 	g.wg.Wait()
 	g.mu.Lock()
 	defer g.mu.Unlock()
-	return g.err
+	return errors.Join(g.errs...)
 }
 
 // Exec executes all tasks in the group sequentially, reporting progress
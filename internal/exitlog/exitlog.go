@@ -0,0 +1,180 @@
+// Package exitlog keeps a small journal of launcher runs and why each one
+// exited, so that an unclean exit (a crash, or the process being killed)
+// can be told apart from an intentional restart such as a self-update.
+package exitlog
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/hytale"
+)
+
+// Exit reasons.
+const (
+	// ReasonQuit is a normal exit requested by the user.
+	ReasonQuit = "quit"
+
+	// ReasonSelfUpdate is an exit to let a launcher update complete and
+	// start the new version.
+	ReasonSelfUpdate = "self_update"
+
+	// ReasonError is an exit after a fatal startup or runtime error.
+	ReasonError = "error"
+
+	// ReasonCrash is an exit caused by a panic on the main goroutine.
+	ReasonCrash = "crash"
+
+	// ReasonUnclean marks a run that ended without recording a reason,
+	// detected on the next startup.
+	ReasonUnclean = "unclean"
+)
+
+const (
+	// fileName is the journal file in the storage directory.
+	fileName = "exit-journal.json"
+
+	// maxEntries is the number of runs kept in the journal.
+	maxEntries = 20
+)
+
+// Path returns the path of the journal file.
+func Path() string {
+	return hytale.InStorageDir(fileName)
+}
+
+// Entry records a single launcher run.
+type Entry struct {
+	PID       int        `json:"pid"`
+	Version   string     `json:"version"`
+	StartedAt time.Time  `json:"started_at"`
+	ExitedAt  *time.Time `json:"exited_at,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+}
+
+var (
+	// mu protects the journal file and the variables below.
+	mu sync.Mutex
+
+	// started is true once Begin has added this run to the journal.
+	started bool
+
+	// unclean is the previous run, if it exited uncleanly.
+	unclean *Entry
+)
+
+// Begin adds this run to the journal. If the previous run never recorded
+// an exit reason, it is marked unclean and returned by UncleanExit.
+func Begin() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	entries := read()
+	if n := len(entries); n > 0 && entries[n-1].ExitedAt == nil {
+		prev := entries[n-1]
+		prev.Reason = ReasonUnclean
+		entries[n-1] = prev
+		unclean = &prev
+
+		slog.Warn("previous launcher run exited uncleanly",
+			"pid", prev.PID,
+			"version", prev.Version,
+			"started_at", prev.StartedAt,
+		)
+	}
+
+	entries = append(entries, Entry{
+		PID:       os.Getpid(),
+		Version:   build.Version,
+		StartedAt: time.Now().UTC(),
+	})
+	if len(entries) > maxEntries {
+		entries = entries[len(entries)-maxEntries:]
+	}
+	write(entries)
+	started = true
+}
+
+// Record records why this run is exiting. Only the first reason recorded
+// is kept, so a specific reason is not overwritten by a generic one on the
+// way out.
+func Record(reason string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !started {
+		return
+	}
+
+	entries := read()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].PID != os.Getpid() || entries[i].ExitedAt != nil {
+			continue
+		}
+		now := time.Now().UTC()
+		entries[i].ExitedAt = &now
+		entries[i].Reason = reason
+		write(entries)
+
+		slog.Info("recorded launcher exit", "reason", reason)
+		return
+	}
+}
+
+// UncleanExit returns the previous run if it exited without recording a
+// reason, or nil.
+func UncleanExit() *Entry {
+	mu.Lock()
+	defer mu.Unlock()
+	return unclean
+}
+
+// Journal returns the recorded runs, oldest first.
+func Journal() []Entry {
+	mu.Lock()
+	defer mu.Unlock()
+	return read()
+}
+
+// read reads the journal. Errors are logged and treated as an empty
+// journal. The caller must hold mu.
+func read() []Entry {
+	data, err := os.ReadFile(hytale.InStorageDir(fileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	var entries []Entry
+	if err == nil {
+		err = json.Unmarshal(data, &entries)
+	}
+	if err != nil {
+		slog.Warn("unable to read exit journal", "error", err)
+		return nil
+	}
+	return entries
+}
+
+// write saves the journal, replacing it atomically. The caller must hold mu.
+func write(entries []Entry) {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		slog.Warn("unable to encode exit journal", "error", err)
+		return
+	}
+
+	path := hytale.InStorageDir(fileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		slog.Warn("unable to write exit journal", "error", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		slog.Warn("unable to write exit journal", "error", err)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"hytale-launcher/internal/ioutil"
 )
 
 // ProgressFunc is called during extraction to report progress.
@@ -36,14 +38,21 @@ func StripRootDir(path string) string {
 
 // safePath validates and constructs a safe file path within the destination directory.
 // It prevents path traversal attacks by ensuring the resulting path is within destDir.
+// Names may use either separator, and components Windows cannot create are
+// renamed.
 func safePath(destDir, name string) (string, error) {
-	fullPath := filepath.Join(destDir, name)
-	cleanDest := filepath.Clean(destDir) + "/"
+	rel, err := ioutil.SafeRelPath(name)
+	if err != nil {
+		return "", fmt.Errorf("illegal file path in archive: %s", name)
+	}
+
+	fullPath := filepath.Join(destDir, rel)
+	cleanDest := filepath.Clean(destDir) + string(os.PathSeparator)
 	cleanFull := filepath.Clean(fullPath)
 
 	// Check that the full path starts with the destination directory
 	if strings.HasPrefix(cleanFull, cleanDest) || cleanFull == filepath.Clean(destDir) {
-		return fullPath, nil
+		return ioutil.LongPath(fullPath), nil
 	}
 
 	return "", fmt.Errorf("illegal file path in archive: %s", name)
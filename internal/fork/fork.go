@@ -1,11 +1,17 @@
 // Package fork provides utilities for spawning child processes with
-// various privilege levels.
+// various privilege levels, detached from the launcher or in a process
+// group that can be terminated as a whole.
 package fork
 
 import (
+	"errors"
 	"os"
 )
 
+// ErrElevationDeclined is returned by RunElevated when the user declines
+// the elevation prompt.
+var ErrElevationDeclined = errors.New("the user declined to run the process with elevated privileges")
+
 // StartOptions contains options for starting a child process.
 type StartOptions struct {
 	// Path is the path to the executable.
@@ -18,6 +24,26 @@ type StartOptions struct {
 	Env []string
 }
 
+// Run starts a process with the privileges of the current process.
+func Run(path string, args []string) (*os.Process, error) {
+	return startProcess(StartOptions{
+		Path: path,
+		Args: args,
+	})
+}
+
+// CanWrite returns true if the current process can create files in dir,
+// so it can be updated without elevated privileges.
+func CanWrite(dir string) bool {
+	f, err := os.CreateTemp(dir, ".write-test-*")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
+
 // startProcess starts a new process with the given options and file descriptors.
 func startProcess(opts StartOptions) (*os.Process, error) {
 	// Prepare attributes
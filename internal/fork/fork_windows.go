@@ -3,6 +3,7 @@
 package fork
 
 import (
+	"errors"
 	"os"
 	"syscall"
 
@@ -36,7 +37,8 @@ func IsElevated() bool {
 }
 
 // RunElevated starts a process with elevated privileges using Windows UAC.
-// This will trigger a UAC prompt for the user to approve.
+// This will trigger a UAC prompt for the user to approve. If the user
+// declines, ErrElevationDeclined is returned.
 func RunElevated(path string, args []string) (*os.Process, error) {
 	verb := "runas"
 	cwd, _ := os.Getwd()
@@ -61,6 +63,9 @@ func RunElevated(path string, args []string) (*os.Process, error) {
 	cwdPtr, _ := syscall.UTF16PtrFromString(cwd)
 
 	err := windows.ShellExecute(0, verbPtr, pathPtr, argsPtr, cwdPtr, windows.SW_SHOWNORMAL)
+	if errors.Is(err, windows.ERROR_CANCELLED) {
+		return nil, ErrElevationDeclined
+	}
 	if err != nil {
 		return nil, err
 	}
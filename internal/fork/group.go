@@ -0,0 +1,40 @@
+package fork
+
+import (
+	"os/exec"
+	"time"
+)
+
+// Group is a started child process together with the processes it starts:
+// a process group on POSIX systems and a job object on Windows.
+type Group struct {
+	// Pid is the process ID of the child that was started.
+	Pid int
+
+	sys groupSys
+}
+
+// TerminateTree asks every process in the group to exit, and kills those
+// still running once exited is closed or grace has passed. exited should
+// be closed when the started child has exited. On Windows processes cannot
+// be asked to exit, so they are killed right away.
+func (g *Group) TerminateTree(grace time.Duration, exited <-chan struct{}) error {
+	if err := g.terminate(); err != nil {
+		return err
+	}
+
+	select {
+	case <-exited:
+	case <-time.After(grace):
+	}
+	return g.kill()
+}
+
+// ensureSysProcAttr returns the system attributes of cmd, creating them if
+// it has none.
+func ensureSysProcAttr(cmd *exec.Cmd) *sysProcAttr {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &sysProcAttr{}
+	}
+	return cmd.SysProcAttr
+}
@@ -0,0 +1,60 @@
+//go:build !windows
+
+package fork
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+type sysProcAttr = syscall.SysProcAttr
+
+// groupSys holds nothing on POSIX systems; the process group ID is the
+// child's PID.
+type groupSys struct{}
+
+// StartDetached starts cmd in a new session, so it keeps running when the
+// launcher exits or its terminal is closed, and does not receive signals
+// sent to the launcher's process group.
+func StartDetached(cmd *exec.Cmd) error {
+	ensureSysProcAttr(cmd).Setsid = true
+	return cmd.Start()
+}
+
+// StartInGroup starts cmd as the leader of a new process group, which the
+// processes it starts join, so the whole tree can be terminated with
+// TerminateTree.
+func StartInGroup(cmd *exec.Cmd) (*Group, error) {
+	ensureSysProcAttr(cmd).Setpgid = true
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &Group{Pid: cmd.Process.Pid}, nil
+}
+
+// terminate sends SIGTERM to every process in the group.
+func (g *Group) terminate() error {
+	return g.signal(syscall.SIGTERM)
+}
+
+// kill sends SIGKILL to every process in the group.
+func (g *Group) kill() error {
+	return g.signal(syscall.SIGKILL)
+}
+
+// signal sends sig to the process group. A group with no processes left
+// is not an error.
+func (g *Group) signal(sig syscall.Signal) error {
+	err := syscall.Kill(-g.Pid, sig)
+	if err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("unable to signal process group %d: %w", g.Pid, err)
+	}
+	return nil
+}
+
+// Close releases the group. Its processes are left running.
+func (g *Group) Close() error {
+	return nil
+}
@@ -0,0 +1,106 @@
+//go:build windows
+
+package fork
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type sysProcAttr = syscall.SysProcAttr
+
+// groupSys is the job object the group's processes are assigned to.
+type groupSys struct {
+	job windows.Handle
+}
+
+// StartDetached starts cmd in a new process group, so it does not receive
+// console control events sent to the launcher, and keeps running when the
+// launcher exits.
+func StartDetached(cmd *exec.Cmd) error {
+	ensureSysProcAttr(cmd).CreationFlags |= windows.CREATE_NEW_PROCESS_GROUP
+	return cmd.Start()
+}
+
+// StartInGroup starts cmd in a new job object, which the processes it
+// starts also belong to, so the whole tree can be terminated with
+// TerminateTree. The job kills its processes when its last handle is
+// closed, so they do not outlive the launcher.
+func StartInGroup(cmd *exec.Cmd) (*Group, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create job object: %w", err)
+	}
+
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	if _, err := windows.SetInformationJobObject(
+		job,
+		windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)),
+		uint32(unsafe.Sizeof(info)),
+	); err != nil {
+		windows.CloseHandle(job)
+		return nil, fmt.Errorf("unable to configure job object: %w", err)
+	}
+
+	ensureSysProcAttr(cmd).CreationFlags |= windows.CREATE_NEW_PROCESS_GROUP
+	if err := cmd.Start(); err != nil {
+		windows.CloseHandle(job)
+		return nil, err
+	}
+
+	g := &Group{Pid: cmd.Process.Pid, sys: groupSys{job: job}}
+	h, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(g.Pid))
+	if err == nil {
+		err = windows.AssignProcessToJobObject(job, h)
+		windows.CloseHandle(h)
+	}
+	if err != nil {
+		// The process runs, but outside the job; only it can be killed.
+		windows.CloseHandle(job)
+		g.sys.job = 0
+	}
+	return g, nil
+}
+
+// terminate kills the job, as there is no signal asking a Windows
+// process to exit.
+func (g *Group) terminate() error {
+	return g.kill()
+}
+
+// kill terminates every process in the job, or only the started child if
+// it could not be assigned to one.
+func (g *Group) kill() error {
+	if g.sys.job == 0 {
+		h, err := windows.OpenProcess(windows.PROCESS_TERMINATE, false, uint32(g.Pid))
+		if err != nil {
+			// The process has already exited.
+			return nil
+		}
+		defer windows.CloseHandle(h)
+		return windows.TerminateProcess(h, 1)
+	}
+	if err := windows.TerminateJobObject(g.sys.job, 1); err != nil {
+		return fmt.Errorf("unable to terminate job object: %w", err)
+	}
+	return nil
+}
+
+// Close releases the job object, which kills any processes still in it.
+func (g *Group) Close() error {
+	if g.sys.job == 0 {
+		return nil
+	}
+	err := windows.CloseHandle(g.sys.job)
+	g.sys.job = 0
+	return err
+}
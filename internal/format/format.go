@@ -0,0 +1,119 @@
+package format
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bytes formats a size in bytes using binary multiples, e.g. "1.5 GB".
+// Values under 10 in their unit keep one decimal place.
+func Bytes(n int64) string {
+	return Current().FormatBytes(n)
+}
+
+// Speed formats a transfer rate in bytes per second, e.g. "12 MB/s".
+func Speed(bytesPerSecond int64) string {
+	return Current().FormatSpeed(bytesPerSecond)
+}
+
+// Duration formats a duration with its two largest units, e.g. "1h 5m" or
+// "45s". Durations under a second are shown as zero seconds.
+func Duration(d time.Duration) string {
+	return Current().FormatDuration(d)
+}
+
+// ETA formats the time left to transfer the remaining bytes at the given
+// speed. Returns an empty string if the speed is not known.
+func ETA(remaining, bytesPerSecond int64) string {
+	return Current().FormatETA(remaining, bytesPerSecond)
+}
+
+// FormatBytes formats a size in bytes in this locale.
+func (l Locale) FormatBytes(n int64) string {
+	if n < 0 {
+		n = 0
+	}
+
+	v := float64(n)
+	unit := 0
+	for v >= 1024 && unit < len(l.Bytes)-1 {
+		v /= 1024
+		unit++
+	}
+
+	decimals := 0
+	if unit > 0 && v < 10 {
+		decimals = 1
+	}
+	return l.number(v, decimals) + nbsp + l.Bytes[unit]
+}
+
+// FormatSpeed formats a transfer rate in this locale.
+func (l Locale) FormatSpeed(bytesPerSecond int64) string {
+	return fmt.Sprintf(l.PerSecond, l.FormatBytes(bytesPerSecond))
+}
+
+// FormatDuration formats a duration in this locale.
+func (l Locale) FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < 0 {
+		d = 0
+	}
+
+	h := int64(d / time.Hour)
+	m := int64(d % time.Hour / time.Minute)
+	s := int64(d % time.Minute / time.Second)
+
+	switch {
+	case h > 0 && m > 0:
+		return l.unit(h, l.Hours) + " " + l.unit(m, l.Minutes)
+	case h > 0:
+		return l.unit(h, l.Hours)
+	case m > 0 && s > 0:
+		return l.unit(m, l.Minutes) + " " + l.unit(s, l.Seconds)
+	case m > 0:
+		return l.unit(m, l.Minutes)
+	default:
+		return l.unit(s, l.Seconds)
+	}
+}
+
+// FormatETA formats the time left for a transfer in this locale.
+func (l Locale) FormatETA(remaining, bytesPerSecond int64) string {
+	if bytesPerSecond <= 0 || remaining < 0 {
+		return ""
+	}
+	seconds := math.Ceil(float64(remaining) / float64(bytesPerSecond))
+	return l.FormatDuration(time.Duration(seconds) * time.Second)
+}
+
+// unit formats a whole number with a unit.
+func (l Locale) unit(n int64, unit string) string {
+	return l.number(float64(n), 0) + l.UnitSpace + unit
+}
+
+// number formats v with the given number of decimal places, using this
+// locale's separators.
+func (l Locale) number(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	if len(intPart) > 3 {
+		var b strings.Builder
+		for i, r := range intPart {
+			if i > 0 && (len(intPart)-i)%3 == 0 {
+				b.WriteString(l.Group)
+			}
+			b.WriteRune(r)
+		}
+		intPart = b.String()
+	}
+
+	if hasFrac {
+		return intPart + l.Decimal + frac
+	}
+	return intPart
+}
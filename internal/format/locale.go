@@ -0,0 +1,132 @@
+// Package format formats byte sizes, durations and download estimates for
+// display, following the selected locale.
+package format
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// Locale holds the conventions used to format values for one language.
+type Locale struct {
+	// Tag is the language tag, e.g. "en" or "de".
+	Tag string `json:"tag"`
+
+	// Decimal separates the integer and fractional parts of a number.
+	Decimal string `json:"decimal"`
+
+	// Group separates groups of thousands.
+	Group string `json:"group"`
+
+	// Bytes are the units for bytes, kilobytes, megabytes, gigabytes and
+	// terabytes.
+	Bytes [5]string `json:"bytes"`
+
+	// PerSecond formats a speed from a size, e.g. "%s/s".
+	PerSecond string `json:"per_second"`
+
+	// Hours, Minutes and Seconds are the duration units.
+	Hours   string `json:"hours"`
+	Minutes string `json:"minutes"`
+	Seconds string `json:"seconds"`
+
+	// UnitSpace goes between a duration value and its unit. Sizes are
+	// always separated from their unit by a non-breaking space.
+	UnitSpace string `json:"unit_space"`
+}
+
+// nbsp is a non-breaking space, used where a locale groups digits or
+// separates units with a space so values do not wrap.
+const nbsp = "\u00a0"
+
+// DefaultLocale is used when no locale is selected or the selected one is
+// not known.
+const DefaultLocale = "en"
+
+// locales are the supported locales, keyed by language tag.
+var locales = map[string]Locale{
+	"en": {Tag: "en", Decimal: ".", Group: ",", Bytes: [5]string{"B", "KB", "MB", "GB", "TB"},
+		PerSecond: "%s/s", Hours: "h", Minutes: "m", Seconds: "s", UnitSpace: ""},
+	"de": {Tag: "de", Decimal: ",", Group: ".", Bytes: [5]string{"B", "KB", "MB", "GB", "TB"},
+		PerSecond: "%s/s", Hours: "Std.", Minutes: "Min.", Seconds: "Sek.", UnitSpace: nbsp},
+	"es": {Tag: "es", Decimal: ",", Group: ".", Bytes: [5]string{"B", "KB", "MB", "GB", "TB"},
+		PerSecond: "%s/s", Hours: "h", Minutes: "min", Seconds: "s", UnitSpace: nbsp},
+	"fr": {Tag: "fr", Decimal: ",", Group: nbsp, Bytes: [5]string{"o", "Ko", "Mo", "Go", "To"},
+		PerSecond: "%s/s", Hours: "h", Minutes: "min", Seconds: "s", UnitSpace: nbsp},
+	"it": {Tag: "it", Decimal: ",", Group: ".", Bytes: [5]string{"B", "KB", "MB", "GB", "TB"},
+		PerSecond: "%s/s", Hours: "h", Minutes: "min", Seconds: "s", UnitSpace: nbsp},
+	"pl": {Tag: "pl", Decimal: ",", Group: nbsp, Bytes: [5]string{"B", "KB", "MB", "GB", "TB"},
+		PerSecond: "%s/s", Hours: "godz.", Minutes: "min", Seconds: "s", UnitSpace: nbsp},
+	"pt": {Tag: "pt", Decimal: ",", Group: ".", Bytes: [5]string{"B", "KB", "MB", "GB", "TB"},
+		PerSecond: "%s/s", Hours: "h", Minutes: "min", Seconds: "s", UnitSpace: nbsp},
+	"ru": {Tag: "ru", Decimal: ",", Group: nbsp, Bytes: [5]string{"Б", "КБ", "МБ", "ГБ", "ТБ"},
+		PerSecond: "%s/с", Hours: "ч", Minutes: "мин", Seconds: "с", UnitSpace: nbsp},
+	"ja": {Tag: "ja", Decimal: ".", Group: ",", Bytes: [5]string{"B", "KB", "MB", "GB", "TB"},
+		PerSecond: "%s/秒", Hours: "時間", Minutes: "分", Seconds: "秒", UnitSpace: ""},
+}
+
+var (
+	// mu protects current.
+	mu sync.RWMutex
+
+	// current is the selected locale.
+	current = locales[DefaultLocale]
+)
+
+// Current returns the selected locale.
+func Current() Locale {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// SetLocale selects the locale for a language tag such as "de" or "pt-BR".
+// Region and encoding suffixes are ignored. Returns the tag of the locale
+// selected, which is DefaultLocale if the language is not supported.
+func SetLocale(tag string) string {
+	l := Lookup(tag)
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = l
+	return l.Tag
+}
+
+// Lookup returns the locale for a language tag, or the default locale if
+// the language is not supported.
+func Lookup(tag string) Locale {
+	if l, ok := locales[language(tag)]; ok {
+		return l
+	}
+	return locales[DefaultLocale]
+}
+
+// Supported returns the tags of the supported locales.
+func Supported() []string {
+	tags := make([]string, 0, len(locales))
+	for tag := range locales {
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// Detect returns the language tag from the environment, as set on Linux
+// and macOS, or DefaultLocale if none is set.
+func Detect() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(key); v != "" && v != "C" && v != "POSIX" {
+			return language(v)
+		}
+	}
+	return DefaultLocale
+}
+
+// language reduces a tag such as "pt-BR" or "de_DE.UTF-8" to its language.
+func language(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_.@"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
@@ -12,7 +12,7 @@ import (
 
 // buildDir constructs the directory path for a specific game build.
 func buildDir(state *appstate.State, buildID int) string {
-	return hytale.PackageDir("game", state.Channel, fmt.Sprintf("build-%d", buildID))
+	return hytale.BuildDir("game", state.Channel, buildID)
 }
 
 // DemoteLatestGame moves the current game version to a numbered build directory,
@@ -72,8 +72,11 @@ func DeleteInstall(state *appstate.State, depName string, dep *appstate.Dep) err
 		"version", dep.Version,
 	)
 
-	// Remove the installation directory
-	if err := os.RemoveAll(dep.Path); err != nil {
+	// Remove the installation directory, unless it was adopted from the
+	// user and is theirs to delete.
+	if dep.Adopted {
+		slog.Info("leaving adopted installation in place", "path", dep.Path)
+	} else if err := os.RemoveAll(dep.Path); err != nil {
 		slog.Error("failed to remove installation directory",
 			"path", dep.Path,
 			"error", err,
@@ -0,0 +1,184 @@
+package hytale
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// LatestVersion is the version directory name that points at the active build.
+const LatestVersion = "latest"
+
+// buildsFile is the name of the manifest recording the installed builds of a package.
+const buildsFile = "builds.json"
+
+// UserDataDirs are the directories in a game build that hold the player's
+// data rather than installed files.
+var UserDataDirs = []string{"saves", "userdata", "screenshots", "mods"}
+
+// InstalledBuild describes one versioned install directory.
+type InstalledBuild struct {
+	Build   int    `json:"build"`
+	Version string `json:"version"`
+	Dir     string `json:"dir"`
+
+	// MinJava and MaxJava bound the Java major the build requires.
+	// Zero means no bound.
+	MinJava int `json:"min_java,omitempty"`
+	MaxJava int `json:"max_java,omitempty"`
+
+	// Adopted is set when Dir is an existing install outside the storage
+	// directory that was imported in place. It is never deleted.
+	Adopted bool `json:"adopted,omitempty"`
+
+	// ReleaseAt is set for a build preloaded ahead of its release, which
+	// is staged but not activated until then. It is cleared once the
+	// build is activated.
+	ReleaseAt *time.Time `json:"release_at,omitempty"`
+}
+
+// Released reports whether the build can be played: it is not a preloaded
+// build, or its release time has passed.
+func (b *InstalledBuild) Released() bool {
+	return b.ReleaseAt == nil || !time.Now().Before(*b.ReleaseAt)
+}
+
+// BuildManifest records the builds of a package installed side by side in
+// a channel, and which of them is the active ("latest") one.
+type BuildManifest struct {
+	// Latest is the build number of the active install.
+	Latest int `json:"latest"`
+
+	// Builds are the installed builds, ordered by build number.
+	Builds []InstalledBuild `json:"builds"`
+}
+
+// BuildDir returns the versioned install directory for a package build.
+// The path follows the pattern: StorageDir/channel/package/pkgID/build-N
+func BuildDir(pkgID, channel string, build int) string {
+	return PackageDir(pkgID, channel, fmt.Sprintf("build-%d", build))
+}
+
+// buildManifestPath returns the path to the build manifest for a package.
+func buildManifestPath(pkgID, channel string) string {
+	return filepath.Join(PackageDir(pkgID, channel, ""), buildsFile)
+}
+
+// LoadBuildManifest reads the build manifest for a package. A missing
+// manifest yields an empty one.
+func LoadBuildManifest(pkgID, channel string) (*BuildManifest, error) {
+	data, err := os.ReadFile(buildManifestPath(pkgID, channel))
+	if errors.Is(err, os.ErrNotExist) {
+		return &BuildManifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build manifest: %w", err)
+	}
+
+	var m BuildManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode build manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// Save writes the build manifest for a package and refreshes the "latest"
+// link. The manifest is written to a temporary file and renamed into place
+// so a crash never leaves it half-written.
+func (m *BuildManifest) Save(pkgID, channel string) error {
+	path := buildManifestPath(pkgID, channel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create package directory: %w", err)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode build manifest: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write build manifest: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace build manifest: %w", err)
+	}
+
+	m.linkLatest(pkgID, channel)
+	return nil
+}
+
+// Get returns the installed build with the given number, or nil.
+func (m *BuildManifest) Get(build int) *InstalledBuild {
+	for i := range m.Builds {
+		if m.Builds[i].Build == build {
+			return &m.Builds[i]
+		}
+	}
+	return nil
+}
+
+// Staged returns the preloaded build waiting for activation, or nil.
+func (m *BuildManifest) Staged() *InstalledBuild {
+	for i := range m.Builds {
+		if m.Builds[i].ReleaseAt != nil && m.Builds[i].Build != m.Latest {
+			return &m.Builds[i]
+		}
+	}
+	return nil
+}
+
+// Add records an installed build, replacing any existing entry for it.
+func (m *BuildManifest) Add(b InstalledBuild) {
+	m.Remove(b.Build)
+	m.Builds = append(m.Builds, b)
+	slices.SortFunc(m.Builds, func(x, y InstalledBuild) int {
+		return x.Build - y.Build
+	})
+}
+
+// Remove drops a build from the manifest.
+func (m *BuildManifest) Remove(build int) {
+	m.Builds = slices.DeleteFunc(m.Builds, func(b InstalledBuild) bool {
+		return b.Build == build
+	})
+	if m.Latest == build {
+		m.Latest = 0
+	}
+}
+
+// linkLatest points the "latest" directory entry at the active build with a
+// symlink, for users and tools that browse the install directory. The
+// manifest remains the source of truth, so failures are only logged. On
+// Windows, creating symlinks requires extra privileges and is skipped.
+func (m *BuildManifest) linkLatest(pkgID, channel string) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	link := PackageDir(pkgID, channel, LatestVersion)
+
+	// Never replace a real directory left by an older launcher version.
+	if info, err := os.Lstat(link); err == nil && info.Mode()&os.ModeSymlink == 0 {
+		return
+	}
+
+	_ = os.Remove(link)
+	if m.Latest == 0 {
+		return
+	}
+
+	target := filepath.Base(BuildDir(pkgID, channel, m.Latest))
+	if b := m.Get(m.Latest); b != nil && b.Adopted {
+		target = b.Dir
+	}
+	if err := os.Symlink(target, link); err != nil {
+		slog.Debug("unable to link latest build", "link", link, "error", err)
+	}
+}
@@ -0,0 +1,111 @@
+package hytale
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+
+	"hytale-launcher/internal/ioutil"
+)
+
+// experimentalDirName is the directory in the storage directory that
+// experimental channels are installed in, apart from the regular channels,
+// so an experimental build never shares files with a release install.
+const experimentalDirName = "experimental"
+
+// experimentalName matches the channel names accepted for experimental
+// channels, which come from the API and become directory names.
+var experimentalName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+var (
+	// experimentalMu protects experimental.
+	experimentalMu sync.RWMutex
+
+	// experimentalOnce guards loading experimental.
+	experimentalOnce sync.Once
+
+	// experimental holds the experimental channels opted into. Each has a
+	// directory in the experimental directory, which is what persists it.
+	experimental map[string]bool
+)
+
+// ExperimentalDir returns the directory experimental channels are
+// installed in.
+func ExperimentalDir() string {
+	return InStorageDir(experimentalDirName)
+}
+
+// loadExperimental fills experimental from the experimental directory.
+func loadExperimental() {
+	experimentalMu.Lock()
+	defer experimentalMu.Unlock()
+
+	experimental = make(map[string]bool)
+	entries, err := os.ReadDir(ExperimentalDir())
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() && experimentalName.MatchString(e.Name()) && !knownChannels[e.Name()] {
+			experimental[e.Name()] = true
+		}
+	}
+}
+
+// IsExperimentalChannel reports whether channel is an experimental channel
+// the user opted into, whose data lives in the experimental directory.
+func IsExperimentalChannel(channel string) bool {
+	experimentalOnce.Do(loadExperimental)
+
+	experimentalMu.RLock()
+	defer experimentalMu.RUnlock()
+	return experimental[channel]
+}
+
+// ExperimentalChannels returns the experimental channels opted into, in
+// alphabetical order.
+func ExperimentalChannels() []string {
+	experimentalOnce.Do(loadExperimental)
+
+	experimentalMu.RLock()
+	defer experimentalMu.RUnlock()
+	channels := make([]string, 0, len(experimental))
+	for channel := range experimental {
+		channels = append(channels, channel)
+	}
+	slices.Sort(channels)
+	return channels
+}
+
+// ValidExperimentalChannel reports whether name can be used as the name of
+// an experimental channel, and so as a directory name.
+func ValidExperimentalChannel(name string) bool {
+	return experimentalName.MatchString(name) && ioutil.SafeName(name) == name
+}
+
+// AddExperimentalChannel makes channel a known channel whose data lives in
+// its own directory in the experimental directory. The regular channels
+// keep their directories, so they cannot be added.
+func AddExperimentalChannel(channel string) error {
+	if knownChannels[channel] {
+		return fmt.Errorf("%s is a regular channel", channel)
+	}
+	if !ValidExperimentalChannel(channel) {
+		return fmt.Errorf("invalid experimental channel name %q", channel)
+	}
+	if IsExperimentalChannel(channel) {
+		return nil
+	}
+
+	if err := ioutil.MkdirAll(filepath.Join(ExperimentalDir(), channel)); err != nil {
+		return fmt.Errorf("unable to create experimental channel directory: %w", err)
+	}
+
+	experimentalMu.Lock()
+	defer experimentalMu.Unlock()
+	experimental[channel] = true
+	return nil
+}
@@ -9,11 +9,18 @@ import (
 	"sync"
 
 	"github.com/getsentry/sentry-go"
+
+	"hytale-launcher/internal/sysinfo"
 )
 
 // getDefaultAppDataDir returns the default application data directory.
-// On Linux, this is XDG_DATA_HOME or ~/.local/share if not set.
+// On Linux, this is XDG_DATA_HOME or ~/.local/share if not set. In a
+// Flatpak or Snap, it is the sandbox's data directory.
 func getDefaultAppDataDir() (string, error) {
+	if dir := sysinfo.SandboxDataDir(); dir != "" {
+		return dir, nil
+	}
+
 	// Check XDG_DATA_HOME first
 	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
 		return dir, nil
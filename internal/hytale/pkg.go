@@ -2,6 +2,9 @@ package hytale
 
 import (
 	"path/filepath"
+	"sort"
+
+	"hytale-launcher/internal/ioutil"
 )
 
 // Known channels for game releases.
@@ -17,20 +20,41 @@ var knownGamePackages = []string{
 	"game",
 }
 
-// ChannelDir returns the directory path for a given channel.
+// ChannelDir returns the directory path for a given channel. Experimental
+// channels are kept apart, in the experimental directory.
 func ChannelDir(channel string) string {
+	if IsExperimentalChannel(channel) {
+		return filepath.Join(ExperimentalDir(), channel)
+	}
 	return filepath.Join(StorageDir(), channel)
 }
 
 // PackageDir returns the directory path for a specific package version.
 // The path follows the pattern: StorageDir/channel/package/pkgID/version
+// Each component is made safe to create on the current platform. The path
+// is returned in normal form for display and comparison; wrap it with
+// ioutil.LongPath before writing deep trees into it on Windows.
 func PackageDir(pkgID, channel, version string) string {
-	return filepath.Join(ChannelDir(channel), "package", pkgID, version)
+	return filepath.Join(ChannelDir(ioutil.SafeName(channel)), "package",
+		ioutil.SafeName(pkgID), ioutil.SafeName(version))
 }
 
-// IsKnownChannel returns true if the channel name is a recognized release channel.
+// IsKnownChannel returns true if the channel name is a recognized release
+// channel or an experimental channel the user opted into.
 func IsKnownChannel(channel string) bool {
-	return knownChannels[channel]
+	return knownChannels[channel] || IsExperimentalChannel(channel)
+}
+
+// KnownChannels returns the names of the recognized release channels and
+// the experimental channels opted into, in alphabetical order.
+func KnownChannels() []string {
+	result := make([]string, 0, len(knownChannels))
+	for channel := range knownChannels {
+		result = append(result, channel)
+	}
+	result = append(result, ExperimentalChannels()...)
+	sort.Strings(result)
+	return result
 }
 
 // KnownGamePackages returns a slice of all known game package identifiers.
@@ -0,0 +1,126 @@
+// Package i18n translates the messages the backend shows to the user, such
+// as error descriptions, update states and native dialogs. Catalogs are
+// embedded JSON files, one per locale, mapping message keys to text.
+// Messages missing from a catalog fall back to English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+
+	"hytale-launcher/internal/format"
+)
+
+// DefaultLocale is the locale messages fall back to. Its catalog must
+// contain every message.
+const DefaultLocale = format.DefaultLocale
+
+//go:embed locales/*.json
+var catalogFiles embed.FS
+
+var (
+	// loadOnce guards reading the catalogs.
+	loadOnce sync.Once
+
+	// catalogs are the messages of each locale, keyed by locale tag and
+	// message key.
+	catalogs map[string]map[string]string
+
+	// mu protects current.
+	mu sync.RWMutex
+
+	// current is the tag of the selected locale.
+	current = DefaultLocale
+)
+
+// Message is a translated message as sent to the frontend. The key lets
+// the frontend substitute its own text.
+type Message struct {
+	Key  string `json:"key"`
+	Text string `json:"text"`
+}
+
+// load reads the embedded catalogs.
+func load() {
+	catalogs = make(map[string]map[string]string)
+
+	entries, err := catalogFiles.ReadDir("locales")
+	if err != nil {
+		slog.Error("unable to read message catalogs", "error", err)
+		return
+	}
+	for _, e := range entries {
+		data, err := catalogFiles.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			slog.Error("unable to read message catalog", "file", e.Name(), "error", err)
+			continue
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			slog.Error("invalid message catalog", "file", e.Name(), "error", err)
+			continue
+		}
+		catalogs[strings.TrimSuffix(e.Name(), ".json")] = messages
+	}
+}
+
+// SetLocale selects the locale messages are translated to, from a language
+// tag such as "de" or "pt-BR". Returns the tag of the locale selected,
+// which is DefaultLocale if there is no catalog for the language.
+func SetLocale(tag string) string {
+	loadOnce.Do(load)
+
+	tag = format.Lookup(tag).Tag
+	if _, ok := catalogs[tag]; !ok {
+		tag = DefaultLocale
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = tag
+	return tag
+}
+
+// Current returns the tag of the selected locale.
+func Current() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T returns the message for key in the selected locale, formatted with
+// args as by fmt.Sprintf. A key with no message is returned as is.
+func T(key string, args ...any) string {
+	loadOnce.Do(load)
+
+	text, ok := catalogs[Current()][key]
+	if !ok {
+		text, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		slog.Warn("missing message", "key", key)
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Has reports whether there is a message for key.
+func Has(key string) bool {
+	loadOnce.Do(load)
+
+	_, ok := catalogs[DefaultLocale][key]
+	return ok
+}
+
+// Msg returns the message for key in the selected locale, with its key.
+func Msg(key string, args ...any) Message {
+	return Message{Key: key, Text: T(key, args...)}
+}
@@ -0,0 +1,62 @@
+// Package installlock provides per-channel lock files that keep two
+// launcher processes, or the launcher and a command line tool, from
+// modifying the same installation at the same time.
+package installlock
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"hytale-launcher/internal/hytale"
+)
+
+// lockFileName is the name of the lock file in each channel directory.
+const lockFileName = ".install.lock"
+
+// ErrBusy is returned when another process is modifying the installation.
+var ErrBusy = errors.New("the installation is being modified by another launcher process")
+
+// Lock is an exclusive lock on a channel's installation.
+type Lock struct {
+	f *os.File
+}
+
+// Acquire takes the install lock for a channel without waiting. It returns
+// ErrBusy if another process holds it. The lock is released by Release or
+// when the process exits.
+func Acquire(channel string) (*Lock, error) {
+	dir := hytale.ChannelDir(channel)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create channel directory: %w", err)
+	}
+
+	path := filepath.Join(dir, lockFileName)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open install lock: %w", err)
+	}
+
+	if err := lockFile(f); err != nil {
+		f.Close()
+		if errors.Is(err, errWouldBlock) {
+			slog.Warn("install lock is held by another process", "channel", channel)
+			return nil, ErrBusy
+		}
+		return nil, fmt.Errorf("failed to lock installation: %w", err)
+	}
+
+	return &Lock{f: f}, nil
+}
+
+// Release releases the lock.
+func (l *Lock) Release() {
+	if l == nil || l.f == nil {
+		return
+	}
+	unlockFile(l.f)
+	l.f.Close()
+	l.f = nil
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package installlock
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// errWouldBlock is returned by lockFile when the lock is held elsewhere.
+var errWouldBlock = errors.New("lock is held")
+
+// lockFile takes an exclusive lock on the file without waiting.
+func lockFile(f *os.File) error {
+	err := unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return errWouldBlock
+	}
+	return err
+}
+
+// unlockFile releases the lock on the file.
+func unlockFile(f *os.File) {
+	unix.Flock(int(f.Fd()), unix.LOCK_UN)
+}
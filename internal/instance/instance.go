@@ -0,0 +1,173 @@
+// Package instance ensures only one launcher runs at a time. The first
+// launcher holds a lock file and listens on a socket in the storage
+// directory; later invocations forward their command line arguments to it
+// and exit, so two launchers never run updaters against the same state.
+package instance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hytale-launcher/internal/hytale"
+)
+
+// socketName is the name of the instance socket in the storage directory.
+const socketName = "launcher.sock"
+
+// lockName is the name of the instance lock file in the storage directory.
+// Only the launcher holding it may listen on, or replace, the socket.
+const lockName = "launcher.lock"
+
+// dialTimeout bounds how long a second instance waits for the first.
+const dialTimeout = 2 * time.Second
+
+// forwardAttempts and forwardRetryDelay bound waiting for a launcher that
+// holds the lock but is not listening yet, as it has only just started.
+const (
+	forwardAttempts   = 10
+	forwardRetryDelay = 200 * time.Millisecond
+)
+
+// ErrAlreadyRunning is returned by Acquire after the arguments have been
+// forwarded to a launcher that is already running.
+var ErrAlreadyRunning = errors.New("launcher is already running")
+
+// Message is sent by a second instance to the running launcher.
+type Message struct {
+	// Args are the second instance's command line arguments.
+	Args []string `json:"args"`
+
+	// WorkingDir is the second instance's working directory.
+	WorkingDir string `json:"working_dir,omitempty"`
+}
+
+// Lock is held by the running launcher.
+type Lock struct {
+	listener net.Listener
+	path     string
+	file     *os.File
+}
+
+// socketPath returns the path of the instance socket.
+func socketPath() string {
+	return hytale.InStorageDir(socketName)
+}
+
+// Acquire takes the instance lock. If another launcher holds it, args are
+// forwarded to that launcher and ErrAlreadyRunning is returned.
+func Acquire(args []string) (*Lock, error) {
+	path := socketPath()
+
+	lockPath := hytale.InStorageDir(lockName)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open instance lock: %w", err)
+	}
+
+	if err := lockFile(f); err != nil {
+		f.Close()
+		if !errors.Is(err, errWouldBlock) {
+			return nil, fmt.Errorf("failed to take instance lock: %w", err)
+		}
+
+		// Another launcher holds the lock. It may have only just started
+		// and not be listening yet, so try for a while.
+		for attempt := 0; attempt < forwardAttempts; attempt++ {
+			if err = forward(path, args); err == nil {
+				return nil, ErrAlreadyRunning
+			}
+			time.Sleep(forwardRetryDelay)
+		}
+		return nil, fmt.Errorf("launcher holding the instance lock did not answer: %w", err)
+	}
+
+	// This launcher holds the lock, so a socket left at path belongs to a
+	// launcher that exited without cleaning up.
+	if err := os.Remove(path); err == nil {
+		slog.Debug("removed stale instance socket", "path", path)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		unlockFile(f)
+		f.Close()
+		return nil, fmt.Errorf("unable to listen on instance socket at %s: %w", path, err)
+	}
+	return &Lock{listener: listener, path: path, file: f}, nil
+}
+
+// forward sends args to the launcher listening on path.
+func forward(path string, args []string) error {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(dialTimeout))
+
+	wd, _ := os.Getwd()
+	if err := json.NewEncoder(conn).Encode(Message{Args: args, WorkingDir: wd}); err != nil {
+		return err
+	}
+
+	// Wait for the acknowledgement so the message is not lost if this
+	// process exits immediately.
+	var ack [1]byte
+	_, err = conn.Read(ack[:])
+	return err
+}
+
+// Serve handles messages from later invocations until the lock is released.
+// It returns immediately; messages are handled on a separate goroutine.
+func (l *Lock) Serve(handler func(Message)) {
+	if l == nil {
+		return
+	}
+
+	go func() {
+		for {
+			conn, err := l.listener.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					slog.Warn("instance socket closed", "error", err)
+				}
+				return
+			}
+			go l.handle(conn, handler)
+		}
+	}()
+}
+
+// handle reads a single message from a connection.
+func (l *Lock) handle(conn net.Conn, handler func(Message)) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(dialTimeout))
+
+	var msg Message
+	if err := json.NewDecoder(conn).Decode(&msg); err != nil {
+		slog.Warn("invalid message from second instance", "error", err)
+		return
+	}
+	conn.Write([]byte{1})
+
+	handler(msg)
+}
+
+// Release stops listening and removes the socket.
+func (l *Lock) Release() {
+	if l == nil {
+		return
+	}
+	l.listener.Close()
+	os.Remove(l.path)
+	unlockFile(l.file)
+	l.file.Close()
+}
@@ -0,0 +1,179 @@
+// Package integrity notices game files that changed outside the launcher,
+// such as by disk corruption, an antivirus quarantine or accidental
+// deletion. A baseline of the game's critical files is recorded for each
+// channel and build, and checked before launch.
+package integrity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"hytale-launcher/internal/attest"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/repair"
+)
+
+// fileName is the name of the baseline file in a channel's directory.
+const fileName = "integrity.json"
+
+// Baseline is the state of a game build's critical files as installed by
+// the launcher.
+type Baseline struct {
+	Channel string    `json:"channel"`
+	Build   int       `json:"build"`
+	Created time.Time `json:"created"`
+
+	// Files maps slash-separated paths relative to the game directory to
+	// their SHA256 hashes.
+	Files map[string]string `json:"files"`
+}
+
+// Report lists the critical files that differ from the baseline.
+type Report struct {
+	Channel string `json:"channel"`
+	Build   int    `json:"build"`
+
+	// Changed are files whose contents differ.
+	Changed []string `json:"changed,omitempty"`
+
+	// Missing are files that were deleted.
+	Missing []string `json:"missing,omitempty"`
+
+	// Added are critical files the launcher did not install, such as a
+	// library dropped into the game directory.
+	Added []string `json:"added,omitempty"`
+}
+
+// Clean reports whether the files match the baseline.
+func (r *Report) Clean() bool {
+	return len(r.Changed) == 0 && len(r.Missing) == 0 && len(r.Added) == 0
+}
+
+// path returns the path of a channel's baseline file.
+func path(channel string) string {
+	return filepath.Join(hytale.ChannelDir(channel), fileName)
+}
+
+// Record hashes the critical files of the game build in gameDir and saves
+// them as the channel's baseline.
+func Record(gameDir, channel string, build int) (*Baseline, error) {
+	hashes, err := repair.HashFiles(gameDir, attest.IsCritical, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to hash game files: %w", err)
+	}
+
+	b := &Baseline{
+		Channel: channel,
+		Build:   build,
+		Created: time.Now(),
+		Files:   hashes,
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := save(channel, data); err != nil {
+		return nil, fmt.Errorf("unable to save integrity baseline: %w", err)
+	}
+
+	slog.Info("recorded integrity baseline", "channel", channel, "build", build, "files", len(hashes))
+	return b, nil
+}
+
+// save writes a channel's baseline file, replacing it atomically.
+func save(channel string, data []byte) error {
+	if err := ioutil.MkdirAll(hytale.ChannelDir(channel)); err != nil {
+		return err
+	}
+	p := path(channel)
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+// Load returns the channel's baseline, or nil if none was recorded.
+func Load(channel string) (*Baseline, error) {
+	data, err := os.ReadFile(path(channel))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid integrity baseline: %w", err)
+	}
+	return &b, nil
+}
+
+// Check compares the critical files of the game build in gameDir with the
+// channel's baseline. If there is no baseline for the build, one is
+// recorded and a clean report returned, as the files are then the ones the
+// launcher knows about.
+func Check(gameDir, channel string, build int) (*Report, error) {
+	b, err := Load(channel)
+	if err != nil {
+		slog.Warn("discarding unreadable integrity baseline", "channel", channel, "error", err)
+	}
+	if b == nil || b.Build != build {
+		if _, err := Record(gameDir, channel, build); err != nil {
+			return nil, err
+		}
+		return &Report{Channel: channel, Build: build}, nil
+	}
+
+	hashes, err := repair.HashFiles(gameDir, attest.IsCritical, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to hash game files: %w", err)
+	}
+
+	r := &Report{Channel: channel, Build: build}
+	for rel, want := range b.Files {
+		got, ok := hashes[rel]
+		switch {
+		case !ok:
+			r.Missing = append(r.Missing, rel)
+		case got != want:
+			r.Changed = append(r.Changed, rel)
+		}
+	}
+	for rel := range hashes {
+		if _, ok := b.Files[rel]; !ok {
+			r.Added = append(r.Added, rel)
+		}
+	}
+	slices.Sort(r.Changed)
+	slices.Sort(r.Missing)
+	slices.Sort(r.Added)
+
+	if !r.Clean() {
+		slog.Warn("game files changed outside the launcher",
+			"channel", channel,
+			"build", build,
+			"changed", len(r.Changed),
+			"missing", len(r.Missing),
+			"added", len(r.Added),
+		)
+	}
+	return r, nil
+}
+
+// Forget removes the channel's baseline, so the next check records a new
+// one.
+func Forget(channel string) error {
+	if err := os.Remove(path(channel)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -4,10 +4,13 @@ package ioutil
 import (
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
+
+	"hytale-launcher/internal/sysinfo"
 )
 
 // MkdirAll creates a directory and all parent directories with permissions 0755.
@@ -46,6 +49,11 @@ func StorageDir() string {
 		return ""
 	}
 
+	// Sandboxed launchers keep their data in the sandbox's directory
+	if dir := sysinfo.SandboxDataDir(); dir != "" {
+		return filepath.Join(dir, "hytale")
+	}
+
 	// Default to ~/.local/share/hytale on Linux
 	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
 		return fmt.Sprintf("%s/hytale", xdg)
@@ -81,3 +89,58 @@ func OpenDirectory(path string) error {
 	slog.Debug("opening directory", "path", path)
 	return nil
 }
+
+// CopyDir recursively copies the contents of src into dst, preserving file
+// modes and symlinks. dst is created if it does not exist.
+func CopyDir(src, dst string) error {
+	src, dst = LongPath(src), LongPath(dst)
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+// copyFile copies a single regular file.
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, CheckDiskFull(err))
+	}
+	return CheckDiskFull(out.Close())
+}
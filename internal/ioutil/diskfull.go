@@ -0,0 +1,19 @@
+package ioutil
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInsufficientSpace is returned when a write fails because the disk is
+// full.
+var ErrInsufficientSpace = errors.New("insufficient disk space")
+
+// CheckDiskFull returns err wrapped with ErrInsufficientSpace if it was
+// caused by a full disk, and err unchanged otherwise.
+func CheckDiskFull(err error) error {
+	if err == nil || errors.Is(err, ErrInsufficientSpace) || !isDiskFull(err) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrInsufficientSpace, err)
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package ioutil
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isDiskFull returns true if err is ENOSPC or EDQUOT.
+func isDiskFull(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EDQUOT)
+}
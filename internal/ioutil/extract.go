@@ -0,0 +1,429 @@
+package ioutil
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ExtractProgress is called during extraction with the work done so far
+// and the total, in bytes. For a zip archive these count uncompressed file
+// contents; a tar.gz archive's uncompressed size is not known up front, so
+// they count the compressed bytes consumed instead.
+type ExtractProgress func(done, total int64)
+
+// extractReportInterval is the minimum time between progress reports.
+const extractReportInterval = 100 * time.Millisecond
+
+// maxExtractWorkers caps the number of files written at once. Beyond a
+// few writers, disks gain nothing.
+const maxExtractWorkers = 8
+
+// maxBufferedEntry is the largest tar entry read into memory to be written
+// by a worker. Larger entries are written directly by the reader, as the
+// stream cannot move on until they are consumed anyway.
+const maxBufferedEntry = 8 << 20
+
+// maxBufferedBytes bounds the memory held by tar entries waiting for a
+// worker.
+const maxBufferedBytes = 64 << 20
+
+// ExtractArchive extracts an archive (zip, tar.gz) to the destination directory.
+func ExtractArchive(archivePath, destDir string) error {
+	return ExtractArchiveProgress(context.Background(), archivePath, destDir, nil)
+}
+
+// ExtractArchiveProgress extracts an archive (zip, tar.gz) to the
+// destination directory, writing files with a pool of workers and
+// reporting progress to onProgress, which may be nil. Entries that would
+// land outside destDir are rejected.
+func ExtractArchiveProgress(ctx context.Context, archivePath, destDir string, onProgress ExtractProgress) error {
+	lower := strings.ToLower(archivePath)
+
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return extractZip(ctx, archivePath, destDir, onProgress)
+	case strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz"):
+		return extractTarGz(ctx, archivePath, destDir, onProgress)
+	default:
+		return fmt.Errorf("unsupported archive format: %s", archivePath)
+	}
+}
+
+// extractDest returns the path an archive entry is extracted to, or an
+// error if it would fall outside destDir. Entry names may use either
+// separator; components Windows cannot create are renamed, and the result
+// is in extended-length form on Windows.
+func extractDest(destDir, name string) (string, error) {
+	rel, err := SafeRelPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	root := filepath.Clean(destDir)
+	destPath := filepath.Join(root, rel)
+	if destPath != root && !strings.HasPrefix(destPath, root+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid file path: %s", name)
+	}
+	return LongPath(destPath), nil
+}
+
+// fileMode returns the permissions to create an extracted file with: those
+// recorded in the archive, always readable and writable by the owner.
+func fileMode(mode fs.FileMode) fs.FileMode {
+	perm := mode.Perm()
+	if perm == 0 {
+		return 0644
+	}
+	return perm | 0600
+}
+
+// extractor runs file writes on a pool of workers and tracks progress.
+type extractor struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	jobs chan func() error
+	wg   sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+
+	done  atomic.Int64
+	total int64
+
+	onProgress ExtractProgress
+	stopReport chan struct{}
+	reportDone chan struct{}
+}
+
+// newExtractor starts the workers and the progress reporter.
+func newExtractor(ctx context.Context, total int64, onProgress ExtractProgress) *extractor {
+	ctx, cancel := context.WithCancel(ctx)
+	e := &extractor{
+		ctx:        ctx,
+		cancel:     cancel,
+		jobs:       make(chan func() error),
+		total:      total,
+		onProgress: onProgress,
+		stopReport: make(chan struct{}),
+		reportDone: make(chan struct{}),
+	}
+
+	workers := min(runtime.NumCPU(), maxExtractWorkers)
+	for range workers {
+		e.wg.Add(1)
+		go e.work()
+	}
+	go e.report()
+	return e
+}
+
+// work runs jobs until the queue is closed. After a failure, remaining
+// jobs are drained without running.
+func (e *extractor) work() {
+	defer e.wg.Done()
+	for job := range e.jobs {
+		if e.ctx.Err() != nil {
+			continue
+		}
+		if err := job(); err != nil {
+			e.fail(err)
+		}
+	}
+}
+
+// fail records the first error and stops further work.
+func (e *extractor) fail(err error) {
+	e.errOnce.Do(func() {
+		e.err = err
+		e.cancel()
+	})
+}
+
+// submit queues a job. It returns false once extraction has failed or
+// been cancelled.
+func (e *extractor) submit(job func() error) bool {
+	select {
+	case e.jobs <- job:
+		return true
+	case <-e.ctx.Done():
+		return false
+	}
+}
+
+// wait closes the queue, waits for the workers and returns the first
+// error. A final progress report is sent on success.
+func (e *extractor) wait() error {
+	close(e.jobs)
+	e.wg.Wait()
+	close(e.stopReport)
+	<-e.reportDone
+
+	err := e.err
+	if err == nil {
+		err = e.ctx.Err()
+	}
+	e.cancel()
+
+	if err == nil && e.onProgress != nil {
+		e.onProgress(e.total, e.total)
+	}
+	return err
+}
+
+// report sends progress at a bounded rate until extraction ends.
+func (e *extractor) report() {
+	defer close(e.reportDone)
+	if e.onProgress == nil {
+		return
+	}
+
+	ticker := time.NewTicker(extractReportInterval)
+	defer ticker.Stop()
+
+	last := int64(-1)
+	for {
+		select {
+		case <-e.stopReport:
+			return
+		case <-ticker.C:
+			if done := e.done.Load(); done != last {
+				last = done
+				e.onProgress(done, e.total)
+			}
+		}
+	}
+}
+
+// writeFile writes r to path, counting the bytes written as progress if
+// count is set.
+func (e *extractor) writeFile(path string, mode fs.FileMode, r io.Reader, count bool) error {
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fileMode(mode))
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = out
+	if count {
+		w = &countingWriter{w: out, n: &e.done}
+	}
+	_, err = io.Copy(w, &contextReader{ctx: e.ctx, r: r})
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return CheckDiskFull(err)
+}
+
+// countingWriter adds the bytes written through it to a counter.
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// contextReader stops reading once its context is done, so a cancelled
+// extraction does not finish writing a large file.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// extractZip extracts a zip archive to the destination directory. Entries
+// are read independently, so both decompression and writes run in
+// parallel.
+func extractZip(ctx context.Context, archivePath, destDir string, onProgress ExtractProgress) error {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open zip: %w", err)
+	}
+	defer r.Close()
+
+	// Validate every path and create the directories up front, so workers
+	// only write files. A path listed twice is written once, from its
+	// last entry, as a sequential extraction would leave it.
+	last := make(map[string]*zip.File)
+	var order []string
+	var total int64
+	for _, f := range r.File {
+		destPath, err := extractDest(destDir, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(destPath, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			return err
+		}
+		if prev, ok := last[destPath]; ok {
+			total -= int64(prev.UncompressedSize64)
+		} else {
+			order = append(order, destPath)
+		}
+		last[destPath] = f
+		total += int64(f.UncompressedSize64)
+	}
+
+	e := newExtractor(ctx, total, onProgress)
+	for _, destPath := range order {
+		f := last[destPath]
+		ok := e.submit(func() error {
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			return e.writeFile(destPath, f.Mode(), rc, true)
+		})
+		if !ok {
+			break
+		}
+	}
+	return e.wait()
+}
+
+// extractTarGz extracts a tar.gz archive to the destination directory. The
+// stream is read in order, and file contents are handed to workers to
+// write while reading continues.
+func extractTarGz(ctx context.Context, archivePath, destDir string, onProgress ExtractProgress) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	e := newExtractor(ctx, info.Size(), onProgress)
+	err = readTarGz(e, &countingReader{r: f, n: &e.done}, destDir)
+	if err != nil {
+		e.fail(err)
+	}
+	return e.wait()
+}
+
+// readTarGz reads a tar.gz stream and queues its files on the extractor.
+func readTarGz(e *extractor, r io.Reader, destDir string) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+
+	// buffered limits the memory held by queued entries.
+	buffered := make(chan struct{}, maxBufferedBytes/maxBufferedEntry)
+
+	// pending holds, for each path queued, a channel closed once it has
+	// been written, so a path listed twice is written in archive order.
+	pending := make(map[string]chan struct{})
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar header: %w", err)
+		}
+		if e.ctx.Err() != nil {
+			return nil
+		}
+
+		destPath, err := extractDest(destDir, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(destPath, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+				return err
+			}
+
+			prev := pending[destPath]
+			mode := header.FileInfo().Mode()
+
+			if header.Size > maxBufferedEntry {
+				if prev != nil {
+					<-prev
+				}
+				if err := e.writeFile(destPath, mode, tr, false); err != nil {
+					return err
+				}
+				delete(pending, destPath)
+				continue
+			}
+
+			data := make([]byte, header.Size)
+			if _, err := io.ReadFull(tr, data); err != nil {
+				return fmt.Errorf("failed to read %s: %w", header.Name, err)
+			}
+
+			written := make(chan struct{})
+			pending[destPath] = written
+			buffered <- struct{}{}
+
+			ok := e.submit(func() error {
+				defer close(written)
+				defer func() { <-buffered }()
+				if prev != nil {
+					<-prev
+				}
+				return e.writeFile(destPath, mode, bytes.NewReader(data), false)
+			})
+			if !ok {
+				<-buffered
+				return nil
+			}
+		}
+	}
+}
+
+// countingReader adds the bytes read through it to a counter.
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
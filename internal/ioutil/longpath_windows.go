@@ -0,0 +1,32 @@
+//go:build windows
+
+package ioutil
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// LongPath returns path in the extended-length form ("\\?\C:\...") that
+// lifts the 260 character MAX_PATH limit, so deep asset trees can be
+// written regardless of the system's long path setting. Relative paths are
+// made absolute first; paths already in extended or device form are
+// returned unchanged.
+func LongPath(path string) string {
+	if path == "" || strings.HasPrefix(path, `\\?\`) || strings.HasPrefix(path, `\\.\`) {
+		return path
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	// Extended-length paths are not normalized by Windows, so they must
+	// use backslashes and contain no "." or ".." components; filepath.Abs
+	// has already cleaned the path.
+	if rest, ok := strings.CutPrefix(abs, `\\`); ok {
+		return `\\?\UNC\` + rest
+	}
+	return `\\?\` + abs
+}
@@ -0,0 +1,113 @@
+package ioutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Errors returned by CheckPath.
+var (
+	ErrPathNotExist    = errors.New("path does not exist")
+	ErrPathNotDir      = errors.New("path is not a directory")
+	ErrPathIsDir       = errors.New("path is a directory")
+	ErrPathNotWritable = errors.New("path is not writable")
+	ErrPathManaged     = errors.New("path is inside a directory managed by the launcher")
+)
+
+// PathRules describe the requirements a user-selected path must meet.
+type PathRules struct {
+	// MustExist requires the path to exist.
+	MustExist bool
+
+	// Dir requires the path to be a directory; File requires it not to be.
+	// When the path does not exist, these apply to what will be created.
+	Dir  bool
+	File bool
+
+	// Writable requires the path, or its parent if it does not exist yet,
+	// to be writable.
+	Writable bool
+
+	// Forbidden lists directories the path must not be inside of.
+	Forbidden []string
+}
+
+// CheckPath validates a user-selected path against the rules. The path
+// must be absolute.
+func CheckPath(path string, rules PathRules) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("path must be absolute: %s", path)
+	}
+	path = filepath.Clean(path)
+
+	for _, dir := range rules.Forbidden {
+		if dir != "" && IsInside(path, dir) {
+			return fmt.Errorf("%w: %s", ErrPathManaged, path)
+		}
+	}
+
+	info, err := os.Stat(path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if !exists && rules.MustExist {
+		return fmt.Errorf("%w: %s", ErrPathNotExist, path)
+	}
+	if exists && rules.Dir && !info.IsDir() {
+		return fmt.Errorf("%w: %s", ErrPathNotDir, path)
+	}
+	if exists && rules.File && info.IsDir() {
+		return fmt.Errorf("%w: %s", ErrPathIsDir, path)
+	}
+
+	if rules.Writable {
+		dir := path
+		if !exists || !info.IsDir() {
+			dir = filepath.Dir(path)
+		}
+		if !IsWritable(dir) {
+			return fmt.Errorf("%w: %s", ErrPathNotWritable, dir)
+		}
+	}
+
+	return nil
+}
+
+// IsInside returns true if path is dir or is inside it, after resolving
+// symlinks where possible.
+func IsInside(path, dir string) bool {
+	path = resolve(path)
+	dir = resolve(dir)
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// resolve returns the path with symlinks resolved, or the cleaned path if
+// it cannot be resolved, such as when it does not exist yet.
+func resolve(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
+// IsWritable returns true if a file can be created in dir.
+func IsWritable(dir string) bool {
+	f, err := os.CreateTemp(dir, ".write-test-*")
+	if err != nil {
+		return false
+	}
+	name := f.Name()
+	f.Close()
+	os.Remove(name)
+	return true
+}
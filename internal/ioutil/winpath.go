@@ -0,0 +1,89 @@
+package ioutil
+
+import (
+	"fmt"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// reservedNames are the device names Windows reserves in every directory,
+// with or without an extension.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// IsReservedName returns true if name, a single path component, cannot be
+// created on Windows: a reserved device name such as "CON" or "nul.txt",
+// or a name ending in a dot or space, which Windows silently strips.
+func IsReservedName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return true
+	}
+	stem, _, _ := strings.Cut(name, ".")
+	return reservedNames[strings.ToUpper(strings.TrimRight(stem, " "))]
+}
+
+// SafeName returns name, a single path component, renamed if it cannot be
+// created on this platform. On Windows a reserved name gets an underscore
+// after its stem ("CON.txt" becomes "CON_.txt") and trailing dots and
+// spaces are replaced by underscores; elsewhere name is returned as is.
+func SafeName(name string) string {
+	if runtime.GOOS != "windows" || !IsReservedName(name) {
+		return name
+	}
+
+	trimmed := strings.TrimRight(name, ". ")
+	suffix := strings.Repeat("_", len(name)-len(trimmed))
+
+	stem, ext, hasExt := strings.Cut(trimmed, ".")
+	if reservedNames[strings.ToUpper(strings.TrimRight(stem, " "))] {
+		stem += "_"
+	}
+	if hasExt {
+		return stem + "." + ext + suffix
+	}
+	return stem + suffix
+}
+
+// SafeRelPath converts a slash- or backslash-separated relative path, such
+// as an archive entry name, to a relative path for this platform. Each
+// component is passed through SafeName, and paths that are absolute or
+// climb out of their root are rejected.
+func SafeRelPath(name string) (string, error) {
+	normalized := strings.ReplaceAll(name, `\`, "/")
+	if path.IsAbs(normalized) || filepath.VolumeName(filepath.FromSlash(normalized)) != "" {
+		return "", fmt.Errorf("invalid file path: %s", name)
+	}
+
+	cleaned := path.Clean(normalized)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid file path: %s", name)
+	}
+
+	parts := strings.Split(cleaned, "/")
+	renamed := false
+	for i, part := range parts {
+		if safe := SafeName(part); safe != part {
+			parts[i] = safe
+			renamed = true
+		}
+	}
+	if renamed {
+		slog.Warn("renamed reserved path component",
+			"path", name,
+			"renamed", strings.Join(parts, "/"),
+		)
+	}
+
+	return filepath.FromSlash(strings.Join(parts, "/")), nil
+}
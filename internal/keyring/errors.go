@@ -0,0 +1,105 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gokeyring "github.com/zalando/go-keyring"
+)
+
+// Kinds of keyring failure. Errors returned by this package match one of
+// these with errors.Is.
+var (
+	// ErrLocked means the keyring exists but is locked and could not be
+	// unlocked without user interaction.
+	ErrLocked = errors.New("keyring is locked")
+
+	// ErrUnavailable means there is no usable keyring service, e.g. no
+	// D-Bus session or Secret Service provider on Linux.
+	ErrUnavailable = errors.New("keyring is unavailable")
+
+	// ErrDenied means the user or the system refused access to the keyring.
+	ErrDenied = errors.New("keyring access was denied")
+)
+
+// Error describes a failed keyring operation.
+type Error struct {
+	// Op is the operation that failed ("get", "set" or "delete").
+	Op string
+
+	// Key is the name of the key being accessed.
+	Key string
+
+	// Kind is ErrLocked, ErrUnavailable or ErrDenied.
+	Kind error
+
+	// Err is the underlying error from the platform keyring.
+	Err error
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	return fmt.Sprintf("keyring %s %q: %v: %v", e.Op, e.Key, e.Kind, e.Err)
+}
+
+// Unwrap returns both the kind and the underlying error, so either can be
+// matched with errors.Is.
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Code returns a stable identifier for the error kind, for use by the frontend.
+func (e *Error) Code() string {
+	switch e.Kind {
+	case ErrLocked:
+		return "locked"
+	case ErrDenied:
+		return "denied"
+	default:
+		return "unavailable"
+	}
+}
+
+// Remediation returns a user-facing suggestion for resolving a keyring error.
+func (e *Error) Remediation() string {
+	switch e.Kind {
+	case ErrLocked:
+		return "Unlock your system keychain or keyring, then retry."
+	case ErrDenied:
+		return "Allow the launcher to access your system keychain or keyring when prompted, then retry."
+	default:
+		return "Credentials could not be saved to the system keyring or to the launcher's data folder. You will stay signed in until the launcher is closed. Make sure the launcher's data folder is writable, then retry."
+	}
+}
+
+// newError wraps an error from the platform keyring with its kind.
+func newError(op, key string, err error) *Error {
+	return &Error{Op: op, Key: key, Kind: classify(err), Err: err}
+}
+
+// classify maps an error from the platform keyring to one of the error kinds.
+// go-keyring reports most failures as plain strings from D-Bus, the macOS
+// security tool or the Windows credential API, so this matches on messages.
+// Unrecognized failures are treated as the keyring being unavailable.
+func classify(err error) error {
+	if errors.Is(err, errFileStore) || errors.Is(err, gokeyring.ErrUnsupportedPlatform) {
+		return ErrUnavailable
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "islocked"),
+		strings.Contains(msg, "is locked"),
+		strings.Contains(msg, "interaction is not allowed"):
+		return ErrLocked
+	case strings.Contains(msg, "dismissed"),
+		strings.Contains(msg, "user canceled"),
+		strings.Contains(msg, "access is denied"),
+		strings.Contains(msg, "permission denied"),
+		strings.Contains(msg, "not authorized"):
+		return ErrDenied
+	default:
+		return ErrUnavailable
+	}
+}
@@ -0,0 +1,269 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+
+	"hytale-launcher/internal/hytale"
+)
+
+// fileName is the name of the encrypted keyring file in the storage
+// directory.
+const fileName = "keyring.dat"
+
+// scrypt parameters for deriving the file's encryption key. The key is
+// derived once per run, so the cost is paid at most once.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 16
+)
+
+// fileContents is the on-disk layout of the keyring file. Each entry is
+// sealed separately, with the service and key as additional data so
+// entries cannot be swapped.
+type fileContents struct {
+	Salt    []byte            `json:"salt"`
+	Entries map[string][]byte `json:"entries"`
+}
+
+// fileKeyStore implements keyStore with an encrypted file in the storage
+// directory, for systems where the platform keyring cannot be used. The
+// encryption key is derived with scrypt from an identifier of the machine
+// and user, so the file is useless when copied elsewhere.
+type fileKeyStore struct {
+	mu sync.Mutex
+
+	// path is the keyring file, or empty for the default location.
+	path string
+
+	// contents is the decoded file, or nil until it is first read.
+	contents *fileContents
+
+	// aead seals the entries, or is nil until the key is first derived.
+	aead cipher.AEAD
+}
+
+// errFileStore marks failures of the keyring file, which leave nowhere to
+// persist credentials.
+var errFileStore = errors.New("keyring file failed")
+
+// files is the encrypted file keyring.
+var files = &fileKeyStore{}
+
+// filePath returns the location of the keyring file.
+func (f *fileKeyStore) filePath() string {
+	if f.path == "" {
+		f.path = hytale.InStorageDir(fileName)
+	}
+	return f.path
+}
+
+// entryName returns the name an entry is stored under.
+func entryName(service, key string) string {
+	return service + "/" + key
+}
+
+// load reads the keyring file, if not already read. A missing file is an
+// empty keyring.
+func (f *fileKeyStore) load() (*fileContents, error) {
+	if f.contents != nil {
+		return f.contents, nil
+	}
+
+	c := &fileContents{}
+	data, err := os.ReadFile(f.filePath())
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("unable to read keyring file: %w", err)
+	default:
+		if err := json.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("invalid keyring file: %w", err)
+		}
+	}
+	if c.Entries == nil {
+		c.Entries = make(map[string][]byte)
+	}
+	f.contents = c
+	return c, nil
+}
+
+// sealer returns the AEAD sealing the entries, deriving its key from the
+// machine identifier and the file's salt the first time. A salt is
+// generated for a new file.
+func (f *fileKeyStore) sealer(c *fileContents) (cipher.AEAD, error) {
+	if f.aead != nil {
+		return f.aead, nil
+	}
+
+	if len(c.Salt) == 0 {
+		c.Salt = make([]byte, saltLen)
+		if _, err := rand.Read(c.Salt); err != nil {
+			return nil, fmt.Errorf("unable to generate salt: %w", err)
+		}
+	}
+
+	id, err := machineSecret()
+	if err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key([]byte(id), c.Salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive keyring key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	f.aead = aead
+	return aead, nil
+}
+
+// save writes the keyring file, replacing it atomically.
+func (f *fileKeyStore) save(c *fileContents) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	path := f.filePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("unable to create keyring directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("unable to write keyring file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("unable to replace keyring file: %w", err)
+	}
+	return nil
+}
+
+// get retrieves a value from the keyring file.
+func (f *fileKeyStore) get(service, key string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	sealed, ok := c.Entries[entryName(service, key)]
+	if !ok {
+		return nil, nil
+	}
+
+	aead, err := f.sealer(c)
+	if err != nil {
+		return nil, err
+	}
+	n := aead.NonceSize()
+	if len(sealed) < n {
+		return nil, errors.New("keyring entry is truncated")
+	}
+	value, err := aead.Open(nil, sealed[:n], sealed[n:], []byte(entryName(service, key)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt keyring entry: %w", err)
+	}
+	return value, nil
+}
+
+// set stores a value in the keyring file.
+func (f *fileKeyStore) set(service, key string, value []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.load()
+	if err != nil {
+		return err
+	}
+	aead, err := f.sealer(c)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("unable to generate nonce: %w", err)
+	}
+	c.Entries[entryName(service, key)] = aead.Seal(nonce, nonce, value, []byte(entryName(service, key)))
+	return f.save(c)
+}
+
+// delete removes a value from the keyring file. The file is deleted once
+// it is empty.
+func (f *fileKeyStore) delete(service, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.load()
+	if err != nil {
+		return err
+	}
+	name := entryName(service, key)
+	if _, ok := c.Entries[name]; !ok {
+		return nil
+	}
+	delete(c.Entries, name)
+
+	if len(c.Entries) == 0 {
+		f.contents, f.aead = nil, nil
+		if err := os.Remove(f.filePath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("unable to remove keyring file: %w", err)
+		}
+		return nil
+	}
+	return f.save(c)
+}
+
+// keys returns the service and key of every entry in the keyring file.
+func (f *fileKeyStore) keys() ([][2]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	var out [][2]string
+	for name := range c.Entries {
+		if service, key, ok := strings.Cut(name, "/"); ok {
+			out = append(out, [2]string{service, key})
+		}
+	}
+	return out, nil
+}
+
+// machineSecret returns the input the file's key is derived from: the
+// machine identifier, falling back to the host name, and the user's home
+// directory, so other users of the machine derive a different key.
+func machineSecret() (string, error) {
+	id, err := machineID()
+	if err != nil || id == "" {
+		if id, err = os.Hostname(); err != nil {
+			return "", fmt.Errorf("unable to identify machine: %w", err)
+		}
+	}
+	home, _ := os.UserHomeDir()
+	return "hytale-launcher\x00" + id + "\x00" + home, nil
+}
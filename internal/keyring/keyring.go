@@ -1,9 +1,14 @@
 // Package keyring provides secure credential storage using the system keyring.
+// Where the system keyring cannot be used, e.g. without a Secret Service
+// provider on Linux, credentials are kept in an encrypted file instead, and
+// moved back to the system keyring once it works again.
 package keyring
 
 import (
 	"crypto/rand"
 	"fmt"
+	"log/slog"
+	"sync"
 )
 
 const (
@@ -15,30 +20,259 @@ const (
 type keyStore interface {
 	get(service, key string) ([]byte, error)
 	set(service, key string, value []byte) error
+	delete(service, key string) error
 }
 
+// Backends credentials can be stored in.
+const (
+	// BackendSystem is the platform keyring.
+	BackendSystem = "system"
+
+	// BackendFile is the encrypted keyring file.
+	BackendFile = "file"
+)
+
 // store is the platform-specific keyring implementation.
 var store keyStore
 
+var (
+	// mu protects degraded, fallback and onError.
+	mu sync.Mutex
+
+	// fallback holds the error that moved credentials to the keyring file.
+	// While set, the platform keyring is not tried.
+	fallback *Error
+
+	// degraded holds the error that put the keyring into degraded mode.
+	// While set, keyring operations fail fast with it instead of retrying
+	// the platform keyring, which may prompt the user each time.
+	degraded *Error
+
+	// onError is called when the keyring enters degraded mode.
+	onError func(*Error)
+)
+
 func init() {
 	store = newKeyStore()
 }
 
+// OnError registers a function to be called when a keyring failure puts
+// the keyring into degraded mode, so the user can be told how to fix it.
+func OnError(fn func(*Error)) {
+	mu.Lock()
+	defer mu.Unlock()
+	onError = fn
+}
+
+// Degraded returns the error that put the keyring into degraded mode,
+// or nil if the keyring is working.
+func Degraded() *Error {
+	mu.Lock()
+	defer mu.Unlock()
+	return degraded
+}
+
+// Backend returns the backend credentials are currently stored in.
+func Backend() string {
+	mu.Lock()
+	defer mu.Unlock()
+	if fallback != nil {
+		return BackendFile
+	}
+	return BackendSystem
+}
+
+// Fallback returns the error that moved credentials to the keyring file, or
+// nil if the platform keyring is in use.
+func Fallback() *Error {
+	mu.Lock()
+	defer mu.Unlock()
+	return fallback
+}
+
+// Retry leaves degraded mode so the next operation tries the platform
+// keyring again, e.g. after the user has unlocked it. If the platform
+// keyring now works, credentials in the keyring file are moved to it.
+func Retry() {
+	mu.Lock()
+	degraded = nil
+	fallback = nil
+	mu.Unlock()
+
+	migrate()
+}
+
+// useFile switches to the keyring file after the platform keyring failed.
+func useFile(op, key string, err error) {
+	kerr := newError(op, key, err)
+
+	mu.Lock()
+	first := fallback == nil
+	if first {
+		fallback = kerr
+	}
+	mu.Unlock()
+
+	if first {
+		slog.Info("system keyring unavailable, storing credentials in an encrypted file",
+			"op", op,
+			"kind", kerr.Kind,
+			"error", err,
+		)
+	}
+}
+
+// migrate moves the entries of the keyring file to the platform keyring.
+// Entries that cannot be moved stay in the file, and the file stays in
+// use.
+func migrate() {
+	entries, err := files.keys()
+	if err != nil {
+		slog.Warn("unable to read keyring file", "error", err)
+		return
+	}
+
+	for _, e := range entries {
+		if Backend() == BackendFile {
+			return
+		}
+		moveToSystem(e[0], e[1])
+	}
+}
+
+// moveToSystem moves an entry from the keyring file to the platform
+// keyring, returning its value, or nil if the file has no such entry or it
+// could not be read.
+func moveToSystem(service, key string) []byte {
+	value, err := files.get(service, key)
+	if err != nil || value == nil {
+		return nil
+	}
+	if err := store.set(service, key, value); err != nil {
+		useFile("set", key, err)
+		return value
+	}
+	if err := files.delete(service, key); err != nil {
+		slog.Warn("unable to remove migrated keyring entry", "key", key, "error", err)
+	}
+	slog.Info("moved credential from keyring file to system keyring", "key", key)
+	return value
+}
+
+// fail records a keyring failure, entering degraded mode and notifying the
+// error handler the first time.
+func fail(op, key string, err error) error {
+	kerr := newError(op, key, err)
+
+	mu.Lock()
+	first := degraded == nil
+	if first {
+		degraded = kerr
+	}
+	fn := onError
+	mu.Unlock()
+
+	if first {
+		slog.Warn("keyring file unavailable, credentials will not be persisted",
+			"op", op,
+			"kind", kerr.Kind,
+			"error", err,
+		)
+		if fn != nil {
+			fn(kerr)
+		}
+	}
+
+	return kerr
+}
+
 // Get retrieves a value from the keyring.
 func Get(key string) ([]byte, error) {
-	return store.get(ServiceName, key)
+	return GetService(ServiceName, key)
 }
 
 // Set stores a value in the keyring.
 func Set(key string, value []byte) error {
-	return store.set(ServiceName, key, value)
+	return SetService(ServiceName, key, value)
+}
+
+// GetService retrieves a value stored under another service name, for
+// tools that keep their own credentials.
+func GetService(service, key string) ([]byte, error) {
+	if d := Degraded(); d != nil {
+		return nil, d
+	}
+	if Backend() == BackendSystem {
+		value, err := store.get(service, key)
+		if err == nil {
+			if value == nil {
+				// Stored while the platform keyring was unavailable.
+				value = moveToSystem(service, key)
+			}
+			return value, nil
+		}
+		useFile("get", key, err)
+	}
+
+	value, err := files.get(service, key)
+	if err != nil {
+		return nil, fail("get", key, fmt.Errorf("%w: %w", errFileStore, err))
+	}
+	return value, nil
+}
+
+// SetService stores a value under another service name.
+func SetService(service, key string, value []byte) error {
+	if d := Degraded(); d != nil {
+		return d
+	}
+	if Backend() == BackendSystem {
+		err := store.set(service, key, value)
+		if err == nil {
+			// Drop any stale copy left from an earlier fallback.
+			if err := files.delete(service, key); err != nil {
+				slog.Warn("unable to remove keyring file entry", "key", key, "error", err)
+			}
+			return nil
+		}
+		useFile("set", key, err)
+	}
+
+	if err := files.set(service, key, value); err != nil {
+		return fail("set", key, fmt.Errorf("%w: %w", errFileStore, err))
+	}
+	return nil
+}
+
+// Delete removes a value from the keyring. A value that does not exist is
+// not an error.
+func Delete(key string) error {
+	return DeleteService(ServiceName, key)
+}
+
+// DeleteService removes a value stored under another service name, from
+// both the platform keyring and the keyring file.
+func DeleteService(service, key string) error {
+	if d := Degraded(); d != nil {
+		return d
+	}
+	if Backend() == BackendSystem {
+		if err := store.delete(service, key); err != nil {
+			useFile("delete", key, err)
+		}
+	}
+	if err := files.delete(service, key); err != nil {
+		return fail("delete", key, fmt.Errorf("%w: %w", errFileStore, err))
+	}
+	return nil
 }
 
 // GetOrGenKey retrieves a key from the keyring, or generates a new one if it doesn't exist.
 // The key is 32 bytes (256 bits) suitable for use with AES-256.
+// Failures are returned as *Error and match ErrLocked, ErrUnavailable or ErrDenied.
 func GetOrGenKey(key string) ([]byte, error) {
 	// Try to get existing key
-	existingKey, err := store.get(ServiceName, key)
+	existingKey, err := Get(key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve key '%s': %w", key, err)
 	}
@@ -55,7 +289,7 @@ func GetOrGenKey(key string) ([]byte, error) {
 	}
 
 	// Store the new key
-	if err := store.set(ServiceName, key, newKey); err != nil {
+	if err := Set(key, newKey); err != nil {
 		return nil, fmt.Errorf("failed to store key '%s': %w", key, err)
 	}
 
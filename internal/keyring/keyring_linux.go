@@ -10,6 +10,10 @@ import (
 	gokeyring "github.com/zalando/go-keyring"
 )
 
+// errDisabled is returned while the system keyring is not enabled, so the
+// keyring file is used instead.
+var errDisabled = errors.New("system keyring not enabled; set HYTALE_LAUNCHER_ENABLE_KEYRING to use it")
+
 // linuxKeyStore implements keyStore for Linux using the system keyring.
 type linuxKeyStore struct {
 	enabled bool
@@ -25,7 +29,7 @@ func newKeyStore() keyStore {
 // get retrieves a value from the Linux keyring.
 func (k *linuxKeyStore) get(service, key string) ([]byte, error) {
 	if !k.enabled {
-		return nil, nil
+		return nil, errDisabled
 	}
 
 	secret, err := gokeyring.Get(service, key)
@@ -48,10 +52,22 @@ func (k *linuxKeyStore) get(service, key string) ([]byte, error) {
 // set stores a value in the Linux keyring.
 func (k *linuxKeyStore) set(service, key string, value []byte) error {
 	if !k.enabled {
-		return nil
+		return errDisabled
 	}
 
 	// Encode to base64 for storage
 	encoded := base64.StdEncoding.EncodeToString(value)
 	return gokeyring.Set(service, key, encoded)
 }
+
+// delete removes a value from the Linux keyring.
+func (k *linuxKeyStore) delete(service, key string) error {
+	if !k.enabled {
+		return errDisabled
+	}
+
+	if err := gokeyring.Delete(service, key); err != nil && !errors.Is(err, gokeyring.ErrNotFound) {
+		return err
+	}
+	return nil
+}
@@ -42,3 +42,11 @@ func (k *windowsKeyStore) set(service, key string, value []byte) error {
 	encoded := base64.StdEncoding.EncodeToString(value)
 	return gokeyring.Set(service, key, encoded)
 }
+
+// delete removes a value from the Windows Credential Manager.
+func (k *windowsKeyStore) delete(service, key string) error {
+	if err := gokeyring.Delete(service, key); err != nil && !errors.Is(err, gokeyring.ErrNotFound) {
+		return err
+	}
+	return nil
+}
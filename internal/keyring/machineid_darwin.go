@@ -0,0 +1,25 @@
+//go:build darwin
+
+package keyring
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+)
+
+// platformUUID matches the hardware UUID in the output of ioreg.
+var platformUUID = regexp.MustCompile(`"IOPlatformUUID" = "([^"]+)"`)
+
+// machineID returns the hardware UUID of the Mac.
+func machineID() (string, error) {
+	out, err := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+	if err != nil {
+		return "", err
+	}
+	m := platformUUID.FindSubmatch(out)
+	if m == nil {
+		return "", errors.New("no IOPlatformUUID in ioreg output")
+	}
+	return string(m[1]), nil
+}
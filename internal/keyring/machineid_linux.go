@@ -0,0 +1,26 @@
+//go:build linux
+
+package keyring
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// machineIDFiles are where systemd and D-Bus keep the machine identifier.
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// machineID returns the systemd machine identifier.
+func machineID() (string, error) {
+	for _, path := range machineIDFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id, nil
+		}
+	}
+	return "", errors.New("no machine-id file")
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+
+package keyring
+
+import "errors"
+
+// machineID is not supported; the host name is used instead.
+func machineID() (string, error) {
+	return "", errors.New("machine identifier not supported")
+}
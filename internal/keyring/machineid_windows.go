@@ -0,0 +1,17 @@
+//go:build windows
+
+package keyring
+
+import "golang.org/x/sys/windows/registry"
+
+// machineID returns the GUID Windows generates at installation.
+func machineID() (string, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Cryptography`, registry.QUERY_VALUE|registry.WOW64_64KEY)
+	if err != nil {
+		return "", err
+	}
+	defer k.Close()
+
+	id, _, err := k.GetStringValue("MachineGuid")
+	return id, err
+}
@@ -0,0 +1,218 @@
+package lanshare
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/repair"
+)
+
+// client talks to peers. Peers are on the local network, so requests never
+// go through a proxy.
+var client = &http.Client{
+	Transport: &http.Transport{
+		ResponseHeaderTimeout: 10 * time.Second,
+	},
+}
+
+// listTimeout bounds asking a peer for its builds.
+const listTimeout = 3 * time.Second
+
+// listBuilds asks a peer for the builds it shares.
+func listBuilds(ctx context.Context, peer Peer) ([]Build, error) {
+	ctx, cancel := context.WithTimeout(ctx, listTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+peer.Addr+"/v1/builds", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	var builds []Build
+	if err := json.NewDecoder(resp.Body).Decode(&builds); err != nil {
+		return nil, fmt.Errorf("failed to decode builds: %w", err)
+	}
+	return builds, nil
+}
+
+// Fetch copies a channel's game build from a peer into dir. checksums maps
+// the build's slash-separated file paths to their SHA256 hashes, and must
+// come from a trusted source such as the game manifest, never the peer.
+//
+// Files in dir that already match are kept. Every other file is copied to
+// a temporary file while it is hashed, and only moved into place if the
+// hash matches; a mismatch stops the copy with ErrHashMismatch. Once all
+// files are in place, files the build does not list are removed, except
+// in the player's data directories. progress is called after each file
+// copied, with the number of files copied and to copy.
+func Fetch(ctx context.Context, peer Peer, channel string, build int, dir string, checksums map[string]string, progress func(done, total int)) error {
+	res, err := repair.Verify(dir, checksums, nil)
+	if err != nil {
+		return err
+	}
+	var needed []string
+	for _, results := range [][]repair.FileResult{res.MissingFiles, res.CorruptedFiles, res.Errors} {
+		for _, f := range results {
+			needed = append(needed, f.Path)
+		}
+	}
+	slices.Sort(needed)
+
+	slog.Info("copying game build from peer",
+		"peer", peer.Name,
+		"addr", peer.Addr,
+		"channel", channel,
+		"build", build,
+		"files", len(needed),
+		"kept", res.OKFiles,
+	)
+
+	for i, rel := range needed {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fetchFile(ctx, peer, channel, build, dir, rel, checksums[rel]); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(i+1, len(needed))
+		}
+	}
+
+	removeUnlisted(dir, checksums)
+	return nil
+}
+
+// fetchFile copies one file of a build from a peer and checks it against
+// its expected hash before moving it into place.
+func fetchFile(ctx context.Context, peer Peer, channel string, build int, dir, rel, want string) error {
+	local, err := ioutil.SafeRelPath(rel)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, local)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL(peer, channel, build, rel), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from %s: %w", rel, peer.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to copy %s from %s: %s", rel, peer.Name, resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmp := path + ".lanshare"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fileMode(resp.Header.Get(modeHeader)))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", rel, err)
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to copy %s from %s: %w", rel, peer.Name, err)
+	}
+
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, want) {
+		os.Remove(tmp)
+		slog.Warn("file from peer does not match its hash",
+			"peer", peer.Name,
+			"addr", peer.Addr,
+			"path", rel,
+			"expected", want,
+			"actual", got,
+		)
+		return fmt.Errorf("%w: %s from %s", ErrHashMismatch, rel, peer.Name)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s: %w", rel, err)
+	}
+	return nil
+}
+
+// fileURL returns the address of a build's file on a peer.
+func fileURL(peer Peer, channel string, build int, rel string) string {
+	parts := strings.Split(rel, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return fmt.Sprintf("http://%s/v1/builds/%s/%d/files/%s",
+		peer.Addr, url.PathEscape(channel), build, strings.Join(parts, "/"))
+}
+
+// fileMode returns the permissions to create a copied file with: executable
+// if the peer's copy is, and readable and writable by the user either way.
+func fileMode(header string) os.FileMode {
+	mode, err := strconv.ParseUint(header, 8, 32)
+	if err == nil && mode&0o111 != 0 {
+		return 0o755
+	}
+	return 0o644
+}
+
+// removeUnlisted removes the files in dir that the build does not list,
+// such as files of the build dir was seeded from, keeping the player's
+// data and the launcher's hidden files, such as the build's .signature.
+func removeUnlisted(dir string, checksums map[string]string) {
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if _, ok := checksums[rel]; ok || inUserDir(rel) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			slog.Warn("failed to remove file not in build", "path", rel, "error", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,40 @@
+package lanshare
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoveUnlisted(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"Client/HytaleClient",
+		"Client/old.dll",
+		".signature",
+		".launcher/state",
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removeUnlisted(dir, map[string]string{"Client/HytaleClient": ""})
+
+	for f, want := range map[string]bool{
+		"Client/HytaleClient": true,
+		"Client/old.dll":      false,
+		".signature":          true,
+		".launcher/state":     true,
+	} {
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(f)))
+		if got := err == nil; got != want {
+			t.Errorf("%s kept = %v, want %v", f, got, want)
+		}
+	}
+}
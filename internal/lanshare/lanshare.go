@@ -0,0 +1,101 @@
+// Package lanshare lets launchers on the same local network copy game
+// builds from each other instead of downloading them from the CDN. A
+// launcher that shares announces itself over multicast DNS and serves the
+// files of its installed builds over HTTP. A launcher that needs a build
+// discovers those peers and copies the build's files from one of them.
+//
+// A peer is not trusted: every file copied is checked against the hashes
+// listed in the game manifest, and a peer that serves a file that does not
+// match is not used again for that copy. The player's own data, such as
+// saves and mods, is never served.
+package lanshare
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+)
+
+// serviceName is the DNS-SD service type launchers announce themselves
+// under.
+const serviceName = "_hytale-launcher._tcp.local."
+
+// protocolVersion is the version of the HTTP API served to peers. Peers
+// announcing another version are ignored.
+const protocolVersion = "1"
+
+// ErrHashMismatch is returned when a file copied from a peer does not match
+// its expected hash.
+var ErrHashMismatch = errors.New("file from peer does not match its expected hash")
+
+// userDirs are top-level directories in a game build that hold the
+// player's data rather than game files. They are never served.
+var userDirs = []string{"mods", "userdata", "logs", "screenshots", "saves"}
+
+// instanceID identifies this launcher in announcements, so it does not
+// discover itself.
+var instanceID = newInstanceID()
+
+// Peer is another launcher on the network that shares its game builds.
+type Peer struct {
+	// ID identifies the launcher for as long as it runs.
+	ID string `json:"id"`
+
+	// Name is the name of the machine, for display.
+	Name string `json:"name"`
+
+	// Addr is the host and port its builds are served from.
+	Addr string `json:"addr"`
+
+	// Builds are the game builds it shares.
+	Builds []Build `json:"builds"`
+}
+
+// Build is a game build shared by a launcher.
+type Build struct {
+	Channel string `json:"channel"`
+	Build   int    `json:"build"`
+	Version string `json:"version"`
+}
+
+// Has reports whether the peer shares a channel's build.
+func (p *Peer) Has(channel string, build int) bool {
+	return slices.ContainsFunc(p.Builds, func(b Build) bool {
+		return b.Channel == channel && b.Build == build
+	})
+}
+
+// inUserDir reports whether a slash-separated path in a game build is in
+// one of the player's data directories.
+func inUserDir(rel string) bool {
+	top, _, _ := strings.Cut(rel, "/")
+	return slices.Contains(userDirs, strings.ToLower(top))
+}
+
+// isHidden reports whether a slash-separated path in a game build has a
+// hidden component, such as the build's signature.
+func isHidden(rel string) bool {
+	return slices.ContainsFunc(strings.Split(rel, "/"), func(part string) bool {
+		return strings.HasPrefix(part, ".")
+	})
+}
+
+// machineName returns the name this launcher announces itself with.
+func machineName() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "hytale-launcher"
+	}
+	name, _, _ = strings.Cut(name, ".")
+	return name
+}
+
+// newInstanceID returns a random identifier for this launcher.
+func newInstanceID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,309 @@
+package lanshare
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// mdnsPort is the port multicast DNS is served on.
+	mdnsPort = 5353
+
+	// announceTTL is how long, in seconds, peers may cache an
+	// announcement.
+	announceTTL = 120
+
+	// maxPacket bounds the size of a multicast DNS packet.
+	maxPacket = 9000
+)
+
+// mdnsAddr is the IPv4 multicast DNS group.
+var mdnsAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: mdnsPort}
+
+// responder answers multicast DNS queries for the launcher service with
+// this launcher's announcement.
+type responder struct {
+	conn *net.UDPConn
+	port uint16
+	name string
+}
+
+// startResponder joins the multicast DNS group and announces the builds
+// served on port to queries for the launcher service.
+func startResponder(port uint16, name string) (*responder, error) {
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdnsAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &responder{conn: conn, port: port, name: name}
+	go r.serve()
+	return r, nil
+}
+
+// close stops answering queries.
+func (r *responder) close() {
+	r.conn.Close()
+}
+
+// serve answers queries until the responder is closed.
+func (r *responder) serve() {
+	buf := make([]byte, maxPacket)
+	for {
+		n, src, err := r.conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			slog.Debug("failed to read multicast DNS packet", "error", err)
+			continue
+		}
+		r.handle(buf[:n], src)
+	}
+}
+
+// handle answers a query if it asks for the launcher service. Queries sent
+// from the multicast DNS port are answered to the group; others come from
+// a one-shot resolver, such as Discover, and are answered directly.
+func (r *responder) handle(msg []byte, src *net.UDPAddr) {
+	var p dnsmessage.Parser
+	h, err := p.Start(msg)
+	if err != nil || h.Response {
+		return
+	}
+	questions, err := p.AllQuestions()
+	if err != nil {
+		return
+	}
+
+	var asked []dnsmessage.Question
+	for _, q := range questions {
+		if (q.Type == dnsmessage.TypePTR || q.Type == dnsmessage.TypeALL) && strings.EqualFold(q.Name.String(), serviceName) {
+			asked = append(asked, q)
+		}
+	}
+	if len(asked) == 0 {
+		return
+	}
+
+	dst := mdnsAddr
+	if src.Port != mdnsPort {
+		dst = src
+	} else {
+		// Multicast responses carry no ID or questions.
+		h.ID = 0
+		asked = nil
+	}
+
+	resp, err := r.announcement(h.ID, asked)
+	if err != nil {
+		slog.Warn("failed to build multicast DNS announcement", "error", err)
+		return
+	}
+	if _, err := r.conn.WriteToUDP(resp, dst); err != nil {
+		slog.Debug("failed to send multicast DNS announcement", "to", dst, "error", err)
+	}
+}
+
+// announcement builds the response to a query for the launcher service:
+// a pointer to this launcher's instance, and the instance's port and
+// identity.
+func (r *responder) announcement(id uint16, questions []dnsmessage.Question) ([]byte, error) {
+	service := dnsmessage.MustNewName(serviceName)
+	instance, err := dnsmessage.NewName(instanceID + "." + serviceName)
+	if err != nil {
+		return nil, err
+	}
+	host, err := dnsmessage.NewName(instanceID + ".local.")
+	if err != nil {
+		return nil, err
+	}
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, Response: true, Authoritative: true})
+	b.EnableCompression()
+
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	for _, q := range questions {
+		if err := b.Question(q); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+	if err := b.PTRResource(resourceHeader(service), dnsmessage.PTRResource{PTR: instance}); err != nil {
+		return nil, err
+	}
+
+	if err := b.StartAdditionals(); err != nil {
+		return nil, err
+	}
+	if err := b.SRVResource(resourceHeader(instance), dnsmessage.SRVResource{Target: host, Port: r.port}); err != nil {
+		return nil, err
+	}
+	txt := []string{
+		"v=" + protocolVersion,
+		"id=" + instanceID,
+		"name=" + truncate(r.name, 63),
+	}
+	if err := b.TXTResource(resourceHeader(instance), dnsmessage.TXTResource{TXT: txt}); err != nil {
+		return nil, err
+	}
+
+	return b.Finish()
+}
+
+// resourceHeader returns the header of a record announced for name.
+func resourceHeader(name dnsmessage.Name) dnsmessage.ResourceHeader {
+	return dnsmessage.ResourceHeader{
+		Name:  name,
+		Class: dnsmessage.ClassINET,
+		TTL:   announceTTL,
+	}
+}
+
+// Discover looks for other launchers sharing game builds on the local
+// network, waiting up to wait for them to answer, and returns those that
+// could be reached along with the builds they share.
+func Discover(ctx context.Context, wait time.Duration) ([]Peer, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4zero})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	query, err := serviceQuery()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteToUDP(query, mdnsAddr); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(wait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetReadDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	found := make(map[string]Peer)
+	buf := make([]byte, maxPacket)
+	for {
+		n, src, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			break
+		}
+		peer, ok := parseAnnouncement(buf[:n], src)
+		if !ok || peer.ID == instanceID {
+			continue
+		}
+		found[peer.ID] = peer
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	peers := make([]Peer, 0, len(found))
+	for _, peer := range found {
+		builds, err := listBuilds(ctx, peer)
+		if err != nil {
+			slog.Debug("unable to list builds of peer", "peer", peer.Name, "addr", peer.Addr, "error", err)
+			continue
+		}
+		peer.Builds = builds
+		peers = append(peers, peer)
+	}
+
+	slog.Debug("discovered launchers on the local network", "peers", len(peers))
+	return peers, nil
+}
+
+// serviceQuery builds a query for launchers announcing the service.
+func serviceQuery() ([]byte, error) {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	err := b.Question(dnsmessage.Question{
+		Name:  dnsmessage.MustNewName(serviceName),
+		Type:  dnsmessage.TypePTR,
+		Class: dnsmessage.ClassINET,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+// parseAnnouncement reads a launcher's announcement from a response. The
+// peer's address is where the response came from, at the announced port.
+func parseAnnouncement(msg []byte, src *net.UDPAddr) (Peer, bool) {
+	var p dnsmessage.Parser
+	h, err := p.Start(msg)
+	if err != nil || !h.Response {
+		return Peer{}, false
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return Peer{}, false
+	}
+	answers, err := p.AllAnswers()
+	if err != nil {
+		return Peer{}, false
+	}
+	if err := p.SkipAllAuthorities(); err != nil {
+		return Peer{}, false
+	}
+	additionals, err := p.AllAdditionals()
+	if err != nil {
+		return Peer{}, false
+	}
+
+	var port uint16
+	txt := make(map[string]string)
+	for _, rr := range append(answers, additionals...) {
+		if !strings.HasSuffix(strings.ToLower(rr.Header.Name.String()), "."+serviceName) {
+			continue
+		}
+		switch body := rr.Body.(type) {
+		case *dnsmessage.SRVResource:
+			port = body.Port
+		case *dnsmessage.TXTResource:
+			for _, kv := range body.TXT {
+				if k, v, ok := strings.Cut(kv, "="); ok {
+					txt[k] = v
+				}
+			}
+		}
+	}
+	if port == 0 || txt["v"] != protocolVersion || txt["id"] == "" {
+		return Peer{}, false
+	}
+
+	return Peer{
+		ID:   txt["id"],
+		Name: txt["name"],
+		Addr: net.JoinHostPort(src.IP.String(), strconv.Itoa(int(port))),
+	}, true
+}
+
+// truncate shortens s to at most n bytes.
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,174 @@
+package lanshare
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/ioutil"
+)
+
+// modeHeader carries the permission bits of a served file, so executables
+// stay executable on the peer.
+const modeHeader = "X-File-Mode"
+
+// Server serves this launcher's game builds to other launchers on the
+// local network and announces them over multicast DNS.
+type Server struct {
+	http      *http.Server
+	responder *responder
+}
+
+// Start starts serving the installed game builds and announcing them. The
+// builds are served on a port chosen by the system, and only to clients
+// on the local network.
+func Start() (*Server, error) {
+	ln, err := net.Listen("tcp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+
+	srv := &http.Server{
+		Handler:           localOnly(handler()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Warn("game build server stopped", "error", err)
+		}
+	}()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	r, err := startResponder(uint16(port), machineName())
+	if err != nil {
+		srv.Close()
+		return nil, err
+	}
+
+	slog.Info("sharing game builds on the local network", "port", port)
+	return &Server{http: srv, responder: r}, nil
+}
+
+// Stop stops announcing and serving the builds. Copies in progress are cut
+// off. It does nothing on a nil Server.
+func (s *Server) Stop() {
+	if s == nil {
+		return
+	}
+	s.responder.close()
+	s.http.Close()
+	slog.Info("stopped sharing game builds")
+}
+
+// handler returns the routes served to peers.
+func handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/builds", serveBuilds)
+	mux.HandleFunc("GET /v1/builds/{channel}/{build}/files/{path...}", serveFile)
+	return mux
+}
+
+// localOnly refuses requests from outside the local network.
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		ip := net.ParseIP(host)
+		if err != nil || ip == nil || !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast()) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveBuilds lists the shared builds.
+func serveBuilds(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sharedBuilds())
+}
+
+// serveFile serves one file of a shared build. Files in the player's data
+// directories, hidden files and anything outside the build are not found.
+func serveFile(w http.ResponseWriter, r *http.Request) {
+	build, err := strconv.Atoi(r.PathValue("build"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	dir, ok := sharedBuildDir(r.PathValue("channel"), build)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	rel, err := ioutil.SafeRelPath(r.PathValue("path"))
+	if err != nil {
+		http.Error(w, "invalid path", http.StatusBadRequest)
+		return
+	}
+	if slashRel := filepath.ToSlash(rel); inUserDir(slashRel) || isHidden(slashRel) {
+		http.NotFound(w, r)
+		return
+	}
+	path := filepath.Join(dir, rel)
+	if !ioutil.IsInside(path, dir) {
+		http.NotFound(w, r)
+		return
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		http.NotFound(w, r)
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set(modeHeader, strconv.FormatUint(uint64(info.Mode().Perm()), 8))
+	http.ServeContent(w, r, "", info.ModTime(), f)
+}
+
+// sharedBuilds returns the released game builds installed on each
+// channel. Builds preloaded ahead of their release are not shared.
+func sharedBuilds() []Build {
+	builds := []Build{}
+	for _, channel := range hytale.KnownChannels() {
+		m, err := hytale.LoadBuildManifest("game", channel)
+		if err != nil {
+			continue
+		}
+		for _, b := range m.Builds {
+			if b.Released() {
+				builds = append(builds, Build{Channel: channel, Build: b.Build, Version: b.Version})
+			}
+		}
+	}
+	return builds
+}
+
+// sharedBuildDir returns the install directory of a shared build.
+func sharedBuildDir(channel string, build int) (string, bool) {
+	if !hytale.IsKnownChannel(channel) {
+		return "", false
+	}
+	m, err := hytale.LoadBuildManifest("game", channel)
+	if err != nil {
+		return "", false
+	}
+	b := m.Get(build)
+	if b == nil || !b.Released() {
+		return "", false
+	}
+	return b.Dir, true
+}
@@ -0,0 +1,41 @@
+package launch
+
+import "time"
+
+// BreakTimer reminds the player to take a break after each interval of
+// continuous play.
+type BreakTimer struct {
+	stop chan struct{}
+}
+
+// StartBreakTimer calls remind with the time played each time another
+// interval has passed since startedAt. A session that started before the
+// timer, such as a re-attached game, keeps its original schedule.
+func StartBreakTimer(startedAt time.Time, interval time.Duration, remind func(played time.Duration)) *BreakTimer {
+	t := &BreakTimer{stop: make(chan struct{})}
+
+	go func() {
+		for {
+			played := time.Since(startedAt)
+			next := interval - played%interval
+
+			timer := time.NewTimer(next)
+			select {
+			case <-t.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+				remind(time.Since(startedAt).Round(time.Minute))
+			}
+		}
+	}()
+
+	return t
+}
+
+// Stop stops the timer. It is safe to call on a nil timer.
+func (t *BreakTimer) Stop() {
+	if t != nil {
+		close(t.stop)
+	}
+}
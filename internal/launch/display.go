@@ -0,0 +1,43 @@
+package launch
+
+import (
+	"os"
+	"path/filepath"
+
+	"hytale-launcher/internal/sysinfo"
+)
+
+// nixOSDriverDir is where NixOS exposes the active OpenGL/Vulkan drivers.
+const nixOSDriverDir = "/run/opengl-driver/lib"
+
+// displayEnv returns environment variables that adapt the game to the
+// current display server. Variables the user has already set are left alone.
+func displayEnv() []string {
+	var env []string
+
+	switch sysinfo.CurrentDisplayServer() {
+	case sysinfo.DisplayWayland:
+		// Prefer native Wayland but allow SDL to fall back to XWayland.
+		if _, ok := os.LookupEnv("SDL_VIDEODRIVER"); !ok {
+			env = append(env, "SDL_VIDEODRIVER=wayland,x11")
+		}
+	case sysinfo.DisplayX11:
+		if _, ok := os.LookupEnv("SDL_VIDEODRIVER"); !ok {
+			env = append(env, "SDL_VIDEODRIVER=x11")
+		}
+	default:
+		return nil
+	}
+
+	// NixOS does not install graphics drivers into the standard library
+	// paths, so the bundled runtime cannot find them without help.
+	if sysinfo.IsNixOS() {
+		libPath := nixOSDriverDir
+		if existing := os.Getenv("LD_LIBRARY_PATH"); existing != "" {
+			libPath += string(filepath.ListSeparator) + existing
+		}
+		env = append(env, "LD_LIBRARY_PATH="+libPath)
+	}
+
+	return env
+}
@@ -0,0 +1,182 @@
+package launch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/sysinfo"
+	"hytale-launcher/internal/throttle"
+)
+
+// driverTableTTL is how long a fetched driver table is reused.
+const driverTableTTL = 6 * time.Hour
+
+// driverFetchTimeout bounds fetching the driver table, and driverRetryDelay
+// is how long to wait after a failed fetch before trying again.
+const (
+	driverFetchTimeout = 5 * time.Second
+	driverRetryDelay   = 5 * time.Minute
+)
+
+// DriverRequirement is an entry in the minimum-known-good driver table.
+type DriverRequirement struct {
+	// Vendor is the GPU vendor the entry applies to (see sysinfo.Vendor*).
+	Vendor string `json:"vendor"`
+
+	// Driver optionally restricts the entry to a specific driver name,
+	// e.g. "nvidia" or "amdgpu" on Linux.
+	Driver string `json:"driver,omitempty"`
+
+	// MinVersion is the minimum recommended driver version.
+	MinVersion string `json:"min_version"`
+
+	// MinGameBuild is the first game build the entry applies to.
+	// Zero means all builds.
+	MinGameBuild int `json:"min_game_build,omitempty"`
+
+	// UpdateURL is where the user can download a newer driver.
+	UpdateURL string `json:"update_url,omitempty"`
+}
+
+// driverTable is the JSON structure returned by the GPU drivers endpoint.
+type driverTable struct {
+	Drivers []DriverRequirement `json:"drivers"`
+}
+
+// DriverWarning describes a GPU whose driver is older than recommended.
+type DriverWarning struct {
+	Code           string `json:"code"`
+	Message        string `json:"message"`
+	Model          string `json:"model,omitempty"`
+	Vendor         string `json:"vendor"`
+	CurrentVersion string `json:"current_version"`
+	MinVersion     string `json:"min_version"`
+	UpdateURL      string `json:"update_url,omitempty"`
+}
+
+var driverTableCache throttle.State[*driverTable]
+
+// fetchDriverTable returns the driver table for the current platform,
+// using the cached copy if it is recent enough. If the table cannot be
+// fetched, the last one fetched is used, and the fetch is not retried for a
+// while.
+func fetchDriverTable(ctx context.Context) (*driverTable, error) {
+	cached, err := driverTableCache.Get()
+	age := time.Since(driverTableCache.UpdatedAt())
+	if err == nil && cached != nil && age < driverTableTTL {
+		return cached, nil
+	}
+	if err != nil && age < driverRetryDelay {
+		if cached != nil {
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, driverFetchTimeout)
+	defer cancel()
+
+	table, err := api.Get[*driverTable](ctx, api.Default, endpoints.GPUDrivers(build.OS()), nil)
+	if err != nil {
+		err = fmt.Errorf("failed to fetch gpu driver table: %w", err)
+		driverTableCache.SetError(err)
+		if cached != nil {
+			slog.Debug("using stale gpu driver table", "error", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	driverTableCache.Set(table)
+	return table, nil
+}
+
+// CheckDrivers compares the installed GPU drivers against the recommended
+// minimums for the given game build and returns a warning for each GPU that
+// is out of date. Failure to fetch the table is not fatal to launching.
+func CheckDrivers(ctx context.Context, gameBuild int) ([]DriverWarning, error) {
+	gpus := sysinfo.GPUs()
+	if len(gpus) == 0 {
+		return nil, nil
+	}
+
+	table, err := fetchDriverTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkDrivers(gpus, table.Drivers, gameBuild), nil
+}
+
+// checkDrivers matches each GPU against the most specific applicable
+// requirement.
+func checkDrivers(gpus []sysinfo.GPU, reqs []DriverRequirement, gameBuild int) []DriverWarning {
+	var warnings []DriverWarning
+
+	for _, gpu := range gpus {
+		if gpu.DriverVersion == "" {
+			continue
+		}
+
+		req := findRequirement(gpu, reqs, gameBuild)
+		if req == nil || sysinfo.CompareVersions(gpu.DriverVersion, req.MinVersion) >= 0 {
+			continue
+		}
+
+		slog.Warn("gpu driver is older than recommended",
+			"vendor", gpu.Vendor,
+			"model", gpu.Model,
+			"version", gpu.DriverVersion,
+			"min_version", req.MinVersion,
+		)
+
+		name := gpu.Model
+		if name == "" {
+			name = gpu.Vendor
+		}
+
+		warnings = append(warnings, DriverWarning{
+			Code:           "outdated_gpu_driver",
+			Message:        fmt.Sprintf("Your %s driver (%s) is older than the recommended version %s. Updating may fix crashes and rendering issues.", name, gpu.DriverVersion, req.MinVersion),
+			Model:          gpu.Model,
+			Vendor:         gpu.Vendor,
+			CurrentVersion: gpu.DriverVersion,
+			MinVersion:     req.MinVersion,
+			UpdateURL:      req.UpdateURL,
+		})
+	}
+
+	return warnings
+}
+
+// findRequirement returns the applicable requirement for a GPU with the
+// highest MinGameBuild, preferring entries that name the GPU's driver.
+func findRequirement(gpu sysinfo.GPU, reqs []DriverRequirement, gameBuild int) *DriverRequirement {
+	var best *DriverRequirement
+
+	for i := range reqs {
+		r := &reqs[i]
+		if r.Vendor != gpu.Vendor || r.MinGameBuild > gameBuild {
+			continue
+		}
+		if r.Driver != "" && r.Driver != gpu.Driver {
+			continue
+		}
+
+		switch {
+		case best == nil:
+			best = r
+		case r.Driver != "" && best.Driver == "":
+			best = r
+		case (r.Driver != "") == (best.Driver != "") && r.MinGameBuild > best.MinGameBuild:
+			best = r
+		}
+	}
+
+	return best
+}
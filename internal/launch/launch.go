@@ -9,6 +9,10 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
+	"slices"
+
+	"hytale-launcher/internal/fork"
+	"hytale-launcher/internal/launchopts"
 )
 
 // AuthError represents an authentication error that occurred during launch.
@@ -52,11 +56,27 @@ type Request struct {
 	// ProfileID is the user's profile identifier.
 	ProfileID string
 
+	// Attestation is the commitment over the game files, if the user has
+	// opted in to attestation, and AttestationToken the signed token the
+	// attestation endpoint issued for it. Either may be empty.
+	Attestation      string
+	AttestationToken string
+
+	// ServerAddress is a server, in host:port form, the game joins once it
+	// has started. Empty starts the game at the main menu.
+	ServerAddress string
+
+	// Options are the presentation settings passed to the game.
+	Options Options
+
 	// ExtraArgs are additional command line arguments.
 	ExtraArgs []string
 
 	// Env contains additional environment variables.
 	Env []string
+
+	// OnStart, if set, is called with the game's PID once it has started.
+	OnStart func(pid int)
 }
 
 // appendSessionArgs appends session-related arguments to the command line.
@@ -70,6 +90,15 @@ func (r *Request) appendSessionArgs(args []string) []string {
 	if r.ProfileID != "" {
 		args = append(args, "--profileId", r.ProfileID)
 	}
+	if r.Attestation != "" {
+		args = append(args, "--attestation", r.Attestation)
+	}
+	if r.AttestationToken != "" {
+		args = append(args, "--attestationToken", r.AttestationToken)
+	}
+	if r.ServerAddress != "" {
+		args = append(args, "--server", r.ServerAddress)
+	}
 	return args
 }
 
@@ -97,17 +126,22 @@ func launchEnv(extra []string) []string {
 
 // startAndWait starts the command and waits for it to complete.
 // It returns an error if the process fails to start or exits with a non-zero code.
-func startAndWait(ctx context.Context, cmd *exec.Cmd) error {
+func startAndWait(ctx context.Context, cmd *exec.Cmd, onStart func(pid int)) error {
 	slog.Info("starting game process",
 		"path", cmd.Path,
 		"args", cmd.Args,
 		"dir", cmd.Dir,
 	)
 
-	if err := cmd.Start(); err != nil {
+	// Detach the game, so it is not taken down with the launcher.
+	if err := fork.StartDetached(cmd); err != nil {
 		return fmt.Errorf("failed to start game process: %w", err)
 	}
 
+	if onStart != nil {
+		onStart(cmd.Process.Pid)
+	}
+
 	// Create a channel to receive the wait result
 	done := make(chan waitResult, 1)
 
@@ -134,7 +168,7 @@ func startAndWait(ctx context.Context, cmd *exec.Cmd) error {
 		if !result.state.Success() {
 			exitCode := result.state.ExitCode()
 			slog.Warn("game process exited with non-zero code", "exitCode", exitCode)
-			return fmt.Errorf("game exited with code %d", exitCode)
+			return &ExitError{ExitCode: exitCode}
 		}
 
 		slog.Info("game process completed successfully")
@@ -161,8 +195,23 @@ func Do(ctx context.Context, req *Request) error {
 		"workingDir", req.WorkingDir,
 	)
 
-	// Build command line arguments
-	args := []string{}
+	// Substitute the variables in the user's custom arguments
+	var customJVM, customGame, customEnv []string
+	if !req.Options.SafeMode {
+		var err error
+		customJVM, customGame, customEnv, err = req.Options.Custom.Expand(map[string]string{
+			launchopts.VarGameDir:     req.WorkingDir,
+			launchopts.VarProfileUUID: req.ProfileID,
+			launchopts.VarChannel:     req.Channel,
+		})
+		if err != nil {
+			return fmt.Errorf("invalid custom launch arguments: %w", err)
+		}
+	}
+
+	// Build command line arguments, starting with the JVM settings
+	args := req.Options.JVM.Args()
+	args = append(args, customJVM...)
 
 	// Add the game JAR as the first argument after java
 	args = append(args, "-jar", req.GamePath)
@@ -173,8 +222,12 @@ func Do(ctx context.Context, req *Request) error {
 	// Add auth arguments
 	args = req.appendAuthArgs(args)
 
-	// Add any extra arguments
+	// Add presentation arguments
+	args = req.Options.appendArgs(args)
+
+	// Add any extra arguments, then the user's own
 	args = append(args, req.ExtraArgs...)
+	args = append(args, customGame...)
 
 	// Create the command
 	cmd := exec.CommandContext(ctx, req.JavaPath, args...)
@@ -184,15 +237,16 @@ func Do(ctx context.Context, req *Request) error {
 		cmd.Dir = req.WorkingDir
 	}
 
-	// Set environment
-	cmd.Env = launchEnv(req.Env)
+	// Set environment, letting request variables override display defaults
+	// and the user's variables override both
+	cmd.Env = launchEnv(slices.Concat(displayEnv(), req.Env, customEnv))
 
 	// Connect stdout and stderr to the current process
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
 	// Start and wait for the game
-	if err := startAndWait(ctx, cmd); err != nil {
+	if err := startAndWait(ctx, cmd, req.OnStart); err != nil {
 		// Check if this is an authentication error
 		var authErr *AuthError
 		if errors.As(err, &authErr) {
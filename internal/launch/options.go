@@ -0,0 +1,105 @@
+package launch
+
+import (
+	"strconv"
+
+	"hytale-launcher/internal/launchopts"
+	"hytale-launcher/internal/sysinfo"
+)
+
+// Power profile hints passed to the game.
+const (
+	// PowerProfileDefault lets the game pick its own performance settings.
+	PowerProfileDefault = ""
+
+	// PowerProfileBalanced asks the game to favor stable frame pacing over
+	// maximum frame rate, as suits handheld devices on external power.
+	PowerProfileBalanced = "balanced"
+
+	// PowerProfileBattery asks the game to reduce power draw while running on battery.
+	PowerProfileBattery = "battery"
+)
+
+// Options holds presentation settings passed to the game on launch.
+type Options struct {
+	// Fullscreen starts the game in fullscreen mode.
+	Fullscreen bool `json:"fullscreen"`
+
+	// Width and Height are the resolution the game renders at. Zero lets
+	// the game choose.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+
+	// GamepadGlyphs makes the game show controller button prompts
+	// instead of keyboard and mouse prompts.
+	GamepadGlyphs bool `json:"gamepad_glyphs"`
+
+	// PowerProfile is a hint about the device's power budget.
+	PowerProfile string `json:"power_profile,omitempty"`
+
+	// SafeMode starts the game with mods, custom shaders and cached
+	// graphics settings disabled, to recover from a crash on startup.
+	SafeMode bool `json:"safe_mode,omitempty"`
+
+	// JVM holds the memory and garbage collector settings for the game JVM.
+	JVM launchopts.JVMOptions `json:"jvm"`
+
+	// Custom holds the user's own arguments and environment variables.
+	// They are left out in safe mode.
+	Custom launchopts.CustomArgs `json:"custom"`
+}
+
+// DefaultOptions returns the launch options appropriate for the current environment.
+// JVM settings are sized from the system's memory and CPU count.
+// On Steam Deck and in gamescope sessions the game starts in handheld mode.
+func DefaultOptions() Options {
+	return OptionsFor(sysinfo.IsBigPicture())
+}
+
+// OptionsFor returns the launch options with handheld mode on or off,
+// regardless of the environment. In handheld mode the game starts
+// fullscreen with controller prompts. On Steam Deck it renders at the
+// panel's native resolution, and a power profile hint is chosen based on
+// battery state.
+func OptionsFor(handheld bool) Options {
+	opts := Options{
+		JVM: launchopts.DefaultJVMOptions(),
+	}
+
+	if !handheld {
+		return opts
+	}
+
+	opts.Fullscreen = true
+	opts.GamepadGlyphs = true
+
+	if sysinfo.IsSteamDeck() {
+		opts.Width, opts.Height = sysinfo.DeckResolution()
+		opts.PowerProfile = PowerProfileBalanced
+		if sysinfo.OnBattery() {
+			opts.PowerProfile = PowerProfileBattery
+		}
+	}
+
+	return opts
+}
+
+// appendArgs appends the game arguments for these options to the command line.
+func (o Options) appendArgs(args []string) []string {
+	if o.Fullscreen {
+		args = append(args, "--fullscreen")
+	}
+	if o.Width > 0 && o.Height > 0 {
+		args = append(args, "--width", strconv.Itoa(o.Width), "--height", strconv.Itoa(o.Height))
+	}
+	if o.GamepadGlyphs {
+		args = append(args, "--gamepadGlyphs")
+	}
+	if o.SafeMode {
+		args = append(args, "--safeMode")
+	}
+	if o.PowerProfile != PowerProfileDefault {
+		args = append(args, "--powerProfile", o.PowerProfile)
+	}
+	return args
+}
@@ -0,0 +1,118 @@
+package launch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"hytale-launcher/internal/hytale"
+)
+
+// runningFileName is the file in the storage directory that records the
+// running game process.
+const runningFileName = "game-process.json"
+
+// pollInterval is how often a re-attached game process is checked for exit.
+const pollInterval = 2 * time.Second
+
+// Running records a game process started by the launcher, so a launcher
+// restarted while the game is still running can re-attach to it.
+type Running struct {
+	// PID is the process ID of the game.
+	PID int `json:"pid"`
+
+	// Channel is the channel the game was launched from.
+	Channel string `json:"channel"`
+
+	// JavaPath is the Java executable the game was started with, used to
+	// check that the PID has not been reused by an unrelated process.
+	JavaPath string `json:"java_path"`
+
+	// StartedAt is when the game was started.
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Save records the process in the storage directory.
+func (r *Running) Save() error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(hytale.InStorageDir(runningFileName), data, 0o644)
+}
+
+// LoadRunning returns the recorded game process, or nil if none is recorded.
+func LoadRunning() (*Running, error) {
+	data, err := os.ReadFile(hytale.InStorageDir(runningFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var r Running
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid game process record: %w", err)
+	}
+	return &r, nil
+}
+
+// ClearRunning removes the recorded game process.
+func ClearRunning() {
+	os.Remove(hytale.InStorageDir(runningFileName))
+}
+
+// Alive returns true if the recorded process is still running and is
+// still the game's Java executable.
+func (r *Running) Alive() bool {
+	if r.PID <= 0 || !processAlive(r.PID) {
+		return false
+	}
+
+	exe, err := processExe(r.PID)
+	if err != nil {
+		return false
+	}
+	return samePath(exe, r.JavaPath)
+}
+
+// Wait blocks until the recorded process exits or the context is cancelled.
+// It is used for a process the launcher did not start in this session and
+// therefore cannot wait on directly.
+func (r *Running) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if !processAlive(r.PID) {
+				return nil
+			}
+		}
+	}
+}
+
+// samePath returns true if two paths refer to the same file after
+// resolving symlinks.
+func samePath(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Clean(a), filepath.Clean(b))
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,193 @@
+package launch
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"sync"
+	"time"
+
+	"hytale-launcher/internal/fork"
+	"hytale-launcher/internal/launchopts"
+)
+
+// ServerRequest contains the parameters for starting a dedicated server.
+type ServerRequest struct {
+	// JarPath is the path to the server JAR.
+	JarPath string
+
+	// JavaPath is the path to the Java executable.
+	JavaPath string
+
+	// WorkingDir is the server directory, holding its worlds and config.
+	WorkingDir string
+
+	// JVM holds the memory and garbage collector settings.
+	JVM launchopts.JVMOptions
+
+	// ExtraArgs are appended after the JAR.
+	ExtraArgs []string
+
+	// OnOutput is called with each line the server writes to "stdout" or
+	// "stderr". It is called from the goroutines reading the output.
+	OnOutput func(stream, line string)
+}
+
+// serverTerminateGrace is how long a server that ignored the stop command
+// is given to exit after being asked to terminate, before it is killed.
+const serverTerminateGrace = 5 * time.Second
+
+// Server is a running dedicated server process.
+type Server struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	// group holds the server and any processes it starts.
+	group *fork.Group
+
+	// stdinMu serialises writes to stdin.
+	stdinMu sync.Mutex
+
+	done chan struct{}
+	err  error
+}
+
+// StartServer starts a dedicated server. Its console output is passed to
+// req.OnOutput line by line, and commands can be sent to it with Send.
+func StartServer(req *ServerRequest) (*Server, error) {
+	if req.JarPath == "" {
+		return nil, errors.New("server jar path is required")
+	}
+	if req.JavaPath == "" {
+		return nil, errors.New("java path is required")
+	}
+
+	args := req.JVM.Args()
+	args = append(args, "-jar", req.JarPath)
+	args = append(args, req.ExtraArgs...)
+
+	cmd := exec.Command(req.JavaPath, args...)
+	cmd.Dir = req.WorkingDir
+	cmd.Env = launchEnv(nil)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("starting server process",
+		"path", cmd.Path,
+		"args", cmd.Args,
+		"dir", cmd.Dir,
+	)
+
+	group, err := fork.StartInGroup(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start server process: %w", err)
+	}
+
+	s := &Server{
+		cmd:   cmd,
+		stdin: stdin,
+		group: group,
+		done:  make(chan struct{}),
+	}
+
+	// The output must be read to the end before Wait is called.
+	var readers sync.WaitGroup
+	readers.Add(2)
+	go scanOutput(&readers, stdout, "stdout", req.OnOutput)
+	go scanOutput(&readers, stderr, "stderr", req.OnOutput)
+
+	go func() {
+		readers.Wait()
+		err := cmd.Wait()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			err = &ExitError{ExitCode: exitErr.ExitCode()}
+		}
+		s.err = err
+		slog.Info("server process exited", "pid", cmd.Process.Pid, "error", err)
+		close(s.done)
+		group.Close()
+	}()
+
+	return s, nil
+}
+
+// scanOutput passes each line read from r to onOutput.
+func scanOutput(wg *sync.WaitGroup, r io.Reader, stream string, onOutput func(stream, line string)) {
+	defer wg.Done()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if onOutput != nil {
+			onOutput(stream, scanner.Text())
+		}
+	}
+}
+
+// PID returns the process ID of the server.
+func (s *Server) PID() int {
+	return s.cmd.Process.Pid
+}
+
+// Done returns a channel that is closed when the server has exited.
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait waits for the server to exit. A non-zero exit code is returned as
+// an *ExitError.
+func (s *Server) Wait() error {
+	<-s.done
+	return s.err
+}
+
+// Send writes a console command to the server.
+func (s *Server) Send(command string) error {
+	select {
+	case <-s.done:
+		return errors.New("server is not running")
+	default:
+	}
+
+	s.stdinMu.Lock()
+	defer s.stdinMu.Unlock()
+	_, err := io.WriteString(s.stdin, command+"\n")
+	return err
+}
+
+// Stop asks the server to shut down with the "stop" console command. If it
+// has not exited within the timeout, it and the processes it started are
+// terminated, and killed if they still run after a short grace period.
+func (s *Server) Stop(timeout time.Duration) error {
+	if err := s.Send("stop"); err != nil {
+		slog.Debug("unable to send stop command to server", "error", err)
+	}
+
+	select {
+	case <-s.done:
+		return nil
+	case <-time.After(timeout):
+	}
+
+	slog.Warn("server did not stop in time, terminating it", "pid", s.PID())
+	if err := s.group.TerminateTree(serverTerminateGrace, s.done); err != nil {
+		return fmt.Errorf("failed to terminate server process: %w", err)
+	}
+	<-s.done
+	return nil
+}
@@ -0,0 +1,151 @@
+package launchopts
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Variables that can be used in custom arguments and environment values,
+// written as ${NAME}.
+const (
+	// VarGameDir is the game's install directory.
+	VarGameDir = "GAME_DIR"
+
+	// VarProfileUUID is the UUID of the profile the game is played as.
+	VarProfileUUID = "PROFILE_UUID"
+
+	// VarChannel is the channel the game is launched from.
+	VarChannel = "CHANNEL"
+)
+
+// TemplateVariables are the variables custom arguments can refer to.
+var TemplateVariables = []string{VarGameDir, VarProfileUUID, VarChannel}
+
+// reservedArgs are the arguments the launcher passes itself. Custom game
+// arguments cannot pass them, so they cannot replace the session.
+var reservedArgs = []string{
+	"-jar",
+	"--sessionToken",
+	"--identityToken",
+	"--profileId",
+	"--attestation",
+	"--attestationToken",
+	"--channel",
+	"--server",
+}
+
+// envName matches a valid environment variable name.
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// CustomArgs are arguments and environment variables an advanced user adds
+// to the game's launch, such as Mesa overrides or DXVK_HUD. Values may refer
+// to the TemplateVariables, which are substituted at launch.
+type CustomArgs struct {
+	// JVMArgs are passed to the JVM, before -jar.
+	JVMArgs []string `json:"jvm_args,omitempty"`
+
+	// GameArgs are passed to the game, after the launcher's arguments.
+	GameArgs []string `json:"game_args,omitempty"`
+
+	// Env are environment variables set for the game. They take precedence
+	// over the launcher's own and the inherited environment.
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// IsZero reports whether no custom arguments or variables are set.
+func (c CustomArgs) IsZero() bool {
+	return len(c.JVMArgs) == 0 && len(c.GameArgs) == 0 && len(c.Env) == 0
+}
+
+// Validate reports whether the custom arguments are usable: JVM arguments
+// are options, no game argument is one the launcher passes itself,
+// environment variables have valid names, and every ${NAME} refers to a
+// known variable.
+func (c CustomArgs) Validate() error {
+	for _, arg := range c.JVMArgs {
+		if !strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("JVM argument %q is not an option", arg)
+		}
+		if arg == "-jar" {
+			return errors.New("JVM arguments cannot set the game JAR")
+		}
+		if _, err := expandTemplate(arg, nil); err != nil {
+			return fmt.Errorf("JVM argument %q: %w", arg, err)
+		}
+	}
+	for _, arg := range c.GameArgs {
+		name, _, _ := strings.Cut(arg, "=")
+		if slices.Contains(reservedArgs, name) {
+			return fmt.Errorf("game argument %s is set by the launcher", name)
+		}
+		if _, err := expandTemplate(arg, nil); err != nil {
+			return fmt.Errorf("game argument %q: %w", arg, err)
+		}
+	}
+	for name, value := range c.Env {
+		if !envName.MatchString(name) {
+			return fmt.Errorf("invalid environment variable name %q", name)
+		}
+		if _, err := expandTemplate(value, nil); err != nil {
+			return fmt.Errorf("environment variable %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Expand returns the custom JVM arguments, game arguments and environment
+// with the variables substituted. Environment variables are in NAME=value
+// form, sorted by name.
+func (c CustomArgs) Expand(vars map[string]string) (jvmArgs, gameArgs, env []string, err error) {
+	for _, arg := range c.JVMArgs {
+		s, err := expandTemplate(arg, vars)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		jvmArgs = append(jvmArgs, s)
+	}
+	for _, arg := range c.GameArgs {
+		s, err := expandTemplate(arg, vars)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		gameArgs = append(gameArgs, s)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Env)) {
+		s, err := expandTemplate(c.Env[name], vars)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		env = append(env, name+"="+s)
+	}
+	return jvmArgs, gameArgs, env, nil
+}
+
+// expandTemplate substitutes each ${NAME} in s with its value in vars. A
+// $ not followed by { is kept as is. With nil vars, s is only checked for
+// unknown or unterminated variables.
+func expandTemplate(s string, vars map[string]string) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		b.WriteString(s[:i])
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", errors.New("unterminated ${")
+		}
+		name := s[i+2 : i+end]
+		if !slices.Contains(TemplateVariables, name) {
+			return "", fmt.Errorf("unknown variable ${%s}", name)
+		}
+		b.WriteString(vars[name])
+		s = s[i+end+1:]
+	}
+}
@@ -0,0 +1,149 @@
+// Package launchopts holds the options the user sets for launching the
+// game: the JVM's memory and garbage collector, and custom arguments. They
+// are kept apart from package launch so the channel state can store them
+// without depending on it.
+package launchopts
+
+import (
+	"fmt"
+
+	"hytale-launcher/internal/sysinfo"
+)
+
+// Garbage collectors that can be selected for the game JVM.
+const (
+	GCDefault  = ""
+	GCSerial   = "serial"
+	GCParallel = "parallel"
+	GCG1       = "g1"
+	GCZ        = "zgc"
+)
+
+// Bounds for the computed heap size, in MiB.
+const (
+	minHeapMB     = 1024
+	maxHeapMB     = 8192
+	heapStepMB    = 256
+	fallbackMemMB = 4096
+)
+
+// JVMOptions controls the memory and garbage collector settings of the game
+// JVM. Zero values are filled in from DefaultJVMOptions, so a partially
+// specified value overrides only the fields that are set.
+type JVMOptions struct {
+	// MaxHeapMB is the maximum heap size (-Xmx) in MiB.
+	MaxHeapMB int `json:"max_heap_mb,omitempty"`
+
+	// MinHeapMB is the initial heap size (-Xms) in MiB.
+	MinHeapMB int `json:"min_heap_mb,omitempty"`
+
+	// GC is one of the GC* constants.
+	GC string `json:"gc,omitempty"`
+}
+
+// DefaultJVMOptions computes JVM settings from the detected system memory and
+// CPU count. The heuristic is:
+//
+//   - Reserve a quarter of physical memory, and at least 2 GiB, for the
+//     operating system, the launcher and other applications.
+//   - Give the game half of what remains, rounded down to 256 MiB and clamped
+//     to between 1 GiB and 8 GiB. Larger heaps mostly lengthen GC pauses.
+//   - Start the heap at a quarter of the maximum so low-memory machines do
+//     not commit memory up front.
+//   - Use the serial collector on machines with two or fewer CPUs or a heap
+//     under 2 GiB, where concurrent collectors cost more than they save, and
+//     G1 otherwise.
+//
+// If the amount of memory cannot be determined, 4 GiB is assumed.
+func DefaultJVMOptions() JVMOptions {
+	return computeJVMOptions(sysinfo.TotalMemory()/(1024*1024), sysinfo.CPUCount())
+}
+
+// computeJVMOptions applies the sizing heuristic to the given total memory
+// in MiB and CPU count.
+func computeJVMOptions(totalMB uint64, cpus int) JVMOptions {
+	total := int(totalMB)
+	if total <= 0 {
+		total = fallbackMemMB
+	}
+
+	reserve := max(total/4, 2048)
+	heap := (total - reserve) / 2
+	heap = heap / heapStepMB * heapStepMB
+	heap = min(max(heap, minHeapMB), maxHeapMB)
+
+	opts := JVMOptions{
+		MaxHeapMB: heap,
+		MinHeapMB: max(heap/4/heapStepMB*heapStepMB, heapStepMB),
+		GC:        GCG1,
+	}
+
+	if cpus <= 2 || heap < 2048 {
+		opts.GC = GCSerial
+	}
+
+	return opts
+}
+
+// Merge returns o with any unset fields taken from defaults.
+func (o JVMOptions) Merge(defaults JVMOptions) JVMOptions {
+	if o.MaxHeapMB == 0 {
+		o.MaxHeapMB = defaults.MaxHeapMB
+	}
+	if o.MinHeapMB == 0 {
+		o.MinHeapMB = defaults.MinHeapMB
+	}
+	if o.GC == GCDefault {
+		o.GC = defaults.GC
+	}
+	return o
+}
+
+// Validate reports whether the options are usable.
+func (o JVMOptions) Validate() error {
+	if o.MaxHeapMB < 0 || o.MinHeapMB < 0 {
+		return fmt.Errorf("heap sizes must not be negative")
+	}
+	if o.MaxHeapMB != 0 && o.MaxHeapMB < 512 {
+		return fmt.Errorf("maximum heap must be at least 512 MiB")
+	}
+	if o.MaxHeapMB != 0 && o.MinHeapMB > o.MaxHeapMB {
+		return fmt.Errorf("initial heap (%d MiB) exceeds maximum heap (%d MiB)", o.MinHeapMB, o.MaxHeapMB)
+	}
+	switch o.GC {
+	case GCDefault, GCSerial, GCParallel, GCG1, GCZ:
+	default:
+		return fmt.Errorf("unknown garbage collector %q", o.GC)
+	}
+	return nil
+}
+
+// Args returns the JVM arguments for these options. They must be placed
+// before -jar on the command line.
+func (o JVMOptions) Args() []string {
+	var args []string
+
+	if o.MaxHeapMB > 0 {
+		args = append(args, fmt.Sprintf("-Xmx%dm", o.MaxHeapMB))
+	}
+	if o.MinHeapMB > 0 {
+		minHeap := o.MinHeapMB
+		if o.MaxHeapMB > 0 {
+			minHeap = min(minHeap, o.MaxHeapMB)
+		}
+		args = append(args, fmt.Sprintf("-Xms%dm", minHeap))
+	}
+
+	switch o.GC {
+	case GCSerial:
+		args = append(args, "-XX:+UseSerialGC")
+	case GCParallel:
+		args = append(args, "-XX:+UseParallelGC")
+	case GCG1:
+		args = append(args, "-XX:+UseG1GC")
+	case GCZ:
+		args = append(args, "-XX:+UseZGC")
+	}
+
+	return args
+}
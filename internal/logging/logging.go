@@ -3,12 +3,10 @@ package logging
 
 import (
 	"fmt"
-	"io"
-	"log"
 	"log/slog"
 	"os"
-	"path/filepath"
 	"sync"
+	"time"
 
 	"hytale-launcher/internal/build"
 	"hytale-launcher/internal/hytale"
@@ -16,24 +14,34 @@ import (
 )
 
 const (
-	// logFileName is the name of the log file.
+	// logDirName is the directory in the storage directory holding log files.
+	logDirName = "logs"
+
+	// logFileName is the name of the current log file.
 	logFileName = "hytale-launcher.log"
 
-	// maxLogFileSize is the maximum size of the log file before it is removed (10MB).
+	// maxLogFileSize is the size at which the log file is rotated (10MB).
 	maxLogFileSize = 10 * 1024 * 1024
+
+	// maxBackups is the number of rotated log files kept.
+	maxBackups = 5
+
+	// maxAge is how long rotated log files are kept.
+	maxAge = 14 * 24 * time.Hour
 )
 
 var (
 	// logFile is the current open log file.
-	logFile *os.File
+	logFile *rotatingFile
 
 	// initOnce ensures Init is only called once.
 	initOnce sync.Once
 )
 
 // Init initializes the logging system.
-// It creates a log file in the hytale storage directory and configures
-// both the standard logger and slog to write to both the file and stdout.
+// It writes JSON logs to rotating files in the logs directory of the
+// hytale storage directory, and text logs to stdout. Output from the
+// standard logger is routed through slog.
 func Init() error {
 	var initErr error
 
@@ -44,39 +52,28 @@ func Init() error {
 	return initErr
 }
 
+// logDir returns the directory log files are written to.
+func logDir() string {
+	return hytale.InStorageDir(logDirName)
+}
+
 func doInit() error {
-	// Get the log file path in the storage directory.
-	logPath := hytale.InStorageDir(logFileName)
-	logDir := filepath.Dir(logPath)
+	dir := logDir()
 
-	// Ensure the storage directory exists.
-	if err := ioutil.MkdirAll(logDir); err != nil {
-		return fmt.Errorf("unable to create storage directory: %w", err)
+	// Ensure the log directory exists.
+	if err := ioutil.MkdirAll(dir); err != nil {
+		return fmt.Errorf("unable to create log directory: %w", err)
 	}
 
-	// Check if the log file exists and is too large.
-	if info, err := os.Stat(logPath); err == nil {
-		if info.Size() > maxLogFileSize {
-			if err := os.Remove(logPath); err != nil {
-				return fmt.Errorf("unable to remove oversized log file %s: %w", logPath, err)
-			}
-		}
-	}
+	// Older versions wrote a single text log to the storage directory.
+	os.Remove(hytale.InStorageDir(logFileName))
 
-	// Open the log file for appending.
-	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	f, err := openRotating(dir)
 	if err != nil {
-		return fmt.Errorf("unable to open log file %s: %w", logPath, err)
+		return err
 	}
 	logFile = f
 
-	// Create a multi-writer that writes to both the file and stdout.
-	multiWriter := io.MultiWriter(logFile, os.Stdout)
-
-	// Configure the standard logger.
-	log.SetOutput(multiWriter)
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
-
 	// Determine the log level based on environment.
 	var logLevel slog.Level
 	if build.DebugLogging() {
@@ -85,10 +82,15 @@ func doInit() error {
 		logLevel = slog.LevelInfo
 	}
 
-	// Configure slog with a text handler.
-	handler := slog.NewTextHandler(multiWriter, &slog.HandlerOptions{
-		Level: logLevel,
-	})
+	// Files get JSON so the log viewer can filter them; the console gets
+	// text for reading during development.
+	opts := &slog.HandlerOptions{Level: logLevel}
+	handler := teeHandler{
+		slog.NewJSONHandler(logFile, &slog.HandlerOptions{Level: logLevel, AddSource: true}),
+		slog.NewTextHandler(os.Stdout, opts),
+	}
+
+	// This also sends the standard logger's output through the handler.
 	slog.SetDefault(slog.New(handler))
 
 	return nil
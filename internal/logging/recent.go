@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a log entry read back from the log files.
+type Entry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"msg"`
+	Source  string         `json:"source,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// Dir returns the directory the log files are written to.
+func Dir() string {
+	return logDir()
+}
+
+// Files returns the paths of the current and rotated log files, newest
+// first. The current file may not exist yet.
+func Files() []string {
+	dir := logDir()
+	return append([]string{filepath.Join(dir, logFileName)}, listBackups(dir)...)
+}
+
+// Recent returns up to n of the most recent log entries at or above the
+// given level, oldest first. It reads the current log file and, if that
+// does not hold enough entries, the rotated ones. Lines that are not JSON,
+// such as those from older launcher versions, are skipped.
+func Recent(n int, level slog.Level) ([]Entry, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	var entries []Entry
+	for _, path := range Files() {
+		fileEntries, err := readEntries(path, level)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		// Older files come later, so their entries go in front.
+		entries = append(fileEntries, entries...)
+		if len(entries) >= n {
+			break
+		}
+	}
+
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, nil
+}
+
+// readEntries reads the entries at or above level from a log file.
+func readEntries(path string, level slog.Level) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry, ok := parseEntry(scanner.Bytes())
+		if !ok || ParseLevel(entry.Level) < level {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// parseEntry decodes a JSON log line. Keys other than the standard ones
+// are collected into Attrs.
+func parseEntry(line []byte) (Entry, bool) {
+	var raw map[string]any
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return Entry{}, false
+	}
+
+	var entry Entry
+	if s, ok := raw[slog.TimeKey].(string); ok {
+		entry.Time, _ = time.Parse(time.RFC3339Nano, s)
+	}
+	entry.Level, _ = raw[slog.LevelKey].(string)
+	entry.Message, _ = raw[slog.MessageKey].(string)
+	if src, ok := raw[slog.SourceKey].(map[string]any); ok {
+		file, _ := src["file"].(string)
+		line, _ := src["line"].(float64)
+		entry.Source = filepath.Base(file) + ":" + strconv.Itoa(int(line))
+	}
+
+	for _, key := range []string{slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey} {
+		delete(raw, key)
+	}
+	if len(raw) > 0 {
+		entry.Attrs = raw
+	}
+	return entry, true
+}
+
+// ParseLevel parses a level name such as "info" or "WARN". Unknown names
+// are treated as debug so that nothing is filtered out.
+func ParseLevel(s string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.ToUpper(s))); err != nil {
+		return slog.LevelDebug
+	}
+	return level
+}
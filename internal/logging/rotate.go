@@ -0,0 +1,140 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatingFile is an io.Writer that writes to a log file and moves it
+// aside once it grows past maxSize. Rotated files are named with a
+// timestamp and are removed once there are more than maxBackups of them
+// or they are older than maxAge.
+type rotatingFile struct {
+	mu   sync.Mutex
+	dir  string
+	f    *os.File
+	size int64
+}
+
+// openRotating opens the current log file in dir for appending.
+func openRotating(dir string) (*rotatingFile, error) {
+	r := &rotatingFile{dir: dir}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	r.prune()
+	return r, nil
+}
+
+// Write implements io.Writer, rotating the file first if the write would
+// take it past maxSize.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return 0, os.ErrClosed
+	}
+	if r.size > 0 && r.size+int64(len(p)) > maxLogFileSize {
+		if err := r.rotate(); err != nil {
+			// Keep logging to the current file rather than losing entries.
+			fmt.Fprintln(os.Stderr, "unable to rotate log file:", err)
+		}
+	}
+
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current log file.
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+// open opens the current log file and records its size.
+func (r *rotatingFile) open() error {
+	path := filepath.Join(r.dir, logFileName)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open log file %s: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("unable to stat log file %s: %w", path, err)
+	}
+	r.f = f
+	r.size = info.Size()
+	return nil
+}
+
+// rotate moves the current file aside, opens a new one and removes
+// backups past the retention limits. The caller must hold mu.
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	r.f = nil
+
+	current := filepath.Join(r.dir, logFileName)
+	backup := filepath.Join(r.dir, backupName(time.Now()))
+	if err := os.Rename(current, backup); err != nil {
+		// Reopen the current file so logging continues.
+		if openErr := r.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("unable to rename log file: %w", err)
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.prune()
+	return nil
+}
+
+// prune removes rotated files past maxBackups or older than maxAge.
+func (r *rotatingFile) prune() {
+	backups := listBackups(r.dir)
+	cutoff := time.Now().Add(-maxAge)
+
+	for i, path := range backups {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if i >= maxBackups || info.ModTime().Before(cutoff) {
+			os.Remove(path)
+		}
+	}
+}
+
+// backupName returns the file name for a log file rotated at t.
+func backupName(t time.Time) string {
+	base := strings.TrimSuffix(logFileName, filepath.Ext(logFileName))
+	return fmt.Sprintf("%s-%s%s", base, t.Format("20060102-150405.000"), filepath.Ext(logFileName))
+}
+
+// listBackups returns the rotated log files in dir, newest first.
+func listBackups(dir string) []string {
+	base := strings.TrimSuffix(logFileName, filepath.Ext(logFileName))
+	matches, _ := filepath.Glob(filepath.Join(dir, base+"-*"+filepath.Ext(logFileName)))
+
+	// Names embed the rotation time, so they sort chronologically.
+	sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+	return matches
+}
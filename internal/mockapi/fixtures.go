@@ -0,0 +1,90 @@
+package mockapi
+
+import (
+	"fmt"
+
+	"hytale-launcher/internal/account"
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/news"
+	"hytale-launcher/internal/verget"
+)
+
+// Fixtures holds the responses served by a mock server. A nil or missing
+// entry is answered with 404 Not Found.
+type Fixtures struct {
+	// LauncherData is served from /launcher-data.
+	LauncherData *LauncherData
+
+	// Manifests holds the version manifests, keyed by ManifestKey.
+	Manifests map[string]*verget.Manifest
+
+	// PatchSets holds the patch sets, keyed by GamePatchKey or JREPatchKey.
+	PatchSets map[string]*PatchSet
+
+	// Articles is served as the launcher news feed.
+	Articles []news.Article
+
+	// Token is returned from the OAuth token endpoint. When its access
+	// token is set, launcher data requires it as a bearer token.
+	Token Token
+
+	// Files holds downloadable files, such as patches and archives, by
+	// name. Use Server.FileURL to refer to them in other fixtures.
+	Files map[string][]byte
+}
+
+// LauncherData is the response of the launcher data endpoint.
+type LauncherData struct {
+	Owner          string                       `json:"owner"`
+	Profiles       []account.Profile            `json:"profiles"`
+	Patchlines     map[string]account.Patchline `json:"patchlines"`
+	EULAAcceptedAt string                       `json:"eula_accepted_at,omitempty"`
+}
+
+// PatchSet is the response of the game and Java runtime patch set
+// endpoints.
+type PatchSet struct {
+	Steps     []PatchStep        `json:"steps"`
+	JRE       *appstate.JRERange `json:"jre,omitempty"`
+	Mandatory bool               `json:"mandatory,omitempty"`
+}
+
+// PatchStep is a single patch between two builds. Its fields are encoded
+// with their Go names, as the launcher expects.
+type PatchStep struct {
+	FromBuild    int
+	ToBuild      int
+	ToVersion    string
+	PatchURL     string
+	PatchSize    int64
+	SignatureURL string
+	SigSize      int64
+	PatchSHA256  string
+	SigSHA256    string
+}
+
+// Token is the response of the OAuth token endpoint.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+}
+
+// ManifestKey returns the Fixtures.Manifests key for a component's
+// version manifest on a channel.
+func ManifestKey(channel, component string) string {
+	return channel + "/" + component
+}
+
+// GamePatchKey returns the Fixtures.PatchSets key for the game patches
+// from a build on a channel.
+func GamePatchKey(channel string, fromBuild int) string {
+	return fmt.Sprintf("%s/%d", channel, fromBuild)
+}
+
+// JREPatchKey returns the Fixtures.PatchSets key for the Java runtime
+// patches of a major from a build on a channel.
+func JREPatchKey(channel string, major, fromBuild int) string {
+	return fmt.Sprintf("%s/jre-%d/%d", channel, major, fromBuild)
+}
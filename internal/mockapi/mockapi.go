@@ -0,0 +1,205 @@
+// Package mockapi provides an in-process mock of the Hytale backend for
+// integration testing. It serves launcher data, version manifests, patch
+// sets, the OAuth token endpoint and the news feed from configurable
+// fixtures, and can route all endpoints to itself.
+package mockapi
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/news"
+)
+
+// Server is a running mock backend.
+type Server struct {
+	// URL is the base URL of the server, such as "http://127.0.0.1:4321".
+	URL string
+
+	srv *httptest.Server
+
+	// mu protects fixtures and requests.
+	mu       sync.Mutex
+	fixtures Fixtures
+	requests []string
+}
+
+// New starts a mock server serving the given fixtures. It must be closed
+// with Close.
+func New(fixtures Fixtures) *Server {
+	s := &Server{fixtures: fixtures}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /launcher/version/{channel}/{component}", s.handleManifest)
+	mux.HandleFunc("GET /launcher/launcher-feed/{release}/feed.json", s.handleFeed)
+	mux.HandleFunc("GET /account-data/launcher-data", s.handleLauncherData)
+	mux.HandleFunc("GET /account-data/patches/{os}/{arch}/{channel}/{build}", s.handleGamePatches)
+	mux.HandleFunc("GET /account-data/patches/{os}/{arch}/{channel}/{jre}/{build}", s.handleJREPatches)
+	mux.HandleFunc("POST /oauth.accounts/oauth2/token", s.handleToken)
+	mux.HandleFunc("GET /files/{name}", s.handleFile)
+
+	s.srv = httptest.NewServer(s.record(mux))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Environment returns the endpoint environment that routes every endpoint
+// to this server.
+func (s *Server) Environment() endpoints.Environment {
+	u, _ := url.Parse(s.URL)
+	return endpoints.Environment{
+		Name:         "mock",
+		Domain:       u.Host,
+		Insecure:     true,
+		ServicePaths: true,
+	}
+}
+
+// Use routes every endpoint to this server until the returned function is
+// called, which restores the previous environment. It only works in
+// development builds.
+func (s *Server) Use() (restore func(), err error) {
+	previous := endpoints.Current()
+	if err := endpoints.SetEnvironment(s.Environment()); err != nil {
+		return nil, err
+	}
+	return func() {
+		if err := endpoints.SetEnvironment(previous); err != nil {
+			slog.Warn("unable to restore endpoint environment", "error", err)
+		}
+	}, nil
+}
+
+// Update changes the fixtures served from now on.
+func (s *Server) Update(fn func(*Fixtures)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.fixtures)
+}
+
+// FileURL returns the URL a file in Fixtures.Files is served from.
+func (s *Server) FileURL(name string) string {
+	return s.URL + "/files/" + url.PathEscape(name)
+}
+
+// Requests returns the method and path of every request received so far,
+// such as "GET /account-data/launcher-data".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// record wraps h to log each request.
+func (s *Server) record(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleManifest(w http.ResponseWriter, r *http.Request) {
+	component := strings.TrimSuffix(r.PathValue("component"), ".json")
+	key := ManifestKey(r.PathValue("channel"), component)
+
+	s.mu.Lock()
+	manifest := s.fixtures.Manifests[key]
+	s.mu.Unlock()
+
+	writeJSON(w, manifest)
+}
+
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	articles := s.fixtures.Articles
+	s.mu.Unlock()
+
+	writeJSON(w, &struct {
+		Articles []news.Article `json:"articles"`
+	}{articles})
+}
+
+func (s *Server) handleLauncherData(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data := s.fixtures.LauncherData
+	token := s.fixtures.Token.AccessToken
+	s.mu.Unlock()
+
+	if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, data)
+}
+
+func (s *Server) handleGamePatches(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("channel") + "/" + r.PathValue("build")
+	s.writePatchSet(w, key)
+}
+
+func (s *Server) handleJREPatches(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("channel") + "/" + r.PathValue("jre") + "/" + r.PathValue("build")
+	s.writePatchSet(w, key)
+}
+
+// writePatchSet writes the patch set fixture with the given key.
+func (s *Server) writePatchSet(w http.ResponseWriter, key string) {
+	s.mu.Lock()
+	set := s.fixtures.PatchSets[key]
+	s.mu.Unlock()
+
+	writeJSON(w, set)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	token := s.fixtures.Token
+	s.mu.Unlock()
+
+	if token.AccessToken == "" {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+	if token.TokenType == "" {
+		token.TokenType = "bearer"
+	}
+	writeJSON(w, &token)
+}
+
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data, ok := s.fixtures.Files[r.PathValue("name")]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(data)
+}
+
+// writeJSON writes v as a JSON response, or 404 Not Found if v is nil.
+func writeJSON[T any](w http.ResponseWriter, v *T) {
+	if v == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("mock api: unable to write response", "error", err)
+	}
+}
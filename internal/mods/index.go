@@ -0,0 +1,151 @@
+// Package mods browses a remote mod index and installs mods into a game
+// build's mods directory.
+package mods
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"hytale-launcher/internal/api"
+)
+
+// PageSize is the number of mods returned per search page.
+const PageSize = 20
+
+// Mod is an entry in the mod index.
+type Mod struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Summary   string    `json:"summary"`
+	Author    string    `json:"author"`
+	IconURL   string    `json:"icon_url,omitempty"`
+	Downloads int64     `json:"downloads"`
+	Versions  []Version `json:"versions"`
+}
+
+// Version is a downloadable release of a mod.
+type Version struct {
+	Version   string    `json:"version"`
+	URL       string    `json:"url"`
+	SHA256    string    `json:"sha256"`
+	Size      int64     `json:"size"`
+	Published time.Time `json:"published"`
+
+	// MinBuild and MaxBuild bound the game builds the version works with.
+	// Zero means unbounded.
+	MinBuild int `json:"min_build,omitempty"`
+	MaxBuild int `json:"max_build,omitempty"`
+}
+
+// Compatible returns true if the version works with the given game build.
+func (v Version) Compatible(build int) bool {
+	if v.MinBuild > 0 && build < v.MinBuild {
+		return false
+	}
+	if v.MaxBuild > 0 && build > v.MaxBuild {
+		return false
+	}
+	return true
+}
+
+// Latest returns the newest version compatible with the given game build,
+// or nil if there is none. Versions are listed newest first by the index.
+func (m *Mod) Latest(build int) *Version {
+	for i := range m.Versions {
+		if m.Versions[i].Compatible(build) {
+			return &m.Versions[i]
+		}
+	}
+	return nil
+}
+
+// Find returns the version with the given name, or nil if there is none.
+func (m *Mod) Find(version string) *Version {
+	for i := range m.Versions {
+		if m.Versions[i].Version == version {
+			return &m.Versions[i]
+		}
+	}
+	return nil
+}
+
+// SearchResult is a page of search results.
+type SearchResult struct {
+	Mods  []Mod `json:"mods"`
+	Page  int   `json:"page"`
+	Pages int   `json:"pages"`
+	Total int   `json:"total"`
+}
+
+// Index is a client for a mod index.
+type Index struct {
+	// URL is the base URL of the index.
+	URL string
+}
+
+// Search returns a page of mods matching the query. Page numbers start at
+// 1. Only versions compatible with the given game build are included, and
+// mods with no compatible version are left out; a build of zero disables
+// the filtering.
+func (idx *Index) Search(ctx context.Context, query string, page, build int) (*SearchResult, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("page", strconv.Itoa(page))
+	params.Set("per_page", strconv.Itoa(PageSize))
+	if build > 0 {
+		params.Set("build", strconv.Itoa(build))
+	}
+
+	result, err := api.Get[SearchResult](ctx, api.Default, idx.endpoint("search"), params)
+	if err != nil {
+		return nil, fmt.Errorf("unable to search mods: %w", err)
+	}
+
+	if build > 0 {
+		mods := result.Mods[:0]
+		for _, m := range result.Mods {
+			m.Versions = compatibleVersions(m.Versions, build)
+			if len(m.Versions) > 0 {
+				mods = append(mods, m)
+			}
+		}
+		result.Mods = mods
+	}
+	return &result, nil
+}
+
+// Get returns the mod with the given ID, including all its versions.
+func (idx *Index) Get(ctx context.Context, id string) (*Mod, error) {
+	mod, err := api.Get[Mod](ctx, api.Default, idx.endpoint("mods", id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch mod %q: %w", id, err)
+	}
+	return &mod, nil
+}
+
+// endpoint returns the URL of a path under the index.
+func (idx *Index) endpoint(elem ...string) string {
+	for i := range elem {
+		elem[i] = url.PathEscape(elem[i])
+	}
+	return strings.TrimSuffix(idx.URL, "/") + "/" + strings.Join(elem, "/")
+}
+
+// compatibleVersions returns the versions that work with the game build.
+func compatibleVersions(versions []Version, build int) []Version {
+	var compatible []Version
+	for _, v := range versions {
+		if v.Compatible(build) {
+			compatible = append(compatible, v)
+		}
+	}
+	return compatible
+}
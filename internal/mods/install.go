@@ -0,0 +1,163 @@
+package mods
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"hytale-launcher/internal/download"
+	"hytale-launcher/internal/ioutil"
+)
+
+// recordFile is the name of the file in the mods directory that records
+// the mods installed from an index.
+const recordFile = ".installed.json"
+
+// Installed records a mod installed from an index.
+type Installed struct {
+	ID          string    `json:"id"`
+	Version     string    `json:"version"`
+	File        string    `json:"file"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// Dir returns the mods directory of a game build directory.
+func Dir(gameDir string) string {
+	return filepath.Join(gameDir, "mods")
+}
+
+// List returns the mods installed from an index into a game build
+// directory, keyed by mod ID. Mods added to the directory by hand are not
+// included.
+func List(gameDir string) (map[string]Installed, error) {
+	installed := make(map[string]Installed)
+
+	data, err := os.ReadFile(filepath.Join(Dir(gameDir), recordFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return installed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &installed); err != nil {
+		return nil, fmt.Errorf("unable to read installed mods: %w", err)
+	}
+	return installed, nil
+}
+
+// Install downloads a version of a mod into the mods directory of a game
+// build directory, replacing any other installed version of it. The
+// download is verified against the version's checksum, which the index
+// must provide.
+func Install(ctx context.Context, gameDir string, mod *Mod, v *Version, reporter download.ProgressReporter) (*Installed, error) {
+	if v.SHA256 == "" {
+		return nil, fmt.Errorf("mod %q version %s has no checksum", mod.ID, v.Version)
+	}
+
+	installed, err := List(gameDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Download next to the mods so the final rename stays on one file
+	// system, in a directory the game does not load mods from.
+	modsDir := Dir(gameDir)
+	stagingDir := filepath.Join(modsDir, ".download")
+	defer os.RemoveAll(stagingDir)
+
+	tmp, err := download.DownloadCached(ctx, http.DefaultClient, stagingDir, v.URL, v.SHA256, reporter)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download mod %q: %w", mod.ID, err)
+	}
+
+	file := fileName(mod.ID, v)
+	if err := os.Rename(tmp, filepath.Join(modsDir, file)); err != nil {
+		return nil, fmt.Errorf("unable to install mod %q: %w", mod.ID, err)
+	}
+
+	if prev, ok := installed[mod.ID]; ok && prev.File != file {
+		if err := os.Remove(filepath.Join(modsDir, prev.File)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("unable to remove previous mod version",
+				"mod", mod.ID,
+				"file", prev.File,
+				"error", err,
+			)
+		}
+	}
+
+	record := Installed{
+		ID:          mod.ID,
+		Version:     v.Version,
+		File:        file,
+		InstalledAt: time.Now(),
+	}
+	installed[mod.ID] = record
+	if err := save(modsDir, installed); err != nil {
+		return nil, err
+	}
+
+	slog.Info("installed mod",
+		"mod", mod.ID,
+		"version", v.Version,
+		"file", file,
+	)
+	return &record, nil
+}
+
+// modExtensions are the extensions a mod can be installed with.
+var modExtensions = []string{".jar", ".zip"}
+
+// fileName returns the name a mod version is installed under, keeping the
+// extension of the downloaded file if it is one of modExtensions. The URL
+// comes from the index, so any other extension, which could hold a path,
+// is replaced with ".jar".
+func fileName(id string, v *Version) string {
+	u := v.URL
+	if before, _, ok := strings.Cut(u, "?"); ok {
+		u = before
+	}
+	ext := strings.ToLower(path.Ext(u))
+	if !slices.Contains(modExtensions, ext) {
+		ext = ".jar"
+	}
+	return sanitize(id) + "-" + sanitize(v.Version) + ext
+}
+
+// sanitize replaces characters that are not safe in file names.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// save writes the installed mod records to the mods directory.
+func save(modsDir string, installed map[string]Installed) error {
+	if err := ioutil.MkdirAll(modsDir); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(installed, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(modsDir, recordFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,34 @@
+package mods
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		v    Version
+		want string
+	}{
+		{"jar", "map-tools", Version{Version: "1.2.0", URL: "https://example.com/map-tools.jar"}, "map-tools-1.2.0.jar"},
+		{"zip with query", "pack", Version{Version: "2", URL: "https://example.com/pack.ZIP?sig=abc"}, "pack-2.zip"},
+		{"no extension", "mod", Version{Version: "1", URL: "https://example.com/download"}, "mod-1.jar"},
+		{"other extension", "mod", Version{Version: "1", URL: "https://example.com/mod.exe"}, "mod-1.jar"},
+		{"path in extension", "mod", Version{Version: "1", URL: `https://example.com/x.\..\..\evil.exe`}, "mod-1.jar"},
+		{"path in id and version", "../mod", Version{Version: `..\..\1`, URL: "https://example.com/mod.jar"}, ".._mod-.._.._1.jar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fileName(tt.id, &tt.v)
+			if got != tt.want {
+				t.Errorf("fileName() = %q, want %q", got, tt.want)
+			}
+			if strings.ContainsAny(got, `/\`) || filepath.Base(got) != got {
+				t.Errorf("fileName() = %q, which is not a plain file name", got)
+			}
+		})
+	}
+}
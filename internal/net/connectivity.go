@@ -10,25 +10,24 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
-	"sync"
 	"time"
 )
 
 // connectivityEndpoints contains URLs used to verify internet connectivity.
-// These are commonly used captive portal detection endpoints.
-var connectivityEndpoints = []string{
-	"http://captive.apple.com/hotspot-detect.html",
-	"http://connectivitycheck.gstatic.com/generate_204",
-	"http://clients3.google.com/generate_204", // Index 2 is skipped in selection
+// These are commonly used captive portal detection endpoints, each with
+// the response it gives when nothing is intercepting the request.
+var connectivityEndpoints = []probeEndpoint{
+	{URL: "http://captive.apple.com/hotspot-detect.html", Status: http.StatusOK, Body: "Success"},
+	{URL: "http://connectivitycheck.gstatic.com/generate_204", Status: http.StatusNoContent},
+	{URL: "http://clients3.google.com/generate_204", Status: http.StatusNoContent}, // Index 2 is skipped in selection
 }
 
 // CheckConnectivity performs a network connectivity check and returns true
-// if the device has an active internet connection.
+// if the device has an active internet connection that the launcher can
+// use. A captive portal or intercepted TLS counts as no connection; use
+// Probe to tell these apart.
 func CheckConnectivity() bool {
-	if !hasActiveNetworkInterface() {
-		return false
-	}
-	return checkInternetConnectivity()
+	return Probe().State == StateOnline
 }
 
 // hasActiveNetworkInterface checks if there are any active network interfaces
@@ -73,10 +72,11 @@ func hasActiveNetworkInterface() bool {
 	return false
 }
 
-// checkInternetConnectivity attempts to connect to known connectivity check
-// endpoints to verify internet access. It launches goroutines to check
-// multiple endpoints concurrently and returns true if any succeeds.
-func checkInternetConnectivity() bool {
+// checkInternetConnectivity queries known connectivity check endpoints
+// concurrently. It returns StateOnline as soon as one gives its expected
+// response, StateCaptivePortal if an endpoint answered with something else,
+// such as a redirect to a login page, and StateNoInternet otherwise.
+func checkInternetConnectivity() State {
 	client := &http.Client{
 		Timeout: 5 * time.Second,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
@@ -90,43 +90,33 @@ func checkInternetConnectivity() bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
 	defer cancel()
 
-	resultCh := make(chan bool, len(endpoints))
-	var wg sync.WaitGroup
-
+	resultCh := make(chan State, len(endpoints))
 	for _, endpoint := range endpoints {
-		wg.Add(1)
-		go func(url string) {
-			defer wg.Done()
-			if checkEndpoint(ctx, client, url) {
-				select {
-				case resultCh <- true:
-				default:
-				}
-			}
+		go func(e probeEndpoint) {
+			resultCh <- checkEndpoint(ctx, client, e)
 		}(endpoint)
 	}
 
-	// Close the result channel when all goroutines complete
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
-
-	// Wait for either a successful result or context timeout
-	select {
-	case result, ok := <-resultCh:
-		if ok && result {
-			return true
+	result := StateNoInternet
+	for range endpoints {
+		select {
+		case state := <-resultCh:
+			if state == StateOnline {
+				return StateOnline
+			}
+			if state == StateCaptivePortal {
+				result = StateCaptivePortal
+			}
+		case <-ctx.Done():
+			return result
 		}
-		return false
-	case <-ctx.Done():
-		return false
 	}
+	return result
 }
 
 // selectEndpoints returns a slice of connectivity check endpoints to use.
-// It returns Apple's endpoint plus one randomly selected from the others.
-func selectEndpoints() []string {
+// It returns Google's endpoint plus one randomly selected from the others.
+func selectEndpoints() []probeEndpoint {
 	// Build indices excluding index 2 (clients3.google.com)
 	var indices []int
 	for i := 0; i < len(connectivityEndpoints); i++ {
@@ -139,20 +129,21 @@ func selectEndpoints() []string {
 	randomIdx := rand.Intn(len(indices))
 	selectedIdx := indices[randomIdx]
 
-	// Return Apple's endpoint (index 2) and the randomly selected one
-	result := make([]string, 2)
+	result := make([]probeEndpoint, 2)
 	result[0] = connectivityEndpoints[2]
 	result[1] = connectivityEndpoints[selectedIdx]
 
 	return result
 }
 
-// checkEndpoint performs an HTTP HEAD request to the given URL to check
-// connectivity. Returns true if the request succeeds.
-func checkEndpoint(ctx context.Context, client *http.Client, url string) bool {
-	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+// checkEndpoint performs an HTTP GET request to a connectivity check
+// endpoint. It returns StateOnline if the endpoint gave its expected
+// response, StateCaptivePortal if something else answered, and
+// StateNoInternet if the request failed.
+func checkEndpoint(ctx context.Context, client *http.Client, e probeEndpoint) State {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.URL, nil)
 	if err != nil {
-		return false
+		return StateNoInternet
 	}
 
 	resp, err := client.Do(req)
@@ -160,11 +151,11 @@ func checkEndpoint(ctx context.Context, client *http.Client, url string) bool {
 		// Don't log context cancellation errors
 		if !errors.Is(err, context.Canceled) {
 			slog.Debug("connectivity check request failed",
-				"url", url,
+				"url", e.URL,
 				"error", err,
 			)
 		}
-		return false
+		return StateNoInternet
 	}
 	defer func() {
 		io.Copy(io.Discard, resp.Body)
@@ -172,9 +163,17 @@ func checkEndpoint(ctx context.Context, client *http.Client, url string) bool {
 	}()
 
 	slog.Debug("received connectivity check response",
-		"url", url,
+		"url", e.URL,
 		"status", resp.StatusCode,
 	)
 
-	return true
+	if !e.matches(resp) {
+		slog.Info("connectivity check intercepted, likely a captive portal",
+			"url", e.URL,
+			"status", resp.StatusCode,
+			"location", resp.Header.Get("Location"),
+		)
+		return StateCaptivePortal
+	}
+	return StateOnline
 }
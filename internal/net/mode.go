@@ -23,8 +23,19 @@ var (
 	modeMu sync.RWMutex
 	// currentMode holds the current network mode.
 	currentMode Mode = ModeOnline
+	// modeHistory records recent mode changes, oldest first.
+	modeHistory []ModeChange
 )
 
+// maxModeHistory is the number of mode changes kept in the history.
+const maxModeHistory = 50
+
+// ModeChange records a change of network mode.
+type ModeChange struct {
+	Time time.Time `json:"time"`
+	Mode Mode      `json:"mode"`
+}
+
 // Current returns the current network mode.
 func Current() Mode {
 	modeMu.RLock()
@@ -36,9 +47,23 @@ func Current() Mode {
 func SetMode(mode Mode) {
 	modeMu.Lock()
 	defer modeMu.Unlock()
+
+	if mode != currentMode || len(modeHistory) == 0 {
+		modeHistory = append(modeHistory, ModeChange{Time: time.Now(), Mode: mode})
+		if len(modeHistory) > maxModeHistory {
+			modeHistory = modeHistory[len(modeHistory)-maxModeHistory:]
+		}
+	}
 	currentMode = mode
 }
 
+// History returns the network mode changes in this session, oldest first.
+func History() []ModeChange {
+	modeMu.RLock()
+	defer modeMu.RUnlock()
+	return append([]ModeChange(nil), modeHistory...)
+}
+
 // ErrOffline is returned when an operation cannot be performed because
 // the launcher is in offline mode.
 var ErrOffline = errors.New("launcher is in offline mode")
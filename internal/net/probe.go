@@ -0,0 +1,212 @@
+package net
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"hytale-launcher/internal/endpoints"
+)
+
+// State describes the result of a connectivity probe.
+type State string
+
+const (
+	// StateOnline means the internet and the launcher APIs are reachable.
+	StateOnline State = "online"
+
+	// StateNoNetwork means no network interface is connected.
+	StateNoNetwork State = "no_network"
+
+	// StateNoInternet means a network is connected but the internet could
+	// not be reached.
+	StateNoInternet State = "offline"
+
+	// StateCaptivePortal means requests are answered by something other
+	// than the intended server, typically a hotel or airport Wi-Fi login
+	// page.
+	StateCaptivePortal State = "captive_portal"
+
+	// StateTLSIntercepted means the launcher APIs presented a certificate
+	// from an unknown issuer, as happens when a proxy or security product
+	// intercepts encrypted traffic.
+	StateTLSIntercepted State = "tls_intercepted"
+)
+
+// Connectivity is the result of a connectivity probe.
+type Connectivity struct {
+	State State `json:"state"`
+
+	// Message tells the user what they can do about the state. It is empty
+	// when online.
+	Message string `json:"message,omitempty"`
+
+	// Host is the launcher API host whose certificate was not trusted. It
+	// is only set when TLS is intercepted.
+	Host string `json:"host,omitempty"`
+
+	// CheckedAt is when the probe ran.
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// messages are the user-facing explanations for each state.
+var messages = map[State]string{
+	StateNoNetwork:      "You're not connected to a network. Connect to Wi-Fi or Ethernet to go online.",
+	StateNoInternet:     "The internet can't be reached. Check your connection; you can keep playing offline.",
+	StateCaptivePortal:  "This network requires you to sign in. Open a web browser to complete the network's login page, then try again.",
+	StateTLSIntercepted: "A proxy or security program is intercepting secure connections to Hytale. Allow Hytale through it, or try another network.",
+}
+
+// probeEndpoint is a connectivity check URL and its expected response.
+type probeEndpoint struct {
+	URL    string
+	Status int
+
+	// Body, if set, must appear in the response body.
+	Body string
+}
+
+// matches reports whether resp is the endpoint's expected response.
+func (e probeEndpoint) matches(resp *http.Response) bool {
+	if resp.StatusCode != e.Status {
+		return false
+	}
+	if e.Body == "" {
+		return true
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return err == nil && strings.Contains(string(body), e.Body)
+}
+
+var (
+	// lastMu protects last.
+	lastMu sync.RWMutex
+
+	// last is the result of the most recent probe.
+	last = Connectivity{State: StateOnline}
+)
+
+// Probe checks connectivity and reports its state: whether a network is
+// connected, whether the internet is reachable without interception, and
+// whether TLS connections to the launcher APIs are genuine.
+func Probe() Connectivity {
+	state := StateNoNetwork
+	if hasActiveNetworkInterface() {
+		state = checkInternetConnectivity()
+	}
+	var host string
+	if state == StateOnline {
+		host = checkTLS()
+		if host != "" {
+			state = StateTLSIntercepted
+		}
+	}
+
+	result := Connectivity{
+		State:     state,
+		Message:   messages[state],
+		Host:      host,
+		CheckedAt: time.Now(),
+	}
+
+	lastMu.Lock()
+	last = result
+	lastMu.Unlock()
+
+	return result
+}
+
+// LastConnectivity returns the result of the most recent probe.
+func LastConnectivity() Connectivity {
+	lastMu.RLock()
+	defer lastMu.RUnlock()
+	return last
+}
+
+// tlsProbeURLs returns the launcher API, account and OAuth hosts that are
+// checked for TLS interception.
+func tlsProbeURLs() []string {
+	return []string{
+		endpoints.FeedBase(),
+		endpoints.LauncherData(),
+		endpoints.OAuthBase(),
+	}
+}
+
+// checkTLS connects to the launcher API hosts concurrently and returns the
+// first host whose certificate is not issued by a trusted authority or not
+// valid for the host, or "" if there is none. Other failures are not
+// treated as interception, since a host being unreachable is not evidence
+// of it.
+func checkTLS() string {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	urls := tlsProbeURLs()
+	resultCh := make(chan string, len(urls))
+	for _, url := range urls {
+		go func(url string) {
+			resultCh <- checkTLSHost(ctx, client, url)
+		}(url)
+	}
+
+	for range urls {
+		select {
+		case host := <-resultCh:
+			if host != "" {
+				return host
+			}
+		case <-ctx.Done():
+			return ""
+		}
+	}
+	return ""
+}
+
+// checkTLSHost requests url and returns its host if the connection was
+// intercepted, or "" otherwise.
+func checkTLSHost(ctx context.Context, client *http.Client, url string) string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return ""
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if isInterception(err) {
+			slog.Warn("tls connection to launcher api intercepted", "url", url, "error", err)
+			return req.URL.Host
+		}
+		slog.Debug("tls check request failed", "url", url, "error", err)
+		return ""
+	}
+	resp.Body.Close()
+	return ""
+}
+
+// isInterception reports whether a request error was caused by a
+// certificate the system does not trust for the host.
+func isInterception(err error) bool {
+	var unknown x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var verify *tls.CertificateVerificationError
+	return errors.As(err, &unknown) ||
+		errors.As(err, &hostname) ||
+		errors.As(err, &invalid) ||
+		errors.As(err, &verify)
+}
@@ -0,0 +1,26 @@
+package net
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckTLSHost(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "https://")
+
+	// The test server's certificate is not trusted by a default client.
+	if got := checkTLSHost(context.Background(), &http.Client{}, srv.URL); got != host {
+		t.Errorf("untrusted certificate: got %q, want %q", got, host)
+	}
+
+	// Its own client trusts it.
+	if got := checkTLSHost(context.Background(), srv.Client(), srv.URL); got != "" {
+		t.Errorf("trusted certificate: got %q, want none", got)
+	}
+}
@@ -2,14 +2,14 @@
 package news
 
 import (
+	"context"
 	"log/slog"
-	"net/http"
 	"net/url"
 	"sync"
 	"time"
 
+	"hytale-launcher/internal/api"
 	"hytale-launcher/internal/endpoints"
-	"hytale-launcher/internal/ioutil"
 )
 
 // cacheDuration is the time between feed refreshes.
@@ -34,6 +34,10 @@ type Article struct {
 
 	// PublishedAt is the publication timestamp.
 	PublishedAt string `json:"published_at"`
+
+	// Body is the article's HTML content, if the feed includes it. It is
+	// sanitized before being handed to the webview.
+	Body string `json:"body,omitempty"`
 }
 
 // feedResponse is the JSON structure returned by the feed endpoint.
@@ -106,7 +110,7 @@ func GetCachedArticles() []Article {
 func fetch() ([]Article, error) {
 	feedURL := endpoints.Feed()
 
-	response, err := ioutil.Get[feedResponse](http.DefaultClient, feedURL, nil)
+	response, err := api.Get[feedResponse](context.Background(), api.Default, feedURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -119,15 +123,12 @@ func fetch() ([]Article, error) {
 		baseURL = base
 	}
 
-	// Resolve relative URLs in articles
+	// Resolve relative URLs and sanitize the articles, proxying their
+	// images
 	for i := range response.Articles {
-		if response.Articles[i].ImageURL != "" {
-			response.Articles[i].ImageURL = resolveURL(response.Articles[i].ImageURL)
-		}
-		if response.Articles[i].LinkURL != "" {
-			response.Articles[i].LinkURL = resolveURL(response.Articles[i].LinkURL)
-		}
+		response.Articles[i] = SanitizeArticle(response.Articles[i])
 	}
+	pruneImages(referencedImages(response.Articles))
 
 	return response.Articles, nil
 }
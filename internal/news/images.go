@@ -0,0 +1,237 @@
+package news
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/net"
+)
+
+// ImagePrefix is the URL path under which news images are served to the
+// webview. An image is requested as /news-images/<id>.
+const ImagePrefix = "/news-images/"
+
+// imageCacheDirName is the directory in the storage directory news images
+// are cached in.
+const imageCacheDirName = "news-images"
+
+// maxImageSize is the largest news image that is proxied.
+const maxImageSize = 10 << 20
+
+// imageFetchTimeout bounds fetching an image that is not cached.
+const imageFetchTimeout = 30 * time.Second
+
+// imageTypes are the content types news images may have. SVG is left out,
+// as it can carry script.
+var imageTypes = []string{"image/png", "image/jpeg", "image/webp", "image/gif"}
+
+// imageSecurityPolicy is sent with every image, like extension assets.
+const imageSecurityPolicy = "default-src 'none'; sandbox"
+
+// imageRefs matches the proxied images in sanitized article bodies.
+var imageRefs = regexp.MustCompile(`src="(` + regexp.QuoteMeta(ImagePrefix) + `[0-9a-f]+)"`)
+
+var (
+	// imagesMu protects images.
+	imagesMu sync.RWMutex
+
+	// images maps the ids of proxied images to their source URLs. Only
+	// images referenced by the feed are proxied, so the proxy cannot be
+	// used to fetch anything else.
+	images = make(map[string]string)
+)
+
+// proxyImage registers an image for the proxy and returns the path it is
+// served at, or an empty string if it is not a web URL.
+func proxyImage(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(resolveURL(strings.TrimSpace(raw)))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return ""
+	}
+
+	src := u.String()
+	sum := sha256.Sum256([]byte(src))
+	id := hex.EncodeToString(sum[:16])
+
+	imagesMu.Lock()
+	images[id] = src
+	imagesMu.Unlock()
+	return ImagePrefix + id
+}
+
+// imageCacheDir returns the directory news images are cached in.
+func imageCacheDir() string {
+	return hytale.InStorageDir(imageCacheDirName)
+}
+
+// ImageHandler returns the HTTP handler that serves news images, for use
+// by the Wails asset server. Images are fetched on first request and
+// cached on disk.
+func ImageHandler() http.Handler {
+	return http.HandlerFunc(serveImage)
+}
+
+// serveImage serves a proxied news image.
+func serveImage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := strings.CutPrefix(r.URL.Path, ImagePrefix)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	imagesMu.RLock()
+	src, ok := images[id]
+	imagesMu.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, contentType, err := loadImage(r.Context(), id, src)
+	if err != nil {
+		slog.Warn("unable to proxy news image", "url", src, "error", err)
+		http.Error(w, "image unavailable", http.StatusBadGateway)
+		return
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", contentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Content-Security-Policy", imageSecurityPolicy)
+	h.Set("Cache-Control", "private, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(data)
+	}
+}
+
+// loadImage returns an image from the cache, fetching it first if it is
+// not there.
+func loadImage(ctx context.Context, id, src string) ([]byte, string, error) {
+	p := filepath.Join(imageCacheDir(), id)
+	if data, err := os.ReadFile(p); err == nil {
+		if contentType, ok := imageType(data); ok {
+			return data, contentType, nil
+		}
+	}
+
+	if err := net.OfflineError(); err != nil {
+		return nil, "", err
+	}
+	data, err := fetchImage(ctx, src)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType, ok := imageType(data)
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported image type %s", contentType)
+	}
+
+	if err := os.MkdirAll(imageCacheDir(), 0o755); err == nil {
+		if err := os.WriteFile(p, data, 0o644); err != nil {
+			slog.Warn("unable to cache news image", "error", err)
+		}
+	}
+	return data, contentType, nil
+}
+
+// fetchImage downloads an image, up to maxImageSize.
+func fetchImage(ctx context.Context, src string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, imageFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, errors.New("image too large")
+	}
+	return data, nil
+}
+
+// imageType returns the content type of an image sniffed from its
+// contents, and whether it is one that may be served. The type the server
+// claimed is not trusted.
+func imageType(data []byte) (string, bool) {
+	contentType := http.DetectContentType(data)
+	for _, t := range imageTypes {
+		if contentType == t {
+			return contentType, true
+		}
+	}
+	return contentType, false
+}
+
+// pruneImages removes cached images no longer referenced by the feed and
+// forgets their sources.
+func pruneImages(keep map[string]bool) {
+	imagesMu.Lock()
+	for id := range images {
+		if !keep[id] {
+			delete(images, id)
+		}
+	}
+	imagesMu.Unlock()
+
+	entries, err := os.ReadDir(imageCacheDir())
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !keep[e.Name()] {
+			os.Remove(filepath.Join(imageCacheDir(), e.Name()))
+		}
+	}
+}
+
+// referencedImages returns the ids of the proxied images articles use.
+func referencedImages(articles []Article) map[string]bool {
+	keep := make(map[string]bool)
+	add := func(p string) {
+		if id, ok := strings.CutPrefix(p, ImagePrefix); ok {
+			keep[id] = true
+		}
+	}
+	for _, a := range articles {
+		add(a.ImageURL)
+		for _, m := range imageRefs.FindAllStringSubmatch(a.Body, -1) {
+			add(m[1])
+		}
+	}
+	return keep
+}
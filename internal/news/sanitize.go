@@ -0,0 +1,156 @@
+package news
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// allowedTags are the elements kept in article bodies. Other elements are
+// removed but their text is kept, except for droppedTags.
+var allowedTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil, "span": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil,
+	"h2": nil, "h3": nil, "h4": nil,
+	"ul": nil, "ol": nil, "li": nil,
+	"blockquote": nil, "code": nil, "pre": nil,
+	"figure": nil, "figcaption": nil,
+	"a":   {"href", "title"},
+	"img": {"src", "alt", "title", "width", "height"},
+}
+
+// droppedTags are removed together with everything inside them.
+var droppedTags = []string{
+	"script", "style", "iframe", "frame", "frameset", "object", "embed",
+	"applet", "noscript", "template", "svg", "math", "form", "textarea",
+	"select", "button", "head", "title", "link", "meta", "base",
+}
+
+// voidTags have no closing tag.
+var voidTags = []string{"br", "hr", "img"}
+
+// linkSchemes are the schemes links in article bodies may use.
+var linkSchemes = []string{"https", "http", "mailto"}
+
+// SanitizeArticle returns the article made safe to hand to the webview.
+// Links must be web or mail links, or are removed. The body is reduced to
+// an allow-list of elements and attributes, with scripts, styles and
+// embedded content dropped, and its images, like the article's own, are
+// served through the image proxy rather than loaded from the network by
+// the webview.
+func SanitizeArticle(a Article) Article {
+	a.LinkURL = safeLink(a.LinkURL)
+	a.ImageURL = proxyImage(a.ImageURL)
+	if a.Body != "" {
+		a.Body = sanitizeHTML(a.Body)
+	}
+	return a
+}
+
+// sanitizeHTML reduces an HTML fragment to the allowed elements and
+// attributes.
+func sanitizeHTML(src string) string {
+	z := html.NewTokenizer(strings.NewReader(src))
+	var b strings.Builder
+	var open []string
+	dropped := 0
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			for i := len(open) - 1; i >= 0; i-- {
+				b.WriteString("</" + open[i] + ">")
+			}
+			return b.String()
+
+		case html.TextToken:
+			if dropped == 0 {
+				b.WriteString(html.EscapeString(string(z.Text())))
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			if slices.Contains(droppedTags, tok.Data) {
+				if tt == html.StartTagToken && !slices.Contains(voidTags, tok.Data) {
+					dropped++
+				}
+				continue
+			}
+			attrs, ok := allowedTags[tok.Data]
+			if dropped > 0 || !ok {
+				continue
+			}
+			tag, ok := sanitizeTag(tok, attrs)
+			if !ok {
+				continue
+			}
+			b.WriteString(tag)
+			if tt == html.StartTagToken && !slices.Contains(voidTags, tok.Data) {
+				open = append(open, tok.Data)
+			}
+
+		case html.EndTagToken:
+			tok := z.Token()
+			if slices.Contains(droppedTags, tok.Data) {
+				dropped = max(dropped-1, 0)
+				continue
+			}
+			if dropped > 0 {
+				continue
+			}
+			// Close the element and any left open inside it.
+			if i := slices.Index(open, tok.Data); i >= 0 && slices.Index(open[i+1:], tok.Data) < 0 {
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+			}
+		}
+	}
+}
+
+// sanitizeTag returns a start tag with only the allowed attributes. Link
+// targets are checked and images rewritten to the proxy; a link or image
+// whose target is refused is dropped.
+func sanitizeTag(tok html.Token, allowed []string) (string, bool) {
+	var b strings.Builder
+	b.WriteString("<" + tok.Data)
+	for _, attr := range tok.Attr {
+		if attr.Namespace != "" || !slices.Contains(allowed, attr.Key) {
+			continue
+		}
+		val := attr.Val
+		switch {
+		case tok.Data == "a" && attr.Key == "href":
+			if val = safeLink(val); val == "" {
+				continue
+			}
+		case tok.Data == "img" && attr.Key == "src":
+			if val = proxyImage(val); val == "" {
+				return "", false
+			}
+		}
+		b.WriteString(" " + attr.Key + `="` + html.EscapeString(val) + `"`)
+	}
+	if tok.Data == "a" {
+		b.WriteString(` rel="noopener noreferrer"`)
+	}
+	b.WriteString(">")
+	return b.String(), true
+}
+
+// safeLink returns a link resolved against the feed base if it uses an
+// allowed scheme, or an empty string.
+func safeLink(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(resolveURL(strings.TrimSpace(raw)))
+	if err != nil || !slices.Contains(linkSchemes, strings.ToLower(u.Scheme)) {
+		return ""
+	}
+	return u.String()
+}
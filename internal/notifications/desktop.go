@@ -0,0 +1,25 @@
+package notifications
+
+import "log/slog"
+
+// desktopNotifier shows notifications through the operating system, and
+// logs them as well.
+type desktopNotifier struct{}
+
+// Send implements Notifier by showing a system notification.
+func (d *desktopNotifier) Send(n Notification) error {
+	(&logNotifier{}).Send(n)
+	if err := sendDesktop(n); err != nil {
+		slog.Warn("unable to show system notification", "error", err)
+		return err
+	}
+	return nil
+}
+
+// UseDesktop makes Send show system notifications on platforms that
+// support them. On other platforms notifications are only logged.
+func UseDesktop() {
+	if desktopSupported() {
+		SetNotifier(&desktopNotifier{})
+	}
+}
@@ -0,0 +1,21 @@
+//go:build darwin
+
+package notifications
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// desktopSupported returns true; notifications are shown with osascript.
+func desktopSupported() bool {
+	return true
+}
+
+// sendDesktop shows a notification in Notification Center.
+func sendDesktop(n Notification) error {
+	script := fmt.Sprintf("display notification %s with title %s",
+		strconv.Quote(n.Message), strconv.Quote(n.Title))
+	return exec.Command("osascript", "-e", script).Run()
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package notifications
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+// notifyTimeout is how long a notification is shown, in milliseconds.
+const notifyTimeout = int32(10000)
+
+// desktopSupported returns true if a session bus is available.
+func desktopSupported() bool {
+	_, err := dbus.SessionBus()
+	return err == nil
+}
+
+// sendDesktop shows a notification through the freedesktop.org
+// notification service.
+func sendDesktop(n Notification) error {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+
+	urgency := byte(1)
+	if n.Type == TypeError {
+		urgency = 2
+	}
+
+	obj := conn.Object("org.freedesktop.Notifications", "/org/freedesktop/Notifications")
+	return obj.Call("org.freedesktop.Notifications.Notify", 0,
+		"Hytale Launcher",
+		uint32(0),
+		"applications-games",
+		n.Title,
+		n.Message,
+		[]string{},
+		map[string]dbus.Variant{"urgency": dbus.MakeVariant(urgency)},
+		notifyTimeout,
+	).Err
+}
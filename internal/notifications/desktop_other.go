@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !windows
+
+package notifications
+
+import "errors"
+
+// desktopSupported reports that system notifications are not implemented
+// on this platform.
+func desktopSupported() bool {
+	return false
+}
+
+// sendDesktop is not supported on this platform.
+func sendDesktop(n Notification) error {
+	return errors.New("system notifications are not supported")
+}
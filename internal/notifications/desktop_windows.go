@@ -0,0 +1,42 @@
+//go:build windows
+
+package notifications
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// toastAppID is the application the toast is attributed to. Windows only
+// shows toasts for registered application IDs, so PowerShell's is used.
+const toastAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// toastScript shows a toast with the title and message passed in the
+// environment, so they need no quoting.
+const toastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:HYTALE_NOTIFY_TITLE)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:HYTALE_NOTIFY_MESSAGE)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($env:HYTALE_NOTIFY_APP).Show($toast)
+`
+
+// desktopSupported returns true; notifications are shown as toasts.
+func desktopSupported() bool {
+	return true
+}
+
+// sendDesktop shows a toast notification through PowerShell.
+func sendDesktop(n Notification) error {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	cmd.Env = append(os.Environ(),
+		"HYTALE_NOTIFY_TITLE="+n.Title,
+		"HYTALE_NOTIFY_MESSAGE="+n.Message,
+		"HYTALE_NOTIFY_APP="+toastAppID,
+	)
+	return cmd.Run()
+}
@@ -25,6 +25,10 @@ const (
 	Scopes   = "openid offline auth:launcher"
 )
 
+// ErrStateMismatch is returned for an authorization response whose state
+// is not that of the login in progress. The login is not affected.
+var ErrStateMismatch = errors.New("invalid state parameter")
+
 // callbackData holds data received from an OAuth callback.
 // Based on decompiled structure analysis:
 // - Offset 0x00: success (bool)
@@ -183,60 +187,83 @@ func (l *Loopback) Start() (string, error) {
 
 // handleCallback processes the OAuth callback from the authorization server.
 func (l *Loopback) handleCallback(w http.ResponseWriter, r *http.Request) {
+	if err := l.handleRedirect(r.URL.Query()); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Send success response to browser
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`<!DOCTYPE html>
+<html>
+<head><title>Login Successful</title></head>
+<body style="background:#1b2636;color:#d2d9e2;font-family:sans-serif;display:flex;justify-content:center;align-items:center;height:100vh;margin:0;">
+<div style="text-align:center;">
+<h1>Login Successful</h1>
+<p>You can close this window and return to the Hytale Launcher.</p>
+</div>
+</body>
+</html>`))
+}
+
+// HandleRedirect completes the login from the query parameters of a
+// redirect delivered outside the loopback server, such as a hytale://
+// deep link opened when the browser cannot reach the loopback address.
+func (l *Loopback) HandleRedirect(query url.Values) error {
+	return l.handleRedirect(query)
+}
+
+// handleRedirect verifies the state of an authorization response and
+// starts exchanging its code for tokens.
+func (l *Loopback) handleRedirect(query url.Values) error {
 	l.mu.Lock()
 	state := l.state
 	l.mu.Unlock()
 
 	if state == nil {
-		http.Error(w, "No login in progress", http.StatusBadRequest)
-		return
+		return errors.New("no login in progress")
 	}
 
-	// Verify state parameter
-	if r.URL.Query().Get("state") != state.State {
-		http.Error(w, "Invalid state parameter", http.StatusBadRequest)
-		l.resultCh <- result{Err: errors.New("invalid state parameter")}
-		return
+	// A response for another login, or a forged one, is dropped so it
+	// cannot fail the login in progress.
+	if query.Get("state") != state.State {
+		return ErrStateMismatch
 	}
 
 	// Check for error response
-	if errParam := r.URL.Query().Get("error"); errParam != "" {
-		errDesc := r.URL.Query().Get("error_description")
-		http.Error(w, fmt.Sprintf("Authorization error: %s", errDesc), http.StatusBadRequest)
+	if errParam := query.Get("error"); errParam != "" {
+		errDesc := query.Get("error_description")
 		l.resultCh <- result{Err: fmt.Errorf("authorization error: %s - %s", errParam, errDesc)}
-		return
+		return fmt.Errorf("authorization error: %s", errDesc)
 	}
 
 	// Get authorization code
-	code := r.URL.Query().Get("code")
+	code := query.Get("code")
 	if code == "" {
-		http.Error(w, "No authorization code received", http.StatusBadRequest)
-		l.resultCh <- result{Err: errors.New("no authorization code received")}
-		return
+		err := errors.New("no authorization code received")
+		l.resultCh <- result{Err: err}
+		return err
 	}
 
-	// Send success response to browser
-	w.Header().Set("Content-Type", "text/html")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`<!DOCTYPE html>
-<html>
-<head><title>Login Successful</title></head>
-<body style="background:#1b2636;color:#d2d9e2;font-family:sans-serif;display:flex;justify-content:center;align-items:center;height:100vh;margin:0;">
-<div style="text-align:center;">
-<h1>Login Successful</h1>
-<p>You can close this window and return to the Hytale Launcher.</p>
-</div>
-</body>
-</html>`))
+	// Consume the state so a second redirect with the same code, from the
+	// loopback server and a deep link, cannot be exchanged twice.
+	l.mu.Lock()
+	if l.state != state {
+		l.mu.Unlock()
+		return errors.New("login already completed")
+	}
+	l.state = nil
+	l.mu.Unlock()
 
 	// Exchange code for tokens
-	go l.exchangeCode(code)
+	go l.exchangeCode(code, state)
+	return nil
 }
 
 // exchangeCode exchanges the authorization code for tokens.
-func (l *Loopback) exchangeCode(code string) {
+func (l *Loopback) exchangeCode(code string, state *stateData) {
 	l.mu.Lock()
-	state := l.state
 	config := l.Config
 	l.mu.Unlock()
 
@@ -0,0 +1,190 @@
+package pinning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/crypto"
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/hytale"
+)
+
+// ConfigKey is the base64 Ed25519 public key pin configurations must be
+// signed with. It is set at build time via ldflags; when empty, pin
+// configurations are not applied and only the shipped pins are used.
+//
+//	-ldflags "-X hytale-launcher/internal/pinning.ConfigKey=..."
+var ConfigKey string
+
+// configFileName is the file in the storage directory the last applied
+// pin configuration is kept in, still signed, so it applies from startup.
+const configFileName = "pins.json"
+
+// configKind is the Kind of a pin configuration. Other documents signed in
+// the same envelope, such as the remote configuration, have other kinds,
+// so one cannot be replayed as the other.
+const configKind = "certificate_pins"
+
+// ErrInvalidConfig is returned for a pin configuration that is not
+// correctly signed, is not a pin configuration for this release, has
+// expired, has no pins or would roll back a newer one.
+var ErrInvalidConfig = errors.New("invalid pin configuration")
+
+// Config is a pin configuration. It replaces the shipped pins, so a key
+// can be added ahead of a certificate rotation and a retired or
+// compromised key removed.
+type Config struct {
+	// Version orders configurations. One older than the applied
+	// configuration is refused, so an old configuration cannot be
+	// replayed.
+	Version int `json:"version"`
+
+	// Kind is always "certificate_pins".
+	Kind string `json:"kind"`
+
+	// Release is the release the configuration is for, such as "release"
+	// or "beta". It must match build.Release.
+	Release string `json:"release"`
+
+	// Expires is when the configuration stops applying, after which the
+	// shipped pins are used again.
+	Expires time.Time `json:"expires"`
+
+	// Pins are the pinned keys, in the form of Pins. There is at least
+	// one; pinning can only be turned off by the user.
+	Pins []string `json:"pins"`
+}
+
+// expired reports whether the configuration no longer applies.
+func (c *Config) expired() bool {
+	return time.Now().After(c.Expires)
+}
+
+// LoadConfig applies the cached pin configuration, if there is one that
+// is still valid. It is called at startup, before any account request.
+func LoadConfig() {
+	data, err := os.ReadFile(hytale.InStorageDir(configFileName))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("unable to read pin configuration", "error", err)
+		}
+		return
+	}
+
+	var sc crypto.Signed
+	if err := json.Unmarshal(data, &sc); err != nil {
+		slog.Warn("unable to decode pin configuration", "error", err)
+		return
+	}
+	cfg, err := verify(sc)
+	if err != nil {
+		slog.Warn("ignoring cached pin configuration", "error", err)
+		return
+	}
+	apply(cfg)
+}
+
+// RefreshConfig fetches the published pin configuration and applies it if
+// it is validly signed and newer than the applied one. The configuration
+// comes from the launcher endpoint, which is not pinned; the signature is
+// what makes it trusted.
+func RefreshConfig(ctx context.Context) error {
+	if ConfigKey == "" {
+		return nil
+	}
+
+	sc, err := api.Get[crypto.Signed](ctx, api.Default, endpoints.CertificatePins(), nil)
+	if err != nil {
+		if errors.Is(err, api.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch pin configuration: %w", err)
+	}
+
+	cfg, err := verify(sc)
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	current := config
+	mu.RUnlock()
+	if current != nil && cfg.Version <= current.Version {
+		if cfg.Version < current.Version {
+			return fmt.Errorf("%w: version %d is older than applied version %d", ErrInvalidConfig, cfg.Version, current.Version)
+		}
+		return nil
+	}
+
+	saveConfig(sc)
+	apply(cfg)
+	return nil
+}
+
+// saveConfig writes a signed pin configuration to the cache file.
+func saveConfig(sc crypto.Signed) {
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return
+	}
+	path := hytale.InStorageDir(configFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		slog.Warn("unable to cache pin configuration", "error", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		slog.Warn("unable to cache pin configuration", "error", err)
+	}
+}
+
+// verify checks the signature of a pin configuration, whose payload is
+// the JSON encoding of a Config, and decodes it.
+func verify(sc crypto.Signed) (*Config, error) {
+	payload, err := sc.Open(ConfigKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(payload, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if cfg.Kind != configKind {
+		return nil, fmt.Errorf("%w: kind %q is not %q", ErrInvalidConfig, cfg.Kind, configKind)
+	}
+	if cfg.Release != build.Release {
+		return nil, fmt.Errorf("%w: for release %q, not %q", ErrInvalidConfig, cfg.Release, build.Release)
+	}
+	if cfg.Expires.IsZero() {
+		return nil, fmt.Errorf("%w: no expiry", ErrInvalidConfig)
+	}
+	if cfg.expired() {
+		return nil, fmt.Errorf("%w: expired at %s", ErrInvalidConfig, cfg.Expires.Format(time.RFC3339))
+	}
+	if len(cfg.Pins) == 0 {
+		return nil, fmt.Errorf("%w: no pins", ErrInvalidConfig)
+	}
+	for _, p := range cfg.Pins {
+		if !validPin(p) {
+			return nil, fmt.Errorf("%w: malformed pin %q", ErrInvalidConfig, p)
+		}
+	}
+	return &cfg, nil
+}
+
+// apply makes cfg the pin configuration in effect.
+func apply(cfg *Config) {
+	mu.Lock()
+	config = cfg
+	mu.Unlock()
+
+	slog.Info("applied pin configuration", "version", cfg.Version, "pins", len(cfg.Pins), "expires", cfg.Expires)
+}
@@ -0,0 +1,66 @@
+package pinning
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/crypto"
+)
+
+// sign returns payload signed with a new key, which it makes ConfigKey for
+// the rest of the test.
+func sign(t *testing.T, payload any) crypto.Signed {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := ConfigKey
+	ConfigKey = base64.StdEncoding.EncodeToString(pub)
+	t.Cleanup(func() { ConfigKey = prev })
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.Signed{
+		Payload:   base64.StdEncoding.EncodeToString(data),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data)),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	sum := sha256.Sum256([]byte("key"))
+	pin := pinPrefix + base64.StdEncoding.EncodeToString(sum[:])
+	expires := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		payload any
+		wantErr bool
+	}{
+		{"valid", Config{Version: 1, Kind: configKind, Release: build.Release, Expires: expires, Pins: []string{pin}}, false},
+		{"no pins", Config{Version: 1, Kind: configKind, Release: build.Release, Expires: expires}, true},
+		{"other release", Config{Version: 1, Kind: configKind, Release: "other", Expires: expires, Pins: []string{pin}}, true},
+		{"expired", Config{Version: 1, Kind: configKind, Release: build.Release, Expires: time.Now().Add(-time.Hour), Pins: []string{pin}}, true},
+		{"remote configuration", map[string]any{"version": 1, "kind": "remote_config", "release": build.Release, "expires": expires}, true},
+		{"untyped", map[string]any{"version": 1, "release": build.Release, "expires": expires, "pins": []string{pin}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verify(sign(t, tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("verify() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
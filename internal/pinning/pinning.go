@@ -0,0 +1,208 @@
+// Package pinning pins the TLS public keys of the account endpoints, so a
+// token exchange or account request cannot be intercepted on a hostile
+// network by a certificate the system happens to trust, such as one
+// issued by a corporate proxy or a compromised authority.
+//
+// A connection to a pinned host is only accepted if one of the keys in its
+// verified certificate chain matches a pin. The pins ship with the
+// launcher and can be replaced by a signed pin configuration, so keys can
+// be rotated without a launcher release. Pinning can be turned off in the
+// launcher settings for networks that intercept TLS on purpose.
+package pinning
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/settings"
+)
+
+// Pins are the shipped pins, separated by commas. Each is the base64
+// SHA-256 hash of a certificate's DER-encoded SubjectPublicKeyInfo,
+// prefixed with "sha256/". They are set at build time via ldflags; when
+// empty and no pin configuration has been applied, nothing is pinned.
+//
+//	-ldflags "-X hytale-launcher/internal/pinning.Pins=sha256/AAAA...,sha256/BBBB..."
+var Pins string
+
+// pinPrefix prefixes every pin, naming the hash it uses.
+const pinPrefix = "sha256/"
+
+// pinnedServices are the services whose hosts are pinned. Only their
+// production hosts are pinned; other environments use other certificates.
+var pinnedServices = []string{"oauth.accounts", "account-data"}
+
+// ErrPinMismatch is returned when a pinned host presents a certificate
+// chain without a pinned key.
+var ErrPinMismatch = errors.New("server certificate does not match a pinned key")
+
+var (
+	// mu protects config.
+	mu sync.RWMutex
+
+	// config is the applied pin configuration, or nil while the shipped
+	// pins are used.
+	config *Config
+)
+
+// installOnce guards Install.
+var installOnce sync.Once
+
+// Install pins the account endpoints on connections made through
+// http.DefaultTransport, which every account request and token exchange
+// uses. It is safe to call more than once.
+func Install() {
+	installOnce.Do(func() {
+		t, ok := http.DefaultTransport.(*http.Transport)
+		if !ok {
+			slog.Warn("default HTTP transport replaced, certificate pinning not installed")
+			return
+		}
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.VerifyConnection = VerifyConnection
+
+		if pins := currentPins(); len(pins) > 0 {
+			slog.Info("certificate pinning installed", "hosts", pinnedHosts(), "pins", len(pins))
+		}
+	})
+}
+
+// VerifyConnection checks the verified chain of a TLS connection to a
+// pinned host against the pins. It is meant for tls.Config.VerifyConnection
+// and accepts connections to other hosts as they are.
+func VerifyConnection(cs tls.ConnectionState) error {
+	if !Enabled() || !IsPinned(cs.ServerName) {
+		return nil
+	}
+	pins := currentPins()
+	if len(pins) == 0 {
+		return nil
+	}
+
+	for _, chain := range cs.VerifiedChains {
+		for _, cert := range chain {
+			if slices.Contains(pins, Pin(cert)) {
+				return nil
+			}
+		}
+	}
+
+	var presented []string
+	if len(cs.PeerCertificates) > 0 {
+		presented = append(presented, Pin(cs.PeerCertificates[0]))
+	}
+	slog.Warn("refusing connection with unpinned certificate",
+		"host", cs.ServerName,
+		"presented", presented,
+	)
+	return fmt.Errorf("%w: %s", ErrPinMismatch, cs.ServerName)
+}
+
+// Enabled reports whether pinning is on, which it is unless the user has
+// turned it off.
+func Enabled() bool {
+	return !settings.Get().CertificatePinningDisabled
+}
+
+// IsPinned reports whether connections to host are pinned.
+func IsPinned(host string) bool {
+	return slices.Contains(pinnedHosts(), strings.ToLower(strings.TrimSuffix(host, ".")))
+}
+
+// pinnedHosts returns the production hosts of the pinned services.
+func pinnedHosts() []string {
+	domain := endpoints.Production().Domain
+	hosts := make([]string, len(pinnedServices))
+	for i, svc := range pinnedServices {
+		hosts[i] = strings.ToLower(svc + "." + domain)
+	}
+	return hosts
+}
+
+// Pin returns the pin of a certificate's public key.
+func Pin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return pinPrefix + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// currentPins returns the pins of the applied configuration, or the
+// shipped pins if none is applied or it has expired.
+func currentPins() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if config != nil && !config.expired() {
+		return config.Pins
+	}
+	return shippedPins()
+}
+
+// shippedPins returns the pins set at build time.
+func shippedPins() []string {
+	var pins []string
+	for _, p := range strings.Split(Pins, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			pins = append(pins, p)
+		}
+	}
+	return pins
+}
+
+// validPin reports whether p is a SHA-256 pin.
+func validPin(p string) bool {
+	enc, ok := strings.CutPrefix(p, pinPrefix)
+	if !ok {
+		return false
+	}
+	sum, err := base64.StdEncoding.DecodeString(enc)
+	return err == nil && len(sum) == sha256.Size
+}
+
+// Status describes the pinning in effect, for display.
+type Status struct {
+	// Enabled is false if the user has turned pinning off.
+	Enabled bool `json:"enabled"`
+
+	// Active is true if connections to the pinned hosts are checked: it
+	// is enabled and there are pins.
+	Active bool `json:"active"`
+
+	// Hosts are the pinned hosts.
+	Hosts []string `json:"hosts"`
+
+	// Pins is the number of keys pinned.
+	Pins int `json:"pins"`
+
+	// ConfigVersion is the version of the applied pin configuration, or
+	// zero while the shipped pins are used.
+	ConfigVersion int `json:"config_version"`
+}
+
+// CurrentStatus returns the pinning in effect.
+func CurrentStatus() Status {
+	pins := currentPins()
+	s := Status{
+		Enabled: Enabled(),
+		Hosts:   pinnedHosts(),
+		Pins:    len(pins),
+	}
+	s.Active = s.Enabled && s.Pins > 0
+
+	mu.RLock()
+	if config != nil && !config.expired() {
+		s.ConfigVersion = config.Version
+	}
+	mu.RUnlock()
+	return s
+}
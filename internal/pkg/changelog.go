@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/endpoints"
+)
+
+// Changelog formats.
+const (
+	ChangelogMarkdown = "markdown"
+	ChangelogHTML     = "html"
+)
+
+// Changelog holds the release notes for a game build.
+type Changelog struct {
+	// Build is the game build the notes describe.
+	Build int `json:"build"`
+
+	// Version is the display version of the build.
+	Version string `json:"version,omitempty"`
+
+	// Format is ChangelogMarkdown or ChangelogHTML.
+	Format string `json:"format"`
+
+	// Body is the release notes in the given format.
+	Body string `json:"body"`
+}
+
+var (
+	// changelogMu protects changelogCache.
+	changelogMu sync.Mutex
+
+	// changelogCache holds fetched release notes keyed by channel and build.
+	// Release notes for a published build do not change, so entries never expire.
+	changelogCache = make(map[string]*Changelog)
+)
+
+// GetChangelog returns the release notes for a build on a channel,
+// fetching them on first use and caching them for the rest of the session.
+func GetChangelog(ctx context.Context, channel string, build int) (*Changelog, error) {
+	key := fmt.Sprintf("%s/%d", channel, build)
+
+	changelogMu.Lock()
+	cached, ok := changelogCache[key]
+	changelogMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	changelog, err := api.Get[*Changelog](ctx, api.Default, endpoints.Changelog(channel, build), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch changelog for %s build %d: %w", channel, build, err)
+	}
+	if changelog == nil {
+		return nil, fmt.Errorf("empty changelog for %s build %d", channel, build)
+	}
+
+	if changelog.Build == 0 {
+		changelog.Build = build
+	}
+	if changelog.Format == "" {
+		changelog.Format = ChangelogMarkdown
+	}
+
+	changelogMu.Lock()
+	changelogCache[key] = changelog
+	changelogMu.Unlock()
+
+	return changelog, nil
+}
@@ -0,0 +1,271 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/download"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/installlock"
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/verget"
+)
+
+// ErrUnknownContent is returned when a content pack is not listed in the
+// channel's game manifest.
+var ErrUnknownContent = errors.New("content pack is not offered on this channel")
+
+// OptionalContent describes an optional content pack offered on a
+// channel, such as a high resolution texture pack, and whether it is
+// installed.
+type OptionalContent struct {
+	ID      string             `json:"id"`
+	Name    string             `json:"name"`
+	Kind    verget.ContentKind `json:"kind"`
+	Version string             `json:"version"`
+	Build   int                `json:"build"`
+	Size    int64              `json:"size"`
+
+	// Installed is set if the pack is installed on the channel.
+	Installed bool `json:"installed"`
+
+	// InstalledVersion is the version installed, if any.
+	InstalledVersion string `json:"installed_version,omitempty"`
+
+	// UpdateAvailable is set if an older version of the pack is installed.
+	UpdateAvailable bool `json:"update_available"`
+}
+
+// contentPack is an optional content pack to install or update.
+type contentPack struct {
+	Entry   verget.ContentEntry
+	Current *appstate.Dep
+}
+
+// contentUpdate represents pending installs or updates of a channel's
+// optional content packs. Each pack is tracked as its own dependency, so
+// packs are updated independently of the game and of each other.
+type contentUpdate struct {
+	Channel string
+	Packs   []*contentPack
+}
+
+// ContentDir returns the install directory of an optional content pack.
+func ContentDir(channel, id string) string {
+	return hytale.PackageDir("content", channel, id)
+}
+
+// ListOptionalContent returns the optional content packs listed in the
+// channel's game manifest, with their install state.
+func ListOptionalContent(ctx context.Context, state *appstate.State, channel string) ([]OptionalContent, error) {
+	cached, err := gameManifest.Get(ctx, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game manifest: %w", err)
+	}
+
+	list := make([]OptionalContent, 0, len(cached.Content))
+	for _, e := range cached.Content {
+		c := OptionalContent{
+			ID:      e.ID,
+			Name:    e.Name,
+			Kind:    e.Kind,
+			Version: e.Version,
+			Build:   e.Build,
+			Size:    e.Download.Size,
+		}
+		if dep := state.GetDependency(appstate.ContentIdentifier(e.ID)); dep != nil {
+			c.Installed = true
+			c.InstalledVersion = dep.Version
+			c.UpdateAvailable = dep.Build < e.Build
+		}
+		list = append(list, c)
+	}
+	return list, nil
+}
+
+// CheckForContentInstall returns the install of an optional content pack,
+// or its update if it is installed and out of date.
+func CheckForContentInstall(ctx context.Context, state *appstate.State, channel, id string) (Update, error) {
+	cached, err := gameManifest.Get(ctx, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game manifest: %w", err)
+	}
+
+	for _, e := range cached.Content {
+		if e.ID != id {
+			continue
+		}
+		current := state.GetDependency(appstate.ContentIdentifier(id))
+		if current != nil && current.Build >= e.Build {
+			return nil, nil
+		}
+		return &contentUpdate{
+			Channel: channel,
+			Packs:   []*contentPack{{Entry: e, Current: current}},
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownContent, id)
+}
+
+// CheckForContentUpdates returns the updates of the channel's installed
+// optional content packs, or nil if they are all up to date. Packs no
+// longer listed in the manifest are kept as they are.
+func CheckForContentUpdates(ctx context.Context, state *appstate.State, channel string) (Update, error) {
+	installed := state.InstalledContent()
+	if len(installed) == 0 {
+		return nil, nil
+	}
+
+	cached, err := gameManifest.Get(ctx, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game manifest: %w", err)
+	}
+
+	u := &contentUpdate{Channel: channel}
+	for _, e := range cached.Content {
+		current, ok := installed[e.ID]
+		if !ok || current.Build >= e.Build {
+			continue
+		}
+		u.Packs = append(u.Packs, &contentPack{Entry: e, Current: current})
+	}
+	if len(u.Packs) == 0 {
+		return nil, nil
+	}
+
+	slog.Info("content updates available",
+		"channel", channel,
+		"packs", len(u.Packs),
+	)
+	return u, nil
+}
+
+// size returns the total download size of the packs.
+func (u *contentUpdate) size() int64 {
+	var total int64
+	for _, p := range u.Packs {
+		total += p.Entry.Download.Size
+	}
+	return total
+}
+
+// version returns the target versions of the packs, for display.
+func (u *contentUpdate) version() string {
+	versions := make([]string, len(u.Packs))
+	for i, p := range u.Packs {
+		versions[i] = p.Entry.Version
+	}
+	return strings.Join(versions, ", ")
+}
+
+// Apply installs or updates the content packs one after the other. A
+// pack's previous version is only replaced once the new one is extracted.
+func (u *contentUpdate) Apply(ctx context.Context, state *appstate.State, reporter ProgressReporter) error {
+	lock, err := installlock.Acquire(u.Channel)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	n := float64(len(u.Packs))
+	for i, p := range u.Packs {
+		offset := float64(i) / n
+		sub := func(status UpdateStatus) {
+			status.Progress = offset + status.Progress/n
+			reporter(status)
+		}
+		if err := u.applyPack(ctx, state, p, sub); err != nil {
+			return err
+		}
+	}
+
+	reporter(UpdateStatus{
+		State:    StateComplete,
+		Progress: 1.0,
+	})
+	return nil
+}
+
+// applyPack downloads and installs one content pack.
+func (u *contentUpdate) applyPack(ctx context.Context, state *appstate.State, p *contentPack, reporter ProgressReporter) error {
+	e := p.Entry
+	slog.Info("applying content update",
+		"channel", u.Channel,
+		"content", e.ID,
+		"version", e.Version,
+	)
+
+	downloadData := map[string]any{
+		"component": "content",
+		"content":   e.ID,
+		"version":   e.Version,
+	}
+	downloadReporter := download.NewReporterWithSize(
+		StateDownloading,
+		downloadData,
+		e.Download.Size,
+		0.8,
+		0,
+		func(report download.ProgressReport) {
+			reporter(UpdateStatus{
+				State:     StateDownloading,
+				Progress:  report.Progress,
+				StateData: downloadData,
+				Current:   report.BytesDownloaded,
+				Total:     e.Download.Size,
+			})
+		},
+	)
+
+	archivePath, err := download.DownloadCachedFrom(ctx, sys.HTTP, download.CacheDir(), e.Download.Source(), e.Download.Checksum, downloadReporter)
+	if err != nil {
+		return fmt.Errorf("failed to download content %s: %w", e.ID, err)
+	}
+	defer sys.FS.Remove(archivePath)
+
+	reporter(UpdateStatus{
+		State:     StateInstalling,
+		StateData: downloadData,
+		Progress:  0.8,
+	})
+
+	dir := ContentDir(u.Channel, e.ID)
+	staging := dir + ".new"
+	if err := sys.FS.RemoveAll(staging); err != nil {
+		return fmt.Errorf("failed to clear content staging directory: %w", err)
+	}
+	if err := sys.FS.MkdirAll(staging, 0755); err != nil {
+		return fmt.Errorf("failed to create content directory: %w", err)
+	}
+	if err := ioutil.ExtractArchiveProgress(ctx, archivePath, staging, extractReporter(reporter, downloadData)); err != nil {
+		sys.FS.RemoveAll(staging)
+		return fmt.Errorf("failed to extract content %s: %w", e.ID, err)
+	}
+	if err := sys.FS.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove old content %s: %w", e.ID, err)
+	}
+	if err := sys.FS.Rename(staging, dir); err != nil {
+		return fmt.Errorf("failed to install content %s: %w", e.ID, err)
+	}
+
+	identifier := appstate.ContentIdentifier(e.ID)
+	if p.Current != nil {
+		state.RemoveDependency(identifier, p.Current.Version)
+	}
+	state.SetDependency(identifier, u.Channel, &appstate.Dep{
+		Build:   e.Build,
+		Version: e.Version,
+		Hash:    e.Download.Checksum,
+		Path:    dir,
+	})
+
+	slog.Info("content update complete",
+		"content", e.ID,
+		"version", e.Version,
+	)
+	return nil
+}
@@ -2,18 +2,25 @@ package pkg
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
-	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
+	"hytale-launcher/internal/api"
 	"hytale-launcher/internal/appstate"
 	"hytale-launcher/internal/download"
 	"hytale-launcher/internal/endpoints"
 	"hytale-launcher/internal/eventgroup"
 	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/installlock"
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/lanshare"
+	"hytale-launcher/internal/remoteconfig"
+	"hytale-launcher/internal/settings"
 )
 
 // Auth holds authentication state for game update checks.
@@ -32,6 +39,18 @@ type GamePatchline struct {
 	Name        string
 	Version     string
 	NewestBuild int
+
+	// Preload is the next build, if it can be downloaded ahead of its
+	// release.
+	Preload *GamePreload
+}
+
+// GamePreload describes a build published ahead of its release. It is
+// downloaded and staged in its own directory, then activated at ReleaseAt.
+type GamePreload struct {
+	Build     int
+	Version   string
+	ReleaseAt time.Time
 }
 
 // Game represents a game channel configuration.
@@ -51,11 +70,18 @@ type gameBuild struct {
 type gamePatch struct {
 	FromBuild    int
 	ToBuild      int
+	ToVersion    string
 	PatchURL     string
 	PatchSize    int64
 	SignatureURL string
 	SigSize      int64
 
+	// PatchSHA256 and SigSHA256 are the hashes of the patch and signature
+	// files, if the server provides them. Files with a known hash are
+	// verified and shared through the download cache.
+	PatchSHA256 string
+	SigSHA256   string
+
 	// Downloaded file paths (set during download)
 	patchPath string
 	sigPath   string
@@ -64,6 +90,63 @@ type gamePatch struct {
 // gamePatchSet represents a collection of patches needed to update.
 type gamePatchSet struct {
 	Steps []*gamePatch `json:"steps"`
+
+	// JRE is the range of Java majors the target build requires.
+	JRE *appstate.JRERange `json:"jre,omitempty"`
+
+	// Mandatory is set when the installed build can no longer be played,
+	// for example because servers have moved to the target build.
+	Mandatory bool `json:"mandatory,omitempty"`
+}
+
+// truncate drops the steps that go past the given build. It returns an
+// error if the remaining steps do not end exactly at that build.
+func (s *gamePatchSet) truncate(build int) error {
+	var steps []*gamePatch
+	for _, step := range s.Steps {
+		if step.ToBuild > build {
+			break
+		}
+		steps = append(steps, step)
+	}
+
+	if len(steps) == 0 || steps[len(steps)-1].ToBuild != build {
+		return fmt.Errorf("no patch path ends at build %d", build)
+	}
+
+	s.Steps = steps
+	return nil
+}
+
+// RequiredJRE returns the range of Java majors needed once the given updates
+// are applied: the requirement of a pending game update if there is one,
+// otherwise that of the installed game.
+func RequiredJRE(state *appstate.State, pending Update) appstate.JRERange {
+	if u, ok := pending.(*gameUpdate); ok && u.Patches != nil && u.Patches.JRE != nil {
+		return *u.Patches.JRE
+	}
+	if u, ok := pending.(*gameUpdate); ok && u.Patches != nil && len(u.Patches.Steps) == 0 {
+		// Switching to an installed build: use its recorded requirement.
+		if manifest, err := hytale.LoadBuildManifest("game", state.Channel); err == nil {
+			if b := manifest.Get(u.TargetBuild); b != nil {
+				return appstate.JRERange{Min: b.MinJava, Max: b.MaxJava}
+			}
+		}
+	}
+	return state.GameJRERange()
+}
+
+// size returns the total download size of all patches and signatures in the set.
+func (s *gamePatchSet) size() int64 {
+	if s == nil {
+		return 0
+	}
+
+	var total int64
+	for _, step := range s.Steps {
+		total += step.PatchSize + step.SigSize
+	}
+	return total
 }
 
 // gameUpdate represents a pending game update.
@@ -73,6 +156,10 @@ type gameUpdate struct {
 	TargetBuild  int
 	Version      string
 	Patches      *gamePatchSet
+
+	// ReleaseAt is set when the target build is preloaded: it is staged,
+	// but not activated until its release.
+	ReleaseAt *time.Time
 }
 
 // currentVersion returns the currently installed game version.
@@ -118,17 +205,49 @@ func (g *Game) CheckForUpdate(ctx context.Context, auth *Auth) (Update, error) {
 		currentBuild = current.Build
 	}
 
-	// No update needed if already on latest
-	if currentBuild == patchline.NewestBuild {
-		return nil, nil
+	// A pinned build caps the update target. Pinning never downgrades, so
+	// an installation at or past the pin is left alone.
+	targetBuild := patchline.NewestBuild
+	targetVersion := patchline.Version
+	if pinned := g.State.PinnedBuild; pinned > 0 && pinned < targetBuild {
+		slog.Debug("game build pinned", "channel", g.Channel, "pinned_build", pinned)
+		targetBuild = pinned
+		targetVersion = strconv.Itoa(pinned)
+		if currentBuild >= pinned {
+			return nil, nil
+		}
+	}
+
+	// Once up to date, the next build may be preloaded, or a preloaded
+	// build activated.
+	if currentBuild == targetBuild {
+		return g.checkPreload(ctx, auth, patchline, current)
+	}
+
+	// A build already installed side by side is switched to without patching.
+	if g.isInstalled(targetBuild) {
+		slog.Debug("target build already installed", "channel", g.Channel, "build", targetBuild)
+		return &gameUpdate{
+			Channel:      g,
+			CurrentBuild: current,
+			TargetBuild:  targetBuild,
+			Version:      targetVersion,
+			Patches:      &gamePatchSet{},
+		}, nil
 	}
 
 	// Get patches from API
-	patches, err := g.getPatchSet(ctx, auth, currentBuild)
+	patches, err := g.getPatchSet(ctx, auth, currentBuild, false)
 	if err != nil {
 		return nil, fmt.Errorf("error getting patch set for channel %s: %w", g.Channel, err)
 	}
 
+	if targetBuild != patchline.NewestBuild {
+		if err := patches.truncate(targetBuild); err != nil {
+			return nil, fmt.Errorf("error applying pinned build for channel %s: %w", g.Channel, err)
+		}
+	}
+
 	if len(patches.Steps) == 0 {
 		return nil, fmt.Errorf("no patches available for channel %s from build %d", g.Channel, currentBuild)
 	}
@@ -136,53 +255,111 @@ func (g *Game) CheckForUpdate(ctx context.Context, auth *Auth) (Update, error) {
 	return &gameUpdate{
 		Channel:      g,
 		CurrentBuild: current,
-		TargetBuild:  patchline.NewestBuild,
-		Version:      patchline.Version,
+		TargetBuild:  targetBuild,
+		Version:      targetVersion,
 		Patches:      patches,
 	}, nil
 }
 
-// getPatchSet retrieves the patches needed to update from the given build.
-func (g *Game) getPatchSet(ctx context.Context, auth *Auth, fromBuild int) (*gamePatchSet, error) {
-	// Get patch set URL from endpoint
-	url := endpoints.GamePatchSet(g.Channel, fromBuild)
+// checkPreload returns the update activating a preloaded build whose
+// release time has passed, or the update staging the patchline's preload.
+// Pinned channels are not preloaded.
+func (g *Game) checkPreload(ctx context.Context, auth *Auth, patchline *GamePatchline, current *gameBuild) (Update, error) {
+	if g.State.PinnedBuild > 0 || current == nil {
+		return nil, nil
+	}
 
-	slog.Debug("fetching patch set",
-		"url", url,
+	manifest, err := hytale.LoadBuildManifest("game", g.Channel)
+	if err != nil {
+		return nil, err
+	}
+	staged := manifest.Staged()
+	if staged != nil && staged.Build > current.Build && staged.Released() {
+		slog.Info("preloaded build released", "channel", g.Channel, "build", staged.Build)
+		return &gameUpdate{
+			Channel:      g,
+			CurrentBuild: current,
+			TargetBuild:  staged.Build,
+			Version:      staged.Version,
+			Patches:      &gamePatchSet{},
+		}, nil
+	}
+
+	preload := patchline.Preload
+	if preload == nil || preload.Build <= current.Build || !time.Now().Before(preload.ReleaseAt) {
+		return nil, nil
+	}
+	if staged != nil && staged.Build == preload.Build {
+		return nil, nil
+	}
+
+	patches, err := g.getPatchSet(ctx, auth, current.Build, true)
+	if err != nil {
+		return nil, fmt.Errorf("error getting preload patch set for channel %s: %w", g.Channel, err)
+	}
+	if err := patches.truncate(preload.Build); err != nil {
+		return nil, fmt.Errorf("error getting preload patch set for channel %s: %w", g.Channel, err)
+	}
+
+	slog.Info("preload available",
 		"channel", g.Channel,
-		"from_build", fromBuild,
+		"build", preload.Build,
+		"release_at", preload.ReleaseAt,
 	)
+	releaseAt := preload.ReleaseAt
+	return &gameUpdate{
+		Channel:      g,
+		CurrentBuild: current,
+		TargetBuild:  preload.Build,
+		Version:      preload.Version,
+		Patches:      patches,
+		ReleaseAt:    &releaseAt,
+	}, nil
+}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// isInstalled returns true if the given build has a complete versioned
+// install directory.
+func (g *Game) isInstalled(build int) bool {
+	manifest, err := hytale.LoadBuildManifest("game", g.Channel)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create patch set request: %w", err)
+		slog.Warn("unable to read build manifest", "channel", g.Channel, "error", err)
+		return false
+	}
+
+	installed := manifest.Get(build)
+	if installed == nil {
+		return false
 	}
+	_, err = sys.FS.Stat(installed.Dir)
+	return err == nil
+}
 
-	// Set Hytale launcher headers
-	hytale.SetUserAgent(req)
+// getPatchSet retrieves the patches needed to update from the given build.
+// With preload set, the patches lead to the patchline's unreleased build.
+func (g *Game) getPatchSet(ctx context.Context, auth *Auth, fromBuild int, preload bool) (*gamePatchSet, error) {
+	// Get patch set URL from endpoint
+	patchSetURL := endpoints.GamePatchSet(g.Channel, fromBuild)
 
-	// Add authorization header if token is available
+	slog.Debug("fetching patch set",
+		"url", patchSetURL,
+		"channel", g.Channel,
+		"from_build", fromBuild,
+	)
+
+	// Add authorization if token is available
+	client := api.Default
 	if auth != nil && auth.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+auth.Token)
+		client = api.New(api.WithToken(auth.Token))
 	}
 
-	// Execute request
-	resp, err := http.DefaultClient.Do(req)
+	var params url.Values
+	if preload {
+		params = url.Values{"preload": {"true"}}
+	}
+	patchSet, err := api.Get[gamePatchSet](ctx, client, patchSetURL, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch patch set: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("patch set request failed with status: %s", resp.Status)
-	}
-
-	// Decode response
-	var patchSet gamePatchSet
-	if err := json.NewDecoder(resp.Body).Decode(&patchSet); err != nil {
-		return nil, fmt.Errorf("failed to decode patch set: %w", err)
-	}
 
 	// Log the patch steps
 	steps := make([]string, len(patchSet.Steps))
@@ -230,7 +407,7 @@ func (p *gamePatch) download(ctx context.Context, idx, total int, reporter Progr
 		},
 	)
 
-	patchPath, err := download.DownloadTempSimple(ctx, p.PatchURL, patchReporter)
+	patchPath, err := download.DownloadCached(ctx, sys.HTTP, download.CacheDir(), p.PatchURL, p.PatchSHA256, patchReporter)
 	if err != nil {
 		return err
 	}
@@ -263,7 +440,7 @@ func (p *gamePatch) download(ctx context.Context, idx, total int, reporter Progr
 		},
 	)
 
-	sigPath, err := download.DownloadTempSimple(ctx, p.SignatureURL, sigReporter)
+	sigPath, err := download.DownloadCached(ctx, sys.HTTP, download.CacheDir(), p.SignatureURL, p.SigSHA256, sigReporter)
 	if err != nil {
 		return err
 	}
@@ -305,7 +482,7 @@ func (p *gamePatch) apply(ctx context.Context, gameDir string, reporter Progress
 	if err != nil {
 		return fmt.Errorf("failed to create staging directory: %w", err)
 	}
-	defer os.RemoveAll(stagingDir)
+	defer sys.FS.RemoveAll(stagingDir)
 
 	// Create state consumer for progress reporting
 	stateConsumer := newStateConsumer(func(progress float64) {
@@ -317,7 +494,7 @@ func (p *gamePatch) apply(ctx context.Context, gameDir string, reporter Progress
 
 	// Apply the patch using wharf
 	if err := applyWharf(ctx, p.patchPath, p.sigPath, gameDir, stagingDir, stateConsumer); err != nil {
-		return fmt.Errorf("failed to apply patch: %w", err)
+		return fmt.Errorf("%w: %w", ErrPatchApply, err)
 	}
 
 	return nil
@@ -339,13 +516,15 @@ func (p *gamePatch) validate(ctx context.Context, gameDir string, reporter Progr
 
 	// Validate using wharf
 	if err := validateWharf(ctx, p.sigPath, gameDir, stateConsumer); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+		return fmt.Errorf("%w: %w", ErrPatchValidation, err)
 	}
 
 	return nil
 }
 
-// Apply applies the game update.
+// Apply applies the game update. A preloaded build is patched and
+// verified in its own directory like any other, but left inactive until
+// its release.
 func (u *gameUpdate) Apply(ctx context.Context, state *appstate.State, reporter ProgressReporter) error {
 	slog.Info("applying game update",
 		"channel", u.Channel.Channel,
@@ -353,9 +532,63 @@ func (u *gameUpdate) Apply(ctx context.Context, state *appstate.State, reporter
 		"to", u.TargetBuild,
 	)
 
-	// Get game directory
-	gameDir := hytale.PackageDir("game", u.Channel.Channel, "latest")
+	lock, err := installlock.Acquire(u.Channel.Channel)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	manifest, err := hytale.LoadBuildManifest("game", u.Channel.Channel)
+	if err != nil {
+		return err
+	}
+
+	// Switch instantly if the target build is already installed.
+	if installed := manifest.Get(u.TargetBuild); installed != nil && len(u.Patches.Steps) == 0 {
+		return u.activate(state, manifest, *installed, reporter)
+	}
+
+	// Each build is patched in its own directory so that earlier builds
+	// remain installed and can be switched back to.
+	gameDir := hytale.BuildDir("game", u.Channel.Channel, u.TargetBuild)
+	if err := u.prepareBuildDir(state, gameDir); err != nil {
+		return err
+	}
+
+	// Copy the build from another launcher on the network if one shares
+	// it, or patch the current build otherwise.
+	if u.copyFromPeer(ctx, state, gameDir, reporter) {
+		// A copied build comes without its signature, which later
+		// validation needs.
+		if err := u.downloadSig(ctx, gameDir); err != nil {
+			slog.Warn("failed to save signature", "error", err)
+		}
+	} else if err := u.applyPatches(ctx, state, gameDir, reporter); err != nil {
+		return err
+	}
 
+	installed := hytale.InstalledBuild{
+		Build:   u.TargetBuild,
+		Version: u.Version,
+		Dir:     gameDir,
+	}
+	if req := u.Patches.JRE; req != nil {
+		installed.MinJava = req.Min
+		installed.MaxJava = req.Max
+	}
+	installed.ReleaseAt = u.ReleaseAt
+	manifest.Add(installed)
+
+	if u.ReleaseAt != nil {
+		return u.stage(manifest, reporter)
+	}
+
+	return u.activate(state, manifest, *manifest.Get(u.TargetBuild), reporter)
+}
+
+// applyPatches downloads the patches and applies them in order to the
+// build directory, which holds a copy of the current build.
+func (u *gameUpdate) applyPatches(ctx context.Context, state *appstate.State, gameDir string, reporter ProgressReporter) error {
 	// Download all patches first
 	for i, patch := range u.Patches.Steps {
 		select {
@@ -400,20 +633,200 @@ func (u *gameUpdate) Apply(ctx context.Context, state *appstate.State, reporter
 	if err := u.saveSig(gameDir); err != nil {
 		slog.Warn("failed to save signature", "error", err)
 	}
+	return nil
+}
+
+// lanDiscoverTimeout bounds waiting for launchers on the network to
+// answer before patching from the CDN.
+const lanDiscoverTimeout = 2 * time.Second
+
+// copyFromPeer copies the target build into the build directory from
+// another launcher on the local network, if sharing is turned on and a
+// launcher shares the build. Every file is checked against the game
+// manifest's file listing, so the build is only copied when the manifest
+// lists its files. Returns false if the build was not copied, with the
+// build directory prepared again for patching.
+func (u *gameUpdate) copyFromPeer(ctx context.Context, state *appstate.State, gameDir string, reporter ProgressReporter) bool {
+	if !settings.Get().LANShareEnabled || !remoteconfig.Enabled(remoteconfig.FeatureLANCopy) {
+		return false
+	}
+	channel := u.Channel.Channel
+
+	checksums, err := GameFileListing(ctx, channel, u.TargetBuild)
+	if err != nil {
+		slog.Debug("not copying game build from the network", "reason", err)
+		return false
+	}
+	peers, err := lanshare.Discover(ctx, lanDiscoverTimeout)
+	if err != nil {
+		slog.Warn("unable to look for launchers on the network", "error", err)
+		return false
+	}
+
+	for _, peer := range peers {
+		if !peer.Has(channel, u.TargetBuild) {
+			continue
+		}
+
+		data := map[string]any{
+			"component": "game",
+			"source":    "lan",
+			"peer":      peer.Name,
+		}
+		err := lanshare.Fetch(ctx, peer, channel, u.TargetBuild, gameDir, checksums, func(done, total int) {
+			reporter(UpdateStatus{
+				State:     StateDownloading,
+				StateData: data,
+				Progress:  float64(done) / float64(total),
+				Current:   int64(done),
+				Total:     int64(total),
+			})
+		})
+		if err == nil {
+			slog.Info("copied game build from the network", "peer", peer.Name, "build", u.TargetBuild)
+			return true
+		}
+		slog.Warn("unable to copy game build from peer", "peer", peer.Name, "addr", peer.Addr, "error", err)
+
+		// Start again from the current build, as the copy may have
+		// replaced some of its files.
+		if ctx.Err() != nil || u.prepareBuildDir(state, gameDir) != nil {
+			return false
+		}
+	}
+	return false
+}
+
+// prepareBuildDir creates the install directory for the target build,
+// seeded with a copy of the current build for the patches to apply to.
+func (u *gameUpdate) prepareBuildDir(state *appstate.State, gameDir string) error {
+	// Remove anything left by an interrupted update.
+	if err := sys.FS.RemoveAll(gameDir); err != nil {
+		return fmt.Errorf("failed to clear build directory: %w", err)
+	}
+
+	var srcDir string
+	if u.CurrentBuild != nil {
+		if dep := state.GetDependency("game"); dep != nil && dep.Path != "" {
+			srcDir = dep.Path
+		} else {
+			// Installs made before versioned directories live in "latest".
+			srcDir = hytale.PackageDir("game", u.Channel.Channel, hytale.LatestVersion)
+		}
+	}
+
+	if srcDir != "" {
+		if _, err := sys.FS.Stat(srcDir); err == nil {
+			slog.Info("copying current build", "from", srcDir, "to", gameDir)
+			if err := ioutil.CopyDir(srcDir, gameDir); err != nil {
+				sys.FS.RemoveAll(gameDir)
+				return fmt.Errorf("failed to copy current build: %w", err)
+			}
+			return nil
+		}
+	}
+
+	return ioutil.MkdirAll(gameDir)
+}
+
+// activate makes an installed build the active one for the channel.
+func (u *gameUpdate) activate(state *appstate.State, manifest *hytale.BuildManifest, build hytale.InstalledBuild, reporter ProgressReporter) error {
+	if err := ActivateBuild(state, manifest, build); err != nil {
+		return err
+	}
 
 	// Demote old versions
-	u.demoteOldVersions(state)
+	u.demoteOldVersions(state, manifest)
 
-	// Update dependency state
-	state.SetDependency("game", "update", &appstate.Dep{
-		Build:   u.TargetBuild,
-		Version: u.Version,
+	reporter(UpdateStatus{
+		State:    StateComplete,
+		Progress: 1.0,
 	})
 
+	return nil
+}
+
+// stage records a preloaded build as installed without activating it.
+func (u *gameUpdate) stage(manifest *hytale.BuildManifest, reporter ProgressReporter) error {
+	slog.Info("staged preloaded game build",
+		"channel", u.Channel.Channel,
+		"build", u.TargetBuild,
+		"release_at", *u.ReleaseAt,
+	)
+	if err := manifest.Save("game", u.Channel.Channel); err != nil {
+		return err
+	}
+
 	reporter(UpdateStatus{
 		State:    StateComplete,
 		Progress: 1.0,
 	})
+	return nil
+}
+
+// ActivateStagedBuild activates the channel's preloaded build once its
+// release time has passed. Returns the build activated, or zero if there
+// was none to activate.
+func ActivateStagedBuild(state *appstate.State) (int, error) {
+	lock, err := installlock.Acquire(state.Channel)
+	if err != nil {
+		return 0, err
+	}
+	defer lock.Release()
+
+	manifest, err := hytale.LoadBuildManifest("game", state.Channel)
+	if err != nil {
+		return 0, err
+	}
+	staged := manifest.Staged()
+	if staged == nil || !staged.Released() || state.PinnedBuild > 0 {
+		return 0, nil
+	}
+	if dep := state.GetDependency("game"); dep != nil && dep.Build >= staged.Build {
+		return 0, nil
+	}
+
+	if err := ActivateBuild(state, manifest, *staged); err != nil {
+		return 0, err
+	}
+	return staged.Build, nil
+}
+
+// ActivateBuild records an installed build as the active ("latest") one in
+// the build manifest and the channel state. A preloaded build cannot be
+// activated before its release.
+func ActivateBuild(state *appstate.State, manifest *hytale.BuildManifest, build hytale.InstalledBuild) error {
+	if !build.Released() {
+		return fmt.Errorf("%w: build %d is released at %s", ErrNotReleased, build.Build, build.ReleaseAt.Format(time.RFC3339))
+	}
+
+	slog.Info("activating game build",
+		"channel", state.Channel,
+		"build", build.Build,
+		"dir", build.Dir,
+	)
+
+	if b := manifest.Get(build.Build); b != nil {
+		b.ReleaseAt = nil
+	}
+	manifest.Latest = build.Build
+	if err := manifest.Save("game", state.Channel); err != nil {
+		return err
+	}
+
+	dep := &appstate.Dep{
+		Build:   build.Build,
+		Version: build.Version,
+		Path:    build.Dir,
+		Adopted: build.Adopted,
+	}
+	if build.MinJava > 0 || build.MaxJava > 0 {
+		dep.JRE = &appstate.JRERange{Min: build.MinJava, Max: build.MaxJava}
+	}
+
+	// Only the active build is tracked as the game dependency.
+	state.SetDependency("game", "", nil)
+	state.SetDependency("game", "update", dep)
 
 	return nil
 }
@@ -427,6 +840,13 @@ func (u *gameUpdate) fallback(ctx context.Context, state *appstate.State, report
 	// Clean up patch files
 	u.deletePatchFiles()
 
+	// Discard the partially patched build directory; the current build
+	// is untouched in its own directory.
+	gameDir := hytale.BuildDir("game", u.Channel.Channel, u.TargetBuild)
+	if err := sys.FS.RemoveAll(gameDir); err != nil {
+		slog.Warn("failed to remove partial build directory", "dir", gameDir, "error", err)
+	}
+
 	// For now, just return the original error
 	// Future: could implement full re-download fallback
 	return originalErr
@@ -434,37 +854,38 @@ func (u *gameUpdate) fallback(ctx context.Context, state *appstate.State, report
 
 // deletePatchFiles removes downloaded patch files.
 func (u *gameUpdate) deletePatchFiles() {
+	if err := u.Patches.deleteFiles(); err != nil {
+		slog.Warn("failed to remove patch files", "error", err)
+	}
+}
+
+// deleteParallelism is the number of files deleted at once.
+const deleteParallelism = 8
+
+// deleteFiles removes the downloaded patch and signature files of every
+// step, returning the errors of the files that could not be removed.
+func (s *gamePatchSet) deleteFiles() error {
 	// Use event group to delete files in parallel
 	var eg eventgroup.Group
+	eg.SetLimit(deleteParallelism)
 
-	for _, patch := range u.Patches.Steps {
-		p := patch // capture for closure
+	remove := func(path string) {
+		if path == "" {
+			return
+		}
 		eg.Go(func() error {
-			if p.patchPath != "" {
-				if err := os.Remove(p.patchPath); err != nil && !os.IsNotExist(err) {
-					slog.Warn("failed to remove patch file",
-						"path", p.patchPath,
-						"error", err,
-					)
-				}
+			if err := sys.FS.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %s: %w", path, err)
 			}
 			return nil
 		})
-
-		if p.sigPath != "" {
-			eg.Go(func() error {
-				if err := os.Remove(p.sigPath); err != nil && !os.IsNotExist(err) {
-					slog.Warn("failed to remove signature file",
-						"path", p.sigPath,
-						"error", err,
-					)
-				}
-				return nil
-			})
-		}
+	}
+	for _, p := range s.Steps {
+		remove(p.patchPath)
+		remove(p.sigPath)
 	}
 
-	_ = eg.Wait()
+	return eg.Wait()
 }
 
 // relBinaryPath returns the relative path to the game binary.
@@ -473,6 +894,25 @@ func (u *gameUpdate) relBinaryPath() string {
 	return filepath.Join("bin", "hytale")
 }
 
+// downloadSig downloads the signature of the target build, which the
+// patches were not downloaded for, and saves it for future validation.
+func (u *gameUpdate) downloadSig(ctx context.Context, gameDir string) error {
+	if len(u.Patches.Steps) == 0 {
+		return nil
+	}
+
+	lastPatch := u.Patches.Steps[len(u.Patches.Steps)-1]
+	if lastPatch.SignatureURL == "" {
+		return nil
+	}
+	sigPath, err := download.DownloadCached(ctx, sys.HTTP, download.CacheDir(), lastPatch.SignatureURL, lastPatch.SigSHA256, nil)
+	if err != nil {
+		return err
+	}
+	lastPatch.sigPath = sigPath
+	return u.saveSig(gameDir)
+}
+
 // saveSig saves the final signature file for future validation.
 func (u *gameUpdate) saveSig(gameDir string) error {
 	if len(u.Patches.Steps) == 0 {
@@ -485,13 +925,67 @@ func (u *gameUpdate) saveSig(gameDir string) error {
 	}
 
 	sigDest := filepath.Join(gameDir, ".signature")
-	return os.Rename(lastPatch.sigPath, sigDest)
+	return sys.FS.Rename(lastPatch.sigPath, sigDest)
 }
 
-// demoteOldVersions marks old game versions as non-latest.
-func (u *gameUpdate) demoteOldVersions(state *appstate.State) {
-	// Implementation depends on version management strategy
+// maxRetainedBuilds is the number of inactive builds kept installed
+// alongside the active one.
+const maxRetainedBuilds = 2
+
+// demoteOldVersions removes the oldest inactive builds beyond
+// maxRetainedBuilds. A pinned build is always kept.
+func (u *gameUpdate) demoteOldVersions(state *appstate.State, manifest *hytale.BuildManifest) {
 	slog.Debug("demoting old game versions",
 		"channel", u.Channel.Channel,
 	)
+
+	var inactive []hytale.InstalledBuild
+	for _, b := range manifest.Builds {
+		if b.Build != manifest.Latest && b.Build != state.PinnedBuild {
+			inactive = append(inactive, b)
+		}
+	}
+	if len(inactive) <= maxRetainedBuilds {
+		return
+	}
+
+	// Builds are ordered oldest first.
+	for _, b := range inactive[:len(inactive)-maxRetainedBuilds] {
+		if b.Adopted {
+			slog.Info("forgetting old adopted game build", "build", b.Build, "dir", b.Dir)
+			manifest.Remove(b.Build)
+			continue
+		}
+
+		slog.Info("removing old game build", "build", b.Build, "dir", b.Dir)
+		if err := sys.FS.RemoveAll(b.Dir); err != nil {
+			slog.Warn("failed to remove old game build", "build", b.Build, "error", err)
+			continue
+		}
+		manifest.Remove(b.Build)
+	}
+
+	if err := manifest.Save("game", u.Channel.Channel); err != nil {
+		slog.Warn("failed to save build manifest", "error", err)
+	}
+}
+
+// GameFileListing returns the file hashes of a channel's game build from
+// the channel's game manifest, keyed by slash-separated path, for
+// verifying an install without its signature. Returns ErrNoFileListing if
+// the manifest predates file listings or describes another build.
+func GameFileListing(ctx context.Context, channel string, build int) (map[string]string, error) {
+	cached, err := gameManifest.Get(ctx, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game manifest: %w", err)
+	}
+	if len(cached.Files) == 0 || cached.Build != build {
+		return nil, ErrNoFileListing
+	}
+
+	checksums := make(map[string]string, len(cached.Files))
+	for _, f := range cached.Files {
+		checksums[f.Path] = f.SHA256
+	}
+	return checksums, nil
 }
@@ -0,0 +1,231 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/download"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/installlock"
+	"hytale-launcher/internal/ioutil"
+)
+
+// StateImporting is reported while an existing install is copied into the
+// storage directory.
+const StateImporting = "importing"
+
+// ImportGame registers a game directory the user already has as the
+// channel's installed build, so it does not have to be downloaded again.
+// The directory must match the signature of the channel's newest build, or
+// of the pinned build if there is one.
+//
+// With adopt set, the directory is used in place and the launcher never
+// deletes it; otherwise it is copied into the channel's build directory.
+// If that build is already installed, the copy replaces its files but keeps
+// the player's data in it. The returned build is active once ImportGame
+// returns.
+func ImportGame(ctx context.Context, state *appstate.State, srcDir string, adopt bool, reporter ProgressReporter) (*hytale.InstalledBuild, error) {
+	srcDir, err := filepath.Abs(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := sys.FS.Stat(srcDir); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", srcDir, err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", srcDir)
+	}
+	if ioutil.IsInside(srcDir, hytale.StorageDir()) {
+		return nil, errors.New("the directory is already managed by the launcher")
+	}
+
+	channel := state.Channel
+	lock, err := installlock.Acquire(channel)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	// The last step of a full install ends at the build to compare with,
+	// and its signature covers the complete build.
+	g := &Game{Channel: channel, State: state}
+	patches, err := g.getPatchSet(ctx, nil, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	if pinned := state.PinnedBuild; pinned > 0 {
+		if err := patches.truncate(pinned); err != nil {
+			return nil, err
+		}
+	}
+	if len(patches.Steps) == 0 {
+		return nil, fmt.Errorf("no builds available for channel %s", channel)
+	}
+	step := patches.Steps[len(patches.Steps)-1]
+
+	slog.Info("importing existing game install",
+		"channel", channel,
+		"dir", srcDir,
+		"build", step.ToBuild,
+		"adopt", adopt,
+	)
+
+	sigData := map[string]any{"build": step.ToBuild}
+	sigReporter := download.NewReporterWithSize("downloading_patch_signature", sigData, step.SigSize, 0.1, 0,
+		func(report download.ProgressReport) {
+			reporter(UpdateStatus{
+				State:     StateDownloadingSignature,
+				Progress:  report.Progress,
+				StateData: sigData,
+			})
+		},
+	)
+	sigPath, err := download.DownloadCached(ctx, sys.HTTP, download.CacheDir(), step.SignatureURL, step.SigSHA256, sigReporter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download build signature: %w", err)
+	}
+	defer sys.FS.Remove(sigPath)
+
+	err = ValidateWharfDir(ctx, sigPath, srcDir, func(progress float64) {
+		reporter(UpdateStatus{
+			State:    StateValidatingPatch,
+			Progress: 0.1 + progress*0.6,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("the directory does not match build %d of %s: %w", step.ToBuild, channel, err)
+	}
+
+	gameDir := srcDir
+	if !adopt {
+		gameDir = hytale.BuildDir("game", channel, step.ToBuild)
+		reporter(UpdateStatus{State: StateImporting, Progress: 0.7})
+		if err := importCopy(srcDir, gameDir); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the signature with the build, as for a downloaded one.
+	if err := copyFile(sigPath, filepath.Join(gameDir, ".signature")); err != nil {
+		slog.Warn("failed to save signature", "error", err)
+	}
+
+	manifest, err := hytale.LoadBuildManifest("game", channel)
+	if err != nil {
+		return nil, err
+	}
+	// A copy of the same build installed before is replaced.
+	if old := manifest.Get(step.ToBuild); old != nil && !old.Adopted && old.Dir != gameDir {
+		if err := sys.FS.RemoveAll(old.Dir); err != nil {
+			slog.Warn("failed to remove replaced build", "dir", old.Dir, "error", err)
+		}
+	}
+	version := step.ToVersion
+	if version == "" {
+		version = strconv.Itoa(step.ToBuild)
+	}
+	installed := hytale.InstalledBuild{
+		Build:   step.ToBuild,
+		Version: version,
+		Dir:     gameDir,
+		Adopted: adopt,
+	}
+	if req := patches.JRE; req != nil {
+		installed.MinJava = req.Min
+		installed.MaxJava = req.Max
+	}
+	manifest.Add(installed)
+
+	if err := ActivateBuild(state, manifest, installed); err != nil {
+		return nil, err
+	}
+	state.Save("import_game")
+
+	reporter(UpdateStatus{State: StateComplete, Progress: 1.0})
+	return &installed, nil
+}
+
+// importCopy copies srcDir to gameDir through a staging directory, so an
+// install already at gameDir is only replaced once the copy is complete.
+// The player's data in that install is moved over to the copy, and moved
+// back if the install cannot be replaced.
+func importCopy(srcDir, gameDir string) error {
+	staging := gameDir + ".import"
+	if err := sys.FS.RemoveAll(staging); err != nil {
+		return fmt.Errorf("failed to clear import staging directory: %w", err)
+	}
+	if err := ioutil.CopyDir(srcDir, staging); err != nil {
+		sys.FS.RemoveAll(staging)
+		return fmt.Errorf("failed to copy game files: %w", err)
+	}
+
+	if _, err := sys.FS.Stat(gameDir); err != nil {
+		if err := sys.FS.Rename(staging, gameDir); err != nil {
+			sys.FS.RemoveAll(staging)
+			return fmt.Errorf("failed to install imported build: %w", err)
+		}
+		return nil
+	}
+
+	var moved []string
+	restore := func() {
+		for _, name := range moved {
+			if err := sys.FS.Rename(filepath.Join(staging, name), filepath.Join(gameDir, name)); err != nil {
+				slog.Error("failed to restore user data", "dir", name, "error", err)
+				return
+			}
+		}
+		sys.FS.RemoveAll(staging)
+	}
+
+	for _, name := range hytale.UserDataDirs {
+		old := filepath.Join(gameDir, name)
+		if _, err := sys.FS.Stat(old); err != nil {
+			continue
+		}
+		// The installed build's data wins over the imported copy's.
+		dst := filepath.Join(staging, name)
+		if err := sys.FS.RemoveAll(dst); err != nil {
+			restore()
+			return fmt.Errorf("failed to keep %s: %w", name, err)
+		}
+		if err := sys.FS.Rename(old, dst); err != nil {
+			restore()
+			return fmt.Errorf("failed to keep %s: %w", name, err)
+		}
+		moved = append(moved, name)
+	}
+
+	replaced := gameDir + ".replaced"
+	if err := sys.FS.RemoveAll(replaced); err != nil {
+		restore()
+		return fmt.Errorf("failed to clear build directory: %w", err)
+	}
+	if err := sys.FS.Rename(gameDir, replaced); err != nil {
+		restore()
+		return fmt.Errorf("failed to clear build directory: %w", err)
+	}
+	if err := sys.FS.Rename(staging, gameDir); err != nil {
+		if err := sys.FS.Rename(replaced, gameDir); err == nil {
+			restore()
+		}
+		return fmt.Errorf("failed to install imported build: %w", err)
+	}
+	if err := sys.FS.RemoveAll(replaced); err != nil {
+		slog.Warn("failed to remove replaced build", "dir", replaced, "error", err)
+	}
+	return nil
+}
+
+// copyFile copies a regular file, replacing dst.
+func copyFile(src, dst string) error {
+	data, err := sys.FS.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return sys.FS.WriteFile(dst, data, 0o644)
+}
@@ -3,16 +3,24 @@ package pkg
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 
+	"hytale-launcher/internal/api"
 	"hytale-launcher/internal/appstate"
 	"hytale-launcher/internal/build"
 	"hytale-launcher/internal/download"
+	"hytale-launcher/internal/endpoints"
 	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/installlock"
 	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/verget"
 
 	"github.com/getsentry/sentry-go"
 )
@@ -23,22 +31,110 @@ type javaUpdate struct {
 	CurrentVersion *appstate.Dep
 	TargetVersion  string
 	TargetBuild    int
-	DownloadURL    string
+	Major          int
+	Download       download.Source
 	Hash           string
 	Size           int64
+
+	// Patches updates the current runtime in place. It is nil when no
+	// delta exists, in which case the full archive is installed.
+	Patches *gamePatchSet
 }
 
-// CheckForJavaUpdate checks if a Java runtime update is available.
-func CheckForJavaUpdate(ctx context.Context, state *appstate.State, channel string) (Update, error) {
-	// Get current Java version
-	current := state.GetDependency("jre")
+var (
+	// javaManifestsMu protects javaManifests.
+	javaManifestsMu sync.Mutex
+
+	// javaManifests holds the manifest getters for specific Java majors.
+	javaManifests = make(map[int]*verget.Getter)
+)
+
+// javaManifestFor returns the manifest getter for a Java major. Each major
+// is published as its own component ("jre-21"); zero selects the default
+// runtime published as "jre".
+func javaManifestFor(major int) *verget.Getter {
+	if major == 0 {
+		return javaManifest
+	}
+
+	javaManifestsMu.Lock()
+	defer javaManifestsMu.Unlock()
+
+	g, ok := javaManifests[major]
+	if !ok {
+		component := fmt.Sprintf("jre-%d", major)
+		g = verget.NewGetter(component, func(ctx context.Context, channel string, fromBuild int) {
+			verget.GetManifest(ctx, channel, component)
+		})
+		javaManifests[major] = g
+	}
+	return g
+}
+
+// invalidateJavaManifests clears the cached manifests for all Java majors.
+func invalidateJavaManifests() {
+	javaManifestsMu.Lock()
+	defer javaManifestsMu.Unlock()
+	for _, g := range javaManifests {
+		g.Invalidate()
+	}
+}
+
+// javaMajor parses the major version from a Java version string such as
+// "21.0.2+13" or the legacy "1.8.0_402". Returns zero if it cannot be parsed.
+func javaMajor(version string) int {
+	version = strings.TrimPrefix(version, "1.")
+	end := strings.IndexFunc(version, func(r rune) bool { return r < '0' || r > '9' })
+	if end == -1 {
+		end = len(version)
+	}
+	major, _ := strconv.Atoi(version[:end])
+	return major
+}
+
+// JavaDir returns the install directory for a Java major. Runtimes of
+// different majors are installed side by side, so games requiring
+// different majors can each use their own.
+func JavaDir(channel string, major int) string {
+	if major == 0 {
+		return hytale.PackageDir("jre", channel, hytale.LatestVersion)
+	}
+	return hytale.PackageDir("jre", channel, strconv.Itoa(major))
+}
+
+// CheckForJavaUpdate checks if a Java runtime satisfying the required range
+// of majors needs to be installed or updated.
+func CheckForJavaUpdate(ctx context.Context, state *appstate.State, channel string, required appstate.JRERange) (Update, error) {
+	// A user-specified Java runtime replaces the bundled one entirely
+	if state.JavaPath != "" {
+		slog.Debug("using custom Java runtime, skipping Java update",
+			"path", state.JavaPath,
+		)
+		return nil, nil
+	}
+
+	adoptLegacyJRE(ctx, state, channel)
+
+	major := required.Preferred()
 
 	// Get manifest for latest version using the getter
-	cached, err := javaManifest.Get(ctx, channel)
+	cached, err := javaManifestFor(major).Get(ctx, channel)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Java manifest: %w", err)
 	}
 
+	if major == 0 {
+		major = javaMajor(cached.Version)
+	}
+
+	// Get current Java version for this major
+	current := state.FindJRE(required)
+	if current != nil && current.Major != major {
+		// A different major satisfies the range; it is kept alongside the
+		// preferred one rather than being replaced.
+		current = nil
+	}
+
 	// Check if update is needed
 	if current != nil && current.Build >= cached.Build {
 		slog.Debug("Java is up to date",
@@ -52,17 +148,100 @@ func CheckForJavaUpdate(ctx context.Context, state *appstate.State, channel stri
 		"current", current,
 		"target", cached.Build,
 		"version", cached.Version,
+		"major", major,
 	)
 
-	return &javaUpdate{
+	update := &javaUpdate{
 		Channel:        channel,
 		CurrentVersion: current,
 		TargetVersion:  cached.Version,
 		TargetBuild:    cached.Build,
-		DownloadURL:    cached.URL,
+		Major:          major,
+		Download:       cached.Source(),
 		Hash:           cached.Hash,
 		Size:           cached.Size,
-	}, nil
+	}
+	if current != nil {
+		update.Patches = getJavaPatchSet(ctx, channel, major, current.Build, cached.Build)
+	}
+
+	return update, nil
+}
+
+// adoptLegacyJRE records the Java major of runtimes installed before majors
+// were tracked, which were installed in the "latest" directory. Without it
+// they satisfy no requirement, so the runtime would be installed again
+// beside them and they would never be updated or removed. The major is
+// probed from the runtime, or parsed from the recorded version if it does
+// not run.
+func adoptLegacyJRE(ctx context.Context, state *appstate.State, channel string) {
+	for _, dep := range state.GetDeps("jre") {
+		if dep.Major != 0 {
+			continue
+		}
+		if dep.Path == "" {
+			dep.Path = JavaDir(channel, 0)
+		}
+
+		major := javaMajor(dep.Version)
+		if probed, err := ProbeJava(ctx, dep.Path); err == nil {
+			major = probed.Major
+		} else {
+			slog.Debug("unable to probe legacy Java runtime", "path", dep.Path, "error", err)
+		}
+		if major == 0 {
+			slog.Warn("unable to determine major of legacy Java runtime",
+				"version", dep.Version,
+				"path", dep.Path,
+			)
+			continue
+		}
+
+		slog.Info("adopting legacy Java runtime",
+			"version", dep.Version,
+			"major", major,
+			"path", dep.Path,
+		)
+		dep.Major = major
+		state.SetDependency("jre", channel, &dep)
+		state.Save("adopt_legacy_jre")
+	}
+}
+
+// getJavaPatchSet retrieves the patches that update an installed runtime to
+// the target build. It returns nil if there is no delta, so that the full
+// archive is downloaded instead.
+func getJavaPatchSet(ctx context.Context, channel string, major, fromBuild, toBuild int) *gamePatchSet {
+	url := endpoints.JREPatchSet(channel, major, fromBuild)
+
+	slog.Debug("fetching Java patch set",
+		"url", url,
+		"channel", channel,
+		"major", major,
+		"from_build", fromBuild,
+	)
+
+	patchSet, err := api.Get[gamePatchSet](ctx, api.Default, url, nil)
+	if err != nil {
+		slog.Debug("no Java patch set available, using full archive",
+			"major", major,
+			"from_build", fromBuild,
+			"error", err,
+		)
+		return nil
+	}
+
+	if err := patchSet.truncate(toBuild); err != nil {
+		slog.Debug("Java patch set does not reach target build, using full archive",
+			"major", major,
+			"from_build", fromBuild,
+			"to_build", toBuild,
+			"error", err,
+		)
+		return nil
+	}
+
+	return &patchSet
 }
 
 // Apply applies the Java runtime update.
@@ -72,39 +251,85 @@ func (u *javaUpdate) Apply(ctx context.Context, state *appstate.State, reporter
 		"build", u.TargetBuild,
 	)
 
+	lock, err := installlock.Acquire(u.Channel)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	// Patch the installed runtime when a delta exists, falling back to the
+	// full archive if that fails.
+	if u.Patches != nil && len(u.Patches.Steps) > 0 {
+		err := u.applyPatches(ctx, state, reporter)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		sentry.CaptureException(err)
+		slog.Warn("Java delta update failed, installing full archive",
+			"version", u.TargetVersion,
+			"error", err,
+		)
+	}
+
+	// Only the full archive is downloaded from here on, so let the overall
+	// progress be recalibrated to its size.
+	if u.Patches != nil && len(u.Patches.Steps) > 0 && u.Size > 0 {
+		reporter(UpdateStatus{
+			State: StateDownloading,
+			Size:  u.Size,
+		})
+	}
+
 	// Uninstall old version first
 	u.uninstall(ctx, state)
 
 	// Get Java installation directory
-	javaDir := hytale.PackageDir("jre", u.Channel, "latest")
+	javaDir := JavaDir(u.Channel, u.Major)
 
 	// Create directory if it doesn't exist
-	if err := os.MkdirAll(javaDir, 0755); err != nil {
+	if err := sys.FS.MkdirAll(javaDir, 0755); err != nil {
 		return fmt.Errorf("failed to create Java directory: %w", err)
 	}
 
 	// Download Java archive
-	downloadReporter := download.NewReporter(UpdateStatus{
-		State: StateDownloading,
-		StateData: map[string]interface{}{
-			"component": "jre",
-			"version":   u.TargetVersion,
+	downloadData := map[string]any{
+		"component": "jre",
+		"version":   u.TargetVersion,
+	}
+	downloadReporter := download.NewReporterWithSize(
+		StateDownloading,
+		downloadData,
+		u.Size,
+		0.8,
+		0,
+		func(report download.ProgressReport) {
+			reporter(UpdateStatus{
+				State:     StateDownloading,
+				Progress:  report.Progress,
+				StateData: downloadData,
+				Current:   report.BytesDownloaded,
+				Total:     u.Size,
+			})
 		},
-	}, 0, 0.8, reporter)
+	)
 
-	archivePath, err := download.DownloadTempSimple(ctx, u.DownloadURL, downloadReporter)
+	archivePath, err := download.DownloadCachedFrom(ctx, sys.HTTP, download.CacheDir(), u.Download, u.Hash, downloadReporter)
 	if err != nil {
 		return fmt.Errorf("failed to download Java: %w", err)
 	}
-	defer os.Remove(archivePath)
+	defer sys.FS.Remove(archivePath)
 
 	// Extract archive
 	reporter(UpdateStatus{
-		State:    StateInstalling,
-		Progress: 0.8,
+		State:     StateInstalling,
+		StateData: downloadData,
+		Progress:  0.8,
 	})
 
-	if err := ioutil.ExtractArchive(archivePath, javaDir); err != nil {
+	if err := ioutil.ExtractArchiveProgress(ctx, archivePath, javaDir, extractReporter(reporter, downloadData)); err != nil {
 		return fmt.Errorf("failed to extract Java: %w", err)
 	}
 
@@ -119,7 +344,7 @@ func (u *javaUpdate) Apply(ctx context.Context, state *appstate.State, reporter
 	// Validate the installation
 	if err := u.validateBin(ctx, javaBin); err != nil {
 		// Clean up on failure
-		os.RemoveAll(javaDir)
+		sys.FS.RemoveAll(javaDir)
 		return fmt.Errorf("Java validation failed: %w", err)
 	}
 
@@ -128,6 +353,8 @@ func (u *javaUpdate) Apply(ctx context.Context, state *appstate.State, reporter
 		Build:   u.TargetBuild,
 		Version: u.TargetVersion,
 		Hash:    u.Hash,
+		Path:    javaDir,
+		Major:   u.Major,
 	})
 
 	reporter(UpdateStatus{
@@ -142,15 +369,100 @@ func (u *javaUpdate) Apply(ctx context.Context, state *appstate.State, reporter
 	return nil
 }
 
-// uninstall removes the old Java installation.
+// applyPatches updates the current runtime in place with the patch set and
+// keeps the final signature alongside it, as is done for game builds.
+func (u *javaUpdate) applyPatches(ctx context.Context, state *appstate.State, reporter ProgressReporter) error {
+	defer func() {
+		if err := u.Patches.deleteFiles(); err != nil {
+			slog.Warn("failed to remove patch files", "error", err)
+		}
+	}()
+
+	javaDir := u.CurrentVersion.Path
+	if javaDir == "" {
+		javaDir = JavaDir(u.Channel, u.Major)
+	}
+
+	for i, patch := range u.Patches.Steps {
+		if err := patch.download(ctx, i, len(u.Patches.Steps), reporter); err != nil {
+			return fmt.Errorf("failed to download Java patch: %w", err)
+		}
+	}
+
+	for _, patch := range u.Patches.Steps {
+		if err := patch.apply(ctx, javaDir, reporter); err != nil {
+			return err
+		}
+		if err := patch.validate(ctx, javaDir, reporter); err != nil {
+			return err
+		}
+	}
+
+	javaBin := u.javaBinaryPath(javaDir)
+	if err := ioutil.MakeExecutable(javaBin); err != nil {
+		return fmt.Errorf("failed to make Java executable: %w", err)
+	}
+	if err := u.validateBin(ctx, javaBin); err != nil {
+		return fmt.Errorf("Java validation failed: %w", err)
+	}
+
+	// Hash is left empty: it names the full archive, which was never
+	// installed, and patch sets do not publish a hash of their result.
+	dep := &appstate.Dep{
+		Build:   u.TargetBuild,
+		Version: u.TargetVersion,
+		Path:    javaDir,
+		Major:   u.Major,
+	}
+
+	last := u.Patches.Steps[len(u.Patches.Steps)-1]
+	if err := sys.FS.Rename(last.sigPath, filepath.Join(javaDir, ".signature")); err != nil {
+		slog.Warn("failed to save Java signature", "error", err)
+	} else {
+		last.sigPath = ""
+		dep.SigDir = javaDir
+		dep.SigFile = ".signature"
+	}
+
+	state.RemoveDependency("jre", u.CurrentVersion.Version)
+	state.SetDependency("jre", u.Channel, dep)
+
+	reporter(UpdateStatus{
+		State:    StateComplete,
+		Progress: 1.0,
+	})
+
+	slog.Info("Java delta update complete",
+		"from", u.CurrentVersion.Build,
+		"to", u.TargetBuild,
+		"patches", len(u.Patches.Steps),
+	)
+
+	return nil
+}
+
+// downloadSize returns the number of bytes the update will download: the
+// patch set if there is one, otherwise the full archive.
+func (u *javaUpdate) downloadSize() int64 {
+	if u.Patches != nil && len(u.Patches.Steps) > 0 {
+		return u.Patches.size()
+	}
+	return u.Size
+}
+
+// uninstall removes the old installation of the Java major being updated.
+// Runtimes of other majors are left in place.
 func (u *javaUpdate) uninstall(ctx context.Context, state *appstate.State) {
 	if u.CurrentVersion == nil {
 		return
 	}
 
-	javaDir := hytale.PackageDir("jre", u.Channel, "latest")
+	javaDir := u.CurrentVersion.Path
+	if javaDir == "" {
+		javaDir = JavaDir(u.Channel, u.CurrentVersion.Major)
+	}
 
-	if err := os.RemoveAll(javaDir); err != nil {
+	if err := sys.FS.RemoveAll(javaDir); err != nil {
 		sentry.CaptureException(err)
 		slog.Warn("failed to remove old java installation",
 			"version", u.CurrentVersion.Version,
@@ -160,11 +472,17 @@ func (u *javaUpdate) uninstall(ctx context.Context, state *appstate.State) {
 	}
 
 	// Clear the dependency
-	state.SetDependency("jre", u.Channel, nil)
+	state.RemoveDependency("jre", u.CurrentVersion.Version)
 }
 
 // validateBin validates the Java binary by running it with --version.
 func (u *javaUpdate) validateBin(ctx context.Context, javaBin string) error {
+	return validateJavaBin(ctx, javaBin, os.Stdout)
+}
+
+// validateJavaBin runs the Java binary with --version, writing its version
+// output to stdout.
+func validateJavaBin(ctx context.Context, javaBin string, stdout io.Writer) error {
 	// Skip validation in dev mode if environment variable is set
 	if build.IsDev() {
 		if _, ok := os.LookupEnv("HYTALE_LAUNCHER_NO_TEST_RUN_BINARIES"); ok {
@@ -182,7 +500,7 @@ func (u *javaUpdate) validateBin(ctx context.Context, javaBin string) error {
 	// Create process with stdin/stdout/stderr
 	cmd := exec.CommandContext(ctx, javaBin, "--version")
 	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = stdout
 	cmd.Stderr = os.Stderr
 
 	// Start the process
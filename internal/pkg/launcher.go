@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"syscall"
 
@@ -14,6 +15,7 @@ import (
 	"hytale-launcher/internal/build"
 	"hytale-launcher/internal/crypto"
 	"hytale-launcher/internal/download"
+	"hytale-launcher/internal/exitlog"
 	"hytale-launcher/internal/fork"
 	"hytale-launcher/internal/ioutil"
 )
@@ -25,7 +27,7 @@ type launcherUpdate struct {
 	CurrentBuild   int
 	TargetVersion  string
 	TargetBuild    int
-	DownloadURL    string
+	Download       download.Source
 	Hash           string
 	Size           int64
 }
@@ -74,7 +76,7 @@ func CheckForLauncherUpdate(ctx context.Context) (Update, error) {
 		CurrentBuild:   currentBuild,
 		TargetVersion:  cached.Version,
 		TargetBuild:    cached.Build,
-		DownloadURL:    cached.URL,
+		Download:       cached.Source(),
 		Hash:           cached.Hash,
 		Size:           cached.Size,
 	}, nil
@@ -96,7 +98,7 @@ func (u *launcherUpdate) Apply(ctx context.Context, state *appstate.State, repor
 		},
 	}, 0, 0.8, reporter)
 
-	newBinaryPath, err := download.DownloadTempSimple(ctx, u.DownloadURL, downloadReporter)
+	newBinaryPath, err := download.DownloadTempFrom(ctx, sys.HTTP, download.CacheDir(), u.Download, "", downloadReporter)
 	if err != nil {
 		return fmt.Errorf("failed to download launcher: %w", err)
 	}
@@ -108,13 +110,13 @@ func (u *launcherUpdate) Apply(ctx context.Context, state *appstate.State, repor
 	})
 
 	if err := u.validateBin(ctx, newBinaryPath); err != nil {
-		os.Remove(newBinaryPath)
+		sys.FS.Remove(newBinaryPath)
 		return fmt.Errorf("launcher validation failed: %w", err)
 	}
 
 	// Perform self-update
-	if err := u.selfUpdate(ctx, newBinaryPath); err != nil {
-		os.Remove(newBinaryPath)
+	if err := u.selfUpdate(ctx, state, newBinaryPath); err != nil {
+		sys.FS.Remove(newBinaryPath)
 		return fmt.Errorf("self-update failed: %w", err)
 	}
 
@@ -151,7 +153,7 @@ func (u *launcherUpdate) validateBin(ctx context.Context, binPath string) error
 }
 
 // selfUpdate performs a self-update by spawning a helper process.
-func (u *launcherUpdate) selfUpdate(ctx context.Context, newBinaryPath string) error {
+func (u *launcherUpdate) selfUpdate(ctx context.Context, state *appstate.State, newBinaryPath string) error {
 	// Load self-update key for signing the update request
 	key, err := crypto.LoadSelfUpdateKey()
 	if err != nil {
@@ -172,32 +174,83 @@ func (u *launcherUpdate) selfUpdate(ctx context.Context, newBinaryPath string) e
 	sig := crypto.HMAC([]byte(pidStr), key)
 
 	// Build arguments for the update helper process
-	args := []string{
-		"-start-pid", pidStr,
-		"-source-exe", newBinaryPath,
-		"-dest-exe", currentExe,
-		"-launcher-patchline", build.Release,
-		"-launcher-version", u.TargetVersion,
-		"-sig", sig,
+	helperArgs := func(dest string) []string {
+		return []string{
+			"-start-pid", pidStr,
+			"-source-exe", newBinaryPath,
+			"-dest-exe", dest,
+			"-launcher-patchline", build.Release,
+			"-launcher-version", u.CurrentVersion,
+			"-sig", sig,
+		}
 	}
 
 	slog.Info("spawning update helper process",
 		"bin", newBinaryPath,
-		"args", args,
+		"args", helperArgs(currentExe),
 	)
 
-	// Run the new binary with elevated privileges if needed
-	if _, err := fork.RunElevated(newBinaryPath, args); err != nil {
+	// Run the new binary with elevated privileges only if the launcher's
+	// directory is not writable. If the user declines elevation, update
+	// a per-user install instead.
+	if fork.CanWrite(filepath.Dir(currentExe)) {
+		_, err = fork.Run(newBinaryPath, helperArgs(currentExe))
+	} else {
+		_, err = fork.RunElevated(newBinaryPath, helperArgs(currentExe))
+		if errors.Is(err, fork.ErrElevationDeclined) {
+			slog.Warn("elevation declined for launcher update")
+			dest := userInstallPath(currentExe)
+			if dest == "" {
+				return fmt.Errorf("%w: %w", ErrElevationRequired, err)
+			}
+			slog.Info("updating per-user launcher install", "dest", dest)
+			_, err = fork.Run(newBinaryPath, helperArgs(dest))
+		}
+	}
+	if err != nil {
 		return err
 	}
 
+	// The updater marks a step done once Apply returns, which it does not
+	// here, so the launcher step is marked done before exiting. Otherwise
+	// the next start would report the batch as interrupted.
+	if state != nil {
+		state.UpdateQueue.MarkDone(UpdateTypeLauncher.String())
+		if len(state.UpdateQueue.Remaining()) == 0 {
+			state.UpdateQueue = nil
+		}
+		state.Save("update_step_launcher")
+	}
+
 	// Exit current process to allow update to complete
+	exitlog.Record(exitlog.ReasonSelfUpdate)
 	os.Exit(0)
 
 	// This line is never reached
 	return nil
 }
 
+// userInstallPath returns the path of the launcher in the per-user
+// programs directory, used when the system-wide install cannot be updated
+// without elevation, or an empty string if there is no writable per-user
+// location. Only Windows has one.
+func userInstallPath(currentExe string) string {
+	if build.OS() != "windows" {
+		return ""
+	}
+
+	// On Windows, the user cache directory is %LocalAppData%.
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	dir = filepath.Join(dir, "Programs", "Hytale Launcher")
+	if err := os.MkdirAll(dir, 0o755); err != nil || !fork.CanWrite(dir) {
+		return ""
+	}
+	return filepath.Join(dir, filepath.Base(currentExe))
+}
+
 // Populate fills in missing launcher update information from manifest.
 func (u *launcherUpdate) Populate(ctx context.Context) error {
 	cached, err := launcherManifest.Get(ctx, build.Release)
@@ -205,7 +258,7 @@ func (u *launcherUpdate) Populate(ctx context.Context) error {
 		return err
 	}
 
-	u.DownloadURL = cached.URL
+	u.Download = cached.Source()
 	u.Hash = cached.Hash
 	u.Size = cached.Size
 	u.TargetVersion = cached.Version
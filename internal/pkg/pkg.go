@@ -4,10 +4,13 @@ package pkg
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
 
 	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/system"
 	"hytale-launcher/internal/verget"
 )
 
@@ -34,12 +37,12 @@ func init() {
 
 		// Java manifest getter
 		javaManifest = verget.NewGetter("jre", func(ctx context.Context, channel string, fromBuild int) {
-			verget.GetManifest(channel, "jre")
+			verget.GetManifest(ctx, channel, "jre")
 		})
 
 		// Launcher manifest getter
 		launcherManifest = verget.NewGetter("launcher", func(ctx context.Context, channel string, fromBuild int) {
-			verget.GetManifest(channel, "launcher")
+			verget.GetManifest(ctx, channel, "launcher")
 		})
 	})
 }
@@ -62,8 +65,64 @@ type UpdateStatus struct {
 	Current    int64                  `json:"current,omitempty"`
 	Total      int64                  `json:"total,omitempty"`
 	Error      error                  `json:"error,omitempty"`
+
+	// Size is set when an update learns its actual download size, for
+	// example when falling back from a patch to a full download. Progress
+	// reported after it starts again from zero, and the overall progress of
+	// a batch is recalibrated to the new size.
+	Size int64 `json:"size,omitempty"`
+
+	// Phase describes which component of a multi-component update is
+	// being applied. It is nil when a single update is applied directly.
+	Phase *UpdatePhase `json:"phase,omitempty"`
+}
+
+// UpdatePhase identifies the component and step an UpdateStatus belongs to
+// when several updates are applied as one batch.
+type UpdatePhase struct {
+	// Component is the component being updated (e.g., "launcher", "jre", "game").
+	Component string `json:"component"`
+
+	// Step is the 1-based position of this component within the batch.
+	Step int `json:"step"`
+
+	// Steps is the total number of components in the batch.
+	Steps int `json:"steps"`
+
+	// Progress is the unscaled progress of this component (0.0 to 1.0).
+	Progress float64 `json:"progress"`
+
+	// Weight is the fraction of the overall progress this component represents.
+	Weight float64 `json:"weight"`
 }
 
+// Errors returned by Apply, matched with errors.Is.
+var (
+	// ErrPatchApply is returned when a patch cannot be applied to the
+	// installed build.
+	ErrPatchApply = errors.New("failed to apply patch")
+
+	// ErrPatchValidation is returned when a patched build does not match
+	// its signature.
+	ErrPatchValidation = errors.New("patched files failed validation")
+
+	// ErrElevationRequired is returned when the launcher cannot update
+	// itself without administrator rights the user declined to grant.
+	ErrElevationRequired = errors.New("administrator rights are required to update the launcher")
+
+	// ErrNotReleased is returned when activating a preloaded build before
+	// its release.
+	ErrNotReleased = errors.New("build is not released yet")
+
+	// ErrNoFileListing is returned by GameFileListing when the manifest
+	// does not list the files of the installed build.
+	ErrNoFileListing = errors.New("manifest has no file listing for this build")
+)
+
+// sys provides the file system and HTTP client updates use. Downloads go
+// through its HTTP client.
+var sys = system.Real()
+
 // Common update state constants
 const (
 	StateDownloading          = "downloading"
@@ -77,6 +136,24 @@ const (
 	StateError                = "error"
 )
 
+// extractReporter reports archive extraction as StateInstalling, scaled
+// into the last part of a component's progress after its download.
+func extractReporter(reporter ProgressReporter, data map[string]any) ioutil.ExtractProgress {
+	return func(done, total int64) {
+		frac := 0.0
+		if total > 0 {
+			frac = float64(done) / float64(total)
+		}
+		reporter(UpdateStatus{
+			State:     StateInstalling,
+			StateData: data,
+			Progress:  0.8 + 0.19*frac,
+			Current:   done,
+			Total:     total,
+		})
+	}
+}
+
 // cancelableSaveConsumer wraps a context to check for cancellation during save operations.
 type cancelableSaveConsumer struct {
 	ctx context.Context
@@ -111,6 +188,8 @@ func InvalidateVersionManifests() {
 	if javaManifest != nil {
 		javaManifest.Invalidate()
 	}
+	invalidateJavaManifests()
+	serverManifest.Invalidate()
 	if launcherManifest != nil {
 		launcherManifest.Invalidate()
 	}
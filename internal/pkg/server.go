@@ -0,0 +1,155 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/download"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/installlock"
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/verget"
+)
+
+// serverManifest is the manifest getter for the dedicated server, published
+// as the "server" component.
+var serverManifest = verget.NewGetter("server", func(ctx context.Context, channel string, fromBuild int) {
+	verget.GetManifest(ctx, channel, "server")
+})
+
+// serverUpdate represents a pending dedicated server install or update.
+type serverUpdate struct {
+	Channel        string
+	CurrentVersion *appstate.Dep
+	TargetVersion  string
+	TargetBuild    int
+	Download       download.Source
+	Hash           string
+	Size           int64
+}
+
+// ServerDir returns the install directory of the dedicated server for a
+// channel. Worlds and configuration created by the server are kept there
+// across updates.
+func ServerDir(channel string) string {
+	return hytale.PackageDir("server", channel, hytale.LatestVersion)
+}
+
+// CheckForServerUpdate checks if the dedicated server for a channel needs to
+// be updated. If it is not installed, an install is only returned when
+// install is true.
+func CheckForServerUpdate(ctx context.Context, state *appstate.State, channel string, install bool) (Update, error) {
+	current := state.GetDependency("server")
+	if current == nil && !install {
+		return nil, nil
+	}
+
+	cached, err := serverManifest.Get(ctx, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server manifest: %w", err)
+	}
+
+	if current != nil && current.Build >= cached.Build {
+		slog.Debug("server is up to date",
+			"current", current.Build,
+			"latest", cached.Build,
+		)
+		return nil, nil
+	}
+
+	slog.Info("server update available",
+		"current", current,
+		"target", cached.Build,
+		"version", cached.Version,
+	)
+
+	return &serverUpdate{
+		Channel:        channel,
+		CurrentVersion: current,
+		TargetVersion:  cached.Version,
+		TargetBuild:    cached.Build,
+		Download:       cached.Source(),
+		Hash:           cached.Hash,
+		Size:           cached.Size,
+	}, nil
+}
+
+// Apply installs or updates the dedicated server. The archive is extracted
+// over the existing installation so that worlds and configuration survive.
+func (u *serverUpdate) Apply(ctx context.Context, state *appstate.State, reporter ProgressReporter) error {
+	slog.Info("applying server update",
+		"version", u.TargetVersion,
+		"build", u.TargetBuild,
+	)
+
+	lock, err := installlock.Acquire(u.Channel)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	serverDir := ServerDir(u.Channel)
+	if err := sys.FS.MkdirAll(serverDir, 0755); err != nil {
+		return fmt.Errorf("failed to create server directory: %w", err)
+	}
+
+	downloadData := map[string]any{
+		"component": "server",
+		"version":   u.TargetVersion,
+	}
+	downloadReporter := download.NewReporterWithSize(
+		StateDownloading,
+		downloadData,
+		u.Size,
+		0.8,
+		0,
+		func(report download.ProgressReport) {
+			reporter(UpdateStatus{
+				State:     StateDownloading,
+				Progress:  report.Progress,
+				StateData: downloadData,
+				Current:   report.BytesDownloaded,
+				Total:     u.Size,
+			})
+		},
+	)
+
+	archivePath, err := download.DownloadCachedFrom(ctx, sys.HTTP, download.CacheDir(), u.Download, u.Hash, downloadReporter)
+	if err != nil {
+		return fmt.Errorf("failed to download server: %w", err)
+	}
+	defer sys.FS.Remove(archivePath)
+
+	reporter(UpdateStatus{
+		State:     StateInstalling,
+		StateData: downloadData,
+		Progress:  0.8,
+	})
+
+	if err := ioutil.ExtractArchiveProgress(ctx, archivePath, serverDir, extractReporter(reporter, downloadData)); err != nil {
+		return fmt.Errorf("failed to extract server: %w", err)
+	}
+
+	if u.CurrentVersion != nil {
+		state.RemoveDependency("server", u.CurrentVersion.Version)
+	}
+	state.SetDependency("server", u.Channel, &appstate.Dep{
+		Build:   u.TargetBuild,
+		Version: u.TargetVersion,
+		Hash:    u.Hash,
+		Path:    serverDir,
+	})
+
+	reporter(UpdateStatus{
+		State:    StateComplete,
+		Progress: 1.0,
+	})
+
+	slog.Info("server update complete",
+		"version", u.TargetVersion,
+	)
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// SystemJava describes a Java runtime installed outside the launcher.
+type SystemJava struct {
+	// Path is the path to the java executable.
+	Path string `json:"path"`
+
+	// Version is the version reported by the runtime, e.g. "21.0.2".
+	Version string `json:"version"`
+
+	// Major is the Java major version.
+	Major int `json:"major"`
+
+	// Source describes where the runtime was found, e.g. "JAVA_HOME" or "PATH".
+	Source string `json:"source"`
+}
+
+// javaExecutable returns the name of the java executable for this platform.
+func javaExecutable() string {
+	if runtime.GOOS == "windows" {
+		return "java.exe"
+	}
+	return "java"
+}
+
+// ProbeJava validates a Java executable and reports its version.
+func ProbeJava(ctx context.Context, javaBin string) (*SystemJava, error) {
+	if info, err := sys.FS.Stat(javaBin); err != nil {
+		return nil, fmt.Errorf("failed to stat java executable: %w", err)
+	} else if info.IsDir() {
+		javaBin = filepath.Join(javaBin, "bin", javaExecutable())
+	}
+
+	var out bytes.Buffer
+	if err := validateJavaBin(ctx, javaBin, &out); err != nil {
+		return nil, err
+	}
+
+	version := parseJavaVersion(out.String())
+	if version == "" {
+		return nil, fmt.Errorf("unable to determine java version of %s", javaBin)
+	}
+
+	return &SystemJava{
+		Path:    javaBin,
+		Version: version,
+		Major:   javaMajor(version),
+	}, nil
+}
+
+// parseJavaVersion extracts the version from the first line of
+// "java --version" output, such as "openjdk 21.0.2 2024-01-16".
+func parseJavaVersion(output string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return ""
+	}
+	return strings.Trim(fields[1], `"`)
+}
+
+// FindSystemJava returns the Java runtimes installed on the system that
+// could be used in place of the bundled runtime. Runtimes that fail to run
+// are skipped.
+func FindSystemJava(ctx context.Context) []SystemJava {
+	type candidate struct {
+		path   string
+		source string
+	}
+
+	var candidates []candidate
+	if home := os.Getenv("JAVA_HOME"); home != "" {
+		candidates = append(candidates, candidate{filepath.Join(home, "bin", javaExecutable()), "JAVA_HOME"})
+	}
+	if path, err := exec.LookPath("java"); err == nil {
+		candidates = append(candidates, candidate{path, "PATH"})
+	}
+	for _, home := range systemJavaHomes() {
+		candidates = append(candidates, candidate{filepath.Join(home, "bin", javaExecutable()), "system"})
+	}
+
+	seen := make(map[string]bool)
+	var found []SystemJava
+	for _, c := range candidates {
+		path := c.path
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		java, err := ProbeJava(ctx, path)
+		if err != nil {
+			slog.Debug("skipping system java",
+				"path", path,
+				"error", err,
+			)
+			continue
+		}
+		java.Source = c.source
+		found = append(found, *java)
+	}
+
+	return found
+}
@@ -0,0 +1,24 @@
+//go:build darwin
+
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// systemJavaHomes returns the JDK bundles installed in the system and user
+// JavaVirtualMachines directories.
+func systemJavaHomes() []string {
+	patterns := []string{"/Library/Java/JavaVirtualMachines/*/Contents/Home"}
+	if home, err := os.UserHomeDir(); err == nil {
+		patterns = append(patterns, filepath.Join(home, "Library/Java/JavaVirtualMachines/*/Contents/Home"))
+	}
+
+	var homes []string
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		homes = append(homes, matches...)
+	}
+	return homes
+}
@@ -0,0 +1,12 @@
+//go:build linux
+
+package pkg
+
+import "path/filepath"
+
+// systemJavaHomes returns the Java installations registered with the
+// distribution's alternatives directory.
+func systemJavaHomes() []string {
+	homes, _ := filepath.Glob("/usr/lib/jvm/*")
+	return homes
+}
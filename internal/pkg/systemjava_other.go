@@ -0,0 +1,8 @@
+//go:build !linux && !windows && !darwin
+
+package pkg
+
+// systemJavaHomes is not supported on this platform.
+func systemJavaHomes() []string {
+	return nil
+}
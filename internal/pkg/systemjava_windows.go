@@ -0,0 +1,57 @@
+//go:build windows
+
+package pkg
+
+import (
+	"golang.org/x/sys/windows/registry"
+)
+
+// javaRegistryKeys are the registry keys under which JDK and JRE vendors
+// register their installations.
+var javaRegistryKeys = []string{
+	`SOFTWARE\JavaSoft\JDK`,
+	`SOFTWARE\JavaSoft\Java Runtime Environment`,
+	`SOFTWARE\Eclipse Adoptium\JDK`,
+	`SOFTWARE\Eclipse Adoptium\JRE`,
+	`SOFTWARE\Microsoft\JDK`,
+	`SOFTWARE\Azul Systems\Zulu`,
+}
+
+// systemJavaHomes returns the Java installations registered in the
+// Windows registry.
+func systemJavaHomes() []string {
+	var homes []string
+	for _, path := range javaRegistryKeys {
+		key, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.ENUMERATE_SUB_KEYS)
+		if err != nil {
+			continue
+		}
+		versions, _ := key.ReadSubKeyNames(-1)
+		for _, version := range versions {
+			if home := registryJavaHome(path + `\` + version); home != "" {
+				homes = append(homes, home)
+			}
+		}
+		key.Close()
+	}
+	return homes
+}
+
+// registryJavaHome reads the install location of a registered Java version.
+// Oracle registers it as JavaHome, other vendors under hotspot\MSI.
+func registryJavaHome(path string) string {
+	for _, sub := range []string{"", `\hotspot\MSI`} {
+		key, err := registry.OpenKey(registry.LOCAL_MACHINE, path+sub, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+		for _, name := range []string{"JavaHome", "Path", "InstallationPath"} {
+			if value, _, err := key.GetStringValue(name); err == nil && value != "" {
+				key.Close()
+				return value
+			}
+		}
+		key.Close()
+	}
+	return ""
+}
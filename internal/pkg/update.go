@@ -2,8 +2,11 @@ package pkg
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/endpoints"
 )
 
 // CheckAllUpdates checks for updates across all components (game, java, launcher).
@@ -21,16 +24,7 @@ func CheckAllUpdates(ctx context.Context, state *appstate.State, auth *Auth, cha
 		return updates, nil
 	}
 
-	// Check for Java update
-	javaUpdate, err := CheckForJavaUpdate(ctx, state, channel)
-	if err != nil {
-		return nil, err
-	}
-	if javaUpdate != nil {
-		updates = append(updates, javaUpdate)
-	}
-
-	// Check for game update
+	// Check for game update first, since it determines the Java major
 	game := &Game{
 		Channel: channel,
 		State:   state,
@@ -39,6 +33,17 @@ func CheckAllUpdates(ctx context.Context, state *appstate.State, auth *Auth, cha
 	if err != nil {
 		return nil, err
 	}
+
+	// Check for Java update matching the game's requirement
+	javaUpdate, err := CheckForJavaUpdate(ctx, state, channel, RequiredJRE(state, gameUpdate))
+	if err != nil {
+		return nil, err
+	}
+
+	// Java must be installed before the game that needs it
+	if javaUpdate != nil {
+		updates = append(updates, javaUpdate)
+	}
 	if gameUpdate != nil {
 		updates = append(updates, gameUpdate)
 	}
@@ -47,8 +52,12 @@ func CheckAllUpdates(ctx context.Context, state *appstate.State, auth *Auth, cha
 }
 
 // ApplyUpdates applies a list of updates in order.
+// Overall progress is weighted by each update's download size, so a small
+// runtime update does not take up as much of the bar as a large game patch.
+// When an update reports its actual size, the weights of it and the updates
+// after it are recalibrated without moving the bar backwards.
 func ApplyUpdates(ctx context.Context, state *appstate.State, updates []Update, reporter ProgressReporter) error {
-	totalUpdates := len(updates)
+	plan := newProgressPlan(updates)
 
 	for i, update := range updates {
 		select {
@@ -57,14 +66,22 @@ func ApplyUpdates(ctx context.Context, state *appstate.State, updates []Update,
 		default:
 		}
 
-		// Create a sub-reporter that scales progress for this update
-		baseProgress := float64(i) / float64(totalUpdates)
-		updateWeight := 1.0 / float64(totalUpdates)
+		plan.begin(i)
 
+		// Create a sub-reporter that scales progress for this update
 		subReporter := func(status UpdateStatus) {
-			// Scale the progress
-			scaledProgress := baseProgress + (status.Progress * updateWeight)
-			status.Progress = scaledProgress
+			if status.Size > 0 {
+				plan.resize(i, status.Size)
+			}
+			p := UpdatePhase{
+				Component: GetUpdateType(update).String(),
+				Step:      i + 1,
+				Steps:     len(updates),
+				Progress:  status.Progress,
+				Weight:    plan.weights[i],
+			}
+			status.Phase = &p
+			status.Progress = plan.progress(status.Progress)
 			reporter(status)
 		}
 
@@ -76,6 +93,128 @@ func ApplyUpdates(ctx context.Context, state *appstate.State, updates []Update,
 	return nil
 }
 
+// progressPlan maps the progress of each update in a batch onto the
+// overall progress.
+type progressPlan struct {
+	// sizes holds the estimated download size of each update.
+	sizes []int64
+
+	// weights holds the fraction of overall progress each update represents.
+	weights []float64
+
+	// current is the index of the update being applied.
+	current int
+
+	// offset is the overall progress at which the current update's
+	// progress starts.
+	offset float64
+
+	// sub is the last progress reported by the current update, and base
+	// is its progress when offset was last set.
+	sub, base float64
+
+	// last is the last overall progress reported.
+	last float64
+}
+
+// newProgressPlan creates a plan weighted by the estimated sizes of updates.
+func newProgressPlan(updates []Update) *progressPlan {
+	sizes := make([]int64, len(updates))
+	for i, u := range updates {
+		sizes[i] = GetUpdateInfo(u).Size
+	}
+
+	p := &progressPlan{
+		sizes:   estimateSizes(sizes),
+		weights: make([]float64, len(updates)),
+	}
+	p.reweigh(0, 0)
+	return p
+}
+
+// begin starts the update at index i, placing it after all earlier updates.
+func (p *progressPlan) begin(i int) {
+	if i > 0 {
+		p.offset += p.weights[p.current]
+	}
+	p.current = i
+	p.sub, p.base = 0, 0
+}
+
+// resize replaces the estimated size of update i with its actual size. The
+// remainder of the bar is shared out among it and the updates after it, and
+// the rest of the update's progress is mapped from the overall progress
+// already reached, so that the bar does not jump.
+func (p *progressPlan) resize(i int, size int64) {
+	if p.sizes[i] == size {
+		return
+	}
+	p.sizes[i] = size
+	p.offset = p.last
+	p.base = p.sub
+	p.reweigh(i, p.last)
+}
+
+// reweigh distributes the progress remaining after done among the updates
+// from index from onwards, in proportion to their sizes.
+func (p *progressPlan) reweigh(from int, done float64) {
+	var total int64
+	for _, size := range p.sizes[from:] {
+		total += size
+	}
+
+	remaining := 1 - done
+	for i := from; i < len(p.sizes); i++ {
+		if total > 0 {
+			p.weights[i] = remaining * float64(p.sizes[i]) / float64(total)
+		} else {
+			p.weights[i] = remaining / float64(len(p.sizes)-from)
+		}
+	}
+}
+
+// progress returns the overall progress for the given progress of the
+// current update. It never decreases.
+func (p *progressPlan) progress(sub float64) float64 {
+	p.sub = sub
+	rebased := sub
+	if p.base < 1 {
+		rebased = max(0, (sub-p.base)/(1-p.base))
+	}
+	overall := p.offset + rebased*p.weights[p.current]
+	if overall < p.last {
+		return p.last
+	}
+	p.last = overall
+	return overall
+}
+
+// estimateSizes fills in unknown sizes with the average of the known ones.
+// If no sizes are known, all updates are given the same size.
+func estimateSizes(sizes []int64) []int64 {
+	var known, knownTotal int64
+	for _, size := range sizes {
+		if size > 0 {
+			known++
+			knownTotal += size
+		}
+	}
+
+	average := int64(1)
+	if known > 0 {
+		average = knownTotal / known
+	}
+
+	estimated := make([]int64, len(sizes))
+	for i, size := range sizes {
+		if size <= 0 {
+			size = average
+		}
+		estimated[i] = size
+	}
+	return estimated
+}
+
 // UpdateType represents the type of update.
 type UpdateType int
 
@@ -83,8 +222,29 @@ const (
 	UpdateTypeLauncher UpdateType = iota
 	UpdateTypeJava
 	UpdateTypeGame
+	UpdateTypeServer
+	UpdateTypeContent
 )
 
+// String returns the component name for the update type, matching the
+// package names used by the updater.
+func (t UpdateType) String() string {
+	switch t {
+	case UpdateTypeLauncher:
+		return "launcher"
+	case UpdateTypeJava:
+		return "jre"
+	case UpdateTypeGame:
+		return "game"
+	case UpdateTypeServer:
+		return "server"
+	case UpdateTypeContent:
+		return "content"
+	default:
+		return "unknown"
+	}
+}
+
 // GetUpdateType returns the type of the given update.
 func GetUpdateType(u Update) UpdateType {
 	switch u.(type) {
@@ -94,27 +254,71 @@ func GetUpdateType(u Update) UpdateType {
 		return UpdateTypeJava
 	case *gameUpdate:
 		return UpdateTypeGame
+	case *serverUpdate:
+		return UpdateTypeServer
+	case *contentUpdate:
+		return UpdateTypeContent
 	default:
 		return UpdateTypeGame
 	}
 }
 
+// MarshalText encodes the update type as its component name,
+// so it reads naturally in JSON sent to the frontend.
+func (t UpdateType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes an update type from its component name.
+func (t *UpdateType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "launcher":
+		*t = UpdateTypeLauncher
+	case "jre":
+		*t = UpdateTypeJava
+	case "game":
+		*t = UpdateTypeGame
+	case "server":
+		*t = UpdateTypeServer
+	case "content":
+		*t = UpdateTypeContent
+	default:
+		return fmt.Errorf("unknown update type %q", text)
+	}
+	return nil
+}
+
 // UpdateInfo contains information about an available update.
 type UpdateInfo struct {
-	Type           UpdateType
-	CurrentVersion string
-	TargetVersion  string
-	Size           int64
+	Type           UpdateType `json:"type"`
+	CurrentVersion string     `json:"current_version,omitempty"`
+	TargetVersion  string     `json:"target_version"`
+	TargetBuild    int        `json:"target_build,omitempty"`
+	Size           int64      `json:"size,omitempty"`
+	SizeText       string     `json:"size_text,omitempty"`
+	ChangelogURL   string     `json:"changelog_url,omitempty"`
+	Changelog      *Changelog `json:"changelog,omitempty"`
+
+	// Mandatory is true if the update must be applied before the game can
+	// be played.
+	Mandatory bool `json:"mandatory,omitempty"`
+
+	// ReleaseAt is set for a preload: the target build is downloaded now
+	// and becomes playable at this time.
+	ReleaseAt *time.Time `json:"release_at,omitempty"`
 }
 
 // GetUpdateInfo extracts information from an update for display purposes.
 func GetUpdateInfo(u Update) UpdateInfo {
 	switch v := u.(type) {
 	case *launcherUpdate:
+		// The launcher does not need to be up to date for the game to be
+		// played, so its update never holds up a launch.
 		return UpdateInfo{
 			Type:           UpdateTypeLauncher,
 			CurrentVersion: v.CurrentVersion,
 			TargetVersion:  v.TargetVersion,
+			TargetBuild:    v.TargetBuild,
 			Size:           v.Size,
 		}
 	case *javaUpdate:
@@ -122,11 +326,15 @@ func GetUpdateInfo(u Update) UpdateInfo {
 		if v.CurrentVersion != nil {
 			current = v.CurrentVersion.Version
 		}
+		// A runtime for a new Java major is needed by the game; an
+		// update to an installed runtime is not.
 		return UpdateInfo{
 			Type:           UpdateTypeJava,
 			CurrentVersion: current,
 			TargetVersion:  v.TargetVersion,
-			Size:           v.Size,
+			TargetBuild:    v.TargetBuild,
+			Size:           v.downloadSize(),
+			Mandatory:      v.CurrentVersion == nil,
 		}
 	case *gameUpdate:
 		var current string
@@ -137,6 +345,34 @@ func GetUpdateInfo(u Update) UpdateInfo {
 			Type:           UpdateTypeGame,
 			CurrentVersion: current,
 			TargetVersion:  v.Version,
+			TargetBuild:    v.TargetBuild,
+			Size:           v.Patches.size(),
+			ChangelogURL:   endpoints.Changelog(v.Channel.Channel, v.TargetBuild),
+			Mandatory:      v.ReleaseAt == nil && (v.CurrentBuild == nil || (v.Patches != nil && v.Patches.Mandatory)),
+			ReleaseAt:      v.ReleaseAt,
+		}
+	case *serverUpdate:
+		var current string
+		if v.CurrentVersion != nil {
+			current = v.CurrentVersion.Version
+		}
+		return UpdateInfo{
+			Type:           UpdateTypeServer,
+			CurrentVersion: current,
+			TargetVersion:  v.TargetVersion,
+			TargetBuild:    v.TargetBuild,
+			Size:           v.Size,
+		}
+	case *contentUpdate:
+		var current string
+		if len(v.Packs) == 1 && v.Packs[0].Current != nil {
+			current = v.Packs[0].Current.Version
+		}
+		return UpdateInfo{
+			Type:           UpdateTypeContent,
+			CurrentVersion: current,
+			TargetVersion:  v.version(),
+			Size:           v.size(),
 		}
 	default:
 		return UpdateInfo{}
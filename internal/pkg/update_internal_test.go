@@ -0,0 +1,34 @@
+package pkg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProgressPlanResize(t *testing.T) {
+	p := &progressPlan{
+		sizes:   []int64{100, 100},
+		weights: make([]float64, 2),
+	}
+	p.reweigh(0, 0)
+
+	check := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: progress = %v, want %v", name, got, want)
+		}
+	}
+
+	p.begin(0)
+	check("halfway", p.progress(0.5), 0.25)
+
+	// The actual size is larger, so the first update now takes more of the
+	// bar. The progress already reported must not move.
+	p.resize(0, 300)
+	check("after resize", p.progress(0.5), 0.25)
+	check("first done", p.progress(1), 0.25+0.75*0.75)
+
+	p.begin(1)
+	check("second started", p.progress(0), 0.25+0.75*0.75)
+	check("second done", p.progress(1), 1)
+}
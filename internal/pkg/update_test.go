@@ -0,0 +1,227 @@
+package pkg_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/mockapi"
+	"hytale-launcher/internal/pkg"
+	"hytale-launcher/internal/verget"
+)
+
+func TestMain(m *testing.M) {
+	// The mock server can only be used in development builds, where the
+	// installed runtime need not be run to be validated. Everything the
+	// pipeline writes goes to a temporary storage directory.
+	build.Release = "dev"
+	os.Setenv("HYTALE_LAUNCHER_NO_TEST_RUN_BINARIES", "1")
+	dir, err := os.MkdirTemp("", "pkg-test-*")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_DATA_HOME", dir)
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// jreArchive returns a zip archive holding a Java runtime, and its SHA-256.
+func jreArchive(t *testing.T) ([]byte, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("bin/java")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("#!/bin/sh\necho 'openjdk 21.0.2 2024-01-16'\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return buf.Bytes(), hex.EncodeToString(sum[:])
+}
+
+// release returns a manifest releasing url for the platform the tests run
+// on.
+func release(version string, buildNum int, url, hash string, size int64) *verget.Manifest {
+	return &verget.Manifest{
+		Version: version,
+		Build:   buildNum,
+		DownloadURL: map[verget.Platform]map[verget.Arch]verget.Release{
+			verget.Platform(build.OS()): {verget.Arch(build.Arch()): {
+				URL:      url,
+				Checksum: hash,
+				Size:     size,
+			}},
+		},
+	}
+}
+
+// serve starts a mock server and routes every endpoint to it for the rest
+// of the test.
+func serve(t *testing.T, fixtures mockapi.Fixtures) *mockapi.Server {
+	t.Helper()
+	srv := mockapi.New(fixtures)
+	t.Cleanup(srv.Close)
+
+	restore, err := srv.Use()
+	if err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+	t.Cleanup(restore)
+	return srv
+}
+
+// gameAuth returns an account entitled to channel, whose newest build is
+// newest.
+func gameAuth(channel string, newest int) *pkg.Auth {
+	return &pkg.Auth{
+		Account: &pkg.GameAccount{
+			Patchlines: map[string]*pkg.GamePatchline{
+				channel: {Name: channel, Version: "build", NewestBuild: newest},
+			},
+		},
+	}
+}
+
+// installedState returns the state of a channel with a game build
+// installed and active.
+func installedState(t *testing.T, channel string, gameBuild int) *appstate.State {
+	t.Helper()
+	dir := hytale.BuildDir("game", channel, gameBuild)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := hytale.LoadBuildManifest("game", channel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed := hytale.InstalledBuild{Build: gameBuild, Version: "build", Dir: dir}
+	manifest.Add(installed)
+
+	state := appstate.New(channel)
+	if err := pkg.ActivateBuild(state, manifest, installed); err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func TestCheckAllUpdatesLauncherFirst(t *testing.T) {
+	prevEnabled, prevBuild := pkg.LauncherUpdateEnabled, build.BuildNumber
+	pkg.LauncherUpdateEnabled, build.BuildNumber = true, 1
+	t.Cleanup(func() {
+		pkg.LauncherUpdateEnabled, build.BuildNumber = prevEnabled, prevBuild
+	})
+
+	const channel = "launcher-first"
+	srv := serve(t, mockapi.Fixtures{
+		Manifests: map[string]*verget.Manifest{
+			mockapi.ManifestKey(build.Release, "launcher"): release("2.0.0", 2, "https://example.com/launcher", "", 10),
+		},
+	})
+
+	state := installedState(t, channel, 1)
+	updates, err := pkg.CheckAllUpdates(context.Background(), state, gameAuth(channel, 2), channel)
+	if err != nil {
+		t.Fatalf("CheckAllUpdates() error = %v", err)
+	}
+	if len(updates) != 1 || pkg.GetUpdateType(updates[0]) != pkg.UpdateTypeLauncher {
+		t.Fatalf("CheckAllUpdates() = %d updates, want only the launcher update", len(updates))
+	}
+	if info := pkg.GetUpdateInfo(updates[0]); info.TargetBuild != 2 || info.Mandatory {
+		t.Errorf("launcher update info = %+v, want optional build 2", info)
+	}
+
+	// Nothing else is checked until the launcher is up to date.
+	for _, req := range srv.Requests() {
+		if req != "GET /launcher/version/"+build.Release+"/launcher.json" {
+			t.Errorf("unexpected request %q", req)
+		}
+	}
+}
+
+func TestCheckAndApplyUpdates(t *testing.T) {
+	const channel = "check-and-apply"
+	archive, hash := jreArchive(t)
+
+	srv := serve(t, mockapi.Fixtures{Files: map[string][]byte{"jre.zip": archive}})
+	srv.Update(func(f *mockapi.Fixtures) {
+		f.Manifests = map[string]*verget.Manifest{
+			mockapi.ManifestKey(channel, "jre"): release("21.0.2+13", 0, srv.FileURL("jre.zip"), hash, int64(len(archive))),
+		}
+	})
+
+	// Build 2 is installed beside the active build 1, so the game update
+	// switches to it without patching.
+	installedState(t, channel, 2)
+	state := installedState(t, channel, 1)
+
+	ctx := context.Background()
+	updates, err := pkg.CheckAllUpdates(ctx, state, gameAuth(channel, 2), channel)
+	if err != nil {
+		t.Fatalf("CheckAllUpdates() error = %v", err)
+	}
+	var types []pkg.UpdateType
+	for _, u := range updates {
+		types = append(types, pkg.GetUpdateType(u))
+	}
+	if len(types) != 2 || types[0] != pkg.UpdateTypeJava || types[1] != pkg.UpdateTypeGame {
+		t.Fatalf("CheckAllUpdates() types = %v, want the Java runtime then the game", types)
+	}
+
+	var last pkg.UpdateStatus
+	err = pkg.ApplyUpdates(ctx, state, updates, func(status pkg.UpdateStatus) {
+		if status.Progress < last.Progress {
+			t.Errorf("progress went back from %v to %v", last.Progress, status.Progress)
+		}
+		last = status
+	})
+	if err != nil {
+		t.Fatalf("ApplyUpdates() error = %v", err)
+	}
+	if last.State != pkg.StateComplete || last.Progress != 1 {
+		t.Errorf("last status = %s at %v, want %s at 1", last.State, last.Progress, pkg.StateComplete)
+	}
+
+	if dep := state.GetDependency("game"); dep == nil || dep.Build != 2 {
+		t.Errorf("game dependency = %+v, want build 2", dep)
+	}
+	jre := state.FindJRE(appstate.JRERange{Min: 21})
+	if jre == nil || jre.Build != 13 {
+		t.Fatalf("installed JRE = %+v, want build 13 of Java 21", jre)
+	}
+	if _, err := os.Stat(filepath.Join(jre.Path, "bin", "java")); err != nil {
+		t.Errorf("Java runtime not extracted: %v", err)
+	}
+
+	// Once applied, nothing is pending.
+	updates, err = pkg.CheckAllUpdates(ctx, state, gameAuth(channel, 2), channel)
+	if err != nil {
+		t.Fatalf("CheckAllUpdates() after applying error = %v", err)
+	}
+	if len(updates) != 0 {
+		t.Errorf("CheckAllUpdates() after applying = %d updates, want none", len(updates))
+	}
+}
+
+func TestCheckAllUpdatesMissingPatchSet(t *testing.T) {
+	const channel = "missing-patches"
+	serve(t, mockapi.Fixtures{})
+
+	state := installedState(t, channel, 1)
+	if _, err := pkg.CheckAllUpdates(context.Background(), state, gameAuth(channel, 2), channel); err == nil {
+		t.Fatal("CheckAllUpdates() without a patch set succeeded, want an error")
+	}
+}
@@ -3,6 +3,8 @@ package pkg
 import (
 	"context"
 	"sync/atomic"
+
+	"hytale-launcher/internal/ioutil"
 )
 
 // stateConsumer wraps progress reporting for wharf operations.
@@ -39,6 +41,9 @@ func (s *stateConsumer) Progress() float64 {
 // applyWharf applies a wharf patch to the target directory.
 // Wharf is itch.io's binary patching system used for efficient game updates.
 func applyWharf(ctx context.Context, patchPath, sigPath, targetDir, stagingDir string, stateConsumer *stateConsumer) error {
+	// Game asset trees are deep enough to exceed MAX_PATH on Windows.
+	targetDir, stagingDir = ioutil.LongPath(targetDir), ioutil.LongPath(stagingDir)
+
 	// Wharf patch application:
 	// 1. Read the patch file
 	// 2. Verify signature
@@ -76,6 +81,8 @@ func applyWharf(ctx context.Context, patchPath, sigPath, targetDir, stagingDir s
 
 // validateWharf validates a directory against a wharf signature.
 func validateWharf(ctx context.Context, sigPath, targetDir string, stateConsumer *stateConsumer) error {
+	targetDir = ioutil.LongPath(targetDir)
+
 	// Wharf validation:
 	// 1. Read the signature file
 	// 2. Walk the target directory
@@ -137,3 +144,8 @@ func ApplyWharfPatch(ctx context.Context, opts WharfPatchOptions, onProgress fun
 
 	return nil
 }
+
+// ValidateWharfDir validates a directory against a wharf signature.
+func ValidateWharfDir(ctx context.Context, sigPath, targetDir string, onProgress func(float64)) error {
+	return validateWharf(ctx, sigPath, targetDir, newStateConsumer(onProgress))
+}
@@ -0,0 +1,63 @@
+// Package portal talks to the XDG desktop portals, through which a
+// sandboxed launcher asks the host to open links and folders and to show
+// file dialogs. In a Flatpak or Snap, starting the browser or a file
+// manager directly fails or opens one inside the sandbox, and native file
+// dialogs only see the sandbox's file system.
+package portal
+
+import "errors"
+
+// ErrUnavailable is returned when no portal service is running, or on
+// platforms without portals.
+var ErrUnavailable = errors.New("desktop portal not available")
+
+// Filter restricts the files shown in a file dialog.
+type Filter struct {
+	// Name is shown in the dialog, e.g. "Backups".
+	Name string
+
+	// Patterns are globs, e.g. "*.zip".
+	Patterns []string
+}
+
+// FileDialog configures a file dialog.
+type FileDialog struct {
+	Title   string
+	Filters []Filter
+
+	// Directory picks a directory instead of a file. It is ignored when
+	// saving.
+	Directory bool
+
+	// Name is the suggested file name when saving.
+	Name string
+}
+
+// Available reports whether the portals can be used.
+func Available() bool {
+	return available()
+}
+
+// OpenURI opens a link in the user's default application for it, such as
+// the browser.
+func OpenURI(uri string) error {
+	return openURI(uri)
+}
+
+// OpenPath shows a directory in the file manager, or opens a file in its
+// default application.
+func OpenPath(path string) error {
+	return openPath(path)
+}
+
+// OpenFile shows a dialog picking an existing file or directory. Returns
+// an empty path if the user cancels.
+func OpenFile(opts FileDialog) (string, error) {
+	return chooseFile("OpenFile", opts)
+}
+
+// SaveFile shows a dialog picking a file to write. Returns an empty path
+// if the user cancels.
+func SaveFile(opts FileDialog) (string, error) {
+	return chooseFile("SaveFile", opts)
+}
@@ -0,0 +1,197 @@
+//go:build linux
+
+package portal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	// busName and objectPath locate the portal service.
+	busName    = "org.freedesktop.portal.Desktop"
+	objectPath = "/org/freedesktop/portal/desktop"
+
+	openURIInterface     = "org.freedesktop.portal.OpenURI"
+	fileChooserInterface = "org.freedesktop.portal.FileChooser"
+	requestInterface     = "org.freedesktop.portal.Request"
+)
+
+// Responses of a portal request.
+const (
+	responseSuccess   = 0
+	responseCancelled = 1
+)
+
+// filterRule and filter are the D-Bus form of a file filter, a(sa(us)).
+// A rule of kind 0 is a glob.
+type filterRule struct {
+	Kind    uint32
+	Pattern string
+}
+
+type filter struct {
+	Name  string
+	Rules []filterRule
+}
+
+// desktop returns the portal service object.
+func desktop() (*dbus.Conn, dbus.BusObject, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return conn, conn.Object(busName, objectPath), nil
+}
+
+func available() bool {
+	_, obj, err := desktop()
+	if err != nil {
+		return false
+	}
+	_, err = obj.GetProperty(openURIInterface + ".version")
+	return err == nil
+}
+
+func openURI(uri string) error {
+	_, obj, err := desktop()
+	if err != nil {
+		return err
+	}
+	return obj.Call(openURIInterface+".OpenURI", 0, "", uri, map[string]dbus.Variant{}).Err
+}
+
+// openPath passes the portal a file descriptor rather than a path, as the
+// path inside the sandbox may not exist on the host.
+func openPath(path string) error {
+	_, obj, err := desktop()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	method := ".OpenFile"
+	if info.IsDir() {
+		method = ".OpenDirectory"
+	}
+	return obj.Call(openURIInterface+method, 0, "", dbus.UnixFD(f.Fd()), map[string]dbus.Variant{}).Err
+}
+
+// chooseFile shows a FileChooser dialog and waits for the user's choice.
+func chooseFile(method string, opts FileDialog) (string, error) {
+	conn, obj, err := desktop()
+	if err != nil {
+		return "", err
+	}
+
+	token, err := handleToken()
+	if err != nil {
+		return "", err
+	}
+	options := map[string]dbus.Variant{
+		"handle_token": dbus.MakeVariant(token),
+		"modal":        dbus.MakeVariant(true),
+	}
+	if len(opts.Filters) > 0 {
+		options["filters"] = dbus.MakeVariant(filters(opts.Filters))
+	}
+	if method == "SaveFile" && opts.Name != "" {
+		options["current_name"] = dbus.MakeVariant(opts.Name)
+	}
+	if method == "OpenFile" && opts.Directory {
+		options["directory"] = dbus.MakeVariant(true)
+	}
+
+	// Subscribe before calling, so a quick response is not missed.
+	handle := requestPath(conn, token)
+	match := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(handle),
+		dbus.WithMatchInterface(requestInterface),
+		dbus.WithMatchMember("Response"),
+	}
+	if err := conn.AddMatchSignal(match...); err != nil {
+		return "", err
+	}
+	defer conn.RemoveMatchSignal(match...)
+	signals := make(chan *dbus.Signal, 1)
+	conn.Signal(signals)
+	defer conn.RemoveSignal(signals)
+
+	if err := obj.Call(fileChooserInterface+"."+method, 0, "", opts.Title, options).Err; err != nil {
+		return "", err
+	}
+
+	for sig := range signals {
+		if sig.Path != handle || len(sig.Body) < 2 {
+			continue
+		}
+		return chosenPath(sig.Body[0], sig.Body[1])
+	}
+	return "", fmt.Errorf("%w: connection closed", ErrUnavailable)
+}
+
+// chosenPath returns the path picked in a FileChooser response.
+func chosenPath(code, body any) (string, error) {
+	switch code, _ := code.(uint32); code {
+	case responseSuccess:
+	case responseCancelled:
+		return "", nil
+	default:
+		return "", fmt.Errorf("file dialog failed with response %d", code)
+	}
+
+	results, _ := body.(map[string]dbus.Variant)
+	uris, _ := results["uris"].Value().([]string)
+	if len(uris) == 0 {
+		return "", nil
+	}
+	u, err := url.Parse(uris[0])
+	if err != nil || u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported file dialog result %q", uris[0])
+	}
+	return u.Path, nil
+}
+
+// filters converts file filters to their D-Bus form.
+func filters(filters []Filter) []filter {
+	result := make([]filter, 0, len(filters))
+	for _, f := range filters {
+		rules := make([]filterRule, 0, len(f.Patterns))
+		for _, p := range f.Patterns {
+			rules = append(rules, filterRule{Pattern: p})
+		}
+		result = append(result, filter{Name: f.Name, Rules: rules})
+	}
+	return result
+}
+
+// handleToken returns a random token naming a request.
+func handleToken() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "hytale_" + hex.EncodeToString(b), nil
+}
+
+// requestPath returns the object path of the request with the given
+// token, as the portal derives it from the caller's bus name.
+func requestPath(conn *dbus.Conn, token string) dbus.ObjectPath {
+	sender := strings.ReplaceAll(strings.TrimPrefix(conn.Names()[0], ":"), ".", "_")
+	return dbus.ObjectPath(objectPath + "/request/" + sender + "/" + token)
+}
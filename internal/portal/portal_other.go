@@ -0,0 +1,19 @@
+//go:build !linux
+
+package portal
+
+func available() bool {
+	return false
+}
+
+func openURI(uri string) error {
+	return ErrUnavailable
+}
+
+func openPath(path string) error {
+	return ErrUnavailable
+}
+
+func chooseFile(method string, opts FileDialog) (string, error) {
+	return "", ErrUnavailable
+}
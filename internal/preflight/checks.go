@@ -0,0 +1,158 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+
+	"hytale-launcher/internal/integrity"
+	"hytale-launcher/internal/pkg"
+	"hytale-launcher/internal/sysinfo"
+)
+
+const (
+	// heapWarnRatio is the share of the system's memory above which the
+	// heap leaves too little for the rest of the game and the system.
+	heapWarnRatio = 0.75
+)
+
+// checkJava checks that the Java runtime runs and suits the game build.
+func checkJava(ctx context.Context, in Input) Check {
+	c := Check{ID: "jre"}
+	fix := FixReinstallJava
+	if in.CustomJava {
+		fix = FixChooseJava
+	}
+
+	if in.JavaPath == "" {
+		c.Status, c.Code, c.Fix = StatusFail, "java_missing", fix
+		c.Message = "Java is not installed"
+		return c
+	}
+
+	java, err := pkg.ProbeJava(ctx, in.JavaPath)
+	if err != nil {
+		c.Status, c.Code, c.Fix = StatusFail, "java_broken", fix
+		c.Message = fmt.Sprintf("Java at %s does not run: %v", in.JavaPath, err)
+		return c
+	}
+	if !in.JRERange.Contains(java.Major) {
+		c.Status, c.Code, c.Fix = StatusWarn, "java_version", fix
+		c.Message = fmt.Sprintf("Java %d does not match the game's requirement (%s)", java.Major, describeRange(in.JRERange.Min, in.JRERange.Max))
+		return c
+	}
+
+	c.Status = StatusPass
+	c.Message = fmt.Sprintf("Java %s", java.Version)
+	return c
+}
+
+// describeRange describes an inclusive range of Java majors.
+func describeRange(lo, hi int) string {
+	switch {
+	case lo > 0 && hi > 0 && lo == hi:
+		return fmt.Sprintf("Java %d", lo)
+	case lo > 0 && hi > 0:
+		return fmt.Sprintf("Java %d to %d", lo, hi)
+	case lo > 0:
+		return fmt.Sprintf("Java %d or newer", lo)
+	default:
+		return fmt.Sprintf("Java %d or older", hi)
+	}
+}
+
+// checkFiles compares the game's critical files with the baseline recorded
+// when they were installed. Missing files stop the launch; changed or added
+// ones may be deliberate, so they only warn.
+func checkFiles(in Input) (Check, *integrity.Report) {
+	c := Check{ID: "game_files"}
+	if in.GameDir == "" {
+		c.Status, c.Code, c.Fix = StatusFail, "game_missing", FixRepair
+		c.Message = "The game is not installed"
+		return c, nil
+	}
+
+	report, err := integrity.Check(in.GameDir, in.Channel, in.Build)
+	if err != nil {
+		c.Status, c.Code = StatusSkipped, "files_unchecked"
+		c.Message = fmt.Sprintf("Unable to check game files: %v", err)
+		return c, nil
+	}
+
+	switch {
+	case len(report.Missing) > 0:
+		c.Status, c.Code, c.Fix = StatusFail, "files_missing", FixRepair
+		c.Message = fmt.Sprintf("%d game files are missing", len(report.Missing))
+	case !report.Clean():
+		c.Status, c.Code, c.Fix = StatusWarn, "files_changed", FixRepair
+		c.Message = fmt.Sprintf("%d game files changed outside the launcher", len(report.Changed)+len(report.Added))
+	default:
+		c.Status = StatusPass
+		c.Message = "Game files are intact"
+	}
+	return c, report
+}
+
+// checkMemory checks that the maximum heap fits in the system's memory.
+func checkMemory(in Input) Check {
+	c := Check{ID: "memory"}
+	total := sysinfo.TotalMemory() / (1 << 20)
+	if total == 0 {
+		c.Status, c.Code = StatusSkipped, "memory_unknown"
+		c.Message = "Unable to determine the system's memory"
+		return c
+	}
+
+	heap := uint64(in.MaxHeapMB)
+	switch {
+	case heap >= total:
+		c.Status, c.Code, c.Fix = StatusFail, "heap_exceeds_memory", FixLowerHeap
+		c.Message = fmt.Sprintf("The maximum heap (%d MiB) exceeds the system's memory (%d MiB)", heap, total)
+	case float64(heap) > float64(total)*heapWarnRatio:
+		c.Status, c.Code, c.Fix = StatusWarn, "heap_too_large", FixLowerHeap
+		c.Message = fmt.Sprintf("The maximum heap (%d MiB) leaves little of the system's memory (%d MiB) for anything else", heap, total)
+	default:
+		c.Status = StatusPass
+		c.Message = fmt.Sprintf("%d MiB heap of %d MiB memory", heap, total)
+	}
+	return c
+}
+
+// checkGraphics checks that the graphics drivers and libraries are
+// installed. Where the platform ships them, the check is skipped.
+func checkGraphics() Check {
+	c := Check{ID: "gpu"}
+	g := sysinfo.ProbeGraphics()
+	switch {
+	case !g.Supported:
+		c.Status = StatusSkipped
+		c.Message = "Graphics drivers are provided by the system"
+	case !g.OpenGL:
+		// The libraries may be where the probe does not look, so the
+		// game is not kept from launching.
+		c.Status, c.Code, c.Fix = StatusWarn, "opengl_missing", FixUpdateDrivers
+		c.Message = "No OpenGL library was found; the game may fail to start"
+	case !g.RenderNode:
+		c.Status, c.Code, c.Fix = StatusWarn, "no_gpu_device", FixUpdateDrivers
+		c.Message = "No GPU device is available; the game may render in software"
+	default:
+		c.Status = StatusPass
+		c.Message = "Graphics drivers are installed"
+		if !g.Vulkan {
+			c.Message = "OpenGL drivers are installed; Vulkan is not available"
+		}
+	}
+	return c
+}
+
+// checkEULA checks that the player has accepted the EULA.
+func checkEULA(in Input) Check {
+	c := Check{ID: "eula"}
+	if !in.EULAAccepted {
+		c.Status, c.Code, c.Fix = StatusFail, "eula_not_accepted", FixAcceptEULA
+		c.Message = "The EULA has not been accepted"
+		return c
+	}
+	c.Status = StatusPass
+	c.Message = "The EULA has been accepted"
+	return c
+}
@@ -0,0 +1,155 @@
+// Package preflight checks that the game can start before it is launched:
+// that the Java runtime runs and suits the build, that the game's critical
+// files are intact, that the configured heap fits in memory, that the
+// graphics stack is installed and that the EULA was accepted. Each check
+// reports what it found and, when it did not pass, what fixes it, so the
+// frontend can offer the fix rather than an opaque launch failure.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/integrity"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// StatusPass means the check found nothing wrong.
+	StatusPass Status = "pass"
+
+	// StatusWarn means the game will probably start, but may misbehave.
+	StatusWarn Status = "warn"
+
+	// StatusFail means the game will not start, so the launch is stopped.
+	StatusFail Status = "fail"
+
+	// StatusSkipped means the check could not run on this system.
+	StatusSkipped Status = "skipped"
+)
+
+// Fixes offered for checks that did not pass. They name actions the
+// frontend can take.
+const (
+	FixReinstallJava = "reinstall_java"
+	FixChooseJava    = "choose_java"
+	FixRepair        = "repair"
+	FixLowerHeap     = "lower_heap"
+	FixUpdateDrivers = "update_drivers"
+	FixAcceptEULA    = "accept_eula"
+)
+
+// Check is the result of one pre-flight check.
+type Check struct {
+	// ID names the check: "jre", "game_files", "memory", "gpu" or "eula".
+	ID string `json:"id"`
+
+	Status Status `json:"status"`
+
+	// Code identifies what the check found, for the frontend to translate.
+	Code string `json:"code,omitempty"`
+
+	// Message describes what the check found.
+	Message string `json:"message"`
+
+	// Fix is the action that resolves a warning or failure, if any.
+	Fix string `json:"fix,omitempty"`
+}
+
+// Report is the result of all pre-flight checks.
+type Report struct {
+	Checks []Check `json:"checks"`
+
+	// OK is set if no check failed.
+	OK bool `json:"ok"`
+
+	// Files is the result of comparing the game's critical files with
+	// their baseline, if they were checked.
+	Files *integrity.Report `json:"files,omitempty"`
+}
+
+// Failed returns the checks that failed.
+func (r *Report) Failed() []Check {
+	var failed []Check
+	for _, c := range r.Checks {
+		if c.Status == StatusFail {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// Error is returned when a launch is stopped by failed checks.
+type Error struct {
+	Report *Report
+}
+
+// Error returns the messages of the failed checks.
+func (e *Error) Error() string {
+	var msgs []string
+	for _, c := range e.Report.Failed() {
+		msgs = append(msgs, c.Message)
+	}
+	return fmt.Sprintf("pre-launch checks failed: %s", strings.Join(msgs, "; "))
+}
+
+// Input is what the checks need to know about the launch.
+type Input struct {
+	// JavaPath is the Java executable the game is launched with. Empty
+	// means no runtime is installed.
+	JavaPath string
+
+	// CustomJava is set if JavaPath was chosen by the user rather than
+	// installed by the launcher.
+	CustomJava bool
+
+	// JRERange is the Java requirement of the game build.
+	JRERange appstate.JRERange
+
+	// GameDir, Channel and Build identify the installed game build.
+	GameDir string
+	Channel string
+	Build   int
+
+	// MaxHeapMB is the maximum heap the game is launched with.
+	MaxHeapMB int
+
+	// EULAAccepted is set if the player has accepted the EULA.
+	EULAAccepted bool
+}
+
+// Run runs every check and returns their results.
+func Run(ctx context.Context, in Input) *Report {
+	files, filesReport := checkFiles(in)
+	r := &Report{
+		Checks: []Check{
+			checkJava(ctx, in),
+			files,
+			checkMemory(in),
+			checkGraphics(),
+			checkEULA(in),
+		},
+		Files: filesReport,
+	}
+
+	r.OK = true
+	for _, c := range r.Checks {
+		if c.Status == StatusFail {
+			r.OK = false
+		}
+		if c.Status == StatusFail || c.Status == StatusWarn {
+			slog.Warn("pre-launch check did not pass",
+				"check", c.ID,
+				"status", c.Status,
+				"code", c.Code,
+				"message", c.Message,
+			)
+		}
+	}
+	return r
+}
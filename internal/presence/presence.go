@@ -0,0 +1,233 @@
+// Package presence shares the user's online status with their friends and
+// follows the status of those friends. Friends' presence is received by
+// long-polling the presence service, which holds each request open until
+// something changes.
+package presence
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/endpoints"
+)
+
+// Status is a user's online status.
+type Status string
+
+const (
+	StatusInLauncher Status = "in_launcher"
+	StatusInGame     Status = "in_game"
+	StatusOffline    Status = "offline"
+)
+
+const (
+	// pollWait is how long, in seconds, the service may hold a poll open
+	// before answering with no changes.
+	pollWait = 30
+
+	// minBackoff and maxBackoff bound the delay before polling again after
+	// a failure.
+	minBackoff = 5 * time.Second
+	maxBackoff = 2 * time.Minute
+
+	// stopTimeout bounds reporting the offline status when stopping.
+	stopTimeout = 5 * time.Second
+)
+
+// Friend is the presence of one of the user's friends.
+type Friend struct {
+	ProfileID string    `json:"profile_id"`
+	Name      string    `json:"name"`
+	Status    Status    `json:"status"`
+	Since     time.Time `json:"since"`
+
+	// Server is the server the friend is playing on, if they share it.
+	Server string `json:"server,omitempty"`
+}
+
+// pollResponse is the response of the friends endpoint.
+type pollResponse struct {
+	Friends []Friend `json:"friends"`
+	Cursor  string   `json:"cursor"`
+}
+
+// statusRequest is the request body of the status endpoint.
+type statusRequest struct {
+	Status Status `json:"status"`
+}
+
+// Client reports the user's status and follows their friends' presence.
+type Client struct {
+	api      *api.Client
+	onChange func([]Friend)
+
+	// mu protects the fields below.
+	mu      sync.Mutex
+	status  Status
+	friends map[string]Friend
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// Start starts sharing the user's status, beginning with StatusInLauncher,
+// and following their friends' presence with the authenticated HTTP client.
+// onChange is called with the friends whose presence changed.
+func Start(httpClient *http.Client, onChange func([]Friend)) *Client {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Client{
+		api:      api.New(api.WithHTTPClient(httpClient)),
+		onChange: onChange,
+		status:   StatusInLauncher,
+		friends:  make(map[string]Friend),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	go c.run(ctx)
+	return c
+}
+
+// Stop reports the user as offline and stops following friends. It is
+// safe to call on a nil Client.
+func (c *Client) Stop() {
+	if c == nil {
+		return
+	}
+
+	c.cancel()
+	<-c.done
+
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	if err := c.report(ctx, StatusOffline); err != nil {
+		slog.Debug("unable to report offline presence", "error", err)
+	}
+}
+
+// SetStatus changes the user's status and reports it immediately. It is
+// safe to call on a nil Client.
+func (c *Client) SetStatus(status Status) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	changed := c.status != status
+	c.status = status
+	c.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	go func() {
+		if err := c.report(context.Background(), status); err != nil {
+			slog.Warn("unable to report presence", "status", status, "error", err)
+		}
+	}()
+}
+
+// Friends returns the last known presence of the user's friends. It
+// returns nil on a nil Client.
+func (c *Client) Friends() []Friend {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	friends := make([]Friend, 0, len(c.friends))
+	for _, f := range c.friends {
+		friends = append(friends, f)
+	}
+	return friends
+}
+
+// report sends the user's status to the presence service.
+func (c *Client) report(ctx context.Context, status Status) error {
+	return api.Post(ctx, c.api, endpoints.Presence()+"/status", statusRequest{Status: status})
+}
+
+// run reports the initial status and polls for friends' presence until
+// ctx is cancelled.
+func (c *Client) run(ctx context.Context) {
+	defer close(c.done)
+
+	if err := c.report(ctx, StatusInLauncher); err != nil {
+		slog.Warn("unable to report presence", "error", err)
+	}
+
+	var cursor string
+	backoff := minBackoff
+	for ctx.Err() == nil {
+		resp, err := c.poll(ctx, cursor)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Debug("presence poll failed", "error", err, "retry_in", backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+
+		backoff = minBackoff
+		cursor = resp.Cursor
+		c.apply(resp.Friends)
+	}
+}
+
+// poll waits for changes to friends' presence since the cursor. An empty
+// cursor returns the presence of all friends at once. The user's status
+// is sent along, so polling also keeps it from expiring.
+func (c *Client) poll(ctx context.Context, cursor string) (*pollResponse, error) {
+	c.mu.Lock()
+	status := c.status
+	c.mu.Unlock()
+
+	params := url.Values{}
+	params.Set("status", string(status))
+	params.Set("wait", strconv.Itoa(pollWait))
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+
+	resp, err := api.Get[pollResponse](ctx, c.api, endpoints.Presence()+"/friends", params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Cursor == "" {
+		return nil, errors.New("presence response has no cursor")
+	}
+	return &resp, nil
+}
+
+// apply records updated friend presence and reports the friends that
+// changed.
+func (c *Client) apply(updates []Friend) {
+	var changed []Friend
+
+	c.mu.Lock()
+	for _, f := range updates {
+		if prev, ok := c.friends[f.ProfileID]; ok && prev == f {
+			continue
+		}
+		c.friends[f.ProfileID] = f
+		changed = append(changed, f)
+	}
+	c.mu.Unlock()
+
+	if len(changed) > 0 && c.onChange != nil {
+		c.onChange(changed)
+	}
+}
@@ -0,0 +1,82 @@
+// Package redact removes credentials and personal details from text and
+// structured data before it leaves the machine, in diagnostics bundles or
+// crash reports.
+package redact
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Placeholder replaces redacted values.
+const Placeholder = "[redacted]"
+
+var (
+	// secretKey matches keys whose values must never leave the machine.
+	secretKey = regexp.MustCompile(`(?i)token|secret|password|authorization|cookie`)
+
+	// secretText matches credentials that may appear in free text, such
+	// as bearer tokens and token query parameters.
+	secretText = regexp.MustCompile(`(?i)(bearer\s+|(?:access|refresh|id|session|identity)_?token["=:\s]+"?)[A-Za-z0-9._~+/=-]{8,}`)
+
+	// userDir matches the user name in home directory paths, including
+	// those of other users and escaped Windows paths in JSON. Names may
+	// contain spaces when followed by another path element; userDirEnd
+	// catches a path that ends at the user name.
+	userDir    = regexp.MustCompile(`(?i)((?:^|[/\\"'\s=:])(?:home|users)[/\\]{1,2})[^/\\"'\r\n]+?([/\\])`)
+	userDirEnd = regexp.MustCompile(`(?i)((?:^|[/\\"'\s=:])(?:home|users)[/\\]{1,2})[^/\\\s"':,;\[]+`)
+
+	// uuid matches account and profile identifiers.
+	uuid = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+)
+
+// IsSecretKey reports whether values stored under key are credentials.
+func IsSecretKey(key string) bool {
+	return secretKey.MatchString(key)
+}
+
+// Text removes credentials from s and replaces the user's home directory,
+// which usually contains their name, with "~". User names in other home
+// directory paths are replaced too.
+func Text(s string) string {
+	s = secretText.ReplaceAllString(s, "${1}"+Placeholder)
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		// JSON log lines escape the backslashes in Windows paths.
+		s = strings.ReplaceAll(s, strings.ReplaceAll(home, `\`, `\\`), "~")
+		s = strings.ReplaceAll(s, home, "~")
+	}
+	s = userDir.ReplaceAllString(s, "${1}[user]${2}")
+	return userDirEnd.ReplaceAllString(s, "${1}[user]")
+}
+
+// Identifiers replaces UUIDs, such as account and profile IDs, in s.
+func Identifiers(s string) string {
+	return uuid.ReplaceAllString(s, "[id]")
+}
+
+// Value redacts a decoded JSON value in place: values under secret keys
+// are replaced and strings are passed through Text. It returns the value
+// for convenience.
+func Value(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, val := range v {
+			if IsSecretKey(key) {
+				v[key] = Placeholder
+				continue
+			}
+			v[key] = Value(val)
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = Value(v[i])
+		}
+		return v
+	case string:
+		return Text(v)
+	default:
+		return v
+	}
+}
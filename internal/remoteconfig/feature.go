@@ -0,0 +1,68 @@
+package remoteconfig
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"hytale-launcher/internal/hytale"
+)
+
+// rolloutIDFileName is the file in the storage directory holding the
+// random identifier installs are placed in rollouts by. It never leaves
+// the machine.
+const rolloutIDFileName = "rollout-id"
+
+// Enabled reports whether a feature is on: it is not switched off, and
+// this install is within its rollout. Every feature is on while no
+// configuration has been applied, or once it has expired.
+func Enabled(feature string) bool {
+	mu.RLock()
+	cfg := current
+	mu.RUnlock()
+	if cfg == nil || cfg.expired() {
+		return true
+	}
+
+	if slices.Contains(cfg.KillSwitches, feature) {
+		return false
+	}
+	percent, ok := cfg.Rollouts[feature]
+	if !ok {
+		return true
+	}
+	return bucket(feature) < percent
+}
+
+// bucket returns this install's position in the rollout of a feature,
+// from 0 to 99. An install keeps its position across restarts, and is
+// placed independently for each feature.
+func bucket(feature string) int {
+	sum := sha256.Sum256([]byte(rolloutID() + "/" + feature))
+	return int(binary.BigEndian.Uint64(sum[:8]) % 100)
+}
+
+// rolloutID returns the random identifier of this install, creating it on
+// first use. If it cannot be saved, one is used for this run only.
+var rolloutID = sync.OnceValue(func() string {
+	path := hytale.InStorageDir(rolloutIDFileName)
+	if data, err := os.ReadFile(path); err == nil {
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id
+		}
+	}
+
+	b := make([]byte, 16)
+	rand.Read(b)
+	id := hex.EncodeToString(b)
+	if err := os.WriteFile(path, []byte(id), 0o644); err != nil {
+		slog.Warn("unable to save rollout identifier", "error", err)
+	}
+	return id
+})
@@ -0,0 +1,317 @@
+// Package remoteconfig fetches the launcher's signed remote configuration,
+// which lets the maintainers steer its behaviour without a release: kill
+// switches turn a misbehaving feature off, rollouts turn a new code path on
+// for a share of installs, and endpoint overrides move a service elsewhere
+// on the production domain.
+//
+// The configuration is fetched at startup and kept on disk, still signed,
+// so the last one fetched applies while offline and from the next start.
+// A configuration that is not signed with Key, is for another release, has
+// expired or is older than the one applied, is ignored. Without any
+// configuration every feature is on.
+package remoteconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/crypto"
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/net"
+)
+
+// Key is the base64 Ed25519 public key the configuration must be signed
+// with. It is set at build time via ldflags; when empty, no configuration
+// is fetched or applied.
+//
+//	-ldflags "-X hytale-launcher/internal/remoteconfig.Key=..."
+var Key string
+
+// cacheFileName is the file in the storage directory the last applied
+// configuration is kept in.
+const cacheFileName = "remote-config.json"
+
+// configKind is the Kind of a remote configuration. Other documents signed
+// in the same envelope, such as pin configurations, have other kinds, so
+// one cannot be replayed as the other.
+const configKind = "remote_config"
+
+// fetchTimeout bounds fetching the configuration.
+const fetchTimeout = 15 * time.Second
+
+// Features that can be switched off or rolled out remotely.
+const (
+	// FeatureMultiSourceDownloads downloads files across their mirrors
+	// when the release selects it.
+	FeatureMultiSourceDownloads = "multi_source_downloads"
+
+	// FeatureLANCopy copies game builds from launchers on the local
+	// network when the user has turned sharing on.
+	FeatureLANCopy = "lan_copy"
+
+	// FeatureTelemetry sends the metrics of users who opted in.
+	FeatureTelemetry = "telemetry"
+)
+
+// fixedServices are never redirected by an override: they receive the
+// user's credentials, and their hosts are pinned.
+var fixedServices = []string{"oauth.accounts", "account-data"}
+
+// ErrInvalidConfig is returned for a configuration that is not correctly
+// signed, is not a remote configuration for this release, has expired or
+// would roll back a newer one.
+var ErrInvalidConfig = errors.New("invalid remote configuration")
+
+// Config is the remote configuration.
+type Config struct {
+	// Version orders configurations. One older than the applied
+	// configuration is refused, so an old configuration cannot be
+	// replayed.
+	Version int `json:"version"`
+
+	// Kind is always "remote_config".
+	Kind string `json:"kind"`
+
+	// Release is the release the configuration is for, such as "release"
+	// or "beta". It must match build.Release, so a configuration cannot be
+	// replayed onto the launchers of another release.
+	Release string `json:"release"`
+
+	// Expires is when the configuration stops applying, after which every
+	// feature is on and no endpoint is overridden again.
+	Expires time.Time `json:"expires"`
+
+	// KillSwitches are the features turned off.
+	KillSwitches []string `json:"kill_switches,omitempty"`
+
+	// Rollouts map features to the percentage of installs, from 0 to 100,
+	// they are turned on for. Features not listed are on for all.
+	Rollouts map[string]int `json:"rollouts,omitempty"`
+
+	// Endpoints map services, such as "launcher", to the base URLs they
+	// are served from instead, in the production environment.
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+}
+
+// expired reports whether the configuration no longer applies.
+func (c *Config) expired() bool {
+	return time.Now().After(c.Expires)
+}
+
+var (
+	// mu protects current, fetchedAt and expiry.
+	mu sync.RWMutex
+
+	// current is the applied configuration, or nil if there is none.
+	current *Config
+
+	// fetchedAt is when the applied configuration was fetched, or zero if
+	// it was loaded from the cache.
+	fetchedAt time.Time
+
+	// expiry lifts the applied configuration when it expires.
+	expiry *time.Timer
+)
+
+// Load applies the cached configuration, if there is one. It is called at
+// startup, before anything the configuration steers.
+func Load() {
+	if Key == "" {
+		return
+	}
+
+	data, err := os.ReadFile(hytale.InStorageDir(cacheFileName))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("unable to read remote configuration", "error", err)
+		}
+		return
+	}
+
+	var sc crypto.Signed
+	if err := json.Unmarshal(data, &sc); err != nil {
+		slog.Warn("unable to decode remote configuration", "error", err)
+		return
+	}
+	cfg, err := verify(sc)
+	if err != nil {
+		slog.Warn("ignoring cached remote configuration", "error", err)
+		return
+	}
+	apply(cfg, time.Time{})
+}
+
+// Refresh fetches the published configuration and applies it if it is
+// validly signed and newer than the applied one. Offline, the applied
+// configuration is kept as is.
+func Refresh(ctx context.Context) error {
+	if Key == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	sc, err := api.Get[crypto.Signed](ctx, api.Default, endpoints.RemoteConfig(), nil)
+	if err != nil {
+		if errors.Is(err, net.ErrOffline) || errors.Is(err, api.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch remote configuration: %w", err)
+	}
+
+	cfg, err := verify(sc)
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	prev := current
+	mu.RUnlock()
+	if prev != nil && cfg.Version < prev.Version {
+		return fmt.Errorf("%w: version %d is older than applied version %d", ErrInvalidConfig, cfg.Version, prev.Version)
+	}
+
+	if prev == nil || cfg.Version > prev.Version {
+		saveCache(sc)
+	}
+	apply(cfg, time.Now())
+	return nil
+}
+
+// verify checks the signature of a configuration, whose payload is the
+// JSON encoding of a Config, and decodes it.
+func verify(sc crypto.Signed) (*Config, error) {
+	payload, err := sc.Open(Key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(payload, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if cfg.Kind != configKind {
+		return nil, fmt.Errorf("%w: kind %q is not %q", ErrInvalidConfig, cfg.Kind, configKind)
+	}
+	if cfg.Release != build.Release {
+		return nil, fmt.Errorf("%w: for release %q, not %q", ErrInvalidConfig, cfg.Release, build.Release)
+	}
+	if cfg.Expires.IsZero() {
+		return nil, fmt.Errorf("%w: no expiry", ErrInvalidConfig)
+	}
+	if cfg.expired() {
+		return nil, fmt.Errorf("%w: expired at %s", ErrInvalidConfig, cfg.Expires.Format(time.RFC3339))
+	}
+	return &cfg, nil
+}
+
+// saveCache writes a signed configuration to the cache file.
+func saveCache(sc crypto.Signed) {
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return
+	}
+	path := hytale.InStorageDir(cacheFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		slog.Warn("unable to cache remote configuration", "error", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		slog.Warn("unable to cache remote configuration", "error", err)
+	}
+}
+
+// apply makes cfg the configuration in effect until it expires, and
+// redirects the services it overrides. Overrides of fixed services are
+// dropped; if any other is invalid, none are applied.
+func apply(cfg *Config, at time.Time) {
+	mu.Lock()
+	current = cfg
+	fetchedAt = at
+	if expiry != nil {
+		expiry.Stop()
+	}
+	expiry = time.AfterFunc(time.Until(cfg.Expires), func() { lift(cfg) })
+	mu.Unlock()
+
+	overrides := maps.Clone(cfg.Endpoints)
+	for service := range overrides {
+		if slices.Contains(fixedServices, service) {
+			slog.Warn("ignoring override of fixed service", "service", service)
+			delete(overrides, service)
+		}
+	}
+	if err := endpoints.SetOverrides(overrides); err != nil {
+		slog.Warn("ignoring remote endpoint overrides", "error", err)
+		endpoints.SetOverrides(nil)
+	}
+
+	slog.Info("applied remote configuration",
+		"version", cfg.Version,
+		"expires", cfg.Expires,
+		"kill_switches", cfg.KillSwitches,
+		"rollouts", cfg.Rollouts,
+	)
+}
+
+// lift stops applying cfg once it has expired, unless another
+// configuration has been applied since.
+func lift(cfg *Config) {
+	mu.Lock()
+	defer mu.Unlock()
+	if current != cfg {
+		return
+	}
+	current = nil
+	fetchedAt = time.Time{}
+	endpoints.SetOverrides(nil)
+	slog.Info("remote configuration expired", "version", cfg.Version)
+}
+
+// Status describes the configuration in effect, for diagnostics.
+type Status struct {
+	// Config is the applied configuration, or nil if there is none.
+	Config *Config `json:"config"`
+
+	// FetchedAt is when it was fetched, or nil if it was loaded from the
+	// cache because it could not be fetched yet.
+	FetchedAt *time.Time `json:"fetched_at,omitempty"`
+
+	// RolloutBuckets are this install's positions, from 0 to 99, in the
+	// rollouts of the configuration, by feature.
+	RolloutBuckets map[string]int `json:"rollout_buckets,omitempty"`
+}
+
+// CurrentStatus returns the configuration in effect.
+func CurrentStatus() Status {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var s Status
+	if current == nil {
+		return s
+	}
+	s.Config = current
+	if !fetchedAt.IsZero() {
+		t := fetchedAt
+		s.FetchedAt = &t
+	}
+	s.RolloutBuckets = make(map[string]int, len(current.Rollouts))
+	for feature := range current.Rollouts {
+		s.RolloutBuckets[feature] = bucket(feature)
+	}
+	return s
+}
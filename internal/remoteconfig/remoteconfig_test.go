@@ -0,0 +1,63 @@
+package remoteconfig
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/crypto"
+)
+
+// sign returns payload signed with a new key, which it makes Key for the
+// rest of the test.
+func sign(t *testing.T, payload any) crypto.Signed {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := Key
+	Key = base64.StdEncoding.EncodeToString(pub)
+	t.Cleanup(func() { Key = prev })
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.Signed{
+		Payload:   base64.StdEncoding.EncodeToString(data),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data)),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	expires := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		payload any
+		wantErr bool
+	}{
+		{"valid", Config{Version: 1, Kind: configKind, Release: build.Release, Expires: expires}, false},
+		{"other release", Config{Version: 1, Kind: configKind, Release: "other", Expires: expires}, true},
+		{"no expiry", Config{Version: 1, Kind: configKind, Release: build.Release}, true},
+		{"expired", Config{Version: 1, Kind: configKind, Release: build.Release, Expires: time.Now().Add(-time.Hour)}, true},
+		{"pin configuration", map[string]any{"version": 1, "kind": "certificate_pins", "release": build.Release, "expires": expires, "pins": []string{"sha256/AAAA"}}, true},
+		{"untyped", map[string]any{"version": 1, "release": build.Release, "expires": expires}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verify(sign(t, tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("verify() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package repair
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// HashFiles computes the SHA256 hash of every file under dir for which
+// match returns true. The result maps slash-separated paths relative to dir
+// to hex-encoded hashes, the same form Verify takes as checksums.
+func HashFiles(dir string, match func(relativePath string) bool, reporter ProgressReporter) (map[string]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel = filepath.ToSlash(rel); match(rel) {
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list files in %s: %w", dir, err)
+	}
+
+	hashes := make(map[string]string, len(paths))
+	for i, rel := range paths {
+		if reporter != nil {
+			reporter(i+1, len(paths), rel)
+		}
+		hash, err := hashFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, err
+		}
+		hashes[rel] = hash
+	}
+	return hashes, nil
+}
+
+// hashFile returns the hex-encoded SHA256 hash of a file.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error opening file for hashing: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("error hashing file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,330 @@
+// Package reset clears selected parts of the launcher's data, so a user
+// can recover from a broken state without reinstalling. Launcher state is
+// moved into a backup directory rather than deleted; game installs and
+// the player's saves are only touched when explicitly asked for.
+package reset
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/auth"
+	"hytale-launcher/internal/download"
+	"hytale-launcher/internal/exitlog"
+	"hytale-launcher/internal/format"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/ioutil"
+	"hytale-launcher/internal/screenshots"
+	"hytale-launcher/internal/settings"
+	"hytale-launcher/internal/uninstall"
+	"hytale-launcher/internal/verget"
+)
+
+// Scope is a part of the launcher's data that can be reset.
+type Scope string
+
+// Scopes, in the order they are reset.
+const (
+	// GameInstalls uninstalls every channel's game, Java runtime and
+	// server. The player's data is archived first unless Saves is also
+	// reset.
+	GameInstalls Scope = "game_installs"
+
+	// Saves deletes the player's worlds, mods and screenshots from the
+	// installed game builds.
+	Saves Scope = "saves"
+
+	// AppState forgets each channel's installed packages and update state.
+	AppState Scope = "appstate"
+
+	// Settings restores the default settings.
+	Settings Scope = "settings"
+
+	// Cache deletes downloaded files, cached manifests and screenshot
+	// thumbnails.
+	Cache Scope = "cache"
+
+	// Account forgets the logged in account.
+	Account Scope = "account"
+)
+
+// scopes lists every scope in the order they are reset. Game installs go
+// first, as uninstalling needs the channel state AppState moves aside.
+var scopes = []Scope{GameInstalls, Saves, AppState, Settings, Cache, Account}
+
+// stateFiles are the per-channel state files moved aside by a reset.
+var stateFiles = []string{"env.dat", "env.json"}
+
+// Result describes a completed reset.
+type Result struct {
+	// Backup is the directory launcher data was moved to, or empty if
+	// nothing was moved.
+	Backup string `json:"backup,omitempty"`
+
+	// Reclaimed is the number of bytes freed by deleting files.
+	Reclaimed int64 `json:"reclaimed"`
+
+	// Archives are the archives the player's data was saved to before
+	// game installs were removed.
+	Archives []string `json:"archives,omitempty"`
+}
+
+// ParseScopes converts scope names to scopes, rejecting unknown names.
+func ParseScopes(names []string) ([]Scope, error) {
+	var out []Scope
+	for _, name := range names {
+		s := Scope(name)
+		if !slices.Contains(scopes, s) {
+			return nil, fmt.Errorf("unknown reset scope %q", name)
+		}
+		if !slices.Contains(out, s) {
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no reset scope given")
+	}
+	return out, nil
+}
+
+// Run resets the given scopes. It keeps going after a failure, so as much
+// as possible is reset, and returns all errors joined.
+func Run(selected []Scope) (*Result, error) {
+	r := &resetter{
+		result: &Result{},
+		backup: backupDir(),
+	}
+
+	var errs []error
+	for _, s := range scopes {
+		if !slices.Contains(selected, s) {
+			continue
+		}
+		slog.Info("resetting launcher data", "scope", s)
+		if err := r.reset(s, slices.Contains(selected, Saves)); err != nil {
+			errs = append(errs, fmt.Errorf("unable to reset %s: %w", s, err))
+		}
+	}
+
+	if r.moved {
+		r.result.Backup = r.backup
+	}
+	slog.Info("launcher data reset",
+		"scopes", selected,
+		"backup", r.result.Backup,
+		"reclaimed", format.Bytes(r.result.Reclaimed),
+	)
+	return r.result, errors.Join(errs...)
+}
+
+// Everything moves the launcher's settings, session and channel state into
+// a backup directory, so the next start begins afresh. Game installs, the
+// download cache, logs and the exit journal stay in place. Returns the
+// backup directory.
+func Everything() (string, error) {
+	root := hytale.StorageDir()
+	r := &resetter{result: &Result{}, backup: backupDir()}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return "", fmt.Errorf("unable to read storage directory: %w", err)
+	}
+
+	var errs []error
+	for _, e := range entries {
+		path := filepath.Join(root, e.Name())
+		switch {
+		case e.Type().IsRegular() && path != exitlog.Path():
+			errs = append(errs, r.move(path))
+		case e.IsDir():
+			errs = append(errs, r.moveStateFiles(path))
+		}
+	}
+	for _, channel := range hytale.ExperimentalChannels() {
+		errs = append(errs, r.moveStateFiles(hytale.ChannelDir(channel)))
+	}
+
+	return r.backup, errors.Join(errs...)
+}
+
+// backupDir returns a new backup directory in the storage directory.
+func backupDir() string {
+	return hytale.InStorageDir("reset-" + time.Now().Format("20060102-150405"))
+}
+
+// resetter accumulates the outcome of a reset.
+type resetter struct {
+	result *Result
+	backup string
+
+	// moved is set once anything is moved into the backup directory.
+	moved bool
+}
+
+// reset resets one scope. deleteSaves is whether the player's data is
+// being reset too.
+func (r *resetter) reset(s Scope, deleteSaves bool) error {
+	switch s {
+	case GameInstalls:
+		return r.eachChannel(func(state *appstate.State) error {
+			res, err := uninstall.Channel(state, !deleteSaves, nil)
+			if err != nil {
+				return err
+			}
+			r.result.Reclaimed += res.Reclaimed
+			if res.Archive != "" {
+				r.result.Archives = append(r.result.Archives, res.Archive)
+			}
+			return nil
+		})
+
+	case Saves:
+		return r.eachChannel(func(state *appstate.State) error {
+			freed, err := uninstall.DeleteUserData(state)
+			r.result.Reclaimed += freed
+			return err
+		})
+
+	case AppState:
+		dirs, err := channelDirs()
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for _, dir := range dirs {
+			errs = append(errs, r.moveStateFiles(dir))
+		}
+		return errors.Join(errs...)
+
+	case Settings:
+		if err := r.move(settings.Path()); err != nil {
+			return err
+		}
+		settings.Reload()
+		return nil
+
+	case Cache:
+		freed, err := download.ClearCache()
+		r.result.Reclaimed += freed
+		if err != nil {
+			return err
+		}
+		freed, err = verget.ClearCache()
+		r.result.Reclaimed += freed
+		if err != nil {
+			return err
+		}
+		size, _ := ioutil.DirSize(screenshots.CacheDir())
+		if err := os.RemoveAll(screenshots.CacheDir()); err != nil {
+			return err
+		}
+		r.result.Reclaimed += size
+		return nil
+
+	case Account:
+		return r.move(auth.AccountFilePath())
+	}
+	return nil
+}
+
+// eachChannel calls fn with the state of every channel that has one.
+// Channels whose state cannot be read are skipped.
+func (r *resetter) eachChannel(fn func(*appstate.State) error) error {
+	dirs, err := channelDirs()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, dir := range dirs {
+		channel := filepath.Base(dir)
+		state, err := appstate.Load(channel)
+		if err != nil {
+			if !errors.Is(err, appstate.ErrNotFound) {
+				slog.Warn("skipping channel with unreadable state", "channel", channel, "error", err)
+			}
+			continue
+		}
+		if err := fn(state); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", channel, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// channelDirs returns the directories in the storage directory, and in
+// its experimental directory, that hold a channel's state.
+func channelDirs() ([]string, error) {
+	root := hytale.StorageDir()
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read storage directory: %w", err)
+	}
+
+	var dirs []string
+	for _, e := range entries {
+		if dir := filepath.Join(root, e.Name()); e.IsDir() && hasStateFiles(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, channel := range hytale.ExperimentalChannels() {
+		if dir := hytale.ChannelDir(channel); hasStateFiles(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
+
+// hasStateFiles reports whether dir holds a channel state file.
+func hasStateFiles(dir string) bool {
+	return slices.ContainsFunc(stateFiles, func(name string) bool {
+		return isRegular(filepath.Join(dir, name))
+	})
+}
+
+// moveStateFiles moves the channel state files in dir to the backup.
+func (r *resetter) moveStateFiles(dir string) error {
+	var errs []error
+	for _, name := range stateFiles {
+		if path := filepath.Join(dir, name); isRegular(path) {
+			errs = append(errs, r.move(path))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// move moves a file in the storage directory to the same place in the
+// backup directory. A file that does not exist is ignored.
+func (r *resetter) move(path string) error {
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Lstat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	rel, err := filepath.Rel(hytale.StorageDir(), path)
+	if err == nil {
+		dst := filepath.Join(r.backup, rel)
+		if err = os.MkdirAll(filepath.Dir(dst), 0o755); err == nil {
+			err = os.Rename(path, dst)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("unable to move %s: %w", path, err)
+	}
+	r.moved = true
+	return nil
+}
+
+// isRegular reports whether path is a regular file.
+func isRegular(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
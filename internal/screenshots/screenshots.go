@@ -0,0 +1,198 @@
+// Package screenshots indexes the screenshots the game saves into its build
+// directory, generates cached thumbnails for them, and watches for new ones
+// while the game runs.
+package screenshots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultGlob matches the screenshots the game saves, relative to its build
+// directory.
+const DefaultGlob = "screenshots/*"
+
+// imageExts are the extensions of files indexed as screenshots. Other files
+// matched by the glob are skipped.
+var imageExts = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+}
+
+// Screenshot describes a screenshot saved by the game.
+type Screenshot struct {
+	// Name is the slash-separated path relative to the game directory.
+	Name string `json:"name"`
+
+	// Path is the absolute path to the file.
+	Path string `json:"path"`
+
+	Size    int64     `json:"size"`
+	TakenAt time.Time `json:"taken_at"`
+
+	// Thumbnail is a data URL of a downscaled copy of the image. It is
+	// empty if the image could not be decoded.
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+// Index finds the screenshots in a game directory matching the glob, which
+// is relative to the directory, newest first. Thumbnails are not filled in.
+func Index(gameDir, glob string) ([]Screenshot, error) {
+	if glob == "" {
+		glob = DefaultGlob
+	}
+	if filepath.IsAbs(glob) || strings.Contains(filepath.ToSlash(glob), "..") {
+		return nil, fmt.Errorf("screenshot glob %q must be relative to the game directory", glob)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(gameDir, filepath.FromSlash(glob)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid screenshot glob %q: %w", glob, err)
+	}
+
+	var result []Screenshot
+	for _, p := range matches {
+		if !imageExts[strings.ToLower(filepath.Ext(p))] {
+			continue
+		}
+		info, err := os.Lstat(p)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		rel, err := filepath.Rel(gameDir, p)
+		if err != nil {
+			continue
+		}
+		result = append(result, Screenshot{
+			Name:    filepath.ToSlash(rel),
+			Path:    p,
+			Size:    info.Size(),
+			TakenAt: info.ModTime(),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TakenAt.After(result[j].TakenAt)
+	})
+	return result, nil
+}
+
+// Find returns the screenshot with the given name, which must be one Index
+// returns for the same directory and glob. Names are never joined onto the
+// directory directly, so a name from the frontend cannot reach other files.
+func Find(gameDir, glob, name string) (*Screenshot, error) {
+	shots, err := Index(gameDir, glob)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shots {
+		if shots[i].Name == name {
+			return &shots[i], nil
+		}
+	}
+	return nil, fmt.Errorf("screenshot %s: %w", name, fs.ErrNotExist)
+}
+
+// Delete removes a screenshot and its cached thumbnail.
+func Delete(gameDir, glob, name string) error {
+	shot, err := Find(gameDir, glob, name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(shot.Path); err != nil {
+		return fmt.Errorf("unable to delete screenshot %s: %w", name, err)
+	}
+	if err := os.Remove(cachePath(shot)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to delete thumbnail of %s: %w", name, err)
+	}
+	return nil
+}
+
+// PruneCache removes cached thumbnails older than maxAge.
+func PruneCache(maxAge time.Duration) error {
+	entries, err := os.ReadDir(CacheDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < maxAge {
+			continue
+		}
+		os.Remove(filepath.Join(CacheDir(), entry.Name()))
+	}
+	return nil
+}
+
+// Watch polls the game directory until ctx is cancelled and calls onNew for
+// each screenshot matching the glob that was not there when Watch started.
+// The thumbnail is filled in if it can be generated.
+func Watch(ctx context.Context, gameDir, glob string, interval time.Duration, onNew func(Screenshot)) {
+	seen := make(map[string]bool)
+	// pending holds the size of new files that could not be decoded yet.
+	pending := make(map[string]int64)
+	if shots, err := Index(gameDir, glob); err == nil {
+		for _, s := range shots {
+			seen[s.Name] = true
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		shots, err := Index(gameDir, glob)
+		if err != nil {
+			continue
+		}
+		for i := len(shots) - 1; i >= 0; i-- {
+			s := shots[i]
+			if seen[s.Name] {
+				continue
+			}
+			// The game may still be writing the file. Try again on the
+			// next tick, unless its size has stopped changing.
+			thumb, err := Thumbnail(&s)
+			if err != nil {
+				if size, ok := pending[s.Name]; !ok || size != s.Size {
+					pending[s.Name] = s.Size
+					continue
+				}
+			}
+			delete(pending, s.Name)
+			seen[s.Name] = true
+			s.Thumbnail = thumb
+			onNew(s)
+		}
+	}
+}
+
+// Dir returns the directory the game saves screenshots in: the part of the
+// glob before its first wildcard, inside the game directory.
+func Dir(gameDir, glob string) string {
+	if glob == "" {
+		glob = DefaultGlob
+	}
+	dir := filepath.FromSlash(glob)
+	for strings.ContainsAny(dir, `*?[`) {
+		dir = filepath.Dir(dir)
+	}
+	return filepath.Join(gameDir, dir)
+}
@@ -0,0 +1,126 @@
+package screenshots
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/ioutil"
+)
+
+// thumbnailWidth is the width thumbnails are scaled down to. The height
+// keeps the image's aspect ratio.
+const thumbnailWidth = 320
+
+// thumbnailQuality is the JPEG quality thumbnails are encoded with.
+const thumbnailQuality = 80
+
+// cacheDirName is the directory in the storage directory thumbnails are
+// cached in. It is kept apart from the download cache, which is flushed on
+// every start.
+const cacheDirName = "thumbnails"
+
+// CacheDir returns the directory thumbnails are cached in.
+func CacheDir() string {
+	return hytale.InStorageDir(cacheDirName)
+}
+
+// cachePath returns the path of a screenshot's cached thumbnail. The name
+// covers the file's path, size and modification time, so a replaced file
+// gets a new thumbnail.
+func cachePath(s *Screenshot) string {
+	h := sha256.New()
+	h.Write([]byte(s.Path))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatInt(s.Size, 10)))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatInt(s.TakenAt.UnixNano(), 10)))
+	return filepath.Join(CacheDir(), hex.EncodeToString(h.Sum(nil))[:32]+".jpg")
+}
+
+// Thumbnail returns a data URL of a downscaled JPEG copy of the screenshot,
+// generating and caching it on first use.
+func Thumbnail(s *Screenshot) (string, error) {
+	p := cachePath(s)
+	data, err := os.ReadFile(p)
+	if err != nil {
+		data, err = makeThumbnail(s.Path)
+		if err != nil {
+			return "", err
+		}
+		if err := ioutil.MkdirAll(CacheDir()); err == nil {
+			tmp := p + ".tmp"
+			if err := os.WriteFile(tmp, data, 0644); err == nil {
+				os.Rename(tmp, p)
+			}
+		}
+	}
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// makeThumbnail decodes an image and encodes a downscaled copy as JPEG.
+func makeThumbnail(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", filepath.Base(path), err)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scale(src, thumbnailWidth), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scale downsamples an image to the given width by averaging the source
+// pixels that fall in each destination pixel. Images narrower than width
+// are returned unchanged.
+func scale(src image.Image, width int) image.Image {
+	b := src.Bounds()
+	if b.Dx() <= width {
+		return src
+	}
+	height := max(1, b.Dy()*width/b.Dx())
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := b.Min.Y + y*b.Dy()/height
+		y1 := max(y0+1, b.Min.Y+(y+1)*b.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := b.Min.X + x*b.Dx()/width
+			x1 := max(x0+1, b.Min.X+(x+1)*b.Dx()/width)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(bl / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"hytale-launcher/internal/crypto"
 	"hytale-launcher/internal/hytale"
@@ -24,6 +25,22 @@ type cleanupNote struct {
 	Channel string `json:"channel"`
 	// Version is the version string of the old launcher.
 	Version string `json:"version"`
+	// NewVersion is the version string of the launcher updated to.
+	NewVersion string `json:"new_version,omitempty"`
+	// Source is the downloaded binary the update was copied from.
+	Source string `json:"source,omitempty"`
+	// HelperPID is the PID of the update helper, which runs from Source.
+	HelperPID int `json:"helper_pid,omitempty"`
+}
+
+// Upgrade describes a completed self-update.
+type Upgrade struct {
+	// Channel is the launcher release channel.
+	Channel string `json:"channel"`
+	// FromVersion is the version updated from.
+	FromVersion string `json:"from_version"`
+	// ToVersion is the version updated to.
+	ToVersion string `json:"to_version"`
 }
 
 // WriteFile writes the cleanup note to the filesystem.
@@ -39,7 +56,7 @@ func (n *cleanupNote) WriteFile() error {
 // It returns the cleanup note if one exists, or nil if not.
 func consumeCleanupNote() (*cleanupNote, error) {
 	defer func() {
-		os.Remove(cleanupNoteFile())
+		sys.FS.Remove(cleanupNoteFile())
 	}()
 
 	data, err := crypto.ReadFile(cleanupNoteFile(), cleanupNoteKeyName)
@@ -58,23 +75,58 @@ func consumeCleanupNote() (*cleanupNote, error) {
 	return note, nil
 }
 
-// CleanupOldLauncher reads the cleanup note and removes the old launcher directory
-// if a cleanup is pending from a previous self-update operation.
-func CleanupOldLauncher() error {
+// CleanupOldLauncher reads the cleanup note and, if a self-update finished
+// since the last start, removes the old launcher directory and the
+// downloaded binary the update was copied from. It returns the upgrade, or
+// nil if none is pending. The note is deleted even if the cleanup fails,
+// so it is not retried on every start.
+func CleanupOldLauncher() (*Upgrade, error) {
 	note, err := consumeCleanupNote()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if note == nil {
-		return nil
+		return nil, nil
 	}
 
+	upgrade := &Upgrade{
+		Channel:     note.Channel,
+		FromVersion: note.Version,
+		ToVersion:   note.NewVersion,
+	}
+
+	var errs []error
 	dir := hytale.PackageDir("launcher", note.Channel, note.Version)
 	slog.Debug("cleaning up old launcher", "dir", dir)
+	if err := sys.FS.RemoveAll(dir); err != nil {
+		errs = append(errs, fmt.Errorf("error removing old launcher directory: %w", err))
+	}
 
-	if err := os.RemoveAll(dir); err != nil {
-		return fmt.Errorf("error removing old launcher directory: %w", err)
+	if isDownload(note.Source) {
+		// The helper runs from the downloaded binary, which cannot be
+		// removed on Windows until it has exited.
+		if note.HelperPID > 0 {
+			waitForProcessExit(note.HelperPID)
+		}
+		slog.Debug("removing downloaded launcher binary", "path", note.Source)
+		if err := sys.FS.Remove(note.Source); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("error removing downloaded launcher binary: %w", err))
+		}
 	}
 
-	return nil
+	return upgrade, errors.Join(errs...)
+}
+
+// isDownload reports whether path is a file the launcher downloaded into
+// its storage directory, and so may be removed, rather than the running
+// executable or a file elsewhere.
+func isDownload(path string) bool {
+	if path == "" {
+		return false
+	}
+	path = filepath.Clean(path)
+	if exe, err := os.Executable(); err == nil && filepath.Clean(exe) == path {
+		return false
+	}
+	return strings.HasPrefix(path, filepath.Clean(hytale.StorageDir())+string(filepath.Separator))
 }
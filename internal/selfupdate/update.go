@@ -5,13 +5,15 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"strings"
+	"path/filepath"
+	"strconv"
 	"time"
 
+	"hytale-launcher/internal/build"
 	"hytale-launcher/internal/crypto"
 	"hytale-launcher/internal/fork"
 	"hytale-launcher/internal/ioutil"
-	"hytale-launcher/internal/keyring"
+	"hytale-launcher/internal/system"
 )
 
 // Package-level variables set by the build system or runtime configuration.
@@ -34,36 +36,30 @@ var (
 const (
 	// cleanupNoteKeyName is the keyring key name for encrypting the cleanup note.
 	cleanupNoteKeyName = "selfupdate"
-	// updateKeyName is the keyring key name for validating update signatures.
-	updateKeyName = "selfupdate"
 	// processWaitTimeout is how long to wait for the parent process to exit.
 	processWaitTimeout = 30 * time.Second
 	// processCheckInterval is how often to check if the parent process has exited.
 	processCheckInterval = 100 * time.Millisecond
 )
 
-// updateKey holds the cached encryption key for update validation.
-var updateKey []byte
+// sys provides the clock and file system self-updates use.
+var sys = system.Real()
 
-// init pre-fetches the update key from the keyring.
-func init() {
-	key, err := keyring.GetOrGenKey(updateKeyName)
-	if err != nil {
-		return
-	}
-	updateKey = key
-}
+// updateKey holds the cached encryption key for update validation. It is
+// fetched on first use rather than at init, as the launcher imports this
+// package to clean up after an update.
+var updateKey []byte
 
 // replaceBin copies the contents of the source binary to the target path.
 func replaceBin(from, to string) error {
 	slog.Debug("replacing binary", "from", from, "to", to)
 
-	data, err := os.ReadFile(from)
+	data, err := sys.FS.ReadFile(from)
 	if err != nil {
 		return fmt.Errorf("error reading source binary: %w", err)
 	}
 
-	if err := os.WriteFile(to, data, 0644); err != nil {
+	if err := sys.FS.WriteFile(to, data, 0644); err != nil {
 		return fmt.Errorf("error writing destination binary: %w", err)
 	}
 
@@ -74,7 +70,7 @@ func replaceBin(from, to string) error {
 func updateBin() error {
 	slog.Info("updating binary", "from", SourceBin, "to", TargetBin)
 
-	if err := os.Remove(TargetBin); err != nil {
+	if err := sys.FS.Remove(TargetBin); err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
 			slog.Error("failed to remove existing executable", "error", err)
 			return err
@@ -94,46 +90,53 @@ func isSet(s string) bool {
 func waitForProcessExit(pid int) {
 	slog.Info("waiting for parent process to exit", "pid", pid)
 
-	deadline := time.Now().Add(processWaitTimeout)
+	deadline := sys.Clock.Now().Add(processWaitTimeout)
 
-	for time.Now().Before(deadline) {
+	for sys.Clock.Now().Before(deadline) {
 		if !processExists(pid) {
 			slog.Debug("parent process has exited", "pid", pid)
 			return
 		}
-		time.Sleep(processCheckInterval)
+		<-sys.Clock.After(processCheckInterval)
 	}
 
 	slog.Warn("timed out waiting for parent process to exit", "pid", pid)
 }
 
 // validate checks that the update is valid by verifying the HMAC signature
-// and ensuring the source and target binaries have valid paths.
+// the launcher made over its PID, and ensuring the source binary exists and
+// both paths are absolute.
 func validate(key []byte) error {
-	// Compute HMAC of the target binary path
-	targetPath := []byte(TargetBin)
-	computed := crypto.HMAC(targetPath, key)
+	// Compute HMAC of the parent PID, as the launcher signed it
+	computed := crypto.HMAC([]byte(strconv.Itoa(ParentPID)), key)
 
 	// Verify the signature matches
 	if computed != UpdateSignature {
 		return errors.New("invalid update signature")
 	}
 
-	// Validate that source and target paths start with "/tmp" prefix
-	// The update binaries should be placed in a temp directory
-	if strings.HasPrefix(SourceBin, "/tmp") && strings.HasPrefix(TargetBin, "/tmp") {
-		return nil
+	if !filepath.IsAbs(SourceBin) || !filepath.IsAbs(TargetBin) {
+		return errors.New("invalid update executables")
 	}
-
-	return errors.New("invalid update executables")
+	info, err := os.Stat(SourceBin)
+	if err != nil || !info.Mode().IsRegular() {
+		return fmt.Errorf("invalid update executables: %s is not a file", SourceBin)
+	}
+	return nil
 }
 
-// fetchUpdateKey retrieves the update validation key.
+// fetchUpdateKey retrieves the update validation key, the one the launcher
+// signs the update request with.
 var fetchUpdateKey = func() ([]byte, error) {
 	if updateKey != nil {
 		return updateKey, nil
 	}
-	return keyring.GetOrGenKey(updateKeyName)
+	key, err := crypto.LoadSelfUpdateKey()
+	if err != nil {
+		return nil, err
+	}
+	updateKey = key
+	return key, nil
 }
 
 // Do performs the self-update process.
@@ -181,8 +184,11 @@ func Do() {
 	// Write cleanup note if old version info is available
 	if isSet(OldChannel) && isSet(OldVersion) {
 		note := &cleanupNote{
-			Channel: OldChannel,
-			Version: OldVersion,
+			Channel:    OldChannel,
+			Version:    OldVersion,
+			NewVersion: build.Version,
+			Source:     SourceBin,
+			HelperPID:  os.Getpid(),
 		}
 		if err := note.WriteFile(); err != nil {
 			slog.Error("failed to write self-update note file", "error", err)
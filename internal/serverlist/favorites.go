@@ -0,0 +1,148 @@
+package serverlist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/ioutil"
+)
+
+// favoritesFileName is the file in the storage directory that holds the
+// user's favorite servers.
+const favoritesFileName = "favorite-servers.json"
+
+// Favorite is a server the user has added to their favorites.
+type Favorite struct {
+	Address string    `json:"address"`
+	Name    string    `json:"name,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+var (
+	// favoritesMu protects favorites and favoritesLoaded.
+	favoritesMu sync.Mutex
+
+	// favorites holds the favorites in memory.
+	favorites []Favorite
+
+	// favoritesLoaded is true once the favorites file has been read.
+	favoritesLoaded bool
+)
+
+// Favorites returns the user's favorite servers in the order they were
+// added.
+func Favorites() []Favorite {
+	favoritesMu.Lock()
+	defer favoritesMu.Unlock()
+
+	loadFavorites()
+	return append([]Favorite(nil), favorites...)
+}
+
+// AddFavorite adds a server to the favorites. Adding a server that is
+// already a favorite updates its name.
+func AddFavorite(addr, name string) error {
+	if err := ValidateAddress(addr); err != nil {
+		return err
+	}
+	addr = NormalizeAddress(addr)
+
+	favoritesMu.Lock()
+	defer favoritesMu.Unlock()
+
+	loadFavorites()
+	updated := append([]Favorite(nil), favorites...)
+	found := false
+	for i := range updated {
+		if updated[i].Address == addr {
+			if name != "" {
+				updated[i].Name = name
+			}
+			found = true
+		}
+	}
+	if !found {
+		updated = append(updated, Favorite{
+			Address: addr,
+			Name:    name,
+			AddedAt: time.Now(),
+		})
+	}
+
+	if err := writeFavorites(updated); err != nil {
+		return fmt.Errorf("unable to save favorite servers: %w", err)
+	}
+	favorites = updated
+	return nil
+}
+
+// RemoveFavorite removes a server from the favorites.
+func RemoveFavorite(addr string) error {
+	addr = NormalizeAddress(addr)
+
+	favoritesMu.Lock()
+	defer favoritesMu.Unlock()
+
+	loadFavorites()
+	var updated []Favorite
+	for _, f := range favorites {
+		if f.Address != addr {
+			updated = append(updated, f)
+		}
+	}
+	if len(updated) == len(favorites) {
+		return nil
+	}
+
+	if err := writeFavorites(updated); err != nil {
+		return fmt.Errorf("unable to save favorite servers: %w", err)
+	}
+	favorites = updated
+	return nil
+}
+
+// loadFavorites reads the favorites file if it has not been read yet. The
+// caller must hold favoritesMu.
+func loadFavorites() {
+	if favoritesLoaded {
+		return
+	}
+	favoritesLoaded = true
+
+	data, err := os.ReadFile(hytale.InStorageDir(favoritesFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &favorites)
+	}
+	if err != nil {
+		slog.Warn("unable to read favorite servers", "error", err)
+		favorites = nil
+	}
+}
+
+// writeFavorites saves the favorites file, replacing it atomically.
+func writeFavorites(list []Favorite) error {
+	if err := ioutil.MkdirAll(hytale.StorageDir()); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := hytale.InStorageDir(favoritesFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
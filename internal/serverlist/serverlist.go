@@ -0,0 +1,168 @@
+// Package serverlist provides the server browser: it fetches the public
+// server directory, merges in the user's favorite servers, and measures the
+// latency to each of them.
+package serverlist
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/endpoints"
+)
+
+const (
+	// DefaultPort is the port used when a server address has none.
+	DefaultPort = 5520
+
+	// pingTimeout bounds the latency measurement of a single server.
+	pingTimeout = 2 * time.Second
+
+	// maxConcurrentPings limits how many servers are measured at once.
+	maxConcurrentPings = 16
+)
+
+// Server is an entry in the server browser.
+type Server struct {
+	Address     string `json:"address"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Players     int    `json:"players"`
+	MaxPlayers  int    `json:"max_players"`
+	Version     string `json:"version,omitempty"`
+
+	// Favorite is true if the user has added the server to their
+	// favorites.
+	Favorite bool `json:"favorite"`
+
+	// Listed is true if the server is in the public directory.
+	Listed bool `json:"listed"`
+
+	// Latency is the round trip time in milliseconds, or -1 if the server
+	// could not be reached.
+	Latency int64 `json:"latency"`
+}
+
+// directoryResponse is the response of the server directory endpoint.
+type directoryResponse struct {
+	Servers []Server `json:"servers"`
+}
+
+// Fetch returns the servers in the public directory.
+func Fetch(ctx context.Context) ([]Server, error) {
+	resp, err := api.Get[directoryResponse](ctx, api.Default, endpoints.ServerDirectory(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch server directory: %w", err)
+	}
+	for i := range resp.Servers {
+		resp.Servers[i].Listed = true
+	}
+	return resp.Servers, nil
+}
+
+// List returns the public directory merged with the user's favorites, with
+// the latency to each server measured. Favorites come first, then the
+// other servers by player count. If the directory cannot be fetched, only
+// the favorites are returned along with the error.
+func List(ctx context.Context) ([]Server, error) {
+	listed, fetchErr := Fetch(ctx)
+
+	byAddr := make(map[string]int, len(listed))
+	servers := make([]Server, 0, len(listed))
+	for _, s := range listed {
+		key := NormalizeAddress(s.Address)
+		if _, dup := byAddr[key]; dup {
+			continue
+		}
+		byAddr[key] = len(servers)
+		servers = append(servers, s)
+	}
+
+	for _, f := range Favorites() {
+		if i, ok := byAddr[f.Address]; ok {
+			servers[i].Favorite = true
+			continue
+		}
+		servers = append(servers, Server{
+			Address:  f.Address,
+			Name:     f.Name,
+			Favorite: true,
+		})
+	}
+
+	Ping(ctx, servers)
+
+	sort.SliceStable(servers, func(i, j int) bool {
+		if servers[i].Favorite != servers[j].Favorite {
+			return servers[i].Favorite
+		}
+		return servers[i].Players > servers[j].Players
+	})
+	return servers, fetchErr
+}
+
+// Ping measures the latency to each server concurrently, setting Latency.
+// Latency is the time taken to open a connection to the server.
+func Ping(ctx context.Context, servers []Server) {
+	sem := make(chan struct{}, maxConcurrentPings)
+	var wg sync.WaitGroup
+
+	for i := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			servers[i].Latency = ping(ctx, servers[i].Address)
+		}()
+	}
+	wg.Wait()
+}
+
+// ping returns the time in milliseconds to connect to addr, or -1 if it
+// cannot be reached.
+func ping(ctx context.Context, addr string) int64 {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", NormalizeAddress(addr))
+	if err != nil {
+		return -1
+	}
+	conn.Close()
+	return time.Since(start).Milliseconds()
+}
+
+// NormalizeAddress returns addr in host:port form, adding the default port
+// if it has none.
+func NormalizeAddress(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return strings.ToLower(addr)
+	}
+	host := strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	return strings.ToLower(net.JoinHostPort(host, strconv.Itoa(DefaultPort)))
+}
+
+// ValidateAddress returns an error if addr is not a usable server address.
+func ValidateAddress(addr string) error {
+	host, port, err := net.SplitHostPort(NormalizeAddress(addr))
+	if err != nil {
+		return fmt.Errorf("invalid server address %q: %w", addr, err)
+	}
+	if host == "" {
+		return fmt.Errorf("invalid server address %q: no host", addr)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid server address %q: bad port", addr)
+	}
+	return nil
+}
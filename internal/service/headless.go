@@ -0,0 +1,78 @@
+package service
+
+import "log/slog"
+
+// Headless is a frontend without a window, for driving the launcher from
+// the command line. Events are passed to OnEvent, or logged if it is nil.
+// Dialogs and the clipboard are unavailable.
+type Headless struct {
+	// OnEvent receives every event emitted by the backend.
+	OnEvent func(name string, args ...any)
+
+	// OnQuit is called when the backend asks to exit.
+	OnQuit func()
+}
+
+// Emit passes the event to OnEvent.
+func (h *Headless) Emit(name string, args ...any) {
+	if h.OnEvent == nil {
+		slog.Debug("headless event", "name", name, "args", args)
+		return
+	}
+	h.OnEvent(name, args...)
+}
+
+// OpenDirectory returns ErrUnsupported.
+func (h *Headless) OpenDirectory(OpenDialog) (string, error) {
+	return "", ErrUnsupported
+}
+
+// OpenFile returns ErrUnsupported.
+func (h *Headless) OpenFile(OpenDialog) (string, error) {
+	return "", ErrUnsupported
+}
+
+// SaveFile returns ErrUnsupported.
+func (h *Headless) SaveFile(SaveDialog) (string, error) {
+	return "", ErrUnsupported
+}
+
+// Info logs the message.
+func (h *Headless) Info(opts MessageDialog) error {
+	slog.Info(opts.Message, "title", opts.Title)
+	return nil
+}
+
+// Error logs the message.
+func (h *Headless) Error(opts MessageDialog) error {
+	slog.Error(opts.Message, "title", opts.Title)
+	return nil
+}
+
+// Confirm returns ErrUnsupported.
+func (h *Headless) Confirm(MessageDialog) (bool, error) {
+	return false, ErrUnsupported
+}
+
+// SetClipboard returns ErrUnsupported.
+func (h *Headless) SetClipboard(string) error {
+	return ErrUnsupported
+}
+
+// Clipboard returns ErrUnsupported.
+func (h *Headless) Clipboard() (string, error) {
+	return "", ErrUnsupported
+}
+
+// Show does nothing; there is no window.
+func (h *Headless) Show() {}
+
+// Hide does nothing; there is no window.
+func (h *Headless) Hide() {}
+
+// Quit calls OnQuit.
+func (h *Headless) Quit() {
+	if h.OnQuit != nil {
+		h.OnQuit()
+	}
+}
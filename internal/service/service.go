@@ -0,0 +1,90 @@
+// Package service defines what the launcher backend needs from the frontend
+// hosting it: event delivery, native dialogs, the clipboard and the window.
+// The backend only talks to these interfaces, so the same App can be driven
+// by Wails v2, Wails v3 or a headless command line.
+package service
+
+import "errors"
+
+// ErrUnsupported is returned by frontends that cannot perform an action,
+// such as showing a dialog without a window.
+var ErrUnsupported = errors.New("not supported by this frontend")
+
+// FileFilter restricts the files shown in a file dialog.
+type FileFilter struct {
+	// DisplayName is shown in the dialog, e.g. "Backups (*.zip)".
+	DisplayName string
+
+	// Pattern is a semicolon-separated list of globs, e.g. "*.zip;*.tar.gz".
+	Pattern string
+}
+
+// OpenDialog configures a dialog picking an existing file or directory.
+type OpenDialog struct {
+	Title                string
+	Filters              []FileFilter
+	CanCreateDirectories bool
+}
+
+// SaveDialog configures a dialog picking a file to write.
+type SaveDialog struct {
+	Title                string
+	DefaultFilename      string
+	Filters              []FileFilter
+	CanCreateDirectories bool
+}
+
+// MessageDialog configures a native message box.
+type MessageDialog struct {
+	Title   string
+	Message string
+}
+
+// Events delivers backend events to the frontend.
+type Events interface {
+	// Emit sends an event with the given name and arguments.
+	Emit(name string, args ...any)
+}
+
+// Dialogs shows native dialogs. The file dialogs return an empty path if
+// the user cancels.
+type Dialogs interface {
+	OpenDirectory(opts OpenDialog) (string, error)
+	OpenFile(opts OpenDialog) (string, error)
+	SaveFile(opts SaveDialog) (string, error)
+
+	// Info shows an informational message.
+	Info(opts MessageDialog) error
+
+	// Error shows an error message.
+	Error(opts MessageDialog) error
+
+	// Confirm asks a yes or no question and returns true for yes.
+	Confirm(opts MessageDialog) (bool, error)
+}
+
+// Clipboard reads and writes the system clipboard.
+type Clipboard interface {
+	SetClipboard(text string) error
+	Clipboard() (string, error)
+}
+
+// Window controls the launcher window.
+type Window interface {
+	// Show shows the window, restoring it if minimized.
+	Show()
+
+	// Hide hides the window, leaving the launcher running.
+	Hide()
+
+	// Quit exits the frontend's event loop.
+	Quit()
+}
+
+// Frontend is everything the backend needs from the frontend hosting it.
+type Frontend interface {
+	Events
+	Dialogs
+	Clipboard
+	Window
+}
@@ -0,0 +1,165 @@
+// Package wailsv2 implements the launcher's service.Frontend on the
+// Wails v2 runtime.
+package wailsv2
+
+import (
+	"context"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"hytale-launcher/internal/portal"
+	"hytale-launcher/internal/service"
+	"hytale-launcher/internal/sysinfo"
+)
+
+// Frontend drives the Wails v2 window and webview.
+type Frontend struct {
+	// ctx is the Wails application context passed to OnStartup.
+	ctx context.Context
+}
+
+// New returns a frontend for the Wails application context.
+func New(ctx context.Context) *Frontend {
+	return &Frontend{ctx: ctx}
+}
+
+// Emit sends an event to the webview.
+func (f *Frontend) Emit(name string, args ...any) {
+	runtime.EventsEmit(f.ctx, name, args...)
+}
+
+// OpenDirectory shows a native directory picker.
+func (f *Frontend) OpenDirectory(opts service.OpenDialog) (string, error) {
+	if usePortal() {
+		return portal.OpenFile(portal.FileDialog{Title: opts.Title, Directory: true})
+	}
+	return runtime.OpenDirectoryDialog(f.ctx, openOptions(opts))
+}
+
+// OpenFile shows a native picker for an existing file.
+func (f *Frontend) OpenFile(opts service.OpenDialog) (string, error) {
+	if usePortal() {
+		return portal.OpenFile(portal.FileDialog{Title: opts.Title, Filters: portalFilters(opts.Filters)})
+	}
+	return runtime.OpenFileDialog(f.ctx, openOptions(opts))
+}
+
+// SaveFile shows a native save dialog.
+func (f *Frontend) SaveFile(opts service.SaveDialog) (string, error) {
+	if usePortal() {
+		return portal.SaveFile(portal.FileDialog{
+			Title:   opts.Title,
+			Name:    opts.DefaultFilename,
+			Filters: portalFilters(opts.Filters),
+		})
+	}
+	return runtime.SaveFileDialog(f.ctx, runtime.SaveDialogOptions{
+		Title:                opts.Title,
+		DefaultFilename:      opts.DefaultFilename,
+		Filters:              filters(opts.Filters),
+		CanCreateDirectories: opts.CanCreateDirectories,
+	})
+}
+
+// Info shows an informational message box.
+func (f *Frontend) Info(opts service.MessageDialog) error {
+	return f.message(runtime.InfoDialog, opts)
+}
+
+// Error shows an error message box.
+func (f *Frontend) Error(opts service.MessageDialog) error {
+	return f.message(runtime.ErrorDialog, opts)
+}
+
+// message shows a message box with a single button.
+func (f *Frontend) message(kind runtime.DialogType, opts service.MessageDialog) error {
+	_, err := runtime.MessageDialog(f.ctx, runtime.MessageDialogOptions{
+		Type:    kind,
+		Title:   opts.Title,
+		Message: opts.Message,
+	})
+	return err
+}
+
+// Confirm asks a yes or no question. Windows and Linux always label the
+// buttons Yes and No, so they are named the same on macOS, where the
+// clicked button's label is returned.
+func (f *Frontend) Confirm(opts service.MessageDialog) (bool, error) {
+	answer, err := runtime.MessageDialog(f.ctx, runtime.MessageDialogOptions{
+		Type:          runtime.QuestionDialog,
+		Title:         opts.Title,
+		Message:       opts.Message,
+		Buttons:       []string{"Yes", "No"},
+		DefaultButton: "No",
+		CancelButton:  "No",
+	})
+	return answer == "Yes", err
+}
+
+// SetClipboard places text on the system clipboard.
+func (f *Frontend) SetClipboard(text string) error {
+	return runtime.ClipboardSetText(f.ctx, text)
+}
+
+// Clipboard returns the text on the system clipboard.
+func (f *Frontend) Clipboard() (string, error) {
+	return runtime.ClipboardGetText(f.ctx)
+}
+
+// Show shows and raises the window.
+func (f *Frontend) Show() {
+	runtime.WindowUnminimise(f.ctx)
+	runtime.WindowShow(f.ctx)
+}
+
+// Hide hides the window.
+func (f *Frontend) Hide() {
+	runtime.WindowHide(f.ctx)
+}
+
+// Quit exits the application.
+func (f *Frontend) Quit() {
+	runtime.Quit(f.ctx)
+}
+
+// openOptions converts dialog options to Wails options.
+func openOptions(opts service.OpenDialog) runtime.OpenDialogOptions {
+	return runtime.OpenDialogOptions{
+		Title:                opts.Title,
+		Filters:              filters(opts.Filters),
+		CanCreateDirectories: opts.CanCreateDirectories,
+	}
+}
+
+// usePortal reports whether file dialogs are shown through the desktop
+// portal. In a Flatpak or Snap, the native dialogs only see the sandbox's
+// file system; the portal's show the host's and grant access to the
+// chosen file.
+func usePortal() bool {
+	return sysinfo.CurrentSandbox() != sysinfo.SandboxNone && portal.Available()
+}
+
+// portalFilters converts file filters to portal filters.
+func portalFilters(filters []service.FileFilter) []portal.Filter {
+	result := make([]portal.Filter, 0, len(filters))
+	for _, f := range filters {
+		result = append(result, portal.Filter{
+			Name:     f.DisplayName,
+			Patterns: strings.Split(f.Pattern, ";"),
+		})
+	}
+	return result
+}
+
+// filters converts file filters to Wails dialog filters.
+func filters(filters []service.FileFilter) []runtime.FileFilter {
+	result := make([]runtime.FileFilter, 0, len(filters))
+	for _, f := range filters {
+		result = append(result, runtime.FileFilter{
+			DisplayName: f.DisplayName,
+			Pattern:     f.Pattern,
+		})
+	}
+	return result
+}
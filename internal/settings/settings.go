@@ -0,0 +1,360 @@
+// Package settings stores launcher-wide preferences that apply regardless
+// of the account or channel, such as whether telemetry is sent.
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/ioutil"
+)
+
+// fileName is the name of the settings file in the storage directory.
+const fileName = "settings.json"
+
+// Settings holds the launcher-wide preferences. The zero value is the
+// default for every setting.
+type Settings struct {
+	// TelemetryEnabled allows anonymized launcher metrics to be sent.
+	TelemetryEnabled bool `json:"telemetry_enabled"`
+
+	// TelemetryEndpoint overrides the URL telemetry is sent to. Empty
+	// means the default endpoint.
+	TelemetryEndpoint string `json:"telemetry_endpoint,omitempty"`
+
+	// AttestationEnabled computes an integrity attestation over the game
+	// files before each launch, for servers that check it.
+	AttestationEnabled bool `json:"attestation_enabled,omitempty"`
+
+	// IntegrityCheckEnabled checks the game's critical files against the
+	// ones the launcher installed before each launch.
+	IntegrityCheckEnabled bool `json:"integrity_check_enabled,omitempty"`
+
+	// CrashReportsDisabled stops error reports from being sent.
+	CrashReportsDisabled bool `json:"crash_reports_disabled,omitempty"`
+
+	// PresenceEnabled shares the user's online status with their friends
+	// and shows theirs in the launcher.
+	PresenceEnabled bool `json:"presence_enabled,omitempty"`
+
+	// DiscordDisabled stops the launcher's activity from being shown on the
+	// user's Discord profile.
+	DiscordDisabled bool `json:"discord_disabled,omitempty"`
+
+	// LANShareEnabled shares installed game builds with other launchers on
+	// the local network, and copies builds from them instead of the CDN.
+	LANShareEnabled bool `json:"lan_share_enabled,omitempty"`
+
+	// NotificationsDisabled stops the launcher from showing system
+	// notifications, such as when an update is ready or the game crashed.
+	NotificationsDisabled bool `json:"notifications_disabled,omitempty"`
+
+	// ModIndexURL overrides the mod index mods are browsed and installed
+	// from. Empty means the default index.
+	ModIndexURL string `json:"mod_index_url,omitempty"`
+
+	// DownloadCacheMB caps the size of the download cache in megabytes.
+	// Zero means the default of 4096; a negative value turns it off.
+	DownloadCacheMB int `json:"download_cache_mb,omitempty"`
+
+	// ScreenshotGlob matches the screenshots shown in the gallery,
+	// relative to the game directory. Empty means "screenshots/*".
+	ScreenshotGlob string `json:"screenshot_glob,omitempty"`
+
+	// Locale is the language tag used for backend messages and formatting
+	// while no account is logged in, or the account has none. Empty means
+	// the system language.
+	Locale string `json:"locale,omitempty"`
+
+	// CloudSync configures syncing saves and settings to remote storage.
+	CloudSync CloudSync `json:"cloud_sync"`
+
+	// Accessibility holds the user interface accessibility preferences.
+	Accessibility Accessibility `json:"accessibility"`
+
+	// DownloadWindow limits background downloads to a time of day.
+	DownloadWindow DownloadWindow `json:"download_window"`
+
+	// HandheldMode is one of the HandheldMode* constants. It decides
+	// whether the game is launched fullscreen with controller prompts, as
+	// suits the Steam Deck and gamescope sessions.
+	HandheldMode string `json:"handheld_mode,omitempty"`
+
+	// CertificatePinningDisabled stops the account endpoints' TLS keys
+	// from being checked against the pinned keys, for networks that
+	// intercept TLS on purpose, such as behind a corporate proxy.
+	CertificatePinningDisabled bool `json:"certificate_pinning_disabled,omitempty"`
+
+	// Environment selects the backend endpoints are served from
+	// (production, staging, local or custom). It is only honoured in
+	// development builds.
+	Environment string `json:"environment,omitempty"`
+
+	// EnvironmentDomain is the domain of a custom environment.
+	EnvironmentDomain string `json:"environment_domain,omitempty"`
+}
+
+// Handheld modes.
+const (
+	// HandheldModeAuto turns handheld mode on when running on a Steam Deck
+	// or in a gamescope session. It is the default.
+	HandheldModeAuto = ""
+
+	// HandheldModeOn always launches the game in handheld mode.
+	HandheldModeOn = "on"
+
+	// HandheldModeOff never launches the game in handheld mode.
+	HandheldModeOff = "off"
+)
+
+// UI scale limits. A zero UIScale means DefaultUIScale.
+const (
+	MinUIScale     = 0.75
+	MaxUIScale     = 2.0
+	DefaultUIScale = 1.0
+)
+
+// Accessibility holds preferences the frontend applies to the user
+// interface. They are portable, so they follow the user to other machines
+// through cloud sync.
+type Accessibility struct {
+	// ReducedMotion turns off animations and transitions.
+	ReducedMotion bool `json:"reduced_motion,omitempty"`
+
+	// HighContrast uses a high-contrast colour scheme.
+	HighContrast bool `json:"high_contrast,omitempty"`
+
+	// UIScale scales the interface, from MinUIScale to MaxUIScale.
+	UIScale float64 `json:"ui_scale,omitempty"`
+}
+
+// Scale returns the UI scale, or DefaultUIScale if none is set.
+func (a Accessibility) Scale() float64 {
+	if a.UIScale == 0 {
+		return DefaultUIScale
+	}
+	return a.UIScale
+}
+
+// DownloadWindow is the time of day updates may be downloaded in the
+// background, such as when data is unmetered at night. Start and End are
+// local times of day as "15:04"; a window that ends before it starts runs
+// past midnight. Downloads the user starts are not limited.
+type DownloadWindow struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Start   string `json:"start,omitempty"`
+	End     string `json:"end,omitempty"`
+}
+
+// Validate checks that the window's times are valid and differ.
+func (w DownloadWindow) Validate() error {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return errors.New("download window start and end must differ")
+	}
+	return nil
+}
+
+// Contains reports whether background downloads are allowed at t. They
+// always are when the window is off or invalid.
+func (w DownloadWindow) Contains(t time.Time) bool {
+	if !w.Enabled || w.Validate() != nil {
+		return true
+	}
+	start, _ := parseClock(w.Start)
+	end, _ := parseClock(w.End)
+
+	m := t.Hour()*60 + t.Minute()
+	if start < end {
+		return m >= start && m < end
+	}
+	return m >= start || m < end
+}
+
+// NextOpen returns when the window next opens after t, or t if it is open.
+func (w DownloadWindow) NextOpen(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	start, _ := parseClock(w.Start)
+	open := time.Date(t.Year(), t.Month(), t.Day(), start/60, start%60, 0, 0, t.Location())
+	if !open.After(t) {
+		open = open.AddDate(0, 0, 1)
+	}
+	return open
+}
+
+// parseClock parses a "15:04" time of day into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Cloud sync targets.
+const (
+	SyncTargetAccount = "account"
+	SyncTargetWebDAV  = "webdav"
+	SyncTargetS3      = "s3"
+)
+
+// CloudSync configures where saves and settings are synced to. The secret
+// for a WebDAV or S3 target is kept in the keyring, not here.
+type CloudSync struct {
+	// Enabled turns syncing on.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Target is where data is synced to. Empty means the account's
+	// storage.
+	Target string `json:"target,omitempty"`
+
+	// URL is the WebDAV collection or S3 endpoint URL.
+	URL string `json:"url,omitempty"`
+
+	// Username is the WebDAV user name or S3 access key ID.
+	Username string `json:"username,omitempty"`
+
+	// Bucket and Region locate an S3 bucket.
+	Bucket string `json:"bucket,omitempty"`
+	Region string `json:"region,omitempty"`
+
+	// Prefix is prepended to every object name on an S3 target.
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// Portable returns the settings with the fields that only make sense on
+// this machine cleared, for syncing to other machines.
+func (s Settings) Portable() Settings {
+	s.CloudSync = CloudSync{}
+	s.DownloadWindow = DownloadWindow{}
+	s.HandheldMode = HandheldModeAuto
+	s.CertificatePinningDisabled = false
+	s.Environment = ""
+	s.EnvironmentDomain = ""
+	return s
+}
+
+// MergePortable replaces the settings with portable settings from another
+// machine, keeping the fields Portable clears.
+func (s *Settings) MergePortable(p Settings) {
+	local := *s
+	*s = p
+	s.CloudSync = local.CloudSync
+	s.DownloadWindow = local.DownloadWindow
+	s.HandheldMode = local.HandheldMode
+	s.CertificatePinningDisabled = local.CertificatePinningDisabled
+	s.Environment = local.Environment
+	s.EnvironmentDomain = local.EnvironmentDomain
+}
+
+var (
+	// mu protects current and loaded.
+	mu sync.Mutex
+
+	// current holds the settings in memory.
+	current Settings
+
+	// loaded is true once the settings file has been read.
+	loaded bool
+)
+
+// Get returns the current settings, reading them from disk on first use.
+// If the file cannot be read, the defaults are returned.
+func Get() Settings {
+	mu.Lock()
+	defer mu.Unlock()
+
+	load()
+	return current
+}
+
+// Reload discards the settings in memory, so they are read from disk again
+// on next use, such as after the file was moved aside.
+func Reload() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	loaded = false
+	current = Settings{}
+}
+
+// Path returns the path of the settings file.
+func Path() string {
+	return hytale.InStorageDir(fileName)
+}
+
+// Update changes the settings with fn and saves them. The cause is logged
+// for debugging purposes.
+func Update(cause string, fn func(*Settings)) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	load()
+	updated := current
+	fn(&updated)
+
+	slog.Debug("saving launcher settings", "cause", cause)
+	if err := write(updated); err != nil {
+		sentry.CaptureException(err)
+		return fmt.Errorf("unable to save settings: %w", err)
+	}
+	current = updated
+	return nil
+}
+
+// load reads the settings file if it has not been read yet. The caller
+// must hold mu.
+func load() {
+	if loaded {
+		return
+	}
+	loaded = true
+
+	data, err := os.ReadFile(hytale.InStorageDir(fileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &current)
+	}
+	if err != nil {
+		slog.Warn("unable to read settings, using defaults", "error", err)
+		current = Settings{}
+	}
+}
+
+// write saves settings to the settings file, replacing it atomically.
+func write(s Settings) error {
+	if err := ioutil.MkdirAll(hytale.StorageDir()); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := hytale.InStorageDir(fileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,312 @@
+// Package steam adds the launcher to Steam as a non-Steam game, so it can
+// be started from Steam's gaming mode on the Steam Deck and from Big
+// Picture. Shortcuts are stored per Steam user in a binary VDF file, which
+// Steam reads when it starts.
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"hytale-launcher/internal/build"
+)
+
+// ErrNoSteam is returned when no Steam installation with a user is found.
+var ErrNoSteam = errors.New("no Steam users found")
+
+// keptKeys are the fields of an existing shortcut that Steam or the user
+// set, such as the play time and collections. Updating the shortcut keeps
+// them.
+var keptKeys = []string{"LastPlayTime", "tags", "IsHidden"}
+
+// Shortcut is a non-Steam game entry.
+type Shortcut struct {
+	// Name is the title shown in the library.
+	Name string
+
+	// Exe is the path of the executable Steam starts.
+	Exe string
+
+	// StartDir is the working directory it is started in.
+	StartDir string
+
+	// Icon is the path of the icon shown in the library, if any.
+	Icon string
+
+	// LaunchOptions are the arguments it is started with.
+	LaunchOptions string
+}
+
+// appID returns the identifier Steam derives for a shortcut, from which
+// its artwork is named.
+func (s Shortcut) appID() uint32 {
+	return crc32.ChecksumIEEE([]byte(quote(s.Exe)+s.Name)) | 0x80000000
+}
+
+// fields returns the VDF fields Steam writes for a shortcut.
+func (s Shortcut) fields() []Field {
+	return []Field{
+		{Key: "appid", Value: s.appID()},
+		{Key: "AppName", Value: s.Name},
+		{Key: "Exe", Value: quote(s.Exe)},
+		{Key: "StartDir", Value: quote(s.StartDir)},
+		{Key: "icon", Value: s.Icon},
+		{Key: "ShortcutPath", Value: ""},
+		{Key: "LaunchOptions", Value: s.LaunchOptions},
+		{Key: "IsHidden", Value: uint32(0)},
+		{Key: "AllowDesktopConfig", Value: uint32(1)},
+		{Key: "AllowOverlay", Value: uint32(1)},
+		{Key: "OpenVR", Value: uint32(0)},
+		{Key: "Devkit", Value: uint32(0)},
+		{Key: "DevkitGameID", Value: ""},
+		{Key: "DevkitOverrideAppID", Value: uint32(0)},
+		{Key: "LastPlayTime", Value: uint32(0)},
+		{Key: "FlatpakAppID", Value: ""},
+		{Key: "tags", Value: []Field{}},
+	}
+}
+
+// quote wraps a path in double quotes, as Steam stores it.
+func quote(path string) string {
+	if path == "" {
+		return ""
+	}
+	return `"` + path + `"`
+}
+
+// unquote removes the double quotes around a stored path.
+func unquote(path string) string {
+	return strings.Trim(path, `"`)
+}
+
+// AddShortcut adds the shortcut to the library of every Steam user on this
+// machine, or updates it where one with the same executable exists.
+// Returns the number of users whose library was changed. Steam only reads
+// shortcuts when it starts, so it must be restarted to show the shortcut.
+func AddShortcut(s Shortcut) (int, error) {
+	users := userConfigDirs()
+	if len(users) == 0 {
+		return 0, ErrNoSteam
+	}
+
+	changed := 0
+	var errs []error
+	for _, dir := range users {
+		ok, err := addShortcut(filepath.Join(dir, "shortcuts.vdf"), s)
+		if err != nil {
+			slog.Warn("unable to add steam shortcut", "dir", dir, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			changed++
+		}
+	}
+	if changed == 0 && len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+
+	slog.Info("added steam shortcut", "exe", s.Exe, "users", changed)
+	return changed, nil
+}
+
+// HasShortcut reports whether any Steam user has a shortcut to exe.
+func HasShortcut(exe string) bool {
+	for _, dir := range userConfigDirs() {
+		shortcuts, err := loadShortcuts(filepath.Join(dir, "shortcuts.vdf"))
+		if err != nil {
+			continue
+		}
+		if findShortcut(shortcuts, exe) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// addShortcut adds or updates the shortcut in a shortcuts file. Returns
+// false if an identical shortcut is already there.
+func addShortcut(path string, s Shortcut) (bool, error) {
+	shortcuts, err := loadShortcuts(path)
+	if err != nil {
+		return false, err
+	}
+
+	entry := s.fields()
+	if i := findShortcut(shortcuts, s.Exe); i >= 0 {
+		old := shortcuts[i].Value.([]Field)
+		if equalFields(old, entry) {
+			return false, nil
+		}
+		for _, key := range keptKeys {
+			if v, ok := Get(old, key); ok {
+				setField(entry, key, v)
+			}
+		}
+		shortcuts[i].Value = entry
+	} else {
+		shortcuts = append(shortcuts, Field{Key: strconv.Itoa(len(shortcuts)), Value: entry})
+	}
+
+	return true, saveShortcuts(path, shortcuts)
+}
+
+// loadShortcuts returns the entries of a shortcuts file, or none if it does
+// not exist.
+func loadShortcuts(path string) ([]Field, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := readVDF(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	v, ok := Get(doc, "shortcuts")
+	if !ok {
+		return nil, nil
+	}
+	shortcuts, ok := v.([]Field)
+	if !ok {
+		return nil, fmt.Errorf("%s: shortcuts is not a map", path)
+	}
+	return shortcuts, nil
+}
+
+// saveShortcuts writes a shortcuts file, keeping the previous one as a
+// backup.
+func saveShortcuts(path string, shortcuts []Field) error {
+	data, err := writeVDF([]Field{{Key: "shortcuts", Value: shortcuts}})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if old, err := os.ReadFile(path); err == nil {
+		if err := os.WriteFile(path+".bak", old, 0o644); err != nil {
+			return fmt.Errorf("failed to back up shortcuts: %w", err)
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// findShortcut returns the index of the entry that starts exe, or -1.
+func findShortcut(shortcuts []Field, exe string) int {
+	for i, f := range shortcuts {
+		entry, ok := f.Value.([]Field)
+		if !ok {
+			continue
+		}
+		// Older Steam versions wrote the key in lower case.
+		v, ok := Get(entry, "Exe")
+		if !ok {
+			v, _ = Get(entry, "exe")
+		}
+		if s, ok := v.(string); ok && samePath(unquote(s), exe) {
+			return i
+		}
+	}
+	return -1
+}
+
+// samePath reports whether two paths name the same file.
+func samePath(a, b string) bool {
+	if build.OS() == "windows" {
+		return strings.EqualFold(filepath.Clean(a), filepath.Clean(b))
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+// equalFields reports whether every field of want, other than the kept
+// ones, has the same value in got.
+func equalFields(got, want []Field) bool {
+	for _, f := range want {
+		if slices.Contains(keptKeys, f.Key) {
+			continue
+		}
+		if v, ok := Get(got, f.Key); !ok || v != f.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// setField replaces the value of the field with the given key.
+func setField(fields []Field, key string, value any) {
+	for i := range fields {
+		if fields[i].Key == key {
+			fields[i].Value = value
+			return
+		}
+	}
+}
+
+// userConfigDirs returns the config directory of every Steam user on this
+// machine.
+func userConfigDirs() []string {
+	var dirs []string
+	seen := map[string]bool{}
+	for _, root := range steamRoots() {
+		if resolved, err := filepath.EvalSymlinks(root); err == nil {
+			root = resolved
+		}
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+
+		entries, err := os.ReadDir(filepath.Join(root, "userdata"))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			// "0" holds settings shared by every user, not a user.
+			if id, err := strconv.ParseUint(e.Name(), 10, 32); err != nil || id == 0 || !e.IsDir() {
+				continue
+			}
+			dirs = append(dirs, filepath.Join(root, "userdata", e.Name(), "config"))
+		}
+	}
+	return dirs
+}
+
+// steamRoots returns the directories Steam may be installed in.
+func steamRoots() []string {
+	home, _ := os.UserHomeDir()
+	switch build.OS() {
+	case "windows":
+		var roots []string
+		for _, env := range []string{"ProgramFiles(x86)", "ProgramFiles"} {
+			if dir := os.Getenv(env); dir != "" {
+				roots = append(roots, filepath.Join(dir, "Steam"))
+			}
+		}
+		return roots
+	case "darwin":
+		return []string{filepath.Join(home, "Library", "Application Support", "Steam")}
+	default:
+		return []string{
+			filepath.Join(home, ".steam", "steam"),
+			filepath.Join(home, ".local", "share", "Steam"),
+			filepath.Join(home, ".var", "app", "com.valvesoftware.Steam", ".local", "share", "Steam"),
+		}
+	}
+}
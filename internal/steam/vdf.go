@@ -0,0 +1,141 @@
+package steam
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Binary VDF value types.
+const (
+	typeMap    = 0x00
+	typeString = 0x01
+	typeInt    = 0x02
+	typeEnd    = 0x08
+)
+
+// Field is one key and value of a binary VDF map. Value is a string, a
+// uint32 or a []Field for a nested map. Maps are kept as ordered fields so
+// a file is written back the way Steam wrote it.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Get returns the value of the first field with the given key.
+func Get(fields []Field, key string) (any, bool) {
+	for _, f := range fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// readVDF parses a binary VDF document.
+func readVDF(data []byte) ([]Field, error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+	fields, err := readMap(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vdf: %w", err)
+	}
+	return fields, nil
+}
+
+// readMap reads fields up to the end of the current map, or of the
+// document at the top level.
+func readMap(r *bufio.Reader) ([]Field, error) {
+	var fields []Field
+	for {
+		t, err := r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return fields, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if t == typeEnd {
+			return fields, nil
+		}
+
+		key, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var value any
+		switch t {
+		case typeMap:
+			value, err = readMap(r)
+		case typeString:
+			value, err = readString(r)
+		case typeInt:
+			var n uint32
+			err = binary.Read(r, binary.LittleEndian, &n)
+			value = n
+		default:
+			return nil, fmt.Errorf("unsupported value type 0x%02x for %q", t, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, Field{Key: key, Value: value})
+	}
+}
+
+// readString reads a NUL-terminated string.
+func readString(r *bufio.Reader) (string, error) {
+	s, err := r.ReadString(0)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return "", io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	return s[:len(s)-1], nil
+}
+
+// writeVDF encodes fields as a binary VDF document.
+func writeVDF(fields []Field) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeMap(&buf, fields); err != nil {
+		return nil, err
+	}
+	buf.WriteByte(typeEnd)
+	return buf.Bytes(), nil
+}
+
+// writeMap writes fields without the end marker of their map.
+func writeMap(buf *bytes.Buffer, fields []Field) error {
+	for _, f := range fields {
+		switch v := f.Value.(type) {
+		case []Field:
+			buf.WriteByte(typeMap)
+			writeString(buf, f.Key)
+			if err := writeMap(buf, v); err != nil {
+				return err
+			}
+			buf.WriteByte(typeEnd)
+		case string:
+			buf.WriteByte(typeString)
+			writeString(buf, f.Key)
+			writeString(buf, v)
+		case uint32:
+			buf.WriteByte(typeInt)
+			writeString(buf, f.Key)
+			binary.Write(buf, binary.LittleEndian, v)
+		default:
+			return fmt.Errorf("unsupported value %T for %q", f.Value, f.Key)
+		}
+	}
+	return nil
+}
+
+// writeString writes a NUL-terminated string.
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteString(s)
+	buf.WriteByte(0)
+}
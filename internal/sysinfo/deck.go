@@ -0,0 +1,113 @@
+// Package sysinfo detects properties of the system the launcher is running on,
+// such as handheld hardware and the desktop session type.
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"hytale-launcher/internal/build"
+)
+
+// Steam Deck DMI identifiers. "Jupiter" is the LCD model, "Galileo" the OLED model.
+var deckProducts = []string{"Jupiter", "Galileo"}
+
+// Native resolution of the Steam Deck's panel, on both models.
+const (
+	deckWidth  = 1280
+	deckHeight = 800
+)
+
+const (
+	dmiDir         = "/sys/devices/virtual/dmi/id"
+	powerSupplyDir = "/sys/class/power_supply"
+)
+
+// readSysFile returns the trimmed contents of a sysfs file, or an empty string
+// if it cannot be read.
+func readSysFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+var isSteamDeck = sync.OnceValue(func() bool {
+	// Steam sets this for every process it starts on the Deck.
+	if os.Getenv("SteamDeck") == "1" {
+		return true
+	}
+
+	if build.OS() != "linux" {
+		return false
+	}
+
+	if readSysFile(filepath.Join(dmiDir, "board_vendor")) != "Valve" {
+		return false
+	}
+
+	product := readSysFile(filepath.Join(dmiDir, "product_name"))
+	for _, p := range deckProducts {
+		if product == p {
+			return true
+		}
+	}
+	return false
+})
+
+// IsSteamDeck returns true if the launcher is running on Steam Deck hardware.
+// The result is computed once and cached.
+func IsSteamDeck() bool {
+	return isSteamDeck()
+}
+
+// DeckResolution returns the native resolution of the Steam Deck's panel.
+func DeckResolution() (width, height int) {
+	return deckWidth, deckHeight
+}
+
+// IsGamescope returns true if the launcher is running inside a gamescope
+// session, such as the Steam Deck's gaming mode.
+func IsGamescope() bool {
+	if _, ok := os.LookupEnv("GAMESCOPE_WAYLAND_DISPLAY"); ok {
+		return true
+	}
+	return strings.EqualFold(os.Getenv("XDG_CURRENT_DESKTOP"), "gamescope")
+}
+
+// IsBigPicture returns true if the launcher should present a controller-friendly
+// interface. This is the case on Steam Deck hardware, inside gamescope, and when
+// started from Steam's Big Picture mode.
+func IsBigPicture() bool {
+	if os.Getenv("SteamGamepadUI") == "1" {
+		return true
+	}
+	return IsSteamDeck() || IsGamescope()
+}
+
+// OnBattery returns true if the system reports a battery that is currently
+// discharging. It always returns false on platforms other than Linux.
+func OnBattery() bool {
+	if build.OS() != "linux" {
+		return false
+	}
+
+	entries, err := os.ReadDir(powerSupplyDir)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		dir := filepath.Join(powerSupplyDir, entry.Name())
+		if readSysFile(filepath.Join(dir, "type")) != "Battery" {
+			continue
+		}
+		if readSysFile(filepath.Join(dir, "status")) == "Discharging" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package sysinfo
+
+import (
+	"os"
+	"strings"
+
+	"hytale-launcher/internal/build"
+)
+
+// DisplayServer identifies the display server of the current desktop session.
+type DisplayServer string
+
+const (
+	// DisplayUnknown is reported on platforms without a choice of display
+	// server, or when the session type cannot be determined.
+	DisplayUnknown DisplayServer = ""
+
+	// DisplayWayland indicates a Wayland session.
+	DisplayWayland DisplayServer = "wayland"
+
+	// DisplayX11 indicates an X11 session.
+	DisplayX11 DisplayServer = "x11"
+)
+
+// CurrentDisplayServer returns the display server of the current session.
+// It always returns DisplayUnknown on platforms other than Linux.
+func CurrentDisplayServer() DisplayServer {
+	if build.OS() != "linux" {
+		return DisplayUnknown
+	}
+
+	switch strings.ToLower(os.Getenv("XDG_SESSION_TYPE")) {
+	case "wayland":
+		return DisplayWayland
+	case "x11":
+		return DisplayX11
+	}
+
+	// XDG_SESSION_TYPE is not set in every session (e.g. when started
+	// from a terminal multiplexer), so fall back to the display variables.
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		return DisplayWayland
+	}
+	if os.Getenv("DISPLAY") != "" {
+		return DisplayX11
+	}
+
+	return DisplayUnknown
+}
+
+// HasXWayland returns true if X11 applications can run in the current session,
+// either natively or through XWayland.
+func HasXWayland() bool {
+	return os.Getenv("DISPLAY") != ""
+}
+
+// IsNixOS returns true if the launcher is running on NixOS, where graphics
+// drivers live outside the standard library paths.
+func IsNixOS() bool {
+	_, err := os.Stat("/etc/NIXOS")
+	return err == nil
+}
+
+// hasNvidiaDriver returns true if the proprietary NVIDIA kernel driver is loaded.
+func hasNvidiaDriver() bool {
+	_, err := os.Stat("/proc/driver/nvidia/version")
+	return err == nil
+}
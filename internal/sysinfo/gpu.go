@@ -0,0 +1,83 @@
+package sysinfo
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// GPU vendor identifiers.
+const (
+	VendorNvidia  = "nvidia"
+	VendorAMD     = "amd"
+	VendorIntel   = "intel"
+	VendorApple   = "apple"
+	VendorUnknown = "unknown"
+)
+
+// GPU describes a graphics adapter and its driver.
+type GPU struct {
+	// Vendor is one of the Vendor* constants.
+	Vendor string `json:"vendor"`
+
+	// Model is the adapter name reported by the system, if available.
+	Model string `json:"model,omitempty"`
+
+	// Driver is the name of the kernel or display driver, if available.
+	Driver string `json:"driver,omitempty"`
+
+	// DriverVersion is the driver version in the format used by the
+	// vendor on this platform, if available.
+	DriverVersion string `json:"driver_version,omitempty"`
+}
+
+var gpus = sync.OnceValue(detectGPUs)
+
+// GPUs returns the graphics adapters present in the system.
+// Detection runs once and the result is cached, since on some platforms
+// it requires starting an external process.
+func GPUs() []GPU {
+	return gpus()
+}
+
+// vendorFromName guesses the vendor from an adapter or manufacturer name.
+func vendorFromName(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "nvidia"):
+		return VendorNvidia
+	case strings.Contains(lower, "amd"), strings.Contains(lower, "advanced micro devices"), strings.Contains(lower, "radeon"):
+		return VendorAMD
+	case strings.Contains(lower, "intel"):
+		return VendorIntel
+	case strings.Contains(lower, "apple"):
+		return VendorApple
+	default:
+		return VendorUnknown
+	}
+}
+
+// CompareVersions compares two dot-separated version strings numerically.
+// It returns -1 if a < b, 0 if a == b, and 1 if a > b. Missing components
+// are treated as zero and non-numeric components compare as zero.
+func CompareVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(strings.TrimSpace(as[i]))
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(strings.TrimSpace(bs[i]))
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,46 @@
+//go:build darwin
+
+package sysinfo
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os/exec"
+)
+
+// displaysReport mirrors the relevant parts of system_profiler's JSON output.
+type displaysReport struct {
+	Displays []struct {
+		Model  string `json:"sppci_model"`
+		Vendor string `json:"spdisplays_vendor"`
+	} `json:"SPDisplaysDataType"`
+}
+
+// detectGPUs queries system_profiler for the installed graphics adapters.
+// Drivers ship with macOS, so no driver version is reported.
+func detectGPUs() []GPU {
+	out, err := exec.Command("system_profiler", "SPDisplaysDataType", "-json").Output()
+	if err != nil {
+		slog.Debug("unable to query displays", "error", err)
+		return nil
+	}
+
+	var report displaysReport
+	if err := json.Unmarshal(out, &report); err != nil {
+		slog.Debug("unable to parse displays", "error", err)
+		return nil
+	}
+
+	result := make([]GPU, 0, len(report.Displays))
+	for _, d := range report.Displays {
+		vendor := vendorFromName(d.Vendor)
+		if vendor == VendorUnknown {
+			vendor = vendorFromName(d.Model)
+		}
+		result = append(result, GPU{
+			Vendor: vendor,
+			Model:  d.Model,
+		})
+	}
+	return result
+}
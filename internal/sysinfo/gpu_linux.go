@@ -0,0 +1,96 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	nvidiaVersionFile = "/proc/driver/nvidia/version"
+	nvidiaGPUsDir     = "/proc/driver/nvidia/gpus"
+	drmDir            = "/sys/class/drm"
+)
+
+// PCI vendor IDs as reported in sysfs.
+var pciVendors = map[string]string{
+	"0x10de": VendorNvidia,
+	"0x1002": VendorAMD,
+	"0x8086": VendorIntel,
+}
+
+// detectGPUs enumerates DRM devices from sysfs and fills in the driver
+// version from the driver's module information where available.
+func detectGPUs() []GPU {
+	cards, _ := filepath.Glob(filepath.Join(drmDir, "card[0-9]*"))
+
+	var result []GPU
+	seen := make(map[string]bool)
+
+	for _, card := range cards {
+		// Skip connectors such as card0-HDMI-A-1.
+		if strings.Contains(filepath.Base(card), "-") {
+			continue
+		}
+
+		device := filepath.Join(card, "device")
+		if real, err := filepath.EvalSymlinks(device); err == nil {
+			if seen[real] {
+				continue
+			}
+			seen[real] = true
+		}
+
+		gpu := GPU{Vendor: VendorUnknown}
+		if v, ok := pciVendors[readSysFile(filepath.Join(device, "vendor"))]; ok {
+			gpu.Vendor = v
+		}
+
+		if driver, err := os.Readlink(filepath.Join(device, "driver")); err == nil {
+			gpu.Driver = filepath.Base(driver)
+			gpu.DriverVersion = readSysFile(filepath.Join("/sys/module", gpu.Driver, "version"))
+		}
+
+		if gpu.Vendor == VendorNvidia {
+			gpu.Model = nvidiaModel()
+			if v := nvidiaDriverVersion(); v != "" {
+				gpu.DriverVersion = v
+			}
+		}
+
+		result = append(result, gpu)
+	}
+
+	return result
+}
+
+// nvidiaDriverVersion parses the proprietary driver version, e.g. "550.54.14",
+// from the NVRM version line.
+func nvidiaDriverVersion() string {
+	line := readSysFile(nvidiaVersionFile)
+	line, _, _ = strings.Cut(line, "\n")
+
+	fields := strings.Fields(line)
+	for _, f := range fields {
+		if strings.Count(f, ".") >= 1 && f[0] >= '0' && f[0] <= '9' {
+			return f
+		}
+	}
+	return ""
+}
+
+// nvidiaModel returns the model name of the first GPU known to the
+// proprietary NVIDIA driver.
+func nvidiaModel() string {
+	infos, _ := filepath.Glob(filepath.Join(nvidiaGPUsDir, "*", "information"))
+	for _, info := range infos {
+		for _, line := range strings.Split(readSysFile(info), "\n") {
+			if key, value, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(key) == "Model" {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+	return ""
+}
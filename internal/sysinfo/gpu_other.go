@@ -0,0 +1,8 @@
+//go:build !linux && !windows && !darwin
+
+package sysinfo
+
+// detectGPUs is not supported on this platform.
+func detectGPUs() []GPU {
+	return nil
+}
@@ -0,0 +1,55 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os/exec"
+	"syscall"
+)
+
+// videoController mirrors the fields selected from Win32_VideoController.
+type videoController struct {
+	Name                 string `json:"Name"`
+	AdapterCompatibility string `json:"AdapterCompatibility"`
+	DriverVersion        string `json:"DriverVersion"`
+}
+
+// detectGPUs queries WMI for the installed video controllers.
+func detectGPUs() []GPU {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command",
+		"Get-CimInstance Win32_VideoController | Select-Object Name,AdapterCompatibility,DriverVersion | ConvertTo-Json")
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+
+	out, err := cmd.Output()
+	if err != nil {
+		slog.Debug("unable to query video controllers", "error", err)
+		return nil
+	}
+
+	// ConvertTo-Json emits a bare object when there is a single controller.
+	var controllers []videoController
+	if err := json.Unmarshal(out, &controllers); err != nil {
+		var single videoController
+		if err := json.Unmarshal(out, &single); err != nil {
+			slog.Debug("unable to parse video controllers", "error", err)
+			return nil
+		}
+		controllers = []videoController{single}
+	}
+
+	result := make([]GPU, 0, len(controllers))
+	for _, c := range controllers {
+		vendor := vendorFromName(c.AdapterCompatibility)
+		if vendor == VendorUnknown {
+			vendor = vendorFromName(c.Name)
+		}
+		result = append(result, GPU{
+			Vendor:        vendor,
+			Model:         c.Name,
+			DriverVersion: c.DriverVersion,
+		})
+	}
+	return result
+}
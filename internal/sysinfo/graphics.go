@@ -0,0 +1,26 @@
+package sysinfo
+
+// GraphicsProbe is what a basic check of the graphics stack found, without
+// creating a rendering context.
+type GraphicsProbe struct {
+	// Supported is set if the stack can be probed on this platform. The
+	// other fields are only meaningful when it is.
+	Supported bool `json:"supported"`
+
+	// RenderNode is set if the kernel exposes a GPU device for rendering,
+	// so rendering is not left to the CPU.
+	RenderNode bool `json:"render_node"`
+
+	// OpenGL is set if an OpenGL library is installed.
+	OpenGL bool `json:"opengl"`
+
+	// Vulkan is set if the Vulkan loader and at least one Vulkan driver
+	// are installed.
+	Vulkan bool `json:"vulkan"`
+}
+
+// ProbeGraphics checks that the graphics drivers and libraries the game
+// renders with are installed.
+func ProbeGraphics() GraphicsProbe {
+	return probeGraphics()
+}
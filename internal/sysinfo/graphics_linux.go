@@ -0,0 +1,110 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// libDirs are the directories searched for graphics libraries, after those
+// in LD_LIBRARY_PATH. NixOS links the system's drivers under
+// /run/opengl-driver.
+var libDirs = []string{
+	"/run/opengl-driver/lib",
+	"/usr/lib/x86_64-linux-gnu",
+	"/usr/lib/aarch64-linux-gnu",
+	"/usr/lib64",
+	"/usr/lib",
+	"/lib64",
+	"/lib",
+}
+
+// icdDirs hold the manifests of the installed Vulkan drivers.
+var icdDirs = []string{
+	"/run/opengl-driver/share/vulkan/icd.d",
+	"/etc/vulkan/icd.d",
+	"/usr/share/vulkan/icd.d",
+	"/usr/local/share/vulkan/icd.d",
+}
+
+// probeGraphics looks for a DRM render node or NVIDIA device, and for the
+// OpenGL and Vulkan libraries in the library search path or the dynamic
+// loader's cache.
+func probeGraphics() GraphicsProbe {
+	nodes, _ := filepath.Glob("/dev/dri/renderD*")
+	_, nvidiaErr := os.Stat("/dev/nvidia0")
+
+	dirs := append(filepath.SplitList(os.Getenv("LD_LIBRARY_PATH")), libDirs...)
+
+	return GraphicsProbe{
+		Supported:  true,
+		RenderNode: len(nodes) > 0 || nvidiaErr == nil,
+		OpenGL:     findLibrary(dirs, "libGL.so.1") || findLibrary(dirs, "libEGL.so.1"),
+		Vulkan:     findLibrary(dirs, "libvulkan.so.1") && hasVulkanDriver(),
+	}
+}
+
+// findLibrary reports whether a shared library is in one of dirs, or known
+// to the dynamic loader.
+func findLibrary(dirs []string, name string) bool {
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return loaderCacheHas(name)
+}
+
+// loaderCache is the list of libraries in the dynamic loader's cache, as
+// printed by "ldconfig -p", or empty if it cannot be read.
+var loaderCache = sync.OnceValue(func() string {
+	for _, ldconfig := range []string{"ldconfig", "/sbin/ldconfig", "/usr/sbin/ldconfig"} {
+		path, err := exec.LookPath(ldconfig)
+		if err != nil {
+			continue
+		}
+		out, err := exec.Command(path, "-p").Output()
+		if err == nil {
+			return string(out)
+		}
+	}
+	return ""
+})
+
+// loaderCacheHas reports whether the dynamic loader's cache lists a
+// library, whose entries read "\tlibGL.so.1 (libc6,x86-64) => /path".
+func loaderCacheHas(name string) bool {
+	for _, line := range strings.Split(loaderCache(), "\n") {
+		if lib, _, ok := strings.Cut(strings.TrimSpace(line), " "); ok && lib == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasVulkanDriver reports whether a Vulkan driver manifest is installed.
+func hasVulkanDriver() bool {
+	dirs := icdDirs
+	if env := os.Getenv("VK_ICD_FILENAMES"); env != "" {
+		return true
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".local/share/vulkan/icd.d"))
+	}
+	for _, dir := range dirs {
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			if strings.HasSuffix(e.Name(), ".json") {
+				return true
+			}
+		}
+	}
+	return false
+}
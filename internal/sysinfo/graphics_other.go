@@ -0,0 +1,9 @@
+//go:build !linux
+
+package sysinfo
+
+// probeGraphics is not supported on this platform, where the graphics
+// stack ships with the system.
+func probeGraphics() GraphicsProbe {
+	return GraphicsProbe{}
+}
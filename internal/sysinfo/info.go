@@ -0,0 +1,72 @@
+package sysinfo
+
+import (
+	"hytale-launcher/internal/build"
+)
+
+// Warning describes a known issue with the current system configuration.
+type Warning struct {
+	// Code is a stable identifier for the issue, for use by the frontend.
+	Code string `json:"code"`
+
+	// Message is a human-readable description of the issue.
+	Message string `json:"message"`
+}
+
+// Info is a snapshot of the system the launcher is running on.
+type Info struct {
+	OS            string        `json:"os"`
+	Arch          string        `json:"arch"`
+	DisplayServer DisplayServer `json:"display_server,omitempty"`
+	SteamDeck     bool          `json:"steam_deck"`
+	Gamescope     bool          `json:"gamescope"`
+	NixOS         bool          `json:"nixos,omitempty"`
+	GPUs          []GPU         `json:"gpus,omitempty"`
+	MemoryBytes   uint64        `json:"memory_bytes"`
+	CPUs          int           `json:"cpus"`
+	Warnings      []Warning     `json:"warnings,omitempty"`
+}
+
+// Collect gathers information about the current system, including warnings
+// for configurations with known rendering issues.
+func Collect() Info {
+	info := Info{
+		OS:            build.OS(),
+		Arch:          build.Arch(),
+		DisplayServer: CurrentDisplayServer(),
+		SteamDeck:     IsSteamDeck(),
+		Gamescope:     IsGamescope(),
+		GPUs:          GPUs(),
+		MemoryBytes:   TotalMemory(),
+		CPUs:          CPUCount(),
+	}
+
+	if info.OS == "linux" {
+		info.NixOS = IsNixOS()
+	}
+
+	info.Warnings = knownIssues(info)
+	return info
+}
+
+// knownIssues returns warnings for system configurations known to cause problems.
+func knownIssues(info Info) []Warning {
+	var warnings []Warning
+
+	if info.DisplayServer == DisplayWayland && !info.Gamescope {
+		if hasNvidiaDriver() {
+			warnings = append(warnings, Warning{
+				Code:    "nvidia_wayland",
+				Message: "NVIDIA drivers on Wayland can cause flickering or stutter. If you see rendering issues, try an X11 session or update your driver.",
+			})
+		}
+		if !HasXWayland() {
+			warnings = append(warnings, Warning{
+				Code:    "no_xwayland",
+				Message: "XWayland is not available in this session. The game will run as a native Wayland client.",
+			})
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,19 @@
+package sysinfo
+
+import (
+	"runtime"
+	"sync"
+)
+
+var totalMemory = sync.OnceValue(detectTotalMemory)
+
+// TotalMemory returns the amount of physical memory in bytes, or zero if it
+// cannot be determined.
+func TotalMemory() uint64 {
+	return totalMemory()
+}
+
+// CPUCount returns the number of logical CPUs available to the launcher.
+func CPUCount() int {
+	return runtime.NumCPU()
+}
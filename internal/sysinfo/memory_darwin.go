@@ -0,0 +1,14 @@
+//go:build darwin
+
+package sysinfo
+
+import "golang.org/x/sys/unix"
+
+// detectTotalMemory reads the hw.memsize sysctl.
+func detectTotalMemory() uint64 {
+	size, err := unix.SysctlUint64("hw.memsize")
+	if err != nil {
+		return 0
+	}
+	return size
+}
@@ -0,0 +1,30 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// detectTotalMemory reads MemTotal from /proc/meminfo.
+func detectTotalMemory() uint64 {
+	for _, line := range strings.Split(readSysFile("/proc/meminfo"), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || key != "MemTotal" {
+			continue
+		}
+
+		// The value is reported in kibibytes, e.g. "16314832 kB".
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			return 0
+		}
+		kb, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return 0
+		}
+		return kb * 1024
+	}
+	return 0
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !windows && !darwin
+
+package sysinfo
+
+// detectTotalMemory is not supported on this platform.
+func detectTotalMemory() uint64 {
+	return 0
+}
@@ -0,0 +1,117 @@
+package sysinfo
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"hytale-launcher/internal/build"
+)
+
+// Sandbox identifies an application sandbox the launcher runs in.
+type Sandbox string
+
+const (
+	// SandboxNone means the launcher is not sandboxed.
+	SandboxNone Sandbox = ""
+
+	// SandboxFlatpak means the launcher runs as a Flatpak.
+	SandboxFlatpak Sandbox = "flatpak"
+
+	// SandboxSnap means the launcher runs as a Snap.
+	SandboxSnap Sandbox = "snap"
+)
+
+// CurrentSandbox returns the sandbox the launcher runs in. Sandboxed
+// launchers cannot start programs on the host, such as the browser, and
+// only see part of its file system, so they go through XDG desktop portals
+// instead. It always returns SandboxNone on platforms other than Linux.
+func CurrentSandbox() Sandbox {
+	if build.OS() != "linux" {
+		return SandboxNone
+	}
+	if os.Getenv("FLATPAK_ID") != "" {
+		return SandboxFlatpak
+	}
+	if _, err := os.Stat("/.flatpak-info"); err == nil {
+		return SandboxFlatpak
+	}
+	if os.Getenv("SNAP_NAME") != "" && os.Getenv("SNAP") != "" {
+		return SandboxSnap
+	}
+	return SandboxNone
+}
+
+// SandboxDataDir returns the directory a sandboxed launcher keeps its data
+// in, in place of XDG_DATA_HOME, or an empty string if it is not sandboxed.
+// A Snap's home directory changes with each revision, and its data is
+// copied on every refresh, so the directory shared by all revisions is
+// used. A Flatpak gets its own XDG_DATA_HOME, which is only derived here
+// when it is missing.
+func SandboxDataDir() string {
+	switch CurrentSandbox() {
+	case SandboxSnap:
+		return snapDataDir()
+	case SandboxFlatpak:
+		if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+			return dir
+		}
+		id := os.Getenv("FLATPAK_ID")
+		home, err := os.UserHomeDir()
+		if id == "" || err != nil {
+			return ""
+		}
+		return filepath.Join(home, ".var", "app", id, "data")
+	}
+	return ""
+}
+
+// storageDirName is the name of the launcher's storage directory in the
+// data directory.
+const storageDirName = "hytale"
+
+// snapDataDir returns the data directory of a Snap, SNAP_USER_COMMON.
+// Launchers that kept their data in the revision's home directory, as
+// earlier versions did, have it moved there the first time. If it cannot
+// be moved, the old directory is kept in use.
+var snapDataDir = sync.OnceValue(func() string {
+	common := os.Getenv("SNAP_USER_COMMON")
+	legacy := legacySnapDataDir()
+	if common == "" || legacy == "" {
+		return common
+	}
+
+	oldDir := filepath.Join(legacy, storageDirName)
+	newDir := filepath.Join(common, storageDirName)
+	if _, err := os.Stat(oldDir); err != nil {
+		return common
+	}
+	if _, err := os.Stat(newDir); err == nil {
+		return common
+	}
+
+	if err := os.Rename(oldDir, newDir); err != nil {
+		slog.Warn("unable to move snap data, keeping it in place",
+			"from", oldDir,
+			"to", newDir,
+			"error", err,
+		)
+		return legacy
+	}
+	slog.Info("moved snap data", "from", oldDir, "to", newDir)
+	return common
+})
+
+// legacySnapDataDir returns the data directory inside a Snap's home
+// directory, which changes with each revision.
+func legacySnapDataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	home := os.Getenv("SNAP_USER_DATA")
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".local", "share")
+}
@@ -0,0 +1,22 @@
+package system
+
+import "time"
+
+// Clock tells the time and waits.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+}
+
+// RealClock is the system clock.
+type RealClock struct{}
+
+// Now returns time.Now.
+func (RealClock) Now() time.Time { return time.Now() }
+
+// Since returns time.Since.
+func (RealClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+// After returns time.After.
+func (RealClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
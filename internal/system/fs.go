@@ -0,0 +1,73 @@
+package system
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// FS is a writable file system addressed by operating system paths. Reads
+// follow io/fs; writes follow the os package.
+//
+// Helpers outside the packages that take an FS, such as ioutil, still use
+// the real file system, so a replacement is expected to wrap OS, for
+// example to inject write failures, rather than be fully in memory.
+type FS interface {
+	fs.StatFS
+	fs.ReadFileFS
+	fs.ReadDirFS
+
+	OpenFile(name string, flag int, perm fs.FileMode) (File, error)
+	Create(name string) (File, error)
+	CreateTemp(dir, pattern string) (File, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+
+	MkdirAll(path string, perm fs.FileMode) error
+	Remove(name string) error
+	RemoveAll(path string) error
+	Rename(oldpath, newpath string) error
+	Link(oldname, newname string) error
+	Chtimes(name string, atime, mtime time.Time) error
+}
+
+// File is an open file in an FS. *os.File implements it.
+type File interface {
+	fs.File
+	io.Writer
+	io.Seeker
+
+	Name() string
+	Sync() error
+	Truncate(size int64) error
+}
+
+// OS is the operating system's file system.
+type OS struct{}
+
+func (OS) Open(name string) (fs.File, error)          { return os.Open(name) }
+func (OS) Stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
+func (OS) ReadFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+func (OS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+
+func (OS) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+func (OS) Create(name string) (File, error) { return os.Create(name) }
+
+func (OS) CreateTemp(dir, pattern string) (File, error) { return os.CreateTemp(dir, pattern) }
+
+func (OS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (OS) MkdirAll(path string, perm fs.FileMode) error { return os.MkdirAll(path, perm) }
+func (OS) Remove(name string) error                     { return os.Remove(name) }
+func (OS) RemoveAll(path string) error                  { return os.RemoveAll(path) }
+func (OS) Rename(oldpath, newpath string) error         { return os.Rename(oldpath, newpath) }
+func (OS) Link(oldname, newname string) error           { return os.Link(oldname, newname) }
+
+func (OS) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(name, atime, mtime)
+}
@@ -0,0 +1,37 @@
+package system
+
+import "net/http"
+
+// HTTPDoer sends HTTP requests. *http.Client implements it.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// defaultHTTP sends requests with http.DefaultClient, looked up on each
+// request so changes to it, such as a proxy transport, apply.
+type defaultHTTP struct{}
+
+func (defaultHTTP) Do(req *http.Request) (*http.Response, error) {
+	return http.DefaultClient.Do(req)
+}
+
+// HTTPClient returns an *http.Client that sends requests through d, for
+// APIs that require a client rather than an HTTPDoer.
+func HTTPClient(d HTTPDoer) *http.Client {
+	if c, ok := d.(*http.Client); ok {
+		return c
+	}
+	if _, ok := d.(defaultHTTP); ok {
+		return http.DefaultClient
+	}
+	return &http.Client{Transport: doerTransport{d}}
+}
+
+// doerTransport adapts an HTTPDoer to an http.RoundTripper.
+type doerTransport struct {
+	d HTTPDoer
+}
+
+func (t doerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.d.Do(req)
+}
@@ -0,0 +1,24 @@
+// Package system abstracts the clock, file system and HTTP client behind
+// interfaces, so packages that depend on them can be exercised against
+// simulated token expiry, full disks and failing networks.
+//
+// Packages keep an unexported System, set to Real, and use it in place of
+// the time, os and net/http functions. Tests in those packages replace it.
+package system
+
+// System bundles the dependencies a package takes from its environment.
+type System struct {
+	Clock Clock
+	FS    FS
+	HTTP  HTTPDoer
+}
+
+// Real returns the system clock, the operating system's file system and
+// http.DefaultClient.
+func Real() System {
+	return System{
+		Clock: RealClock{},
+		FS:    OS{},
+		HTTP:  defaultHTTP{},
+	}
+}
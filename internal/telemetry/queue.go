@@ -0,0 +1,129 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+
+	"hytale-launcher/internal/hytale"
+)
+
+const (
+	// queueFileName is the file in the storage directory that holds
+	// events not yet sent, so they survive a restart.
+	queueFileName = "telemetry-queue.json"
+
+	// maxQueued is the maximum number of events kept. The oldest events
+	// are dropped when the queue is full.
+	maxQueued = 1000
+)
+
+// eventQueue holds events waiting to be sent, mirrored to disk.
+type eventQueue struct {
+	mu     sync.Mutex
+	events []Event
+	loaded bool
+}
+
+// queue is the process-wide event queue.
+var queue eventQueue
+
+// load reads events queued by a previous session.
+func (q *eventQueue) load() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.loadLocked()
+}
+
+// loadLocked reads the queue file once. The caller must hold mu.
+func (q *eventQueue) loadLocked() {
+	if q.loaded {
+		return
+	}
+	q.loaded = true
+
+	data, err := os.ReadFile(hytale.InStorageDir(queueFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	var saved []Event
+	if err == nil {
+		err = json.Unmarshal(data, &saved)
+	}
+	if err != nil {
+		slog.Warn("unable to read telemetry queue, discarding it", "error", err)
+		return
+	}
+	q.events = append(saved, q.events...)
+	q.trimLocked()
+}
+
+// add appends an event and saves the queue.
+func (q *eventQueue) add(e Event) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.loadLocked()
+	q.events = append(q.events, e)
+	q.trimLocked()
+	q.saveLocked()
+}
+
+// peek returns up to n of the oldest events without removing them.
+func (q *eventQueue) peek(n int) []Event {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.loadLocked()
+	n = min(n, len(q.events))
+	return append([]Event(nil), q.events[:n]...)
+}
+
+// remove removes the n oldest events and saves the queue.
+func (q *eventQueue) remove(n int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n = min(n, len(q.events))
+	q.events = q.events[n:]
+	q.saveLocked()
+}
+
+// clear removes all events and the queue file.
+func (q *eventQueue) clear() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.loaded = true
+	q.events = nil
+	if err := os.Remove(hytale.InStorageDir(queueFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Warn("unable to remove telemetry queue", "error", err)
+	}
+}
+
+// trimLocked drops the oldest events past maxQueued. The caller must hold mu.
+func (q *eventQueue) trimLocked() {
+	if len(q.events) > maxQueued {
+		q.events = q.events[len(q.events)-maxQueued:]
+	}
+}
+
+// saveLocked writes the queue to disk. The caller must hold mu.
+func (q *eventQueue) saveLocked() {
+	path := hytale.InStorageDir(queueFileName)
+	if len(q.events) == 0 {
+		os.Remove(path)
+		return
+	}
+
+	data, err := json.Marshal(q.events)
+	if err != nil {
+		slog.Warn("unable to encode telemetry queue", "error", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		slog.Warn("unable to write telemetry queue", "error", err)
+	}
+}
@@ -0,0 +1,195 @@
+// Package telemetry collects anonymized launcher metrics, such as update
+// durations, download throughput and failure codes, and sends them in
+// batches. Nothing is recorded or sent unless the user has turned on
+// settings.TelemetryEnabled; turning it off discards anything queued.
+//
+// Events carry no account, profile or machine identifiers, no paths and no
+// error messages, only the launcher version, platform and the values
+// recorded.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/endpoints"
+	"hytale-launcher/internal/net"
+	"hytale-launcher/internal/remoteconfig"
+	"hytale-launcher/internal/settings"
+)
+
+const (
+	// flushInterval is how often queued events are sent.
+	flushInterval = 10 * time.Minute
+
+	// batchSize is the maximum number of events sent per request.
+	batchSize = 100
+
+	// sendTimeout bounds a single send request.
+	sendTimeout = 30 * time.Second
+)
+
+// Event is a single metric.
+type Event struct {
+	Name     string             `json:"name"`
+	Time     time.Time          `json:"time"`
+	Version  string             `json:"version"`
+	Platform string             `json:"platform"`
+	Arch     string             `json:"arch"`
+	Labels   map[string]string  `json:"labels,omitempty"`
+	Values   map[string]float64 `json:"values,omitempty"`
+}
+
+// batch is the request body sent to the telemetry endpoint.
+type batch struct {
+	Events []Event `json:"events"`
+}
+
+// Enabled returns true if the user has opted in to telemetry.
+func Enabled() bool {
+	return settings.Get().TelemetryEnabled
+}
+
+// Record queues an event if telemetry is enabled.
+func Record(name string, labels map[string]string, values map[string]float64) {
+	if !Enabled() {
+		return
+	}
+
+	queue.add(Event{
+		Name:     name,
+		Time:     time.Now().UTC().Truncate(time.Second),
+		Version:  build.Version,
+		Platform: build.OS(),
+		Arch:     build.Arch(),
+		Labels:   labels,
+		Values:   values,
+	})
+}
+
+// UpdateApplied records how long applying an update to a package took and
+// whether it succeeded.
+func UpdateApplied(pkg string, d time.Duration, err error) {
+	labels := map[string]string{"package": pkg, "result": "ok"}
+	if err != nil {
+		labels["result"] = "failed"
+		labels["code"] = Code(err)
+	}
+	Record("update", labels, map[string]float64{"seconds": d.Seconds()})
+}
+
+// LauncherUpdated records a completed self-update of the launcher.
+func LauncherUpdated(from, to string) {
+	Record("launcher_update", map[string]string{"from": from, "to": to}, nil)
+}
+
+// Downloaded records the throughput of a completed download.
+func Downloaded(pkg string, bytes int64, d time.Duration) {
+	if bytes <= 0 || d <= 0 {
+		return
+	}
+	Record("download", map[string]string{"package": pkg}, map[string]float64{
+		"bytes":            float64(bytes),
+		"seconds":          d.Seconds(),
+		"bytes_per_second": float64(bytes) / d.Seconds(),
+	})
+}
+
+// Failure records that an operation, such as "launch", failed with err.
+func Failure(op string, err error) {
+	Record("failure", map[string]string{"op": op, "code": Code(err)}, nil)
+}
+
+// Code reduces an error to a short code that identifies its kind without
+// any of its details.
+func Code(err error) string {
+	var status *api.StatusError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, net.ErrOffline):
+		return "offline"
+	case errors.As(err, &status):
+		return "http_" + strconv.Itoa(status.StatusCode)
+	case errors.Is(err, syscall.ENOSPC):
+		return "disk_full"
+	case errors.Is(err, os.ErrPermission):
+		return "permission"
+	case errors.Is(err, os.ErrNotExist):
+		return "not_found"
+	default:
+		return "other"
+	}
+}
+
+// Start loads events queued by a previous session and sends queued events
+// periodically until ctx is cancelled.
+func Start(ctx context.Context) {
+	queue.load()
+
+	go func() {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+
+		for {
+			Flush(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Flush sends queued events if telemetry is enabled, has not been
+// switched off remotely, and the launcher is online. Events that cannot be
+// sent stay queued for the next attempt.
+func Flush(ctx context.Context) {
+	s := settings.Get()
+	if !s.TelemetryEnabled {
+		return
+	}
+	if net.Current() == net.ModeOffline || !remoteconfig.Enabled(remoteconfig.FeatureTelemetry) {
+		return
+	}
+
+	endpoint := s.TelemetryEndpoint
+	if endpoint == "" {
+		endpoint = endpoints.Telemetry()
+	}
+
+	for {
+		events := queue.peek(batchSize)
+		if len(events) == 0 {
+			return
+		}
+
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err := api.Post(sendCtx, api.Default, endpoint, batch{Events: events})
+		cancel()
+		if err != nil {
+			slog.Debug("unable to send telemetry", "error", err)
+			return
+		}
+
+		queue.remove(len(events))
+		slog.Debug("sent telemetry", "events", len(events))
+	}
+}
+
+// Discard removes all queued events, for when the user opts out.
+func Discard() {
+	queue.clear()
+}
@@ -0,0 +1,181 @@
+package throttle
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// EmitFunc delivers a single event.
+type EmitFunc func(name string, args ...any)
+
+// EmitterOptions configures an Emitter.
+type EmitterOptions struct {
+	// Coalesce reports whether only the latest event with the given name
+	// matters, as for progress. Queued events with that name are replaced
+	// by newer ones instead of all being delivered.
+	Coalesce func(name string) bool
+
+	// Interval is the minimum time between batches of deliveries.
+	Interval time.Duration
+
+	// BatchSize is the maximum number of events delivered per batch.
+	BatchSize int
+
+	// QueueSize is the maximum number of events waiting for delivery.
+	// Events emitted while the queue is full are dropped.
+	QueueSize int
+}
+
+// queuedEvent is an event waiting for delivery.
+type queuedEvent struct {
+	name string
+	args []any
+}
+
+// Emitter delivers events from a single goroutine, in the order they were
+// emitted, at a bounded rate. It is safe for concurrent use.
+type Emitter struct {
+	deliver EmitFunc
+	opts    EmitterOptions
+
+	mu      sync.Mutex
+	queue   []queuedEvent
+	dropped map[string]uint64
+	// unreported counts drops since the last warning was logged.
+	unreported uint64
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewEmitter creates an Emitter that delivers events with fn, and starts
+// its delivery goroutine.
+func NewEmitter(fn EmitFunc, opts EmitterOptions) *Emitter {
+	if opts.Coalesce == nil {
+		opts.Coalesce = func(string) bool { return false }
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1
+	}
+
+	e := &Emitter{
+		deliver: fn,
+		opts:    opts,
+		dropped: make(map[string]uint64),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go e.loop()
+	return e
+}
+
+// Emit queues an event for delivery. A coalesced event replaces any queued
+// event with the same name and moves to the back of the queue, so it is
+// still delivered after the events emitted before it.
+func (e *Emitter) Emit(name string, args ...any) {
+	e.mu.Lock()
+	if e.opts.Coalesce(name) {
+		for i := range e.queue {
+			if e.queue[i].name == name {
+				e.queue = append(e.queue[:i], e.queue[i+1:]...)
+				break
+			}
+		}
+	}
+	if len(e.queue) >= e.opts.QueueSize {
+		e.dropped[name]++
+		e.unreported++
+		e.mu.Unlock()
+		return
+	}
+	e.queue = append(e.queue, queuedEvent{name: name, args: args})
+	e.mu.Unlock()
+
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was
+// full, by event name.
+func (e *Emitter) Dropped() map[string]uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	out := make(map[string]uint64, len(e.dropped))
+	for name, n := range e.dropped {
+		out[name] = n
+	}
+	return out
+}
+
+// Close delivers the events still queued and stops the Emitter. Events
+// emitted after Close are queued but never delivered.
+func (e *Emitter) Close() {
+	select {
+	case <-e.stop:
+	default:
+		close(e.stop)
+	}
+	<-e.done
+}
+
+// loop delivers queued events in batches, waiting at least Interval
+// between batches.
+func (e *Emitter) loop() {
+	defer close(e.done)
+
+	for {
+		select {
+		case <-e.wake:
+		case <-e.stop:
+			for e.flush() {
+			}
+			return
+		}
+
+		if e.flush() {
+			// More events are waiting; deliver them on the next batch.
+			select {
+			case e.wake <- struct{}{}:
+			default:
+			}
+		}
+
+		select {
+		case <-time.After(e.opts.Interval):
+		case <-e.stop:
+		}
+	}
+}
+
+// flush delivers up to BatchSize queued events and returns true if more
+// are waiting.
+func (e *Emitter) flush() bool {
+	e.mu.Lock()
+	n := min(len(e.queue), e.opts.BatchSize)
+	batch := make([]queuedEvent, n)
+	copy(batch, e.queue)
+	e.queue = e.queue[n:]
+	more := len(e.queue) > 0
+
+	unreported := e.unreported
+	e.unreported = 0
+	e.mu.Unlock()
+
+	if unreported > 0 {
+		slog.Warn("event queue full, dropped events", "count", unreported)
+	}
+
+	for _, ev := range batch {
+		e.deliver(ev.name, ev.args...)
+	}
+	return more
+}
@@ -1,4 +1,5 @@
-// Package throttle provides utilities for throttling and rate limiting operations.
+// Package throttle provides utilities for throttling and rate limiting
+// operations, and a scheduler for periodic jobs.
 package throttle
 
 // ProgressGate is a gate that throttles progress updates.
@@ -0,0 +1,314 @@
+package throttle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// pauseRetry is how long a job that came due while the scheduler was
+// paused waits before checking again.
+const pauseRetry = time.Minute
+
+// Job is a named function run periodically by a Scheduler.
+type Job struct {
+	// Name identifies the job for RunNow, logs and the persisted last run.
+	Name string
+
+	// Interval is the time between runs.
+	Interval time.Duration
+
+	// Jitter is the maximum random delay added to each run, so clients do
+	// not all hit a server at once.
+	Jitter time.Duration
+
+	// Pausable jobs are held back while the scheduler is paused, and run
+	// once it is resumed.
+	Pausable bool
+
+	// Run performs the job. Errors are logged and reported to Sentry.
+	Run func() error
+}
+
+// SchedulerOptions configures a Scheduler.
+type SchedulerOptions struct {
+	// StatePath is the file the time of each job's last run is kept in,
+	// so intervals carry over restarts. Empty keeps them in memory only.
+	StatePath string
+
+	// Paused reports whether pausable jobs must wait, such as while the
+	// game is running. Nil never pauses.
+	Paused func() bool
+}
+
+// JobStatus describes a scheduled job.
+type JobStatus struct {
+	Name     string `json:"name"`
+	Pausable bool   `json:"pausable"`
+
+	// Interval is the time between runs, in seconds.
+	Interval int64 `json:"interval"`
+
+	// LastRun is when the job last finished, or zero if it never ran.
+	LastRun time.Time `json:"last_run,omitzero"`
+
+	// NextRun is when the job is next due, or zero if the scheduler is
+	// not started.
+	NextRun time.Time `json:"next_run,omitzero"`
+
+	// Running is true while the job runs.
+	Running bool `json:"running"`
+
+	// Paused is true if the job is due but held back while paused.
+	Paused bool `json:"paused"`
+
+	// LastError is the error of the last run, if it failed.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// scheduledJob is a registered job and its run-now trigger.
+type scheduledJob struct {
+	Job
+	trigger chan struct{}
+
+	// The fields below are protected by the Scheduler's mu.
+	next    time.Time
+	running bool
+	paused  bool
+	lastErr error
+}
+
+// Scheduler runs named jobs periodically, each on its own goroutine.
+type Scheduler struct {
+	opts SchedulerOptions
+	jobs map[string]*scheduledJob
+
+	// mu protects lastRun and the state of each job.
+	mu      sync.Mutex
+	lastRun map[string]time.Time
+
+	cancel context.CancelFunc
+}
+
+// NewScheduler creates a Scheduler for the given jobs. Call Start to run
+// them.
+func NewScheduler(opts SchedulerOptions, jobs ...Job) *Scheduler {
+	s := &Scheduler{
+		opts:    opts,
+		jobs:    make(map[string]*scheduledJob, len(jobs)),
+		lastRun: make(map[string]time.Time),
+	}
+	for _, j := range jobs {
+		s.jobs[j.Name] = &scheduledJob{Job: j, trigger: make(chan struct{}, 1)}
+	}
+	return s
+}
+
+// Start loads the persisted last runs and starts the jobs. A job that ran
+// before is next run one interval after its last run; one that never ran
+// is first run one interval from now.
+func (s *Scheduler) Start() {
+	s.load()
+
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	for _, j := range s.jobs {
+		go s.loop(ctx, j)
+	}
+}
+
+// Stop stops all jobs. A job already running is left to finish.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		j.next = time.Time{}
+	}
+}
+
+// RunNow runs a job right away, even while paused, and restarts its
+// interval. It returns false if there is no job with that name.
+func (s *Scheduler) RunNow(name string) bool {
+	j, ok := s.jobs[name]
+	if !ok {
+		return false
+	}
+	select {
+	case j.trigger <- struct{}{}:
+	default:
+		// A run is already requested.
+	}
+	return true
+}
+
+// Status returns the status of every job, sorted by name.
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		st := JobStatus{
+			Name:     j.Name,
+			Interval: int64(j.Interval / time.Second),
+			Pausable: j.Pausable,
+			LastRun:  s.lastRun[j.Name],
+			NextRun:  j.next,
+			Running:  j.running,
+			Paused:   j.paused,
+		}
+		if j.lastErr != nil {
+			st.LastError = j.lastErr.Error()
+		}
+		out = append(out, st)
+	}
+	slices.SortFunc(out, func(a, b JobStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return out
+}
+
+// LastRun returns when a job last ran, or the zero time if it never did.
+func (s *Scheduler) LastRun(name string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun[name]
+}
+
+// loop runs a job whenever it is due or triggered, until ctx is done.
+func (s *Scheduler) loop(ctx context.Context, j *scheduledJob) {
+	timer := time.NewTimer(s.schedule(j, s.firstDelay(j)))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-j.trigger:
+		case <-timer.C:
+			if j.Pausable && s.opts.Paused != nil && s.opts.Paused() {
+				slog.Debug("scheduled job paused", "job", j.Name)
+				s.setPaused(j, true)
+				timer.Reset(s.schedule(j, pauseRetry))
+				continue
+			}
+		}
+
+		s.setPaused(j, false)
+		s.run(j)
+		timer.Reset(s.schedule(j, j.Interval+jitter(j.Jitter)))
+	}
+}
+
+// schedule records that a job is next due after d, and returns d.
+func (s *Scheduler) schedule(j *scheduledJob, d time.Duration) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.next = time.Now().Add(d)
+	return d
+}
+
+// setPaused records whether a job is held back by the pause.
+func (s *Scheduler) setPaused(j *scheduledJob, paused bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.paused = paused
+}
+
+// firstDelay returns how long to wait before a job's first run.
+func (s *Scheduler) firstDelay(j *scheduledJob) time.Duration {
+	d := j.Interval
+	if last := s.LastRun(j.Name); !last.IsZero() {
+		d = max(time.Until(last.Add(j.Interval)), 0)
+	}
+	return d + jitter(j.Jitter)
+}
+
+// run runs a job and records the time it ran.
+func (s *Scheduler) run(j *scheduledJob) {
+	slog.Debug("running scheduled job", "job", j.Name)
+	s.mu.Lock()
+	j.running = true
+	s.mu.Unlock()
+
+	err := j.Run()
+	if err != nil {
+		slog.Error("scheduled job failed", "job", j.Name, "error", err)
+		sentry.CaptureException(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.running = false
+	j.lastErr = err
+	s.lastRun[j.Name] = time.Now()
+	s.save()
+}
+
+// jitter returns a random duration below limit.
+func jitter(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(limit)))
+}
+
+// load reads the last runs from StatePath.
+func (s *Scheduler) load() {
+	if s.opts.StatePath == "" {
+		return
+	}
+	data, err := os.ReadFile(s.opts.StatePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	var lastRun map[string]time.Time
+	if err == nil {
+		err = json.Unmarshal(data, &lastRun)
+	}
+	if err != nil {
+		slog.Warn("unable to read scheduler state", "path", s.opts.StatePath, "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, t := range lastRun {
+		s.lastRun[name] = t
+	}
+}
+
+// save writes the last runs to StatePath. s.mu must be held.
+func (s *Scheduler) save() {
+	if s.opts.StatePath == "" {
+		return
+	}
+
+	data, err := json.Marshal(s.lastRun)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(s.opts.StatePath), 0o755)
+	}
+	if err == nil {
+		tmp := s.opts.StatePath + ".tmp"
+		if err = os.WriteFile(tmp, data, 0o644); err == nil {
+			err = os.Rename(tmp, s.opts.StatePath)
+		}
+	}
+	if err != nil {
+		slog.Warn("unable to save scheduler state", "path", s.opts.StatePath, "error", err)
+	}
+}
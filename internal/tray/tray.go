@@ -0,0 +1,56 @@
+// Package tray provides a system tray icon with a menu of launcher actions,
+// allowing the launcher to keep running in the background without a window.
+//
+// Only Linux is supported, on desktops that host StatusNotifierItem icons.
+// On Windows and macOS, Start returns ErrUnsupported: the launcher then
+// keeps its window, exits when it is closed, and ignores --background.
+package tray
+
+import "errors"
+
+// ErrUnsupported is returned by Start when the platform or desktop session
+// has no system tray the launcher can use.
+var ErrUnsupported = errors.New("system tray is not supported")
+
+// Item is an entry in the tray menu.
+type Item struct {
+	// ID identifies the item to the Handler.
+	ID string
+
+	// Label is the text shown in the menu.
+	Label string
+}
+
+// Handler receives tray interactions. Activate is called when the icon
+// itself is clicked; Select is called with the ID of a chosen menu item.
+type Handler struct {
+	Activate func()
+	Select   func(id string)
+}
+
+// Tray is a running tray icon.
+type Tray struct {
+	close func()
+}
+
+// Start shows a tray icon with the given title and menu items.
+// Returns ErrUnsupported if no system tray is available.
+func Start(title string, items []Item, h Handler) (*Tray, error) {
+	closeFn, err := start(title, items, h)
+	if err != nil {
+		return nil, err
+	}
+	return &Tray{close: closeFn}, nil
+}
+
+// Close removes the tray icon.
+func (t *Tray) Close() {
+	if t != nil && t.close != nil {
+		t.close()
+	}
+}
+
+// Supported returns true if a system tray is available in this session.
+func Supported() bool {
+	return supported()
+}
@@ -0,0 +1,259 @@
+//go:build linux
+
+package tray
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+// The tray icon is published with the StatusNotifierItem protocol, and its
+// menu with the dbusmenu protocol, both over the session bus.
+const (
+	itemInterface    = "org.kde.StatusNotifierItem"
+	itemPath         = dbus.ObjectPath("/StatusNotifierItem")
+	menuInterface    = "com.canonical.dbusmenu"
+	menuPath         = dbus.ObjectPath("/MenuBar")
+	watcherName      = "org.kde.StatusNotifierWatcher"
+	watcherPath      = dbus.ObjectPath("/StatusNotifierWatcher")
+	iconName         = "applications-games"
+	dbusMenuVersion  = uint32(3)
+	menuRootID       = int32(0)
+	menuEventClicked = "clicked"
+)
+
+// supported returns true if a StatusNotifierWatcher is running, which is
+// the case when the desktop shows tray icons.
+func supported() bool {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return false
+	}
+
+	var hasOwner bool
+	err = conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, watcherName).Store(&hasOwner)
+	return err == nil && hasOwner
+}
+
+// start exports the tray icon and menu on the session bus and registers
+// the icon with the StatusNotifierWatcher.
+func start(title string, items []Item, h Handler) (func(), error) {
+	if !supported() {
+		return nil, ErrUnsupported
+	}
+
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to session bus: %w", err)
+	}
+
+	name := fmt.Sprintf("org.kde.StatusNotifierItem-%d-1", os.Getpid())
+	reply, err := conn.RequestName(name, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		conn.Close()
+		return nil, fmt.Errorf("unable to acquire bus name %s: %v", name, err)
+	}
+
+	if err := conn.Export(&statusItem{h: h}, itemPath, itemInterface); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to export tray item: %w", err)
+	}
+	if _, err := prop.Export(conn, itemPath, prop.Map{
+		itemInterface: {
+			"Category":   {Value: "ApplicationStatus"},
+			"Id":         {Value: "hytale-launcher"},
+			"Title":      {Value: title},
+			"Status":     {Value: "Active"},
+			"IconName":   {Value: iconName},
+			"ItemIsMenu": {Value: false},
+			"Menu":       {Value: menuPath},
+		},
+	}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to export tray item properties: %w", err)
+	}
+
+	if err := conn.Export(&menu{items: items, h: h}, menuPath, menuInterface); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to export tray menu: %w", err)
+	}
+	if _, err := prop.Export(conn, menuPath, prop.Map{
+		menuInterface: {
+			"Version":       {Value: dbusMenuVersion},
+			"TextDirection": {Value: "ltr"},
+			"Status":        {Value: "normal"},
+			"IconThemePath": {Value: []string{}},
+		},
+	}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to export tray menu properties: %w", err)
+	}
+
+	watcher := conn.Object(watcherName, watcherPath)
+	if call := watcher.Call(watcherName+".RegisterStatusNotifierItem", 0, name); call.Err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to register tray item: %w", call.Err)
+	}
+
+	slog.Debug("tray icon registered", "name", name)
+	return func() { conn.Close() }, nil
+}
+
+// statusItem implements the StatusNotifierItem methods.
+type statusItem struct {
+	h Handler
+}
+
+// Activate is called when the icon is clicked.
+func (s *statusItem) Activate(x, y int32) *dbus.Error {
+	if s.h.Activate != nil {
+		go s.h.Activate()
+	}
+	return nil
+}
+
+// SecondaryActivate is called when the icon is middle-clicked.
+func (s *statusItem) SecondaryActivate(x, y int32) *dbus.Error {
+	return s.Activate(x, y)
+}
+
+// ContextMenu is called by hosts that do not display the menu themselves.
+func (s *statusItem) ContextMenu(x, y int32) *dbus.Error {
+	return nil
+}
+
+// Scroll is called when the mouse wheel is used over the icon.
+func (s *statusItem) Scroll(delta int32, orientation string) *dbus.Error {
+	return nil
+}
+
+// menuLayout is a dbusmenu layout node: (ia{sv}av).
+type menuLayout struct {
+	ID         int32
+	Properties map[string]dbus.Variant
+	Children   []dbus.Variant
+}
+
+// menuProperties is an entry of GetGroupProperties: (ia{sv}).
+type menuProperties struct {
+	ID         int32
+	Properties map[string]dbus.Variant
+}
+
+// menuEvent is an entry of EventGroup: (isvu).
+type menuEvent struct {
+	ID        int32
+	EventID   string
+	Data      dbus.Variant
+	Timestamp uint32
+}
+
+// menu implements the dbusmenu methods for a flat list of items. Item IDs
+// on the bus are the item's index plus one; zero is the root.
+type menu struct {
+	items []Item
+	h     Handler
+}
+
+// properties returns the dbusmenu properties of a menu node.
+func (m *menu) properties(id int32) map[string]dbus.Variant {
+	if id == menuRootID {
+		return map[string]dbus.Variant{"children-display": dbus.MakeVariant("submenu")}
+	}
+	return map[string]dbus.Variant{
+		"label":   dbus.MakeVariant(m.items[id-1].Label),
+		"enabled": dbus.MakeVariant(true),
+		"visible": dbus.MakeVariant(true),
+	}
+}
+
+// valid returns true if id refers to a menu node.
+func (m *menu) valid(id int32) bool {
+	return id >= menuRootID && int(id) <= len(m.items)
+}
+
+// GetLayout returns the menu tree below parentID.
+func (m *menu) GetLayout(parentID int32, recursionDepth int32, propertyNames []string) (uint32, menuLayout, *dbus.Error) {
+	if !m.valid(parentID) {
+		return 0, menuLayout{}, dbus.MakeFailedError(fmt.Errorf("unknown menu item %d", parentID))
+	}
+
+	layout := menuLayout{
+		ID:         parentID,
+		Properties: m.properties(parentID),
+		Children:   []dbus.Variant{},
+	}
+	if parentID == menuRootID && recursionDepth != 0 {
+		for i := range m.items {
+			id := int32(i + 1)
+			layout.Children = append(layout.Children, dbus.MakeVariant(menuLayout{
+				ID:         id,
+				Properties: m.properties(id),
+				Children:   []dbus.Variant{},
+			}))
+		}
+	}
+	return 1, layout, nil
+}
+
+// GetGroupProperties returns the properties of several menu nodes.
+func (m *menu) GetGroupProperties(ids []int32, propertyNames []string) ([]menuProperties, *dbus.Error) {
+	var result []menuProperties
+	for _, id := range ids {
+		if m.valid(id) {
+			result = append(result, menuProperties{ID: id, Properties: m.properties(id)})
+		}
+	}
+	return result, nil
+}
+
+// GetProperty returns a single property of a menu node.
+func (m *menu) GetProperty(id int32, name string) (dbus.Variant, *dbus.Error) {
+	if !m.valid(id) {
+		return dbus.Variant{}, dbus.MakeFailedError(fmt.Errorf("unknown menu item %d", id))
+	}
+	value, ok := m.properties(id)[name]
+	if !ok {
+		return dbus.Variant{}, dbus.MakeFailedError(fmt.Errorf("unknown property %s", name))
+	}
+	return value, nil
+}
+
+// Event is called when the user interacts with a menu node.
+func (m *menu) Event(id int32, eventID string, data dbus.Variant, timestamp uint32) *dbus.Error {
+	if eventID != menuEventClicked || id == menuRootID || !m.valid(id) {
+		return nil
+	}
+	if m.h.Select != nil {
+		go m.h.Select(m.items[id-1].ID)
+	}
+	return nil
+}
+
+// EventGroup delivers several events at once.
+func (m *menu) EventGroup(events []menuEvent) ([]int32, *dbus.Error) {
+	var idErrors []int32
+	for _, e := range events {
+		if !m.valid(e.ID) {
+			idErrors = append(idErrors, e.ID)
+			continue
+		}
+		m.Event(e.ID, e.EventID, e.Data, e.Timestamp)
+	}
+	return idErrors, nil
+}
+
+// AboutToShow is called before a node is shown. The menu never changes,
+// so no update is needed.
+func (m *menu) AboutToShow(id int32) (bool, *dbus.Error) {
+	return false, nil
+}
+
+// AboutToShowGroup is called before several nodes are shown.
+func (m *menu) AboutToShowGroup(ids []int32) ([]int32, []int32, *dbus.Error) {
+	return []int32{}, []int32{}, nil
+}
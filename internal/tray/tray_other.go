@@ -0,0 +1,14 @@
+//go:build !linux
+
+package tray
+
+// supported reports that no tray backend is implemented for this platform.
+// Windows and macOS are not supported; see the package documentation.
+func supported() bool {
+	return false
+}
+
+// start is not supported on this platform.
+func start(title string, items []Item, h Handler) (func(), error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,264 @@
+// Package uninstall removes everything the launcher installed for a
+// channel: game builds, Java runtimes and the dedicated server, optionally
+// archiving the player's data from the game first.
+package uninstall
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/deletex"
+	"hytale-launcher/internal/format"
+	"hytale-launcher/internal/hytale"
+	"hytale-launcher/internal/installlock"
+	"hytale-launcher/internal/ioutil"
+)
+
+// packages are the packages removed with a channel. The launcher's own
+// package is left alone, as the running launcher may live there.
+var packages = []string{"game", "jre", "server"}
+
+// dependencies are the state dependencies cleared with a channel.
+var dependencies = []string{"game", "lkg", "jre", "server"}
+
+// backupsDirName is the directory in the storage directory that user data
+// archives are written to.
+const backupsDirName = "backups"
+
+// Result describes an uninstalled channel.
+type Result struct {
+	Channel string `json:"channel"`
+
+	// Reclaimed is the number of bytes freed.
+	Reclaimed int64 `json:"reclaimed"`
+
+	// Archive is the path of the archive the player's data was saved to,
+	// or empty if it was not kept or there was none.
+	Archive string `json:"archive,omitempty"`
+}
+
+// ProgressFunc is called after each file is deleted with the number of
+// files deleted so far and the total.
+type ProgressFunc func(current, total int)
+
+// BackupsDir returns the directory user data archives are written to.
+func BackupsDir() string {
+	return hytale.InStorageDir(backupsDirName)
+}
+
+// Channel removes the packages installed for a channel and clears them
+// from its state. With keepSaves set, the player's data in the active game
+// build is archived to BackupsDir first, and nothing is deleted if that
+// fails. A game build adopted from elsewhere is forgotten but not deleted.
+func Channel(state *appstate.State, keepSaves bool, onProgress ProgressFunc) (*Result, error) {
+	channel := state.Channel
+	lock, err := installlock.Acquire(channel)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	result := &Result{Channel: channel}
+
+	if keepSaves {
+		if gameDep := state.GetDependency("game"); gameDep != nil && gameDep.Path != "" {
+			archive, err := archiveUserData(channel, gameDep.Path)
+			if err != nil {
+				return nil, fmt.Errorf("unable to archive user data: %w", err)
+			}
+			result.Archive = archive
+		}
+	}
+
+	var dirs []string
+	total := 0
+	for _, pkgID := range packages {
+		dir := hytale.PackageDir(pkgID, channel, "")
+		if _, err := os.Lstat(dir); err != nil {
+			continue
+		}
+		size, files := measure(dir)
+		result.Reclaimed += size
+		total += files
+		dirs = append(dirs, dir)
+	}
+
+	slog.Info("uninstalling channel",
+		"channel", channel,
+		"files", total,
+		"size", format.Bytes(result.Reclaimed),
+		"keep_saves", keepSaves,
+	)
+
+	current := 0
+	for _, dir := range dirs {
+		err := deletex.Dir(dir, func() {
+			current++
+			if onProgress != nil {
+				onProgress(current, total)
+			}
+		})
+		if err != nil {
+			// Some files are gone, so the install is broken either way.
+			clearDependencies(state)
+			return nil, fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+	}
+
+	clearDependencies(state)
+	slog.Info("channel uninstalled", "channel", channel, "reclaimed", format.Bytes(result.Reclaimed))
+	return result, nil
+}
+
+// clearDependencies removes the channel's installed packages from its
+// state and saves it.
+func clearDependencies(state *appstate.State) {
+	for _, dep := range dependencies {
+		state.SetDependency(dep, "uninstall", nil)
+	}
+	state.Save("uninstall_channel")
+}
+
+// DeleteUserData deletes the player's data from the channel's active game
+// build, leaving the game installed, and returns the bytes freed. Data in
+// a build adopted from elsewhere is left alone.
+func DeleteUserData(state *appstate.State) (int64, error) {
+	gameDep := state.GetDependency("game")
+	if gameDep == nil || gameDep.Path == "" || gameDep.Adopted {
+		return 0, nil
+	}
+
+	lock, err := installlock.Acquire(state.Channel)
+	if err != nil {
+		return 0, err
+	}
+	defer lock.Release()
+
+	var freed int64
+	for _, name := range hytale.UserDataDirs {
+		dir := filepath.Join(gameDep.Path, name)
+		if _, err := os.Lstat(dir); err != nil {
+			continue
+		}
+		size, _ := measure(dir)
+		if err := os.RemoveAll(dir); err != nil {
+			return freed, fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+		freed += size
+	}
+
+	slog.Info("deleted user data", "channel", state.Channel, "freed", format.Bytes(freed))
+	return freed, nil
+}
+
+// measure returns the total size and number of files under dir. Symlinks
+// are counted but not followed, as deleting them leaves their targets.
+func measure(dir string) (int64, int) {
+	var size int64
+	var files int
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		files++
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size, files
+}
+
+// archiveUserData writes the user data directories of a game build to a
+// zip archive in BackupsDir and returns its path, or an empty path if the
+// build has no user data.
+func archiveUserData(channel, gameDir string) (string, error) {
+	var present []string
+	for _, name := range hytale.UserDataDirs {
+		if info, err := os.Stat(filepath.Join(gameDir, name)); err == nil && info.IsDir() {
+			present = append(present, name)
+		}
+	}
+	if len(present) == 0 {
+		return "", nil
+	}
+
+	if err := ioutil.MkdirAll(BackupsDir()); err != nil {
+		return "", err
+	}
+	path := filepath.Join(BackupsDir(), fmt.Sprintf("%s-%s.zip", channel, time.Now().Format("20060102-150405")))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+
+	zw := zip.NewWriter(f)
+	for _, name := range present {
+		if err = addDir(zw, gameDir, name); err != nil {
+			break
+		}
+	}
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	slog.Info("archived user data", "channel", channel, "archive", path, "dirs", present)
+	return path, nil
+}
+
+// addDir adds the regular files of a directory in root to a zip archive,
+// named by their slash-separated path relative to root.
+func addDir(zw *zip.Writer, root, name string) error {
+	return filepath.WalkDir(filepath.Join(root, name), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		header.Method = zip.Deflate
+
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(w, src)
+		return err
+	})
+}
@@ -72,6 +72,9 @@ type Event struct {
 	// Name is the event identifier.
 	Name string `json:"name"`
 
+	// Channel is the channel being updated.
+	Channel string `json:"channel,omitempty"`
+
 	// Package is the package being updated.
 	Package string `json:"package,omitempty"`
 
@@ -83,14 +86,26 @@ type Event struct {
 
 	// Error contains error details if the event represents a failure.
 	Error string `json:"error,omitempty"`
+
+	// Code identifies the kind of failure, as one of the errcode codes.
+	Code string `json:"code,omitempty"`
+
+	// Message describes the failure in the selected locale.
+	Message string `json:"message,omitempty"`
 }
 
 // Notification represents a status update notification.
 type Notification struct {
+	// Channel is the channel being updated.
+	Channel string `json:"channel,omitempty"`
+
 	// Package is the package being updated.
 	Package string `json:"package,omitempty"`
 
-	// Status is a human-readable status message.
+	// State is the package's update state, such as "downloading".
+	State string `json:"state,omitempty"`
+
+	// Status is a human-readable status message in the selected locale.
 	Status string `json:"status,omitempty"`
 
 	// Progress is the overall progress (0-100).
@@ -104,6 +119,12 @@ type Notification struct {
 
 	// Speed is the current download speed in bytes per second.
 	Speed int64 `json:"speed,omitempty"`
+
+	// SizeText, SpeedText and ETAText are the download size, speed and
+	// time remaining formatted for the selected locale.
+	SizeText  string `json:"size_text,omitempty"`
+	SpeedText string `json:"speed_text,omitempty"`
+	ETAText   string `json:"eta_text,omitempty"`
 }
 
 // Listener is an interface for receiving update events and notifications.
@@ -141,3 +162,16 @@ type GamePackage struct{}
 
 // Name returns "game".
 func (p *GamePackage) Name() string { return "game" }
+
+// ContentPackage represents the optional content packs installed on a
+// channel.
+type ContentPackage struct{}
+
+// Name returns "content".
+func (p *ContentPackage) Name() string { return "content" }
+
+// LauncherPackage represents the launcher self-update package.
+type LauncherPackage struct{}
+
+// Name returns "launcher".
+func (p *LauncherPackage) Name() string { return "launcher" }
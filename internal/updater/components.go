@@ -0,0 +1,103 @@
+package updater
+
+import (
+	"context"
+	"strings"
+
+	"hytale-launcher/internal/pkg"
+	"hytale-launcher/internal/update"
+)
+
+// The components every channel manages, updated in the order
+// launcher -> jre -> game -> content.
+func init() {
+	Register(Component{
+		Name:  "launcher",
+		Pkg:   &update.LauncherPackage{},
+		Check: checkLauncher,
+	})
+	Register(Component{
+		Name:      "jre",
+		Pkg:       &update.JREPackage{},
+		DependsOn: []string{"launcher"},
+		Check:     checkJRE,
+	})
+	Register(Component{
+		Name:      "game",
+		Pkg:       &update.GamePackage{},
+		DependsOn: []string{"jre"},
+		Check:     checkGame,
+	})
+	Register(Component{
+		Name:      "content",
+		Pkg:       &update.ContentPackage{},
+		DependsOn: []string{"game"},
+		Optional:  true,
+		Check:     checkContent,
+	})
+}
+
+// checkLauncher checks for a launcher self-update.
+func checkLauncher(ctx context.Context, c *CheckContext) (pkg.Update, error) {
+	return pkg.CheckForLauncherUpdate(ctx)
+}
+
+// checkJRE checks for an update of the Java runtime the game needs,
+// including a pending game update's requirement.
+func checkJRE(ctx context.Context, c *CheckContext) (pkg.Update, error) {
+	required := pkg.RequiredJRE(c.State, c.Pending("game"))
+	return pkg.CheckForJavaUpdate(ctx, c.State, c.Channel, required)
+}
+
+// checkGame checks for a game update on the channel. Game updates need a
+// logged in account.
+func checkGame(ctx context.Context, c *CheckContext) (pkg.Update, error) {
+	if c.Auth == nil || !c.Auth.IsLoggedIn() {
+		return nil, nil
+	}
+	acct := c.Auth.GetAccount()
+	if acct == nil {
+		return nil, nil
+	}
+
+	gameAuth := &pkg.Auth{
+		Account: &pkg.GameAccount{
+			Patchlines: make(map[string]*pkg.GamePatchline),
+		},
+	}
+	// Populate patchlines from account data
+	if acct.CurrentProfile != nil {
+		for _, ent := range acct.CurrentProfile.Entitlements {
+			if name, ok := strings.CutPrefix(ent, "patchline:"); ok && name != "" {
+				patchline := &pkg.GamePatchline{
+					Name:        name,
+					NewestBuild: 1, // Will be populated from server
+				}
+				if p := acct.Patchlines[name].Preload; p != nil {
+					patchline.Preload = &pkg.GamePreload{
+						Build:     p.Build,
+						Version:   p.Version,
+						ReleaseAt: p.ReleaseAt,
+					}
+				}
+				gameAuth.Account.Patchlines[name] = patchline
+			}
+		}
+	}
+
+	game := &pkg.Game{
+		Channel: c.Channel,
+		State:   c.State,
+	}
+	return game.CheckForUpdate(ctx, gameAuth)
+}
+
+// checkContent checks for updates of the optional content packs installed
+// on the channel. Packs that are not installed are never offered here;
+// they are installed on request.
+func checkContent(ctx context.Context, c *CheckContext) (pkg.Update, error) {
+	if c.State == nil {
+		return nil, nil
+	}
+	return pkg.CheckForContentUpdates(ctx, c.State, c.Channel)
+}
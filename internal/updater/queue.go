@@ -0,0 +1,130 @@
+package updater
+
+import (
+	"fmt"
+
+	"hytale-launcher/internal/appstate"
+)
+
+// ResultStatus describes the outcome of a single package update.
+type ResultStatus string
+
+const (
+	// StatusApplied indicates the update was applied successfully.
+	StatusApplied ResultStatus = "applied"
+
+	// StatusFailed indicates the update failed to apply.
+	StatusFailed ResultStatus = "failed"
+
+	// StatusSkipped indicates the update was not attempted because
+	// a package it depends on failed.
+	StatusSkipped ResultStatus = "skipped"
+)
+
+// Result is the outcome of applying the update for one package.
+type Result struct {
+	// Package is the package name.
+	Package string `json:"package"`
+
+	// Version is the version the package was being updated to.
+	Version string `json:"version"`
+
+	// Status is the outcome of the update.
+	Status ResultStatus `json:"status"`
+
+	// Error describes why the update failed or was skipped.
+	Error string `json:"error,omitempty"`
+
+	// Code identifies the kind of failure, as one of the errcode codes.
+	Code string `json:"code,omitempty"`
+}
+
+// order returns the registered packages sorted so that every package comes
+// after the packages it depends on. Dependencies on packages that are not
+// registered are ignored. Registration order is preserved among packages
+// with no ordering constraint between them.
+// Caller must hold u.mu.
+func (u *Updater) order() ([]*Package, error) {
+	byName := make(map[string]*Package, len(u.packages))
+	for _, p := range u.packages {
+		byName[p.Name] = p
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	marks := make(map[string]int, len(u.packages))
+	ordered := make([]*Package, 0, len(u.packages))
+
+	var visit func(p *Package) error
+	visit = func(p *Package) error {
+		switch marks[p.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle detected at package %s", p.Name)
+		}
+
+		marks[p.Name] = visiting
+		for _, dep := range p.DependsOn {
+			if d, ok := byName[dep]; ok {
+				if err := visit(d); err != nil {
+					return err
+				}
+			}
+		}
+		marks[p.Name] = visited
+
+		ordered = append(ordered, p)
+		return nil
+	}
+
+	for _, p := range u.packages {
+		if err := visit(p); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// blockedBy returns the name of a required dependency of p that failed or was
+// skipped in the current batch, or an empty string if p may be applied.
+func (u *Updater) blockedBy(p *Package, blocked map[string]string) string {
+	for _, dep := range p.DependsOn {
+		if _, ok := blocked[dep]; ok {
+			return dep
+		}
+	}
+	return ""
+}
+
+// beginQueue records the batch in the state's update queue. If the state
+// already holds a queue from an interrupted batch, completed steps for the
+// same package versions are carried over so they are not applied twice.
+func (u *Updater) beginQueue(state *appstate.State, queue []*Package) {
+	next := &appstate.UpdateQueue{}
+	for _, p := range queue {
+		version := p.AvailableUpdate.Version
+		next.Steps = append(next.Steps, appstate.QueueStep{
+			Package: p.Name,
+			Version: version,
+			Done:    state.UpdateQueue.IsDone(p.Name, version),
+		})
+	}
+
+	state.UpdateQueue = next
+	state.Save("update_queue_begin")
+}
+
+// Interrupted returns the packages left unfinished by a previous batch
+// that did not complete, or nil if there is none.
+func Interrupted(state *appstate.State) []string {
+	if state == nil {
+		return nil
+	}
+	return state.UpdateQueue.Remaining()
+}
@@ -0,0 +1,118 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"hytale-launcher/internal/appstate"
+	"hytale-launcher/internal/auth"
+	"hytale-launcher/internal/pkg"
+	"hytale-launcher/internal/update"
+)
+
+// CheckFunc looks for an update of a component, returning nil if it is up
+// to date.
+type CheckFunc func(ctx context.Context, c *CheckContext) (pkg.Update, error)
+
+// CheckContext is what a CheckFunc gets to decide whether its component
+// needs an update.
+type CheckContext struct {
+	// State is the channel's state, or nil if no channel is selected.
+	State *appstate.State
+
+	// Channel is the channel being checked.
+	Channel string
+
+	// Auth is the session, or nil if the user is not logged in.
+	Auth *auth.Controller
+
+	// updater is the updater running the check.
+	updater *Updater
+}
+
+// Pending returns the update found for another component in this check,
+// or nil. Components are checked after the components that depend on
+// them, so the JRE sees the game's pending update and the Java major it
+// requires.
+func (c *CheckContext) Pending(name string) pkg.Update {
+	if p := c.updater.find(name); p != nil {
+		return p.pending
+	}
+	return nil
+}
+
+// Component is a managed component that registers itself with the
+// updater, so it is checked and updated on every channel without changes
+// to the call sites creating updaters.
+type Component struct {
+	// Name is the package identifier.
+	Name string
+
+	// Pkg is the update package implementation.
+	Pkg update.Package
+
+	// DependsOn lists the components that must be updated before this one.
+	DependsOn []string
+
+	// Optional indicates that a failure to update this component does not
+	// fail the batch or block components that depend on it.
+	Optional bool
+
+	// Check looks for an update of the component.
+	Check CheckFunc
+}
+
+var (
+	// registryMu protects registry.
+	registryMu sync.RWMutex
+
+	// registry holds the registered components in registration order.
+	registry []Component
+)
+
+// Register adds a component to the registry. Components are usually
+// registered from an init function. Registering a name twice panics.
+func Register(c Component) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, r := range registry {
+		if r.Name == c.Name {
+			panic(fmt.Sprintf("updater: component %q registered twice", c.Name))
+		}
+	}
+	registry = append(registry, c)
+}
+
+// lookup returns the registered component with the given name.
+func lookup(name string) (Component, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, c := range registry {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Component{}, false
+}
+
+// Registered returns a package for every registered component, in
+// registration order, for passing to New.
+func Registered() []Package {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	pkgs := make([]Package, 0, len(registry))
+	for _, c := range registry {
+		pkgs = append(pkgs, Package{
+			Name:      c.Name,
+			Pkg:       c.Pkg,
+			DependsOn: c.DependsOn,
+			Optional:  c.Optional,
+			Check:     c.Check,
+		})
+	}
+	return pkgs
+}
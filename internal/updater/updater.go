@@ -6,11 +6,17 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
+	"time"
 
 	"hytale-launcher/internal/appstate"
 	"hytale-launcher/internal/auth"
+	"hytale-launcher/internal/errcode"
+	"hytale-launcher/internal/format"
+	"hytale-launcher/internal/i18n"
 	"hytale-launcher/internal/pkg"
+	"hytale-launcher/internal/telemetry"
 	"hytale-launcher/internal/update"
 )
 
@@ -24,6 +30,21 @@ type Package struct {
 
 	// AvailableUpdate holds the pending update info, if any.
 	AvailableUpdate *update.Item
+
+	// DependsOn lists the packages that must be updated before this one.
+	// If nil, the default ordering for well-known packages is used.
+	DependsOn []string
+
+	// Optional indicates that a failure to update this package does not
+	// fail the batch or block packages that depend on it.
+	Optional bool
+
+	// Check looks for an update of the package. If nil, the check of the
+	// registered component with the same name is used.
+	Check CheckFunc
+
+	// pending is the update found by the last check, applied by ApplyUpdates.
+	pending pkg.Update
 }
 
 // Updater manages a collection of updatable packages.
@@ -39,6 +60,9 @@ type Updater struct {
 }
 
 // New creates a new Updater instance with the given listener and packages.
+// Pass Registered() for the registered components. A package left without
+// dependencies or a check takes them from the registered component with the
+// same name.
 func New(listener update.Listener, pkgs ...Package) *Updater {
 	u := &Updater{
 		packages: make([]*Package, 0, len(pkgs)),
@@ -47,9 +71,23 @@ func New(listener update.Listener, pkgs ...Package) *Updater {
 
 	for i := range pkgs {
 		p := pkgs[i]
+
+		dependsOn, check := p.DependsOn, p.Check
+		if c, ok := lookup(p.Name); ok {
+			if dependsOn == nil {
+				dependsOn = c.DependsOn
+			}
+			if check == nil {
+				check = c.Check
+			}
+		}
+
 		u.packages = append(u.packages, &Package{
-			Name: p.Name,
-			Pkg:  p.Pkg,
+			Name:      p.Name,
+			Pkg:       p.Pkg,
+			DependsOn: dependsOn,
+			Optional:  p.Optional,
+			Check:     check,
 		})
 	}
 
@@ -65,6 +103,7 @@ func (u *Updater) CheckForUpdates(state *appstate.State, authCtrl *auth.Controll
 	// Clear previous update info.
 	for _, p := range u.packages {
 		p.AvailableUpdate = nil
+		p.pending = nil
 	}
 
 	channel := ""
@@ -74,8 +113,17 @@ func (u *Updater) CheckForUpdates(state *appstate.State, authCtrl *auth.Controll
 
 	ctx := context.Background()
 	updateCount := 0
+	cc := &CheckContext{State: state, Channel: channel, Auth: authCtrl, updater: u}
 
-	for _, p := range u.packages {
+	// Check dependents before their dependencies, so that requirements
+	// such as the game's Java major are known when the JRE is checked.
+	checkOrder, err := u.order()
+	if err != nil {
+		return 0, err
+	}
+	slices.Reverse(checkOrder)
+
+	for _, p := range checkOrder {
 		slog.Debug("checking for update",
 			"package", p.Name,
 			"channel", channel,
@@ -89,51 +137,10 @@ func (u *Updater) CheckForUpdates(state *appstate.State, authCtrl *auth.Controll
 			})
 		}
 
-		// Check for updates based on package type
-		var pkgUpdate pkg.Update
-		var err error
-
-		switch p.Name {
-		case "jre":
-			pkgUpdate, err = pkg.CheckForJavaUpdate(ctx, state, channel)
-		case "game":
-			// Build auth context for game updates
-			var gameAuth *pkg.Auth
-			if authCtrl != nil && authCtrl.IsLoggedIn() {
-				acct := authCtrl.GetAccount()
-				if acct != nil {
-					gameAuth = &pkg.Auth{
-						Account: &pkg.GameAccount{
-							Patchlines: make(map[string]*pkg.GamePatchline),
-						},
-					}
-					// Populate patchlines from account data
-					if acct.CurrentProfile != nil {
-						for _, ent := range acct.CurrentProfile.Entitlements {
-							// Parse patchline entitlements
-							if len(ent) > 10 && ent[:10] == "patchline:" {
-								patchlineName := ent[10:]
-								gameAuth.Account.Patchlines[patchlineName] = &pkg.GamePatchline{
-									Name:        patchlineName,
-									NewestBuild: 1, // Will be populated from server
-								}
-							}
-						}
-					}
-				}
-			}
-
-			if gameAuth != nil && gameAuth.Account != nil {
-				game := &pkg.Game{
-					Channel: channel,
-					State:   state,
-				}
-				pkgUpdate, err = game.CheckForUpdate(ctx, gameAuth)
-			}
-		case "launcher":
-			pkgUpdate, err = pkg.CheckForLauncherUpdate(ctx)
+		if p.Check == nil {
+			continue
 		}
-
+		pkgUpdate, err := p.Check(ctx, cc)
 		if err != nil {
 			slog.Warn("error checking for update",
 				"package", p.Name,
@@ -150,8 +157,10 @@ func (u *Updater) CheckForUpdates(state *appstate.State, authCtrl *auth.Controll
 				Name:           p.Name,
 				Version:        info.TargetVersion,
 				CurrentVersion: info.CurrentVersion,
+				IsBlocking:     info.Mandatory,
 				Size:           info.Size,
 			}
+			p.pending = pkgUpdate
 			updateCount++
 		}
 
@@ -181,6 +190,17 @@ func (u *Updater) GetPackages() []*Package {
 	return result
 }
 
+// find returns a package by name, or nil if not found.
+// Caller must hold u.mu.
+func (u *Updater) find(name string) *Package {
+	for _, p := range u.packages {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
 // GetPackage returns a package by name, or nil if not found.
 func (u *Updater) GetPackage(name string) *Package {
 	u.mu.RLock()
@@ -194,6 +214,26 @@ func (u *Updater) GetPackage(name string) *Package {
 	return nil
 }
 
+// PendingInfo returns details of every pending update, in the order
+// the updates would be applied.
+func (u *Updater) PendingInfo() []pkg.UpdateInfo {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	ordered, err := u.order()
+	if err != nil {
+		ordered = u.packages
+	}
+
+	var infos []pkg.UpdateInfo
+	for _, p := range ordered {
+		if p.pending != nil {
+			infos = append(infos, pkg.GetUpdateInfo(p.pending))
+		}
+	}
+	return infos
+}
+
 // HasPendingUpdates returns true if any package has an available update.
 func (u *Updater) HasPendingUpdates() bool {
 	u.mu.RLock()
@@ -227,79 +267,178 @@ func (u *Updater) ClearPendingUpdates() {
 
 	for _, pkg := range u.packages {
 		pkg.AvailableUpdate = nil
+		pkg.pending = nil
 	}
 }
 
-// ApplyUpdates applies all pending updates.
-// It returns an error if any update fails.
-func (u *Updater) ApplyUpdates(state *appstate.State) error {
+// ApplyUpdates applies all pending updates in dependency order.
+//
+// The batch is recorded in the state's update queue and the state is saved
+// after each step, so if the launcher exits part way through (including a
+// launcher self-update restart), the next batch resumes at the step that did
+// not complete.
+//
+// A failed package causes the packages that depend on it to be skipped, but
+// unrelated packages are still applied. A result is returned for every
+// pending package. The returned error is non-nil if any required package
+// failed or was skipped.
+func (u *Updater) ApplyUpdates(state *appstate.State) ([]Result, error) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
-	ctx := context.Background()
+	ordered, err := u.order()
+	if err != nil {
+		return nil, err
+	}
 
-	for _, p := range u.packages {
-		if p.AvailableUpdate == nil {
-			continue
+	var queue []*Package
+	for _, p := range ordered {
+		if p.AvailableUpdate != nil {
+			queue = append(queue, p)
 		}
+	}
 
-		slog.Info("applying update",
-			"package", p.Name,
-			"version", p.AvailableUpdate.Version,
-		)
+	if len(queue) == 0 {
+		return nil, nil
+	}
 
-		// Emit applying event.
-		if u.listener != nil {
-			u.listener.Event(update.Event{
-				Name:    "applying",
-				Package: p.Name,
-				Version: p.AvailableUpdate.Version,
-			})
-		}
+	u.beginQueue(state, queue)
 
-		// Create progress reporter that emits notifications
-		reporter := func(status pkg.UpdateStatus) {
-			u.reportProgress(p.Name, 0, 0, status.Progress)
-		}
+	ctx := context.Background()
+	blocked := make(map[string]string)
+	results := make([]Result, 0, len(queue))
+	var firstErr error
 
-		// Re-check and apply the update based on package type
-		var err error
-		switch p.Name {
-		case "jre":
-			var javaUpdate pkg.Update
-			javaUpdate, err = pkg.CheckForJavaUpdate(ctx, state, state.Channel)
-			if err == nil && javaUpdate != nil {
-				err = javaUpdate.Apply(ctx, state, reporter)
-			}
-		case "launcher":
-			var launcherUpdate pkg.Update
-			launcherUpdate, err = pkg.CheckForLauncherUpdate(ctx)
-			if err == nil && launcherUpdate != nil {
-				err = launcherUpdate.Apply(ctx, state, reporter)
+	for _, p := range queue {
+		version := p.AvailableUpdate.Version
+
+		// Skip packages whose required dependencies did not update.
+		if dep := u.blockedBy(p, blocked); dep != "" {
+			slog.Warn("skipping update due to failed dependency",
+				"package", p.Name,
+				"dependency", dep,
+			)
+			blocked[p.Name] = dep
+			u.reportSkipped(p.Name, version)
+			results = append(results, Result{
+				Package: p.Name,
+				Version: version,
+				Status:  StatusSkipped,
+				Error:   fmt.Sprintf("dependency %s was not updated", dep),
+			})
+			if !p.Optional && firstErr == nil {
+				firstErr = fmt.Errorf("%s update skipped: dependency %s was not updated", p.Name, dep)
 			}
+			continue
 		}
 
-		if err != nil {
-			slog.Error("failed to apply update",
+		// Skip steps already completed by an interrupted batch.
+		if state.UpdateQueue.IsDone(p.Name, version) {
+			slog.Info("update already applied by previous batch",
 				"package", p.Name,
-				"error", err,
+				"version", version,
 			)
-			u.reportError(p.Name, err)
-			return fmt.Errorf("failed to apply %s update: %w", p.Name, err)
+			results = append(results, Result{
+				Package: p.Name,
+				Version: version,
+				Status:  StatusApplied,
+			})
+			p.AvailableUpdate = nil
+			p.pending = nil
+			continue
 		}
 
-		// Emit complete event.
-		if u.listener != nil {
-			u.listener.Event(update.Event{
-				Name:    "complete",
+		if err := u.apply(ctx, state, p); err != nil {
+			if !p.Optional {
+				blocked[p.Name] = p.Name
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to apply %s update: %w", p.Name, err)
+				}
+			}
+			results = append(results, Result{
 				Package: p.Name,
-				Version: p.AvailableUpdate.Version,
+				Version: version,
+				Status:  StatusFailed,
+				Error:   err.Error(),
+				Code:    errcode.Of(err),
 			})
+			continue
 		}
 
-		p.AvailableUpdate = nil
+		state.UpdateQueue.MarkDone(p.Name)
+		state.Save("update_step_" + p.Name)
+
+		results = append(results, Result{
+			Package: p.Name,
+			Version: version,
+			Status:  StatusApplied,
+		})
+	}
+
+	// Only clear the queue once every step has completed, so a failed
+	// step is retried first by the next batch.
+	if len(state.UpdateQueue.Remaining()) == 0 {
+		state.UpdateQueue = nil
+		state.Save("update_queue_complete")
+	}
+
+	return results, firstErr
+}
+
+// apply applies the pending update for a single package and emits
+// the applying and complete events around it.
+func (u *Updater) apply(ctx context.Context, state *appstate.State, p *Package) error {
+	version := p.AvailableUpdate.Version
+
+	slog.Info("applying update",
+		"package", p.Name,
+		"version", version,
+	)
+
+	if u.listener != nil {
+		u.listener.Event(update.Event{
+			Name:    "applying",
+			Package: p.Name,
+			Version: version,
+		})
 	}
 
+	if p.pending == nil {
+		err := fmt.Errorf("no pending update for %s, check for updates first", p.Name)
+		u.reportError(p.Name, err)
+		return err
+	}
+
+	// Create progress reporter that emits notifications
+	started := time.Now()
+	var downloaded int64
+	reporter := func(status pkg.UpdateStatus) {
+		downloaded = status.Current
+		u.reportProgress(p.Name, status.State, status.Current, status.Total, status.Progress, started)
+	}
+
+	err := p.pending.Apply(ctx, state, reporter)
+	telemetry.UpdateApplied(p.Name, time.Since(started), err)
+	if err != nil {
+		slog.Error("failed to apply update",
+			"package", p.Name,
+			"error", err,
+		)
+		u.reportError(p.Name, err)
+		return err
+	}
+	telemetry.Downloaded(p.Name, downloaded, time.Since(started))
+
+	if u.listener != nil {
+		u.listener.Event(update.Event{
+			Name:    "complete",
+			Package: p.Name,
+			Version: version,
+		})
+	}
+
+	p.AvailableUpdate = nil
+	p.pending = nil
 	return nil
 }
 
@@ -344,27 +483,57 @@ func (u *Updater) Verify(state *appstate.State) error {
 // reportError sends an error event to the listener.
 func (u *Updater) reportError(pkg string, err error) {
 	if u.listener != nil {
+		code := errcode.Of(err)
 		u.listener.Event(update.Event{
 			Name:    "error",
 			Package: pkg,
 			Error:   err.Error(),
+			Code:    code,
+			Message: errcode.Text(code),
 		})
 	}
 }
 
-// reportProgress sends a progress notification to the listener.
-func (u *Updater) reportProgress(pkg string, downloaded, total int64, progress float64) {
+// reportProgress sends a progress notification to the listener. The speed
+// is averaged over the time since the update started.
+func (u *Updater) reportProgress(pkg, state string, downloaded, total int64, progress float64, started time.Time) {
+	if u.listener == nil {
+		return
+	}
+
+	var speed int64
+	if elapsed := time.Since(started).Seconds(); elapsed >= 1 {
+		speed = int64(float64(downloaded) / elapsed)
+	}
+
+	n := update.Notification{
+		Package:         pkg,
+		State:           state,
+		BytesDownloaded: downloaded,
+		BytesTotal:      total,
+		Progress:        progress,
+		Speed:           speed,
+	}
+	if state != "" && i18n.Has("update.state."+state) {
+		n.Status = i18n.T("update.state." + state)
+	}
+	if total > 0 {
+		n.SizeText = format.Bytes(downloaded) + " / " + format.Bytes(total)
+		n.ETAText = format.ETA(total-downloaded, speed)
+	}
+	if speed > 0 {
+		n.SpeedText = format.Speed(speed)
+	}
+	u.listener.Notify(n)
+}
+
+// reportSkipped sends a skipped event to the listener.
+func (u *Updater) reportSkipped(pkg, version string) {
 	if u.listener != nil {
-		u.listener.Notify(update.Notification{
-			Package:         pkg,
-			BytesDownloaded: downloaded,
-			BytesTotal:      total,
-			Progress:        progress,
+		u.listener.Event(update.Event{
+			Name:    "skipped",
+			Package: pkg,
+			Version: version,
 		})
 	}
 }
-
-// ensure reportError and reportProgress are used (avoid unused warnings)
-var _ = (*Updater)(nil).reportError
-var _ = (*Updater)(nil).reportProgress
-var _ = fmt.Errorf
@@ -4,25 +4,83 @@ package verget
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"hytale-launcher/internal/api"
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/download"
 	"hytale-launcher/internal/endpoints"
-	"hytale-launcher/internal/ioutil"
-	"hytale-launcher/internal/net"
+	"hytale-launcher/internal/hytale"
 )
 
 // FetchFunc is a callback for fetching patch/version data.
 type FetchFunc func(ctx context.Context, channel string, fromBuild int)
 
-// Getter provides cached version manifest retrieval.
+// cacheDirName is the directory in the storage directory manifests are
+// cached in.
+const cacheDirName = "manifest-cache"
+
+// cache keeps fetched manifests across restarts, so they can be
+// revalidated cheaply and used when the API cannot be reached.
+var cache = api.NewCache(func() string { return hytale.InStorageDir(cacheDirName) })
+
+// defaultTTL is how long a manifest of a component without an entry in
+// manifestTTLs is used before it is revalidated.
+const defaultTTL = 15 * time.Minute
+
+// manifestTTLs is how long each component's manifest is used before it is
+// revalidated. Game and server builds change most often; Java runtimes
+// rarely.
+var manifestTTLs = map[string]time.Duration{
+	"game":     5 * time.Minute,
+	"server":   5 * time.Minute,
+	"launcher": time.Hour,
+	"jre":      6 * time.Hour,
+}
+
+// manifestTTL returns how long a component's manifest is used before it is
+// revalidated.
+func manifestTTL(component string) time.Duration {
+	if ttl, ok := manifestTTLs[component]; ok {
+		return ttl
+	}
+	return defaultTTL
+}
+
+// ClearCache deletes the cached manifests and returns the bytes freed.
+func ClearCache() (int64, error) {
+	return cache.Clear()
+}
+
+// Getter provides cached version manifest retrieval for one component.
+// Manifests are kept in memory and on disk per channel, and revalidated
+// with a conditional request once older than the component's TTL or after
+// Invalidate. Concurrent requests for a channel share one fetch.
 type Getter struct {
 	component string
 	fetch     FetchFunc
-	mu        sync.RWMutex
-	cache     *CachedManifest
+	mu        sync.Mutex
+	cache     map[string]*CachedManifest
+
+	// inflight holds the fetch in progress for each channel.
+	inflight map[string]*fetchCall
+
+	// invalidated is when Invalidate was last called.
+	invalidated time.Time
+}
+
+// fetchCall is a manifest fetch shared by concurrent callers. done is
+// closed once manifest and err are set.
+type fetchCall struct {
+	done     chan struct{}
+	manifest *CachedManifest
+	err      error
 }
 
 // CachedManifest holds a cached manifest with metadata.
@@ -33,6 +91,47 @@ type CachedManifest struct {
 	URL      string
 	Hash     string
 	Size     int64
+
+	// Files lists the release's files, if the manifest has them.
+	Files []FileEntry
+
+	// Mirrors and Transport are the release's, see Release.
+	Mirrors   []string
+	Transport string
+
+	// Content lists the optional content offered with the component.
+	Content []ContentEntry
+
+	// Channel is the channel the manifest is for.
+	Channel string
+
+	// Fetched is when the manifest was last fetched from or revalidated
+	// with the API.
+	Fetched time.Time
+
+	// Stale is set if the API could not be reached and the manifest is
+	// the last one fetched.
+	Stale bool
+
+	// checked is when this process last fetched or revalidated the
+	// manifest, from which the TTL is counted in memory.
+	checked time.Time
+}
+
+// Source returns where the release is downloaded from, for
+// download.DownloadCachedFrom.
+func (c *CachedManifest) Source() download.Source {
+	return download.Source{
+		URL:       c.URL,
+		Mirrors:   c.Mirrors,
+		Transport: c.Transport,
+		Size:      c.Size,
+	}
+}
+
+// Age returns how long ago the manifest was fetched or revalidated.
+func (c *CachedManifest) Age() time.Duration {
+	return time.Since(c.Fetched)
 }
 
 // NewGetter creates a new version manifest getter for a component.
@@ -40,41 +139,89 @@ func NewGetter(component string, fetch FetchFunc) *Getter {
 	return &Getter{
 		component: component,
 		fetch:     fetch,
+		cache:     make(map[string]*CachedManifest),
+		inflight:  make(map[string]*fetchCall),
 	}
 }
 
-// Invalidate clears the cached manifest.
+// Invalidate makes the next Get of every channel revalidate its manifest
+// with the API.
 func (g *Getter) Invalidate() {
 	g.mu.Lock()
 	defer g.mu.Unlock()
-	g.cache = nil
+	clear(g.cache)
+	g.invalidated = time.Now()
 }
 
-// Get returns the cached manifest or fetches a new one.
+// Get returns the channel's cached manifest or fetches a new one. A caller
+// arriving while the channel's manifest is being fetched waits for that
+// fetch, and gets its error if it fails.
 func (g *Getter) Get(ctx context.Context, channel string) (*CachedManifest, error) {
-	g.mu.RLock()
-	if g.cache != nil {
-		defer g.mu.RUnlock()
-		return g.cache, nil
-	}
-	g.mu.RUnlock()
+	ttl := manifestTTL(g.component)
 
-	// Fetch new manifest
-	manifest, err := GetManifest(channel, g.component)
-	if err != nil {
-		return nil, err
+	g.mu.Lock()
+	if c := g.cache[channel]; c != nil && time.Since(c.checked) < ttl {
+		g.mu.Unlock()
+		return c, nil
 	}
-
-	cached := &CachedManifest{
-		Manifest: manifest,
-		Version:  manifest.Version,
+	if call, ok := g.inflight[channel]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.manifest, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
+	call := &fetchCall{done: make(chan struct{})}
+	g.inflight[channel] = call
+	maxAge := min(ttl, time.Since(g.invalidated))
+	g.mu.Unlock()
+
+	call.manifest, call.err = g.load(ctx, channel, maxAge)
 
 	g.mu.Lock()
-	g.cache = cached
+	// A stale manifest is not kept, so the next call tries the API again.
+	if call.err == nil && !call.manifest.Stale {
+		g.cache[channel] = call.manifest
+	}
+	delete(g.inflight, channel)
 	g.mu.Unlock()
+	close(call.done)
+
+	return call.manifest, call.err
+}
+
+// load fetches a channel's manifest through the disk cache.
+func (g *Getter) load(ctx context.Context, channel string, maxAge time.Duration) (*CachedManifest, error) {
+	manifestURL := endpoints.LauncherVersion(channel, g.component)
+	manifest, info, err := api.GetCached[Manifest](ctx, api.Default, cache, manifestURL, nil, maxAge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest for %s/%s: %w", channel, g.component, err)
+	}
 
-	return cached, nil
+	platform, arch := Platform(build.OS()), Arch(build.Arch())
+	release := manifest.GetRelease(platform, arch)
+	if release == nil {
+		return nil, fmt.Errorf("%w: %s/%s on %s/%s", ErrNoRelease, channel, g.component, platform, arch)
+	}
+
+	return &CachedManifest{
+		Manifest:  &manifest,
+		Build:     manifest.BuildNumber(),
+		Version:   manifest.Version,
+		URL:       release.URL,
+		Hash:      release.Checksum,
+		Size:      release.Size,
+		Files:     release.Files,
+		Mirrors:   release.Mirrors,
+		Transport: release.Transport,
+		Content:   manifest.Content,
+		Channel:   channel,
+		Fetched:   info.Fetched,
+		Stale:     info.Stale,
+		checked:   time.Now(),
+	}, nil
 }
 
 // Platform represents the target operating system.
@@ -104,16 +251,146 @@ type Release struct {
 
 	// Size is the download size in bytes.
 	Size int64 `json:"size"`
+
+	// Files lists the files the release installs. Only schema v2
+	// manifests have it.
+	Files []FileEntry `json:"files,omitempty"`
+
+	// Mirrors are other URLs serving the same download.
+	Mirrors []string `json:"mirrors,omitempty"`
+
+	// Transport selects how the download is fetched, such as
+	// download.TransportMultiSource to spread it across the mirrors when
+	// the CDN is congested. Empty means a plain HTTP download.
+	Transport string `json:"transport,omitempty"`
+}
+
+// Source returns where the release is downloaded from, for
+// download.DownloadCachedFrom.
+func (r *Release) Source() download.Source {
+	return download.Source{
+		URL:       r.URL,
+		Mirrors:   r.Mirrors,
+		Transport: r.Transport,
+		Size:      r.Size,
+	}
+}
+
+// Checksums returns the release's file hashes keyed by path, in the form
+// repair.Verify takes, or nil if the release has no file listing.
+func (r *Release) Checksums() map[string]string {
+	if len(r.Files) == 0 {
+		return nil
+	}
+	checksums := make(map[string]string, len(r.Files))
+	for _, f := range r.Files {
+		checksums[f.Path] = f.SHA256
+	}
+	return checksums
+}
+
+// ErrNoRelease is returned when a manifest has no release for the
+// platform and architecture asked for.
+var ErrNoRelease = errors.New("no release available")
+
+// Manifest schema versions. Version 2 adds the per-file listing; a
+// manifest without a schema field is version 1.
+const (
+	SchemaV1 = 1
+	SchemaV2 = 2
+)
+
+// FileEntry describes one file of a release, for verifying an install.
+type FileEntry struct {
+	// Path is the slash-separated path relative to the install directory.
+	Path string `json:"path"`
+
+	// Size is the file size in bytes.
+	Size int64 `json:"size"`
+
+	// SHA256 is the hex-encoded SHA256 hash of the file.
+	SHA256 string `json:"sha256"`
+}
+
+// ContentKind is the kind of an optional content pack.
+type ContentKind string
+
+// Kinds of optional content.
+const (
+	ContentTexturePack  ContentKind = "texture_pack"
+	ContentSoundtrack   ContentKind = "soundtrack"
+	ContentLanguagePack ContentKind = "language_pack"
+)
+
+// ContentEntry describes an optional content pack the player can install
+// alongside a component, such as high resolution textures. Packs do not
+// depend on the platform, so each has a single download.
+type ContentEntry struct {
+	// ID identifies the pack across versions.
+	ID string `json:"id"`
+
+	// Name is the pack's display name.
+	Name string `json:"name"`
+
+	// Kind is the kind of content the pack holds.
+	Kind ContentKind `json:"kind"`
+
+	// Version is the pack's version string.
+	Version string `json:"version"`
+
+	// Build is the pack's build number, which increases with every
+	// version.
+	Build int `json:"build"`
+
+	// Download is the pack's archive.
+	Download Release `json:"download"`
 }
 
 // Manifest represents version information for a component.
 // It contains the version string and download URLs for each platform/arch combination.
 type Manifest struct {
+	// Schema is the manifest schema version; zero means SchemaV1.
+	Schema int `json:"schema,omitempty"`
+
 	// Version is the version string for this manifest.
 	Version string `json:"version"`
 
+	// Build is the build number, if the manifest has one. See BuildNumber.
+	Build int `json:"build,omitempty"`
+
 	// DownloadURL maps platform -> arch -> release info.
 	DownloadURL map[Platform]map[Arch]Release `json:"download_url"`
+
+	// Content lists the optional content packs offered with this version.
+	Content []ContentEntry `json:"content,omitempty"`
+}
+
+// SchemaVersion returns the manifest's schema version.
+func (m *Manifest) SchemaVersion() int {
+	if m.Schema == 0 {
+		return SchemaV1
+	}
+	return m.Schema
+}
+
+// BuildNumber returns the manifest's build number, used to decide whether
+// an installed build is out of date. Manifests without a build field have
+// it parsed from the version: a version that is a number, or the number
+// after a '+' as in Java's "21.0.2+13". Returns zero if there is none,
+// which no installed build is older than.
+func (m *Manifest) BuildNumber() int {
+	if m.Build > 0 {
+		return m.Build
+	}
+	if n, err := strconv.Atoi(m.Version); err == nil && n > 0 {
+		return n
+	}
+	if _, after, ok := strings.Cut(m.Version, "+"); ok {
+		if n, err := strconv.Atoi(after); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
 }
 
 // GetRelease returns the release info for a specific platform and architecture.
@@ -141,35 +418,18 @@ func (m *Manifest) GetRelease(platform Platform, arch Arch) *Release {
 // The component is the name of the software component (e.g., "launcher", "jre").
 //
 // Returns net.ErrOffline if the launcher is in offline mode.
-func GetManifest(channel, component string) (*Manifest, error) {
-	// Check offline mode first
-	if err := net.OfflineError(); err != nil {
-		return nil, err
-	}
-
-	manifestURL := endpoints.LauncherVersion(channel, component)
-
-	manifest, err := ioutil.Get[Manifest](nil, manifestURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch manifest for %s/%s: %w", channel, component, err)
-	}
-
-	return &manifest, nil
+func GetManifest(ctx context.Context, channel, component string) (*Manifest, error) {
+	return GetManifestWithClient(ctx, api.Default, channel, component, nil)
 }
 
-// GetManifestWithClient fetches the version manifest using a custom HTTP client.
+// GetManifestWithClient fetches the version manifest using a custom API client.
 // This is useful when authentication or custom transport is needed.
 //
 // Returns net.ErrOffline if the launcher is in offline mode.
-func GetManifestWithClient(client *http.Client, channel, component string, params url.Values) (*Manifest, error) {
-	// Check offline mode first
-	if err := net.OfflineError(); err != nil {
-		return nil, err
-	}
-
+func GetManifestWithClient(ctx context.Context, client *api.Client, channel, component string, params url.Values) (*Manifest, error) {
 	manifestURL := endpoints.LauncherVersion(channel, component)
 
-	manifest, err := ioutil.Get[Manifest](client, manifestURL, params)
+	manifest, err := api.Get[Manifest](ctx, client, manifestURL, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch manifest for %s/%s: %w", channel, component, err)
 	}
@@ -179,8 +439,8 @@ func GetManifestWithClient(client *http.Client, channel, component string, param
 
 // GetLatestVersion fetches just the version string for a component.
 // This is a convenience method that only extracts the version from the manifest.
-func GetLatestVersion(channel, component string) (string, error) {
-	manifest, err := GetManifest(channel, component)
+func GetLatestVersion(ctx context.Context, channel, component string) (string, error) {
+	manifest, err := GetManifest(ctx, channel, component)
 	if err != nil {
 		return "", err
 	}
@@ -189,16 +449,15 @@ func GetLatestVersion(channel, component string) (string, error) {
 
 // GetDownloadInfo fetches the download information for a specific component,
 // platform, and architecture combination.
-func GetDownloadInfo(channel, component string, platform Platform, arch Arch) (*Release, error) {
-	manifest, err := GetManifest(channel, component)
+func GetDownloadInfo(ctx context.Context, channel, component string, platform Platform, arch Arch) (*Release, error) {
+	manifest, err := GetManifest(ctx, channel, component)
 	if err != nil {
 		return nil, err
 	}
 
 	release := manifest.GetRelease(platform, arch)
 	if release == nil {
-		return nil, fmt.Errorf("no release available for %s/%s on %s/%s",
-			channel, component, platform, arch)
+		return nil, fmt.Errorf("%w: %s/%s on %s/%s", ErrNoRelease, channel, component, platform, arch)
 	}
 
 	return release, nil
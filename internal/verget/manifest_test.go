@@ -0,0 +1,161 @@
+package verget_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/mockapi"
+	"hytale-launcher/internal/verget"
+)
+
+func TestMain(m *testing.M) {
+	// The mock server can only be used in development builds, and the
+	// manifest cache is kept in the storage directory.
+	build.Release = "dev"
+	dir, err := os.MkdirTemp("", "verget-test-*")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_DATA_HOME", dir)
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// otherPlatform returns a platform other than the one the tests run on.
+func otherPlatform() verget.Platform {
+	if build.OS() == string(verget.PlatformWindows) {
+		return verget.PlatformLinux
+	}
+	return verget.PlatformWindows
+}
+
+// serve starts a mock server serving manifest as the component's manifest
+// on channel, and routes every endpoint to it for the rest of the test.
+func serve(t *testing.T, channel, component string, manifest *verget.Manifest) {
+	t.Helper()
+	srv := mockapi.New(mockapi.Fixtures{
+		Manifests: map[string]*verget.Manifest{
+			mockapi.ManifestKey(channel, component): manifest,
+		},
+	})
+	t.Cleanup(srv.Close)
+
+	restore, err := srv.Use()
+	if err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+	t.Cleanup(restore)
+}
+
+func TestGetReleaseMissingPlatform(t *testing.T) {
+	m := &verget.Manifest{
+		Version: "1",
+		DownloadURL: map[verget.Platform]map[verget.Arch]verget.Release{
+			verget.PlatformLinux: {verget.ArchAMD64: {URL: "https://example.com/linux"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		platform verget.Platform
+		arch     verget.Arch
+		want     string
+	}{
+		{"present", verget.PlatformLinux, verget.ArchAMD64, "https://example.com/linux"},
+		{"missing platform", verget.PlatformWindows, verget.ArchAMD64, ""},
+		{"missing arch", verget.PlatformLinux, verget.ArchARM64, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := m.GetRelease(tt.platform, tt.arch)
+			var got string
+			if r != nil {
+				got = r.URL
+			}
+			if got != tt.want {
+				t.Errorf("GetRelease(%s, %s) URL = %q, want %q", tt.platform, tt.arch, got, tt.want)
+			}
+		})
+	}
+
+	if r := (&verget.Manifest{}).GetRelease(verget.PlatformLinux, verget.ArchAMD64); r != nil {
+		t.Errorf("GetRelease on a manifest without downloads = %+v, want nil", r)
+	}
+}
+
+func TestGetterMissingPlatform(t *testing.T) {
+	serve(t, "missing-platform", "jre", &verget.Manifest{
+		Version: "21.0.2+13",
+		DownloadURL: map[verget.Platform]map[verget.Arch]verget.Release{
+			otherPlatform(): {verget.Arch(build.Arch()): {URL: "https://example.com/jre"}},
+		},
+	})
+
+	g := verget.NewGetter("jre", nil)
+	_, err := g.Get(context.Background(), "missing-platform")
+	if !errors.Is(err, verget.ErrNoRelease) {
+		t.Fatalf("Get() error = %v, want %v", err, verget.ErrNoRelease)
+	}
+}
+
+func TestGetterFillsRelease(t *testing.T) {
+	serve(t, "present-platform", "jre", &verget.Manifest{
+		Version: "21.0.2+13",
+		DownloadURL: map[verget.Platform]map[verget.Arch]verget.Release{
+			verget.Platform(build.OS()): {verget.Arch(build.Arch()): {
+				URL:      "https://example.com/jre",
+				Checksum: "abc",
+				Size:     42,
+			}},
+		},
+	})
+
+	g := verget.NewGetter("jre", nil)
+	c, err := g.Get(context.Background(), "present-platform")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if c.URL != "https://example.com/jre" || c.Hash != "abc" || c.Size != 42 {
+		t.Errorf("Get() release = %q %q %d, want the release for this platform", c.URL, c.Hash, c.Size)
+	}
+	if c.Build != 13 {
+		t.Errorf("Get() build = %d, want 13", c.Build)
+	}
+}
+
+func TestGetDownloadInfoMissingPlatform(t *testing.T) {
+	serve(t, "download-info", "launcher", &verget.Manifest{
+		Version: "5",
+		DownloadURL: map[verget.Platform]map[verget.Arch]verget.Release{
+			verget.PlatformLinux: {verget.ArchAMD64: {URL: "https://example.com/launcher"}},
+		},
+	})
+
+	_, err := verget.GetDownloadInfo(context.Background(), "download-info", "launcher", verget.PlatformDarwin, verget.ArchARM64)
+	if !errors.Is(err, verget.ErrNoRelease) {
+		t.Fatalf("GetDownloadInfo() error = %v, want %v", err, verget.ErrNoRelease)
+	}
+}
+
+func TestBuildNumber(t *testing.T) {
+	tests := []struct {
+		manifest verget.Manifest
+		want     int
+	}{
+		{verget.Manifest{Build: 7, Version: "3"}, 7},
+		{verget.Manifest{Version: "42"}, 42},
+		{verget.Manifest{Version: "21.0.2+13"}, 13},
+		{verget.Manifest{Version: "2026.01.10-abc"}, 0},
+		{verget.Manifest{}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.manifest.BuildNumber(); got != tt.want {
+			t.Errorf("BuildNumber() of %+v = %d, want %d", tt.manifest, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+// Package launcherkit is the public library layer of the launcher. Its
+// subpackages expose the launcher's generic building blocks with stable
+// APIs, so that tools such as headless updaters and server provisioners can
+// reuse them without depending on the desktop application:
+//
+//   - download: HTTP downloads with progress and SHA-256 verification
+//   - manifest: fetching component version manifests
+//   - secret: system keyring storage and AES-GCM encryption
+//
+// launcherkit is a module of its own that depends only on the standard
+// library and the system keyring bindings, so importing it does not pull in
+// the launcher. The launcher itself uses it for encryption. Exported
+// identifiers in launcherkit follow semantic versioning as given by
+// Version: they are only removed or changed incompatibly in a new major
+// version.
+//
+// Applying wharf patches is not part of launcherkit yet; it will be added
+// once the launcher's patching is complete.
+//
+// See the package examples for downloading the latest release of a
+// component.
+package launcherkit
+
+// Version is the version of the launcherkit API.
+const Version = "0.1.0"
@@ -0,0 +1,136 @@
+// Package download downloads files over HTTP with progress reporting and
+// SHA-256 verification.
+//
+//	path, err := download.Fetch(ctx, url, download.Options{
+//		SHA256: checksum,
+//		OnProgress: func(p download.Progress) {
+//			fmt.Printf("\r%d/%d bytes", p.Bytes, p.Total)
+//		},
+//	})
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Progress describes how far a download has got.
+type Progress struct {
+	// Bytes is the number of bytes downloaded so far.
+	Bytes int64
+
+	// Total is the expected size in bytes, from Options.Size. It is zero
+	// if the size is unknown.
+	Total int64
+
+	// Speed is the average download speed so far, in bytes per second.
+	Speed int64
+}
+
+// Options configures a download. The zero value is valid.
+type Options struct {
+	// Client performs the request. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// Dir is the directory the file is downloaded to. Defaults to the
+	// system temporary directory.
+	Dir string
+
+	// SHA256 is the expected hex-encoded hash of the file. If set, a
+	// download that does not match it fails.
+	SHA256 string
+
+	// Size is the expected size in bytes, reported as Progress.Total.
+	Size int64
+
+	// OnProgress is called as the download progresses.
+	OnProgress func(Progress)
+}
+
+// Fetch downloads url to a new temporary file and returns its path. The
+// caller is responsible for removing the file. A response other than 200
+// OK, or a file that does not match Options.SHA256, is an error.
+func Fetch(ctx context.Context, url string, opts Options) (path string, err error) {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	dir := opts.Dir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("download: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download: %s: %s", url, resp.Status)
+	}
+
+	f, err := os.CreateTemp(dir, "dl-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		f.Close()
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	hash := sha256.New()
+	var body io.Reader = resp.Body
+	if opts.OnProgress != nil {
+		body = &progressReader{r: body, opts: &opts, start: time.Now()}
+	}
+	if _, err := io.Copy(io.MultiWriter(f, hash), body); err != nil {
+		return "", fmt.Errorf("download: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	if opts.SHA256 != "" {
+		if sum := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(sum, opts.SHA256) {
+			return "", fmt.Errorf("download: checksum mismatch: expected %s, got %s", opts.SHA256, sum)
+		}
+	}
+	return f.Name(), nil
+}
+
+// progressReader reports the progress of reads from r.
+type progressReader struct {
+	r     io.Reader
+	opts  *Options
+	start time.Time
+	bytes int64
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.bytes += int64(n)
+		var speed int64
+		if elapsed := time.Since(p.start).Seconds(); elapsed > 0 {
+			speed = int64(float64(p.bytes) / elapsed)
+		}
+		p.opts.OnProgress(Progress{Bytes: p.bytes, Total: p.opts.Size, Speed: speed})
+	}
+	return n, err
+}
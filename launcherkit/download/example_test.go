@@ -0,0 +1,52 @@
+package download_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"hytale-launcher/launcherkit/download"
+)
+
+func ExampleFetch() {
+	body := []byte("hello, world\n")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	sum := sha256.Sum256(body)
+	path, err := download.Fetch(context.Background(), srv.URL+"/hello.txt", download.Options{
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   int64(len(body)),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(string(data))
+	// Output: hello, world
+}
+
+func ExampleFetch_checksumMismatch() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("tampered"))
+	}))
+	defer srv.Close()
+
+	_, err := download.Fetch(context.Background(), srv.URL, download.Options{
+		SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	})
+	fmt.Println(err != nil)
+	// Output: true
+}
@@ -0,0 +1,34 @@
+package launcherkit_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"hytale-launcher/launcherkit/download"
+	"hytale-launcher/launcherkit/manifest"
+)
+
+// This example downloads the latest Java runtime released for the current
+// platform, verifying it against the checksum in its manifest.
+func Example() {
+	ctx := context.Background()
+
+	m, err := manifest.Fetch(ctx, "release", "jre")
+	if err != nil {
+		log.Fatal(err)
+	}
+	r := m.Release(manifest.CurrentPlatform())
+	if r == nil {
+		log.Fatal("no release for this platform")
+	}
+
+	path, err := download.Fetch(ctx, r.URL, download.Options{SHA256: r.Checksum, Size: r.Size})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(path)
+
+	fmt.Println("downloaded Java", m.Version, "to", path)
+}
@@ -0,0 +1,42 @@
+package manifest_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+
+	"hytale-launcher/launcherkit/manifest"
+)
+
+func ExampleFetch() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version/release/jre.json" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{
+			"version": "21.0.2+13",
+			"download_url": {
+				"linux": {"amd64": {"url": "https://example.com/jre.tar.gz", "checksum": "abc", "size": 1024}}
+			}
+		}`)
+	}))
+	defer srv.Close()
+	manifest.BaseURL = srv.URL
+
+	m, err := manifest.Fetch(context.Background(), "release", "jre")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(m.Version)
+	if r := m.Release(manifest.Platform{OS: "linux", Arch: "amd64"}); r != nil {
+		fmt.Println(r.URL, r.Size)
+	}
+	fmt.Println(m.Release(manifest.Platform{OS: "plan9", Arch: "386"}) == nil)
+	// Output:
+	// 21.0.2+13
+	// https://example.com/jre.tar.gz 1024
+	// true
+}
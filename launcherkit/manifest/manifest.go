@@ -0,0 +1,118 @@
+// Package manifest fetches the version manifests that describe the latest
+// release of each launcher component ("launcher", "jre", ...) per platform.
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+)
+
+// BaseURL is the base URL manifests are fetched from. It can be changed to
+// fetch them from another environment.
+var BaseURL = "https://launcher.hytale.com"
+
+// Platform identifies an operating system and CPU architecture, such as
+// {"linux", "amd64"}.
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// CurrentPlatform returns the platform the program is running on.
+func CurrentPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+// Release describes the download of a component for one platform.
+type Release struct {
+	// URL is the download URL.
+	URL string
+
+	// Checksum is the hex-encoded SHA-256 hash of the download.
+	Checksum string
+
+	// Size is the download size in bytes.
+	Size int64
+}
+
+// Manifest describes the latest release of a component.
+type Manifest struct {
+	// Version is the version of the release.
+	Version string
+
+	releases map[Platform]Release
+}
+
+// Release returns the download for a platform, or nil if the component is
+// not released for it.
+func (m *Manifest) Release(p Platform) *Release {
+	r, ok := m.releases[p]
+	if !ok {
+		return nil
+	}
+	return &r
+}
+
+// Platforms returns the platforms the component is released for.
+func (m *Manifest) Platforms() []Platform {
+	platforms := make([]Platform, 0, len(m.releases))
+	for p := range m.releases {
+		platforms = append(platforms, p)
+	}
+	return platforms
+}
+
+// URL returns the URL of a component's manifest on a channel.
+func URL(channel, component string) string {
+	return fmt.Sprintf("%s/version/%s/%s.json", BaseURL, channel, component)
+}
+
+// rawManifest is a manifest as it is served.
+type rawManifest struct {
+	Version     string `json:"version"`
+	DownloadURL map[string]map[string]struct {
+		URL      string `json:"url"`
+		Checksum string `json:"checksum"`
+		Size     int64  `json:"size"`
+	} `json:"download_url"`
+}
+
+// Fetch fetches the manifest of a component on a channel ("release",
+// "beta", ...).
+func Fetch(ctx context.Context, channel, component string) (*Manifest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, URL(channel, component), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: unable to fetch %s/%s: %w", channel, component, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("manifest: unable to fetch %s/%s: %s", channel, component, resp.Status)
+	}
+
+	var raw rawManifest
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("manifest: unable to decode %s/%s: %w", channel, component, err)
+	}
+
+	m := &Manifest{
+		Version:  raw.Version,
+		releases: make(map[Platform]Release),
+	}
+	for os, arches := range raw.DownloadURL {
+		for arch, r := range arches {
+			m.releases[Platform{OS: os, Arch: arch}] = Release{
+				URL:      r.URL,
+				Checksum: r.Checksum,
+				Size:     r.Size,
+			}
+		}
+	}
+	return m, nil
+}
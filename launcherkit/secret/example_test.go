@@ -0,0 +1,38 @@
+package secret_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"hytale-launcher/launcherkit/secret"
+)
+
+func ExampleEncrypt() {
+	key := bytes.Repeat([]byte{0x2a}, secret.KeySize)
+
+	sealed, err := secret.Encrypt([]byte("session token"), key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opened, err := secret.Decrypt(sealed, key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(opened))
+	// Output: session token
+}
+
+// This example keeps a tool's state encrypted with a key stored in the
+// system keyring.
+func ExampleKey() {
+	key, err := secret.Key("my-tool", "state-key")
+	if err != nil {
+		log.Fatal(err)
+	}
+	sealed, err := secret.Encrypt([]byte(`{"last_build": 42}`), key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(sealed) > 0)
+}
@@ -0,0 +1,127 @@
+// Package secret stores secrets in the system keyring and encrypts data with
+// keys kept there.
+//
+//	key, err := secret.Key("my-tool", "state-key")
+//	if err != nil {
+//		return err
+//	}
+//	sealed, err := secret.Encrypt(data, key)
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	gokeyring "github.com/zalando/go-keyring"
+)
+
+// KeySize is the size in bytes of keys created by Key, suitable for AES-256.
+const KeySize = 32
+
+// ErrKeyring matches errors caused by the system keyring being locked,
+// unavailable or denying access.
+var ErrKeyring = errors.New("secret: keyring unavailable")
+
+// Get returns the value stored for key under service, or nil if there is
+// none.
+func Get(service, key string) ([]byte, error) {
+	encoded, err := gokeyring.Get(service, key)
+	if errors.Is(err, gokeyring.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, keyringError(err)
+	}
+	// Values are kept base64-encoded, as the launcher keeps them.
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("secret: malformed value for %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Set stores value for key under service.
+func Set(service, key string, value []byte) error {
+	if err := gokeyring.Set(service, key, base64.StdEncoding.EncodeToString(value)); err != nil {
+		return keyringError(err)
+	}
+	return nil
+}
+
+// Key returns the encryption key stored for key under service, creating
+// and storing a random one if there is none.
+func Key(service, key string) ([]byte, error) {
+	existing, err := Get(service, key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	created := make([]byte, KeySize)
+	if _, err := rand.Read(created); err != nil {
+		return nil, fmt.Errorf("secret: unable to generate key: %w", err)
+	}
+	if err := Set(service, key, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// encryptedMarker prefixes data encrypted by Encrypt.
+const encryptedMarker = 'E'
+
+// Encrypt encrypts data with AES-GCM in the launcher's format, so it can
+// be read back with Decrypt: a marker byte, the nonce, then the
+// ciphertext.
+func Encrypt(data, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 1+gcm.NonceSize(), 1+gcm.NonceSize()+len(data)+gcm.Overhead())
+	out[0] = encryptedMarker
+	if _, err := rand.Read(out[1:]); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(out, out[1:], data, nil), nil
+}
+
+// Decrypt decrypts data produced by Encrypt. Data that is not encrypted is
+// returned unchanged.
+func Decrypt(data, key []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != encryptedMarker {
+		return data, nil
+	}
+	data = data[1:]
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("secret: ciphertext too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+// newGCM returns an AES-GCM cipher for key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// keyringError wraps a keyring failure so that it matches ErrKeyring.
+func keyringError(err error) error {
+	return fmt.Errorf("%w: %w", ErrKeyring, err)
+}
@@ -4,8 +4,13 @@ package main
 
 import (
 	"embed"
+	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"strings"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -15,17 +20,74 @@ import (
 	"github.com/wailsapp/wails/v2/pkg/options/windows"
 
 	"hytale-launcher/internal/app"
+	extassets "hytale-launcher/internal/assets"
 	"hytale-launcher/internal/build"
+	"hytale-launcher/internal/crashreport"
+	"hytale-launcher/internal/deeplink"
+	"hytale-launcher/internal/exitlog"
+	"hytale-launcher/internal/instance"
 	"hytale-launcher/internal/logging"
+	"hytale-launcher/internal/news"
+	"hytale-launcher/internal/selfupdate"
+	"hytale-launcher/internal/tray"
 )
 
 //go:embed frontend/dist
 var assets embed.FS
 
 func main() {
+	flags := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	status := flags.Bool("status", false, "print the launcher status and exit")
+	asJSON := flags.Bool("json", false, "print the launcher status as JSON and exit")
+	background := flags.Bool("background", false, "start hidden in the system tray")
+	test := flags.Bool("test", false, "exit immediately; used to validate an updated binary")
+	safeMode := flags.Bool("safe-mode", false, "start without optional integrations, to recover a misbehaving launcher")
+	flags.Bool("launch", false, "launch the game once the launcher has started")
+
+	// Self-update helper flags, passed by the launcher being replaced.
+	flags.IntVar(&selfupdate.ParentPID, "start-pid", 0, "PID of the launcher to wait for")
+	flags.StringVar(&selfupdate.SourceBin, "source-exe", "", "new launcher binary")
+	flags.StringVar(&selfupdate.TargetBin, "dest-exe", "", "launcher binary to replace")
+	flags.StringVar(&selfupdate.OldChannel, "launcher-patchline", "", "patchline of the launcher being replaced")
+	flags.StringVar(&selfupdate.OldVersion, "launcher-version", "", "version of the launcher being replaced")
+	flags.StringVar(&selfupdate.UpdateSignature, "sig", "", "signature of the update request")
+
+	// Arguments the launcher does not know, such as the -psn_ argument
+	// macOS passes or a hytale:// link, are left for others to handle.
+	if err := flags.Parse(knownArgs(flags, os.Args[1:])); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+	}
+
+	if *test {
+		return
+	}
+
+	// Act as the self-update helper: replace the old binary and start the
+	// new one. Do only returns if the update failed.
+	if selfupdate.SourceBin != "" {
+		logging.Init()
+		selfupdate.Do()
+		os.Exit(1)
+	}
+
+	// Status output goes to stdout, so it must not be mixed with log output.
+	if *status || *asJSON {
+		if err := app.WriteStatus(os.Stdout, *asJSON); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Initialize logging
 	logging.Init()
 
+	// Set up crash reporting, which honours the user's opt-out
+	if err := crashreport.Init(); err != nil {
+		slog.Warn("unable to set up crash reporting", "error", err)
+	}
+	defer crashreport.Flush()
+
 	slog.Info("starting Hytale Launcher",
 		"version", build.Version,
 		"release", build.Release,
@@ -33,22 +95,59 @@ func main() {
 		"arch", build.Arch(),
 	)
 
+	// Hand off to a launcher that is already running, if there is one
+	lock, err := instance.Acquire(os.Args[1:])
+	if errors.Is(err, instance.ErrAlreadyRunning) {
+		slog.Info("launcher already running, forwarded arguments")
+		return
+	}
+	if err != nil {
+		slog.Warn("unable to acquire instance lock", "error", err)
+	}
+	defer lock.Release()
+
+	// Record this run so the next one can tell whether it exited cleanly
+	exitlog.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			exitlog.Record(exitlog.ReasonCrash)
+			crashreport.Flush()
+			panic(r)
+		}
+	}()
+
 	// Create the application instance
 	application := app.New()
+	lock.Serve(func(msg instance.Message) {
+		application.HandleSecondInstance(msg.Args)
+	})
+	if link := deeplink.FromArgs(os.Args[1:]); link != "" {
+		application.SetStartupLink(link)
+	}
+	if *safeMode {
+		application.StartInSafeMode()
+	}
+	startHidden := *background && tray.Supported() && !*safeMode
+	if startHidden {
+		application.StartInBackground()
+	}
 
 	// Run the Wails application
-	err := wails.Run(&options.App{
+	err = wails.Run(&options.App{
 		Title:     "Hytale Launcher",
 		Width:     1280,
 		Height:    800,
 		MinWidth:  1024,
 		MinHeight: 700,
 		AssetServer: &assetserver.Options{
-			Assets: assets,
+			Assets:  assets,
+			Handler: assetHandler(),
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        application.Startup,
 		OnDomReady:       application.DomReady,
+		OnBeforeClose:    application.BeforeClose,
+		StartHidden:      startHidden,
 		Bind: []interface{}{
 			application,
 		},
@@ -68,14 +167,60 @@ func main() {
 			},
 			WebviewIsTransparent: true,
 			WindowIsTranslucent:  true,
+			OnUrlOpen:            application.HandleDeepLink,
 		},
 		Linux: &linux.Options{
 			ProgramName: "Hytale Launcher",
 		},
 	})
 
+	if err == nil {
+		err = application.StartupError()
+	}
 	if err != nil {
 		slog.Error("application error", "error", err)
+		exitlog.Record(exitlog.ReasonError)
+		crashreport.Flush()
+		lock.Release()
 		os.Exit(1)
 	}
+
+	exitlog.Record(exitlog.ReasonQuit)
+}
+
+// knownArgs returns the arguments in args that are flags of fs, with their
+// values, dropping everything else.
+func knownArgs(fs *flag.FlagSet, args []string) []string {
+	var known []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" || arg == "--" {
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		f := fs.Lookup(name)
+		if f == nil {
+			continue
+		}
+		known = append(known, arg)
+
+		isBool := false
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok {
+			isBool = b.IsBoolFlag()
+		}
+		if !hasValue && !isBool && i+1 < len(args) {
+			i++
+			known = append(known, args[i])
+		}
+	}
+	return known
+}
+
+// assetHandler returns the Wails asset server's fallback handler, serving
+// extension assets and proxied news images.
+func assetHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(extassets.Prefix, extassets.Handler())
+	mux.Handle(news.ImagePrefix, news.ImageHandler())
+	return mux
 }